@@ -45,6 +45,7 @@ type database interface {
 	put(key *protocol.DeviceID, rec *discosrv.DatabaseRecord) error
 	merge(key *protocol.DeviceID, addrs []*discosrv.DatabaseAddress, seen int64) error
 	get(key *protocol.DeviceID) (*discosrv.DatabaseRecord, error)
+	iterate(fn func(key protocol.DeviceID, rec *discosrv.DatabaseRecord) bool)
 }
 
 type inMemoryStore struct {
@@ -140,6 +141,12 @@ func (s *inMemoryStore) get(key *protocol.DeviceID) (*discosrv.DatabaseRecord, e
 	return rec, nil
 }
 
+// iterate calls fn for each record in the database, until fn returns
+// false.
+func (s *inMemoryStore) iterate(fn func(key protocol.DeviceID, rec *discosrv.DatabaseRecord) bool) {
+	s.m.Range(fn)
+}
+
 func (s *inMemoryStore) Serve(ctx context.Context) error {
 	if s.flushInterval <= 0 {
 		<-ctx.Done()
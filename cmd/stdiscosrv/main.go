@@ -55,6 +55,13 @@ const (
 
 	// Size of the replication outbox channel
 	replicationOutboxSize = 10000
+
+	// Peer replication timing and limits
+	replicationDialTimeout       = 10 * time.Second
+	replicationHeartbeatInterval = 30 * time.Second
+	replicationReadTimeout       = 2 * replicationHeartbeatInterval
+	replicationWriteTimeout      = 30 * time.Second
+	replicationMaxRecordSize     = 1 << 20
 )
 
 var debug = false
@@ -83,6 +90,9 @@ type CLI struct {
 
 	AMQPAddress string `group:"AMQP replication" hidden:"true" help:"Address to AMQP broker" env:"DISCOVERY_AMQP_ADDRESS"`
 
+	ReplicationListen string   `group:"Peer replication" help:"Listen address for replication connections from peers" env:"DISCOVERY_REPLICATION_LISTEN"`
+	ReplicationPeers  []string `group:"Peer replication" placeholder:"ID@ADDRESS" help:"Peers to replicate to, as device ID@address; must be all other servers" env:"DISCOVERY_REPLICATION_PEERS"`
+
 	Debug   bool `short:"d" help:"Print debug output" env:"DISCOVERY_DEBUG"`
 	Version bool `short:"v" help:"Print version and exit"`
 }
@@ -101,8 +111,12 @@ func main() {
 
 	buildInfo.WithLabelValues(build.Version, runtime.Version(), build.User, build.Date.UTC().Format("2006-01-02T15:04:05Z")).Set(1)
 
+	// Peer replication uses the certificate even when the API is served
+	// over plain HTTP.
+	replication := cli.ReplicationListen != "" || len(cli.ReplicationPeers) > 0
+
 	var cert tls.Certificate
-	if !cli.HTTP {
+	if !cli.HTTP || replication {
 		var err error
 		cert, err = tls.LoadX509KeyPair(cli.Cert, cli.Key)
 		if os.IsNotExist(err) {
@@ -141,12 +155,41 @@ func main() {
 	main.Add(db)
 
 	// If we have an AMQP broker for replication, start that
-	var repl replicator
+	var repls replicationMultiplexer
 	if cli.AMQPAddress != "" {
 		clientID := rand.String(10)
 		kr := newAMQPReplicator(cli.AMQPAddress, clientID, db)
 		main.Add(kr)
-		repl = kr
+		repls = append(repls, kr)
+	}
+
+	// If we have replication peers, send to them and accept their
+	// connections.
+	if replication {
+		peers, err := parseReplicationPeers(cli.ReplicationPeers)
+		if err != nil {
+			log.Fatalln("Replication peers:", err)
+		}
+		if err := validateReplicationMesh(protocol.NewDeviceID(cert.Certificate[0]), cli.ReplicationListen, peers); err != nil {
+			log.Fatalln("Replication:", err)
+		}
+		ids := make([]protocol.DeviceID, len(peers))
+		for i, peer := range peers {
+			rs := newReplicationSender(peer, cert, db)
+			main.Add(rs)
+			repls = append(repls, rs)
+			ids[i] = peer.id
+		}
+		main.Add(newReplicationListener(cli.ReplicationListen, cert, ids, db))
+	}
+
+	var repl replicator
+	switch len(repls) {
+	case 0:
+	case 1:
+		repl = repls[0]
+	default:
+		repl = repls
 	}
 
 	// Start the main API server.
@@ -0,0 +1,392 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/syncthing/syncthing/internal/gen/discosrv"
+	"github.com/syncthing/syncthing/internal/protoutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/tlsutil"
+)
+
+// Peer replication runs over mutually authenticated TLS connections, each
+// side verifying the device ID of the other. Every server connects to each
+// of its peers and sends records in the same length prefixed format as the
+// database file: first a full copy of the database, then every
+// announcement as it happens. Records without a key are heartbeats.
+//
+// Records aren't forwarded, so the servers must form a full mesh: each one
+// listens and has all the others as peers. A server can't see the whole
+// mesh, but validateReplicationMesh refuses configurations that can't be
+// part of one, and connections from servers that aren't peers are refused
+// and logged.
+
+type replicationPeer struct {
+	id   protocol.DeviceID
+	addr string
+}
+
+// parseReplicationPeers parses peers given as "device ID@address".
+func parseReplicationPeers(peers []string) ([]replicationPeer, error) {
+	res := make([]replicationPeer, 0, len(peers))
+	for _, peer := range peers {
+		idStr, addr, ok := strings.Cut(peer, "@")
+		if !ok || addr == "" {
+			return nil, fmt.Errorf("%q: not in the format device ID@address", peer)
+		}
+		id, err := protocol.DeviceIDFromString(idStr)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", peer, err)
+		}
+		res = append(res, replicationPeer{id: id, addr: addr})
+	}
+	return res, nil
+}
+
+// validateReplicationMesh checks that a server with the given device ID
+// can be part of a full replication mesh with the given peers.
+func validateReplicationMesh(own protocol.DeviceID, listen string, peers []replicationPeer) error {
+	if listen == "" {
+		return errors.New("a listen address is required, as peers don't forward records")
+	}
+	if len(peers) == 0 {
+		return errors.New("peers are required, as peers don't forward records")
+	}
+	seen := make(map[protocol.DeviceID]struct{}, len(peers))
+	for _, peer := range peers {
+		if peer.id == own {
+			return fmt.Errorf("%s: this server can't be its own peer", peer.id.Short())
+		}
+		if _, ok := seen[peer.id]; ok {
+			return fmt.Errorf("%s: listed more than once", peer.id.Short())
+		}
+		seen[peer.id] = struct{}{}
+	}
+	return nil
+}
+
+// replicationMultiplexer sends to several replicators.
+type replicationMultiplexer []replicator
+
+func (m replicationMultiplexer) send(key *protocol.DeviceID, ps []*discosrv.DatabaseAddress, seen int64) {
+	for _, r := range m {
+		r.send(key, ps, seen)
+	}
+}
+
+// replicationSender connects to one peer and sends our records to it.
+type replicationSender struct {
+	peer   replicationPeer
+	cert   tls.Certificate
+	db     database
+	outbox chan *discosrv.ReplicationRecord
+}
+
+func newReplicationSender(peer replicationPeer, cert tls.Certificate, db database) *replicationSender {
+	return &replicationSender{
+		peer:   peer,
+		cert:   cert,
+		db:     db,
+		outbox: make(chan *discosrv.ReplicationRecord, replicationOutboxSize),
+	}
+}
+
+func (s *replicationSender) Serve(ctx context.Context) error {
+	tlsCfg := tlsutil.SecureDefaultTLS13()
+	tlsCfg.Certificates = []tls.Certificate{s.cert}
+	// The certificates are self signed, we verify the device ID instead.
+	tlsCfg.InsecureSkipVerify = true
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: replicationDialTimeout},
+		Config:    tlsCfg,
+	}
+	conn, err := dialer.DialContext(ctx, "tcp", s.peer.addr)
+	if err != nil {
+		return fmt.Errorf("replication dial: %w", err)
+	}
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	tc := conn.(*tls.Conn)
+	if id, err := replicationPeerID(tc); err != nil {
+		return fmt.Errorf("replication: %w", err)
+	} else if id != s.peer.id {
+		return fmt.Errorf("replication: unexpected device ID %s at %s", id, s.peer.addr)
+	}
+
+	w := &replicationWriter{conn: tc, bw: bufio.NewWriter(tc)}
+
+	// Anti-entropy: the peer gets everything we know as soon as we're
+	// connected, covering whatever it missed while we were apart.
+	n, err := s.sendAll(ctx, w)
+	if err != nil {
+		return err
+	}
+	log.Printf("Replication: sent %d records to %s", n, s.peer.id.Short())
+
+	heartbeat := time.NewTicker(replicationHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case rec := <-s.outbox:
+			if err := w.write(rec); err != nil {
+				replicationSendsTotal.WithLabelValues("error").Inc()
+				return err
+			}
+			if err := w.flush(); err != nil {
+				replicationSendsTotal.WithLabelValues("error").Inc()
+				return err
+			}
+			replicationSendsTotal.WithLabelValues("success").Inc()
+
+		case <-heartbeat.C:
+			if err := w.write(&discosrv.ReplicationRecord{}); err != nil {
+				return err
+			}
+			if err := w.flush(); err != nil {
+				return err
+			}
+
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// sendAll sends all current records in the database.
+func (s *replicationSender) sendAll(ctx context.Context, w *replicationWriter) (int, error) {
+	now := time.Now()
+	cutoff1w := now.Add(-7 * 24 * time.Hour).UnixNano()
+	n := 0
+	var err error
+	s.db.iterate(func(key protocol.DeviceID, rec *discosrv.DatabaseRecord) bool {
+		if rec.Seen < cutoff1w {
+			return true
+		}
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+		err = w.write(&discosrv.ReplicationRecord{
+			Key:       key[:],
+			Addresses: expire(slices.Clone(rec.Addresses), now),
+			Seen:      rec.Seen,
+		})
+		n++
+		return err == nil
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, w.flush()
+}
+
+func (s *replicationSender) String() string {
+	return fmt.Sprintf("replicationSender(%s@%s)", s.peer.id.Short(), s.peer.addr)
+}
+
+func (s *replicationSender) send(key *protocol.DeviceID, ps []*discosrv.DatabaseAddress, seen int64) {
+	item := &discosrv.ReplicationRecord{
+		Key:       key[:],
+		Addresses: ps,
+		Seen:      seen,
+	}
+
+	// The send should never block. The outbox is suitably buffered for at
+	// least a few seconds of stalls or a reconnect.
+	select {
+	case s.outbox <- item:
+	default:
+		replicationSendsTotal.WithLabelValues("drop").Inc()
+	}
+}
+
+// replicationListener accepts connections from peers and merges the
+// records they send into the database.
+type replicationListener struct {
+	addr    string
+	cert    tls.Certificate
+	allowed []protocol.DeviceID
+	db      database
+}
+
+func newReplicationListener(addr string, cert tls.Certificate, allowed []protocol.DeviceID, db database) *replicationListener {
+	return &replicationListener{
+		addr:    addr,
+		cert:    cert,
+		allowed: allowed,
+		db:      db,
+	}
+}
+
+func (l *replicationListener) Serve(ctx context.Context) error {
+	listener, err := tls.Listen("tcp", l.addr, l.tlsConfig())
+	if err != nil {
+		return fmt.Errorf("replication listen: %w", err)
+	}
+	return l.serve(ctx, listener)
+}
+
+func (l *replicationListener) tlsConfig() *tls.Config {
+	tlsCfg := tlsutil.SecureDefaultTLS13()
+	tlsCfg.Certificates = []tls.Certificate{l.cert}
+	tlsCfg.ClientAuth = tls.RequireAnyClientCert
+	return tlsCfg
+}
+
+func (l *replicationListener) serve(ctx context.Context, listener net.Listener) error {
+	defer listener.Close()
+	stop := context.AfterFunc(ctx, func() { listener.Close() })
+	defer stop()
+
+	for {
+		conn, err := listener.Accept()
+		if ctx.Err() != nil {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("replication accept: %w", err)
+		}
+		go l.handle(ctx, conn.(*tls.Conn))
+	}
+}
+
+func (l *replicationListener) handle(ctx context.Context, conn *tls.Conn) {
+	defer conn.Close()
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	_ = conn.SetDeadline(time.Now().Add(replicationDialTimeout))
+	if err := conn.HandshakeContext(ctx); err != nil {
+		log.Println("Replication handshake:", err)
+		return
+	}
+	id, err := replicationPeerID(conn)
+	if err != nil {
+		log.Println("Replication:", err)
+		return
+	}
+	if !slices.Contains(l.allowed, id) {
+		log.Printf("Replication: rejecting connection from unknown device %s at %s; all servers must have each other as peers", id, conn.RemoteAddr())
+		return
+	}
+	_ = conn.SetWriteDeadline(time.Time{})
+	if debug {
+		log.Println("Replication: connection from", id)
+	}
+
+	br := bufio.NewReader(conn)
+	var buf []byte
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(replicationReadTimeout))
+		var rec discosrv.ReplicationRecord
+		buf, err = readReplicationRecord(br, buf, &rec)
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, io.EOF) {
+				log.Printf("Replication from %s: %v", id.Short(), err)
+			}
+			return
+		}
+		if len(rec.Key) == 0 {
+			// Heartbeat
+			continue
+		}
+
+		key, err := protocol.DeviceIDFromBytes(rec.Key)
+		if err != nil {
+			log.Println("Replication device ID:", err)
+			replicationRecvsTotal.WithLabelValues("error").Inc()
+			continue
+		}
+
+		// Merging requires the addresses to be sorted.
+		slices.SortFunc(rec.Addresses, Cmp)
+		rec.Addresses = slices.CompactFunc(rec.Addresses, Equal)
+		if err := l.db.merge(&key, rec.Addresses, rec.Seen); err != nil {
+			log.Println("Replication database merge:", err)
+			replicationRecvsTotal.WithLabelValues("error").Inc()
+			return
+		}
+		replicationRecvsTotal.WithLabelValues("success").Inc()
+	}
+}
+
+func (l *replicationListener) String() string {
+	return fmt.Sprintf("replicationListener(%q)", l.addr)
+}
+
+func replicationPeerID(conn *tls.Conn) (protocol.DeviceID, error) {
+	certs := conn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return protocol.EmptyDeviceID, errors.New("peer presented no certificate")
+	}
+	return protocol.NewDeviceID(certs[0].Raw), nil
+}
+
+// replicationWriter writes length prefixed records to a connection.
+type replicationWriter struct {
+	conn net.Conn
+	bw   *bufio.Writer
+	buf  []byte
+}
+
+func (w *replicationWriter) write(rec *discosrv.ReplicationRecord) error {
+	size := proto.Size(rec)
+	if size+4 > len(w.buf) {
+		w.buf = make([]byte, size+4)
+	}
+	n, err := protoutil.MarshalTo(w.buf[4:], rec)
+	if err != nil {
+		return fmt.Errorf("replication marshal: %w", err)
+	}
+	binary.BigEndian.PutUint32(w.buf, uint32(n))
+	_ = w.conn.SetWriteDeadline(time.Now().Add(replicationWriteTimeout))
+	if _, err := w.bw.Write(w.buf[:n+4]); err != nil {
+		return fmt.Errorf("replication write: %w", err)
+	}
+	return nil
+}
+
+func (w *replicationWriter) flush() error {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(replicationWriteTimeout))
+	if err := w.bw.Flush(); err != nil {
+		return fmt.Errorf("replication write: %w", err)
+	}
+	return nil
+}
+
+func readReplicationRecord(r io.Reader, buf []byte, rec *discosrv.ReplicationRecord) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return buf, err
+	}
+	if n > replicationMaxRecordSize {
+		return buf, fmt.Errorf("record size %d too large", n)
+	}
+	if int(n) > len(buf) {
+		buf = make([]byte, n)
+	}
+	if _, err := io.ReadFull(r, buf[:n]); err != nil {
+		return buf, err
+	}
+	return buf, proto.Unmarshal(buf[:n], rec)
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/internal/gen/discosrv"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/tlsutil"
+)
+
+func TestReplication(t *testing.T) {
+	certA, idA := replicationTestCert(t)
+	certB, idB := replicationTestCert(t)
+	dbA := newInMemoryStore(t.TempDir(), 0, nil)
+	dbB := newInMemoryStore(t.TempDir(), 0, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	listener := newReplicationListener("", certB, []protocol.DeviceID{idA}, dbB)
+	tl, err := tls.Listen("tcp", "127.0.0.1:0", listener.tlsConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go listener.serve(ctx, tl)
+
+	// A record that exists before connecting is sent in the initial full
+	// copy.
+	dev1 := protocol.DeviceID{1}
+	expires := time.Now().Add(time.Hour).UnixNano()
+	if err := dbA.merge(&dev1, []*discosrv.DatabaseAddress{{Address: "tcp://1.2.3.4:5", Expires: expires}}, time.Now().UnixNano()); err != nil {
+		t.Fatal(err)
+	}
+
+	sender := newReplicationSender(replicationPeer{id: idB, addr: tl.Addr().String()}, certA, dbA)
+	go sender.Serve(ctx)
+	waitForAddress(t, dbB, dev1, "tcp://1.2.3.4:5")
+
+	// Announcements are sent as they happen.
+	dev2 := protocol.DeviceID{2}
+	sender.send(&dev2, []*discosrv.DatabaseAddress{{Address: "tcp://6.7.8.9:10", Expires: expires}}, time.Now().UnixNano())
+	waitForAddress(t, dbB, dev2, "tcp://6.7.8.9:10")
+
+	// A sender expecting another device at the address refuses to
+	// replicate to it.
+	wrong := newReplicationSender(replicationPeer{id: idA, addr: tl.Addr().String()}, certA, dbA)
+	if err := wrong.Serve(ctx); err == nil {
+		t.Error("Expected error connecting to unexpected device")
+	}
+}
+
+func TestParseReplicationPeers(t *testing.T) {
+	id := protocol.DeviceID{1, 2, 3}
+	peers, err := parseReplicationPeers([]string{id.String() + "@192.0.2.42:19200"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(peers) != 1 || peers[0].id != id || peers[0].addr != "192.0.2.42:19200" {
+		t.Errorf("Unexpected peers %v", peers)
+	}
+
+	for _, bad := range []string{"192.0.2.42:19200", id.String() + "@", "foo@192.0.2.42:19200"} {
+		if _, err := parseReplicationPeers([]string{bad}); err == nil {
+			t.Errorf("Expected error for %q", bad)
+		}
+	}
+}
+
+func TestValidateReplicationMesh(t *testing.T) {
+	own := protocol.DeviceID{1}
+	a := replicationPeer{id: protocol.DeviceID{2}, addr: "192.0.2.42:19200"}
+	b := replicationPeer{id: protocol.DeviceID{3}, addr: "192.0.2.43:19200"}
+
+	if err := validateReplicationMesh(own, ":19200", []replicationPeer{a, b}); err != nil {
+		t.Error("Unexpected error:", err)
+	}
+
+	cases := []struct {
+		listen string
+		peers  []replicationPeer
+	}{
+		{"", []replicationPeer{a}},
+		{":19200", nil},
+		{":19200", []replicationPeer{a, {id: own, addr: "192.0.2.44:19200"}}},
+		{":19200", []replicationPeer{a, b, a}},
+	}
+	for _, tc := range cases {
+		if err := validateReplicationMesh(own, tc.listen, tc.peers); err == nil {
+			t.Errorf("Expected error for listen %q and peers %v", tc.listen, tc.peers)
+		}
+	}
+}
+
+func replicationTestCert(t *testing.T) (tls.Certificate, protocol.DeviceID) {
+	t.Helper()
+	cert, err := tlsutil.NewCertificateInMemory("stdiscosrv", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, protocol.NewDeviceID(cert.Certificate[0])
+}
+
+func waitForAddress(t *testing.T, db database, dev protocol.DeviceID, addr string) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		rec, err := db.get(&dev)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(rec.Addresses) == 1 && rec.Addresses[0].Address == addr {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Address %s for %s was not replicated", addr, dev.Short())
+}
@@ -11,10 +11,11 @@ import (
 )
 
 type indexCommand struct {
-	Dump     struct{} `cmd:"" help:"Print the entire db"`
-	DumpSize struct{} `cmd:"" help:"Print the db size of different categories of information"`
-	Check    struct{} `cmd:"" help:"Check the database for inconsistencies"`
-	Account  struct{} `cmd:"" help:"Print key and value size statistics per key type"`
+	Dump      struct{} `cmd:"" help:"Print the entire db"`
+	DumpSize  struct{} `cmd:"" help:"Print the db size of different categories of information"`
+	Check     struct{} `cmd:"" help:"Check the database for inconsistencies"`
+	Account   struct{} `cmd:"" help:"Print key and value size statistics per key type"`
+	Integrity struct{} `cmd:"" help:"Check the integrity of the database storage (SQLite only)"`
 }
 
 func (*indexCommand) Run(kongCtx *kong.Context) error {
@@ -27,6 +28,8 @@ func (*indexCommand) Run(kongCtx *kong.Context) error {
 		return indexCheck()
 	case "account":
 		return indexAccount()
+	case "integrity":
+		return indexIntegrity()
 	}
 	return nil
 }
@@ -53,9 +53,10 @@ func indexDump() error {
 
 		case db.KeyTypeBlock:
 			folder := binary.BigEndian.Uint32(key[1:])
-			hash := key[1+4 : 1+4+32]
-			name := nulString(key[1+4+32:])
-			fmt.Printf("[block] F:%d H:%x N:%q I:%d\n", folder, hash, name, binary.BigEndian.Uint32(it.Value()))
+			algo := protocol.HashAlgorithm(key[1+4])
+			hash := key[1+4+1 : 1+4+1+32]
+			name := nulString(key[1+4+1+32:])
+			fmt.Printf("[block] F:%d A:%v H:%x N:%q I:%d\n", folder, algo, hash, name, binary.BigEndian.Uint32(it.Value()))
 
 		case db.KeyTypeDeviceStatistic:
 			fmt.Printf("[dstat] K:%x V:%x\n", key, it.Value())
@@ -154,6 +155,9 @@ func indexDump() error {
 			proto.Unmarshal(it.Value(), &od)
 			fmt.Printf("[pendingDevice] D:%v V:%v\n", device, &od)
 
+		case db.KeyTypeFolderPins:
+			fmt.Printf("[pins] K:%q V:%q\n", key[1:], it.Value())
+
 		default:
 			fmt.Printf("[??? %d]\n  %x\n  %x\n", key[0], key, it.Value())
 		}
@@ -12,6 +12,7 @@ import (
 	"sort"
 
 	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 func indexDumpSize() error {
@@ -49,9 +50,10 @@ func indexDumpSize() error {
 
 		case db.KeyTypeBlock:
 			folder := binary.BigEndian.Uint32(key[1:])
-			hash := key[1+4 : 1+4+32]
-			name := nulString(key[1+4+32:])
-			ele.key = fmt.Sprintf("BLOCK:%d:%x:%s", folder, hash, name)
+			algo := protocol.HashAlgorithm(key[1+4])
+			hash := key[1+4+1 : 1+4+1+32]
+			name := nulString(key[1+4+1+32:])
+			ele.key = fmt.Sprintf("BLOCK:%d:%v:%x:%s", folder, algo, hash, name)
 
 		case db.KeyTypeDeviceStatistic:
 			ele.key = fmt.Sprintf("DEVICESTATS:%s", key[1:])
@@ -62,6 +64,9 @@ func indexDumpSize() error {
 		case db.KeyTypeVirtualMtime:
 			ele.key = fmt.Sprintf("MTIME:%s", key[1:])
 
+		case db.KeyTypeFolderPins:
+			ele.key = fmt.Sprintf("FOLDERPINS:%s", key[1:])
+
 		case db.KeyTypeFolderIdx:
 			id := binary.BigEndian.Uint32(key[1:])
 			ele.key = fmt.Sprintf("FOLDERIDX:%d", id)
@@ -0,0 +1,32 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/syncthing/syncthing/lib/db/backend"
+)
+
+func indexIntegrity() error {
+	ldb, err := getDB()
+	if err != nil {
+		return err
+	}
+	defer ldb.Close()
+
+	checker, ok := ldb.(backend.IntegrityChecker)
+	if !ok {
+		return errors.New("the integrity check is only supported by the SQLite database backend")
+	}
+	if err := checker.CheckIntegrity(); err != nil {
+		return err
+	}
+	fmt.Println("Database integrity check passed.")
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"net/url"
 	"path/filepath"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/syncthing/syncthing/lib/config"
@@ -21,16 +23,40 @@ type folderOverrideCommand struct {
 	FolderID string `arg:""`
 }
 
+type folderHydrateCommand struct {
+	FolderID string   `arg:""`
+	Paths    []string `arg:"" optional:"" help:"Paths to pull, relative to the folder root (default everything)"`
+}
+
+type folderConfirmChangesCommand struct {
+	FolderID string `arg:""`
+}
+
+type folderRollbackCommand struct {
+	FolderID string `arg:""`
+	Time     string `arg:"" help:"Time to roll back to, as RFC 3339 or local \"YYYY-MM-DD HH:MM\""`
+	Sub      string `help:"Only roll back this path, relative to the folder root"`
+	DryRun   bool   `help:"Only show what would be done"`
+}
+
+type folderVerifyCommand struct {
+	FolderID string `arg:""`
+}
+
 type defaultIgnoresCommand struct {
 	Path string `arg:""`
 }
 
 type operationCommand struct {
-	Restart        struct{}              `cmd:"" help:"Restart syncthing"`
-	Shutdown       struct{}              `cmd:"" help:"Shutdown syncthing"`
-	Upgrade        struct{}              `cmd:"" help:"Upgrade syncthing (if a newer version is available)"`
-	FolderOverride folderOverrideCommand `cmd:"" help:"Override changes on folder (remote for sendonly, local for receiveonly). WARNING: Destructive - deletes/changes your data"`
-	DefaultIgnores defaultIgnoresCommand `cmd:"" help:"Set the default ignores (config) from a file"`
+	Restart              struct{}                    `cmd:"" help:"Restart syncthing"`
+	Shutdown             struct{}                    `cmd:"" help:"Shutdown syncthing"`
+	Upgrade              struct{}                    `cmd:"" help:"Upgrade syncthing (if a newer version is available)"`
+	FolderOverride       folderOverrideCommand       `cmd:"" help:"Override changes on folder (remote for sendonly, local for receiveonly). WARNING: Destructive - deletes/changes your data"`
+	FolderHydrate        folderHydrateCommand        `cmd:"" help:"Pull files of an on-demand folder and keep them in sync"`
+	FolderRollback       folderRollbackCommand       `cmd:"" help:"Restore files of a folder from their versions to their state at a given time"`
+	FolderConfirmChanges folderConfirmChangesCommand `cmd:"" help:"Let a folder pull changes held off as a mass change"`
+	FolderVerify         folderVerifyCommand         `cmd:"" help:"Check the database of a folder for inconsistencies and repair them"`
+	DefaultIgnores       defaultIgnoresCommand       `cmd:"" help:"Set the default ignores (config) from a file"`
 }
 
 func (*operationCommand) Run(ctx Context, kongCtx *kong.Context) error {
@@ -81,6 +107,71 @@ func (f *folderOverrideCommand) Run(ctx Context) error {
 	return fmt.Errorf("Folder %q not found", rid)
 }
 
+func (f *folderHydrateCommand) Run(ctx Context) error {
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	qs := url.Values{"folder": []string{f.FolderID}}
+	for _, path := range f.Paths {
+		qs.Add("sub", path)
+	}
+	_, err = client.Post("db/hydrate?"+qs.Encode(), "")
+	return err
+}
+
+func (f *folderConfirmChangesCommand) Run(ctx Context) error {
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	qs := url.Values{"folder": []string{f.FolderID}}
+	_, err = client.Post("db/masschange/confirm?"+qs.Encode(), "")
+	return err
+}
+
+func (f *folderRollbackCommand) Run(ctx Context) error {
+	at, err := time.Parse(time.RFC3339, f.Time)
+	if err != nil {
+		at, err = time.ParseInLocation("2006-01-02 15:04", f.Time, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid time %q", f.Time)
+		}
+	}
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	qs := url.Values{
+		"folder": []string{f.FolderID},
+		"time":   []string{at.Format(time.RFC3339)},
+	}
+	if f.Sub != "" {
+		qs.Set("sub", f.Sub)
+	}
+	if f.DryRun {
+		qs.Set("dryrun", "true")
+	}
+	response, err := client.Post("folder/rollback?"+qs.Encode(), "")
+	if err != nil {
+		return err
+	}
+	return prettyPrintResponse(response)
+}
+
+func (f *folderVerifyCommand) Run(ctx Context) error {
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	qs := url.Values{"folder": []string{f.FolderID}}
+	response, err := client.Post("db/verify?"+qs.Encode(), "")
+	if err != nil {
+		return err
+	}
+	return prettyPrintResponse(response)
+}
+
 func (d *defaultIgnoresCommand) Run(ctx Context) error {
 	client, err := ctx.clientFactory.getClient()
 	if err != nil {
@@ -0,0 +1,76 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package cli
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/alecthomas/kong"
+)
+
+type tokensCommand struct {
+	List   struct{}           `cmd:"" help:"List API tokens, with when and from where they were last used"`
+	Create tokenCreateCommand `cmd:"" help:"Create an API token, showing it once"`
+	Revoke tokenRevokeCommand `cmd:"" help:"Revoke an API token"`
+}
+
+type tokenCreateCommand struct {
+	Name     string        `arg:""`
+	Role     string        `help:"Role of the token: admin, operator or monitor" default:"admin"`
+	Folder   []string      `help:"Folders an operator token may operate on (default all)"`
+	Endpoint []string      `help:"REST endpoints, or path prefixes of them, the token may be used with (default all)"`
+	Method   []string      `help:"HTTP methods the token may be used with (default all)"`
+	Network  []string      `help:"Networks in CIDR notation the token may be used from (default all)"`
+	Expires  time.Duration `help:"Time after which the token expires (default never)"`
+}
+
+type tokenRevokeCommand struct {
+	ID string `arg:""`
+}
+
+func (*tokensCommand) Run(ctx Context, kongCtx *kong.Context) error {
+	switch kongCtx.Selected().Name {
+	case "list":
+		return indexDumpOutput("system/apitokens", ctx.clientFactory)
+	}
+
+	return nil
+}
+
+func (c *tokenCreateCommand) Run(ctx Context) error {
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	req := map[string]any{
+		"name":      c.Name,
+		"role":      c.Role,
+		"folders":   c.Folder,
+		"endpoints": c.Endpoint,
+		"methods":   c.Method,
+		"networks":  c.Network,
+	}
+	if c.Expires > 0 {
+		req["expires"] = time.Now().Add(c.Expires)
+	}
+	response, err := client.PostJSON("system/apitokens", req)
+	if err != nil {
+		return err
+	}
+	return prettyPrintResponse(response)
+}
+
+func (c *tokenRevokeCommand) Run(ctx Context) error {
+	client, err := ctx.clientFactory.getClient()
+	if err != nil {
+		return err
+	}
+	qs := url.Values{"id": []string{c.ID}}
+	_, err = client.Delete("system/apitokens?" + qs.Encode())
+	return err
+}
@@ -134,6 +134,12 @@ func prettyPrintResponse(response *http.Response) error {
 }
 
 func getDB() (backend.Backend, error) {
+	// After a migration only the database of the selected backend is
+	// left.
+	sqlitePath := locations.Get(locations.SQLiteDB)
+	if _, err := os.Stat(sqlitePath); err == nil {
+		return backend.OpenSQLiteRO(sqlitePath)
+	}
 	return backend.OpenLevelDBRO(locations.Get(locations.Database))
 }
 
@@ -0,0 +1,151 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package index implements the `syncthing index` subcommand.
+package index
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/gofrs/flock"
+
+	"github.com/syncthing/syncthing/cmd/syncthing/cmdutil"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/indexexport"
+	"github.com/syncthing/syncthing/lib/locations"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/stats"
+	"github.com/syncthing/syncthing/lib/syncthing"
+)
+
+type CLI struct {
+	cmdutil.CommonOptions
+	DataDir string `name:"data" placeholder:"PATH" env:"STDATADIR" help:"Set data directory (database and logs)"`
+
+	Export exportCommand `cmd:"" help:"Export the index of a folder to a file"`
+	Import importCommand `cmd:"" help:"Import an exported folder index, trusting files that are unchanged on disk"`
+}
+
+func (c CLI) AfterApply() error {
+	if err := cmdutil.SetConfigDataLocationsFromFlags(c.HomeDir, c.ConfDir, c.DataDir); err != nil {
+		return fmt.Errorf("command line options: %w", err)
+	}
+	return nil
+}
+
+type exportCommand struct {
+	Folder string `arg:"" required:"1" help:"ID of the folder to export"`
+	File   string `arg:"" required:"1" type:"path" help:"File to write the index to"`
+}
+
+func (c *exportCommand) Run() error {
+	cfg, ldb, closeDB, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	if _, ok := cfg.Folder(c.Folder); !ok {
+		return fmt.Errorf("folder %q is not configured", c.Folder)
+	}
+	fset, err := db.NewFileSet(c.Folder, ldb)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.Create(c.File)
+	if err != nil {
+		return err
+	}
+	if err := indexexport.Export(fd, c.Folder, fset, stats.NewFolderStatisticsReference(ldb, c.Folder)); err != nil {
+		fd.Close()
+		os.Remove(c.File)
+		return fmt.Errorf("exporting index: %w", err)
+	}
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported index of folder %q to %s\n", c.Folder, c.File)
+	return nil
+}
+
+type importCommand struct {
+	File string `arg:"" required:"1" type:"existingfile" help:"File to read the index from"`
+}
+
+func (c *importCommand) Run() error {
+	fd, err := os.Open(c.File)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	r, err := indexexport.NewReader(fd)
+	if err != nil {
+		return err
+	}
+
+	cfg, ldb, closeDB, err := openDatabase()
+	if err != nil {
+		return err
+	}
+	defer closeDB()
+
+	folder := r.Folder()
+	fcfg, ok := cfg.Folder(folder)
+	if !ok {
+		return fmt.Errorf("folder %q is not configured", folder)
+	}
+	res, err := r.Import(ldb, fcfg)
+	if errors.Is(err, indexexport.ErrNotEmpty) {
+		return fmt.Errorf("%w; import only into a newly added folder", err)
+	} else if err != nil {
+		return fmt.Errorf("importing index: %w", err)
+	}
+	fmt.Printf("Imported index of folder %q exported at %v\n", folder, r.Created().Format("2006-01-02 15:04:05"))
+	fmt.Printf("%d local files unchanged, %d left to be scanned\n", res.LocalFiles, res.SkippedFiles)
+	fmt.Printf("%d files of %d remote devices\n", res.RemoteFiles, res.Devices)
+	return nil
+}
+
+// openDatabase loads the configuration and opens the database, holding the
+// same lock as a running Syncthing so that the two never use the database
+// at the same time. The returned function closes the database and releases
+// the lock.
+func openDatabase() (config.Wrapper, *db.Lowlevel, func(), error) {
+	lf := flock.New(locations.Get(locations.LockFile))
+	locked, err := lf.TryLock()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("acquiring lock: %w", err)
+	} else if !locked {
+		return nil, nil, nil, errors.New("failed to acquire lock: is Syncthing running?")
+	}
+
+	cfg, _, err := config.Load(locations.Get(locations.ConfigFile), protocol.EmptyDeviceID, events.NoopLogger)
+	if err != nil {
+		lf.Unlock()
+		return nil, nil, nil, fmt.Errorf("loading config: %w", err)
+	}
+	backend, err := syncthing.OpenDBBackend(cfg.Options())
+	if err != nil {
+		lf.Unlock()
+		return nil, nil, nil, fmt.Errorf("opening database: %w", err)
+	}
+	ldb, err := db.NewLowlevel(backend, events.NoopLogger)
+	if err != nil {
+		backend.Close()
+		lf.Unlock()
+		return nil, nil, nil, err
+	}
+	closeDB := func() {
+		ldb.Close()
+		lf.Unlock()
+	}
+	return cfg, ldb, closeDB, nil
+}
@@ -38,10 +38,13 @@ import (
 	"github.com/syncthing/syncthing/cmd/syncthing/cmdutil"
 	"github.com/syncthing/syncthing/cmd/syncthing/decrypt"
 	"github.com/syncthing/syncthing/cmd/syncthing/generate"
+	"github.com/syncthing/syncthing/cmd/syncthing/index"
+	"github.com/syncthing/syncthing/lib/audit"
 	_ "github.com/syncthing/syncthing/lib/automaxprocs"
 	"github.com/syncthing/syncthing/lib/build"
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
 	"github.com/syncthing/syncthing/lib/dialer"
 	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/fs"
@@ -132,6 +135,7 @@ var entrypoint struct {
 	Serve              serveOptions                 `cmd:"" help:"Run Syncthing"`
 	Generate           generate.CLI                 `cmd:"" help:"Generate key and config, then exit"`
 	Decrypt            decrypt.CLI                  `cmd:"" help:"Decrypt or verify an encrypted folder"`
+	Index              index.CLI                    `cmd:"" help:"Export or import the index of a folder, then exit"`
 	Cli                cli.CLI                      `cmd:"" help:"Command line interface for Syncthing"`
 	InstallCompletions kongplete.InstallCompletions `cmd:"" help:"Print commands to install shell completions"`
 }
@@ -594,8 +598,7 @@ func syncthingMain(options serveOptions) {
 		})
 	}
 
-	dbFile := locations.Get(locations.Database)
-	ldb, err := syncthing.OpenDBBackend(dbFile, cfgWrapper.Options().DatabaseTuning)
+	ldb, err := syncthing.OpenDBBackend(cfgWrapper.Options())
 	if err != nil {
 		l.Warnln("Error opening database:", err)
 		os.Exit(1)
@@ -650,7 +653,7 @@ func syncthingMain(options serveOptions) {
 			auditFile = options.AuditFile
 		}
 
-		appOpts.AuditWriter = auditWriter(auditFile)
+		appOpts.AuditWriter = auditWriter(auditFile, cfgWrapper.Options())
 	}
 
 	if dur, err := time.ParseDuration(os.Getenv("STRECHECKDBEVERY")); err == nil {
@@ -747,11 +750,10 @@ func loadOrDefaultConfig() (config.Wrapper, error) {
 	return cfg, err
 }
 
-func auditWriter(auditFile string) io.Writer {
+func auditWriter(auditFile string, opts config.OptionsConfiguration) io.Writer {
 	var fd io.Writer
 	var err error
 	var auditDest string
-	var auditFlags int
 
 	if auditFile == "-" {
 		fd = os.Stdout
@@ -762,11 +764,10 @@ func auditWriter(auditFile string) io.Writer {
 	} else {
 		if auditFile == "" {
 			auditFile = locations.GetTimestamped(locations.AuditLog)
-			auditFlags = os.O_WRONLY | os.O_CREATE | os.O_EXCL
-		} else {
-			auditFlags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
 		}
-		fd, err = os.OpenFile(auditFile, auditFlags, 0o600)
+		maxSize := int64(opts.AuditMaxSizeMiB) << 20
+		maxAge := time.Duration(opts.AuditMaxAgeH) * time.Hour
+		fd, err = audit.OpenRotatingFile(auditFile, maxSize, maxAge, opts.AuditMaxFiles)
 		if err != nil {
 			l.Warnln("Audit:", err)
 			os.Exit(svcutil.ExitError.AsInt())
@@ -774,13 +775,30 @@ func auditWriter(auditFile string) io.Writer {
 		auditDest = auditFile
 	}
 
-	l.Infoln("Audit log in", auditDest)
+	l.Infof("Audit log in %s, as %s", auditDest, opts.AuditFormat)
+
+	if opts.AuditSyslogAddress != "" {
+		syslog, err := audit.NewSyslogWriter(opts.AuditSyslogAddress)
+		if err != nil {
+			l.Warnln("Audit:", err)
+			os.Exit(svcutil.ExitError.AsInt())
+		}
+		l.Infoln("Audit log also sent to", opts.AuditSyslogAddress)
+		// The syslog writer sends in the background and never fails,
+		// so it doesn't keep the file from being written.
+		fd = io.MultiWriter(fd, syslog)
+	}
 
 	return fd
 }
 
 func resetDB() error {
-	return os.RemoveAll(locations.Get(locations.Database))
+	for _, loc := range []locations.LocationEnum{locations.Database, locations.SQLiteDB} {
+		if err := backend.RemoveAll(locations.Get(loc)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func autoUpgradePossible(options serveOptions) bool {
@@ -25,6 +25,10 @@ angular.module('syncthing.core')
                 // Get index.html again (likely cached) to retrieve the version header
                 $http.get('').success(setVersionFromHeader).error(setVersionFromHeader);
 
+                $http.get(authUrlbase + '/methods').success(function (data) {
+                    $scope.login.methods = data;
+                });
+
                 // Can't proceed yet - wait for the page reload after successful login.
                 return;
             }
@@ -42,6 +46,7 @@ angular.module('syncthing.core')
             username: '',
             password: '',
             errors: {},
+            methods: { password: true },
         };
         $scope.completion = {};
         $scope.config = {};
@@ -97,6 +102,7 @@ angular.module('syncthing.core')
             cleanupIntervalS: 3600,
             simpleKeep: 5,
             staggeredMaxAge: 365,
+            retentionPolicy: "",
             externalCommand: "",
         };
 
@@ -127,6 +133,14 @@ angular.module('syncthing.core')
             });
         };
 
+        $scope.authenticateOIDC = function () {
+            var url = authUrlbase + '/oidc/login';
+            if ($scope.login.stayLoggedIn) {
+                url += '?stayLoggedIn=true';
+            }
+            location.href = url;
+        };
+
         $scope.logout = function() {
             $http.post(authUrlbase + '/logout', {})
             .then(function () {
@@ -579,7 +593,7 @@ angular.module('syncthing.core')
             // This function should match IsAuthEnabled() in guiconfiguration.go
             var guiCfg = $scope.config && $scope.config.gui;
             if (guiCfg) {
-                return guiCfg.authMode === 'ldap' || (guiCfg.user && guiCfg.password);
+                return guiCfg.authMode === 'ldap' || guiCfg.authMode === 'oidc' || (guiCfg.user && guiCfg.password);
             }
             return false;
         };
@@ -601,7 +615,7 @@ angular.module('syncthing.core')
                 && !$scope.isAuthEnabled()
                 && !guiCfg.insecureAdminAccess;
 
-            if ((guiCfg.user && guiCfg.password) || guiCfg.authMode === 'ldap') {
+            if ((guiCfg.user && guiCfg.password) || guiCfg.authMode === 'ldap' || guiCfg.authMode === 'oidc') {
                 $scope.dismissNotification('authenticationUserAndPassword');
             }
         }
@@ -2367,12 +2381,16 @@ angular.module('syncthing.core')
                 $scope.currentFolder._guiVersioning.trashcanClean = +currentVersioning.params.cleanoutDays;
                 break;
             case "simple":
+            case "dedup":
                 $scope.currentFolder._guiVersioning.simpleKeep = +currentVersioning.params.keep;
                 $scope.currentFolder._guiVersioning.trashcanClean = +currentVersioning.params.cleanoutDays;
                 break;
             case "staggered":
                 $scope.currentFolder._guiVersioning.staggeredMaxAge = Math.floor(+currentVersioning.params.maxAge / 86400);
                 break;
+            case "retention":
+                $scope.currentFolder._guiVersioning.retentionPolicy = currentVersioning.params.policy || "";
+                break;
             case "external":
                 $scope.currentFolder._guiVersioning.externalCommand = currentVersioning.params.command;
                 break;
@@ -2552,12 +2570,16 @@ angular.module('syncthing.core')
                 folderCfg.versioning.params.cleanoutDays = '' + folderCfg._guiVersioning.trashcanClean;
                 break;
             case "simple":
+            case "dedup":
                 folderCfg.versioning.params.keep = '' + folderCfg._guiVersioning.simpleKeep,
                 folderCfg.versioning.params.cleanoutDays = '' + folderCfg._guiVersioning.trashcanClean;
                 break;
             case "staggered":
                 folderCfg.versioning.params.maxAge = '' + (folderCfg._guiVersioning.staggeredMaxAge * 86400);
                 break;
+            case "retention":
+                folderCfg.versioning.params.policy = '' + folderCfg._guiVersioning.retentionPolicy;
+                break;
             case "external":
                 folderCfg.versioning.params.command = '' + folderCfg._guiVersioning.externalCommand;
                 break;
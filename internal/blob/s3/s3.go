@@ -8,10 +8,16 @@ package s3
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/credentials"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -79,19 +85,127 @@ func (s *Session) LatestKey(_ context.Context) (string, error) {
 }
 
 func (s *Session) list(fn func(*Object) bool) error {
+	return s.ListPrefix(context.Background(), "", "", func(obj *Object, _ string) bool {
+		if obj == nil {
+			return true
+		}
+		return fn(obj)
+	})
+}
+
+// ObjectInfo is the result of a HEAD request on an object.
+type ObjectInfo struct {
+	Size         int64
+	LastModified time.Time
+	Metadata     map[string]string
+}
+
+// ErrNotFound is returned when an object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// Head returns the size, modification time and user metadata of the
+// object, or ErrNotFound.
+func (s *Session) Head(ctx context.Context, key string) (ObjectInfo, error) {
+	resp, err := s3.New(s.s3sess).HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return ObjectInfo{}, translateError(err)
+	}
+	info := ObjectInfo{
+		Size:         aws.Int64Value(resp.ContentLength),
+		LastModified: aws.TimeValue(resp.LastModified),
+		Metadata:     make(map[string]string, len(resp.Metadata)),
+	}
+	for k, v := range resp.Metadata {
+		info.Metadata[strings.ToLower(k)] = aws.StringValue(v)
+	}
+	return info, nil
+}
+
+// GetRange returns a reader for length bytes of the object starting at
+// offset.
+func (s *Session) GetRange(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	resp, err := s3.New(s.s3sess).GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Range:  aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)),
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return resp.Body, nil
+}
+
+// Put uploads the object with the given user metadata.
+func (s *Session) Put(ctx context.Context, key string, r io.Reader, metadata map[string]string) error {
+	uploader := s3manager.NewUploader(s.s3sess)
+	_, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket:   aws.String(s.bucket),
+		Key:      aws.String(key),
+		Body:     r,
+		Metadata: aws.StringMap(metadata),
+	})
+	return err
+}
+
+// Copy copies the object within the bucket. If metadata is nil the
+// metadata of the source is kept, otherwise it is replaced. Copying an
+// object onto itself is how the metadata of an object is changed.
+func (s *Session) Copy(ctx context.Context, src, dst string, metadata map[string]string) error {
+	input := &s3.CopyObjectInput{
+		Bucket:     aws.String(s.bucket),
+		CopySource: aws.String((&url.URL{Path: s.bucket + "/" + src}).EscapedPath()),
+		Key:        aws.String(dst),
+	}
+	if metadata != nil {
+		input.Metadata = aws.StringMap(metadata)
+		input.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+	}
+	_, err := s3.New(s.s3sess).CopyObjectWithContext(ctx, input)
+	return translateError(err)
+}
+
+// Delete removes the object. Deleting an object that doesn't exist is not
+// an error.
+func (s *Session) Delete(ctx context.Context, key string) error {
+	_, err := s3.New(s.s3sess).DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return translateError(err)
+}
+
+// ListPrefix calls fn for each object below the given prefix. If a
+// delimiter is given, keys containing it after the prefix are rolled up
+// into common prefixes, for which fn is called with a nil object. Listing
+// stops when fn returns false.
+func (s *Session) ListPrefix(ctx context.Context, prefix, delimiter string, fn func(obj *Object, commonPrefix string) bool) error {
 	svc := s3.New(s.s3sess)
 
 	opts := &s3.ListObjectsV2Input{
 		Bucket: aws.String(s.bucket),
 	}
+	if prefix != "" {
+		opts.Prefix = aws.String(prefix)
+	}
+	if delimiter != "" {
+		opts.Delimiter = aws.String(delimiter)
+	}
 	for {
-		resp, err := svc.ListObjectsV2(opts)
+		resp, err := svc.ListObjectsV2WithContext(ctx, opts)
 		if err != nil {
-			return err
+			return translateError(err)
 		}
 
 		for _, item := range resp.Contents {
-			if !fn(item) {
+			if !fn(item, "") {
+				return nil
+			}
+		}
+		for _, item := range resp.CommonPrefixes {
+			if !fn(nil, aws.StringValue(item.Prefix)) {
 				return nil
 			}
 		}
@@ -104,3 +218,11 @@ func (s *Session) list(fn func(*Object) bool) error {
 
 	return nil
 }
+
+func translateError(err error) error {
+	var aerr awserr.RequestFailure
+	if errors.As(err, &aerr) && aerr.StatusCode() == http.StatusNotFound {
+		return fmt.Errorf("%w: %w", ErrNotFound, err)
+	}
+	return err
+}
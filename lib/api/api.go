@@ -24,6 +24,7 @@ import (
 	"reflect"
 	"runtime"
 	"runtime/pprof"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -31,7 +32,6 @@ import (
 	"unicode"
 
 	"github.com/calmh/incontainer"
-	"github.com/julienschmidt/httprouter"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rcrowley/go-metrics"
 	"github.com/thejerf/suture/v4"
@@ -92,6 +92,8 @@ type service struct {
 	listenerAddr         net.Addr
 	exitChan             chan *svcutil.FatalErr
 	miscDB               *db.NamespacedKV
+	apiTokens            *apiTokenManager
+	sessions             *tokenManager
 	shutdownTimeout      time.Duration
 
 	guiErrors logger.Recorder
@@ -130,6 +132,8 @@ func New(id protocol.DeviceID, cfg config.Wrapper, assetDir, tlsDefaultCommonNam
 		startedOnce:          make(chan struct{}),
 		exitChan:             make(chan *svcutil.FatalErr, 1),
 		miscDB:               miscDB,
+		apiTokens:            newAPITokenManager(miscDB),
+		sessions:             newSessionManager(miscDB),
 		shutdownTimeout:      100 * time.Millisecond,
 	}
 }
@@ -245,72 +249,89 @@ func (s *service) Serve(ctx context.Context) error {
 	s.cfg.Subscribe(s)
 	defer s.cfg.Unsubscribe(s)
 
-	restMux := httprouter.New()
+	guiCfg := s.cfg.GUI()
+
+	// Routes are for admins only, unless registered on one of the other
+	// muxes.
+	restMux := newRoleMux(guiCfg, s.apiTokens)
+	monitorMux := restMux.withRole(config.GUIRoleMonitor)
+	operatorMux := restMux.withRole(config.GUIRoleOperator)
 
 	// The GET handlers
-	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/devices", s.getPendingDevices) // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/folders", s.getPendingFolders) // [device]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/completion", s.getDBCompletion)             // [device] [folder]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/file", s.getDBFile)                         // folder file
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/ignores", s.getDBIgnores)                   // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/need", s.getDBNeed)                         // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/remoteneed", s.getDBRemoteNeed)             // device folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/localchanged", s.getDBLocalChanged)         // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/status", s.getDBStatus)                     // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/db/browse", s.getDBBrowse)                     // folder [prefix] [dirsonly] [levels]
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/versions", s.getFolderVersions)         // folder
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/errors", s.getFolderErrors)             // folder [perpage] [page]
-	restMux.HandlerFunc(http.MethodGet, "/rest/folder/pullerrors", s.getFolderErrors)         // folder (deprecated)
-	restMux.HandlerFunc(http.MethodGet, "/rest/events", s.getIndexEvents)                     // [since] [limit] [timeout] [events]
-	restMux.HandlerFunc(http.MethodGet, "/rest/events/disk", s.getDiskEvents)                 // [since] [limit] [timeout]
-	restMux.HandlerFunc(http.MethodGet, "/rest/noauth/health", s.getHealth)                   // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/stats/device", s.getDeviceStats)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/stats/folder", s.getFolderStats)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/deviceid", s.getDeviceID)                  // id
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/lang", s.getLang)                          // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/report", s.getReport)                      // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/svc/random/string", s.getRandomString)         // [length]
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/browse", s.getSystemBrowse)             // current
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/connections", s.getSystemConnections)   // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/discovery", s.getSystemDiscovery)       // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/error", s.getSystemError)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/paths", s.getSystemPaths)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/ping", s.restPing)                      // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/status", s.getSystemStatus)             // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/upgrade", s.getSystemUpgrade)           // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/version", s.getSystemVersion)           // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/debug", s.getSystemDebug)               // -
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/log", s.getSystemLog)                   // [since]
-	restMux.HandlerFunc(http.MethodGet, "/rest/system/log.txt", s.getSystemLogTxt)            // [since]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/devices", s.getPendingDevices)         // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/cluster/pending/folders", s.getPendingFolders)         // [device]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/completion", s.getDBCompletion)                     // [device] [folder]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/file", s.getDBFile)                                 // folder file
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/ignores", s.getDBIgnores)                           // folder
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/need", s.getDBNeed)                                 // folder [perpage] [page]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/remoteneed", s.getDBRemoteNeed)                     // device folder [perpage] [page]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/localchanged", s.getDBLocalChanged)                 // folder [perpage] [page]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/status", s.getDBStatus)                             // folder
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/browse", s.getDBBrowse)                             // folder [prefix] [dirsonly] [levels]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/db/verify", s.getDBVerify)                             // -
+	operatorMux.HandlerFunc(http.MethodGet, "/rest/folder/versions", s.getFolderVersions)                // folder
+	operatorMux.HandlerFunc(http.MethodGet, "/rest/folder/versions/expired", s.getFolderExpiredVersions) // folder
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/folder/errors", s.getFolderErrors)                     // folder [perpage] [page]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/folder/pullerrors", s.getFolderErrors)                 // folder (deprecated)
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/folder/conflicts", s.getFolderConflicts)               // folder [perpage] [page]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/events", s.getIndexEvents)                             // [since] [limit] [timeout] [events]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/events/disk", s.getDiskEvents)                         // [since] [limit] [timeout]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/noauth/health", s.getHealth)                           // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/stats/device", s.getDeviceStats)                       // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/stats/folder", s.getFolderStats)                       // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/svc/deviceid", s.getDeviceID)                          // id
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/svc/lang", s.getLang)                                  // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/svc/report", s.getReport)                              // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/svc/random/string", s.getRandomString)                 // [length]
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/apitokens", s.getAPITokens)                        // -
+	restMux.HandlerFunc(http.MethodGet, "/rest/system/browse", s.getSystemBrowse)                        // current
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/connections", s.getSystemConnections)           // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/discovery", s.getSystemDiscovery)               // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/error", s.getSystemError)                       // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/paths", s.getSystemPaths)                       // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/ping", s.restPing)                              // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/status", s.getSystemStatus)                     // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/upgrade", s.getSystemUpgrade)                   // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/version", s.getSystemVersion)                   // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/debug", s.getSystemDebug)                       // -
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/log", s.getSystemLog)                           // [since]
+	monitorMux.HandlerFunc(http.MethodGet, "/rest/system/log.txt", s.getSystemLogTxt)                    // [since]
 
 	// The POST handlers
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/prio", s.postDBPrio)                          // folder file
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/ignores", s.postDBIgnores)                    // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/override", s.postDBOverride)                  // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/revert", s.postDBRevert)                      // folder
-	restMux.HandlerFunc(http.MethodPost, "/rest/db/scan", s.postDBScan)                          // folder [sub...] [delay]
-	restMux.HandlerFunc(http.MethodPost, "/rest/folder/versions", s.postFolderVersionsRestore)   // folder <body>
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/error", s.postSystemError)                // <body>
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/error/clear", s.postSystemErrorClear)     // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/ping", s.restPing)                        // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/reset", s.postSystemReset)                // [folder]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/restart", s.postSystemRestart)            // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/shutdown", s.postSystemShutdown)          // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/upgrade", s.postSystemUpgrade)            // -
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/pause", s.makeDevicePauseHandler(true))   // [device]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/resume", s.makeDevicePauseHandler(false)) // [device]
-	restMux.HandlerFunc(http.MethodPost, "/rest/system/debug", s.postSystemDebug)                // [enable] [disable]
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/prio", s.postDBPrio)                            // folder file
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/hydrate", s.postDBHydrate)                      // folder [sub...]
+	restMux.HandlerFunc(http.MethodPost, "/rest/db/ignores", s.postDBIgnores)                          // folder
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/masschange/confirm", s.postDBMassChangeConfirm) // folder
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/override", s.postDBOverride)                    // folder
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/revert", s.postDBRevert)                        // folder
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/scan", s.postDBScan)                            // folder [sub...] [delay]
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/db/verify", s.postDBVerify)                        // folder
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/folder/versions", s.postFolderVersionsRestore)     // folder <body>
+	operatorMux.HandlerFunc(http.MethodPost, "/rest/folder/rollback", s.postFolderRollback)            // folder time [sub] [dryrun]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/apitokens", s.postAPIToken)                     // <body>
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/error", s.postSystemError)                      // <body>
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/error/clear", s.postSystemErrorClear)           // -
+	monitorMux.HandlerFunc(http.MethodPost, "/rest/system/ping", s.restPing)                           // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/reset", s.postSystemReset)                      // [folder]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/restart", s.postSystemRestart)                  // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/shutdown", s.postSystemShutdown)                // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/upgrade", s.postSystemUpgrade)                  // -
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/pause", s.makeDevicePauseHandler(true))         // [device]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/resume", s.makeDevicePauseHandler(false))       // [device]
+	restMux.HandlerFunc(http.MethodPost, "/rest/system/debug", s.postSystemDebug)                      // [enable] [disable]
 
 	// The DELETE handlers
 	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/devices", s.deletePendingDevices) // device
 	restMux.HandlerFunc(http.MethodDelete, "/rest/cluster/pending/folders", s.deletePendingFolders) // folder [device]
+	restMux.HandlerFunc(http.MethodDelete, "/rest/system/apitokens", s.deleteAPIToken)              // id
 
 	// Config endpoints
 
 	configBuilder := &configMuxBuilder{
-		Router: restMux,
-		id:     s.id,
-		cfg:    s.cfg,
+		roleMux:  restMux,
+		id:       s.id,
+		cfg:      s.cfg,
+		evLogger: s.evLogger,
 	}
 
 	configBuilder.registerConfig("/rest/config")
@@ -359,25 +380,33 @@ func (s *service) Serve(ctx context.Context) error {
 	promHttpHandler := promhttp.Handler()
 	mux.Handle("/metrics", promHttpHandler)
 
-	guiCfg := s.cfg.GUI()
-
 	// Wrap everything in CSRF protection. The /rest prefix should be
 	// protected, other requests will grant cookies.
-	var handler http.Handler = newCsrfManager(s.id.Short().String(), "/rest", guiCfg, mux, s.miscDB)
+	var handler http.Handler = newCsrfManager(s.id.Short().String(), "/rest", guiCfg, s.apiTokens, mux, s.miscDB)
 
 	// Add our version and ID as a header to responses
 	handler = withDetailsMiddleware(s.id, handler)
 
 	// Wrap everything in basic auth, if user/password is set.
 	if guiCfg.IsAuthEnabled() {
-		tokenCookieManager := newTokenCookieManager(s.id.Short().String(), guiCfg, s.evLogger, s.miscDB)
-		authMW := newBasicAuthAndSessionMiddleware(tokenCookieManager, guiCfg, s.cfg.LDAP(), handler, s.evLogger)
+		tokenCookieManager := newTokenCookieManager(s.id.Short().String(), guiCfg, s.evLogger, s.sessions)
+		authMW := newBasicAuthAndSessionMiddleware(tokenCookieManager, guiCfg, s.cfg.LDAP(), s.apiTokens, handler, s.evLogger)
 		handler = authMW
 
-		restMux.Handler(http.MethodPost, "/rest/noauth/auth/password", http.HandlerFunc(authMW.passwordAuthHandler))
+		monitorMux.Handler(http.MethodPost, "/rest/noauth/auth/password", http.HandlerFunc(authMW.passwordAuthHandler))
 
 		// Logout is a no-op without a valid session cookie, so /noauth/ is fine here
-		restMux.Handler(http.MethodPost, "/rest/noauth/auth/logout", http.HandlerFunc(authMW.handleLogout))
+		monitorMux.Handler(http.MethodPost, "/rest/noauth/auth/logout", http.HandlerFunc(authMW.handleLogout))
+		monitorMux.Handler(http.MethodGet, "/rest/noauth/auth/methods", http.HandlerFunc(authMW.handleMethods))
+
+		if guiCfg.AuthMode == config.AuthModeOIDC {
+			if len(guiCfg.OIDC.RoleMappings) == 0 {
+				l.Warnln("OIDC login has no role mappings; nobody will be able to log in")
+			}
+			oidcAuth := newOIDCAuthenticator(tokenCookieManager, guiCfg, s.evLogger)
+			monitorMux.Handler(http.MethodGet, "/rest/noauth/auth/oidc/login", http.HandlerFunc(oidcAuth.loginHandler))
+			monitorMux.Handler(http.MethodGet, oidcCallbackPath, http.HandlerFunc(oidcAuth.callbackHandler))
+		}
 	}
 
 	// Redirect to HTTPS if we are supposed to
@@ -489,7 +518,7 @@ func (s *service) CommitConfiguration(from, to config.Configuration) bool {
 	// No action required when this changes, so mask the fact that it changed at all.
 	from.GUI.Debugging = to.GUI.Debugging
 
-	if to.GUI == from.GUI {
+	if to.GUI.Equal(from.GUI) {
 		// No GUI changes, we're done here.
 		return true
 	}
@@ -498,6 +527,21 @@ func (s *service) CommitConfiguration(from, to config.Configuration) bool {
 		s.statics.setTheme(to.GUI.Theme)
 	}
 
+	// Users that were changed or removed must log in again, to get their
+	// new role and folders or to be locked out.
+	for _, user := range from.GUI.Users {
+		if !slices.ContainsFunc(to.GUI.Users, user.Equal) {
+			s.sessions.DeleteOwnedBy(user.Name)
+		}
+	}
+	// Any change to OIDC may change who OIDC users are or what they may
+	// do, so they must all log in again.
+	fromOIDC := config.GUIConfiguration{AuthMode: from.GUI.AuthMode, OIDC: from.GUI.OIDC}
+	toOIDC := config.GUIConfiguration{AuthMode: to.GUI.AuthMode, OIDC: to.GUI.OIDC}
+	if !toOIDC.Equal(fromOIDC) {
+		s.sessions.DeleteOwnedWithPrefix(oidcUsernamePrefix)
+	}
+
 	// Tell the serve loop to restart
 	s.configChanged <- struct{}{}
 
@@ -671,7 +715,9 @@ func (s *service) deletePendingDevices(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.model.DismissPendingDevice(deviceID); err != nil {
+	err = s.model.DismissPendingDevice(deviceID)
+	s.userAction("device.reject", r, map[string]any{"device": deviceID.String()}, err)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -705,7 +751,9 @@ func (s *service) deletePendingFolders(w http.ResponseWriter, r *http.Request) {
 	}
 	folderID := qs.Get("folder")
 
-	if err := s.model.DismissPendingFolder(deviceID, folderID); err != nil {
+	err = s.model.DismissPendingFolder(deviceID, folderID)
+	s.userAction("folder.reject", r, map[string]any{"device": deviceID.String(), "folder": folderID}, err)
+	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
 }
@@ -837,15 +885,68 @@ func (s *service) getDBStatus(w http.ResponseWriter, r *http.Request) {
 func (s *service) postDBOverride(_ http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
+	s.userAction("folder.override", r, map[string]any{"folder": folder}, nil)
 	go s.model.Override(folder)
 }
 
 func (s *service) postDBRevert(_ http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
+	s.userAction("folder.revert", r, map[string]any{"folder": folder}, nil)
 	go s.model.Revert(folder)
 }
 
+func (s *service) postDBMassChangeConfirm(w http.ResponseWriter, r *http.Request) {
+	folder := r.URL.Query().Get("folder")
+	err := s.model.ConfirmMassChange(folder)
+	s.userAction("folder.confirmMassChange", r, map[string]any{"folder": folder}, err)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if isFolderNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+	}
+}
+
+func (s *service) getDBVerify(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, s.model.DatabaseVerifyResults())
+}
+
+func (s *service) postDBVerify(w http.ResponseWriter, r *http.Request) {
+	folder := r.URL.Query().Get("folder")
+	res, err := s.model.VerifyDatabase(r.Context(), folder)
+	s.userAction("folder.verifyDatabase", r, map[string]any{"folder": folder}, err)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if isFolderNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	sendJSON(w, res)
+}
+
+func (s *service) postDBHydrate(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	subs := qs["sub"]
+	if len(subs) == 0 {
+		subs = []string{""}
+	}
+	for _, sub := range subs {
+		if err := s.model.Hydrate(folder, sub); err != nil {
+			status := http.StatusInternalServerError
+			if isFolderNotFound(err) {
+				status = http.StatusNotFound
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+}
+
 func getPagingParams(qs url.Values) (int, int) {
 	page, err := strconv.Atoi(qs.Get("page"))
 	if err != nil || page < 1 {
@@ -928,7 +1029,12 @@ func (s *service) getDBLocalChanged(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *service) getSystemConnections(w http.ResponseWriter, _ *http.Request) {
-	sendJSON(w, s.model.ConnectionStats())
+	res := s.model.ConnectionStats()
+	if res == nil {
+		res = make(map[string]interface{})
+	}
+	res["bandwidthProfiles"] = s.connectionsService.BandwidthProfiles()
+	sendJSON(w, res)
 }
 
 func (s *service) getDeviceStats(w http.ResponseWriter, _ *http.Request) {
@@ -1036,6 +1142,11 @@ func (s *service) postSystemRestart(w http.ResponseWriter, _ *http.Request) {
 	})
 }
 
+// userAction records what whoever makes the request did, for the audit log.
+func (s *service) userAction(action string, r *http.Request, data map[string]any, err error) {
+	emitUserAction(action, requestPrincipal(r, s.cfg.GUI(), s.apiTokens), r, data, err, s.evLogger)
+}
+
 func (s *service) postSystemReset(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -1051,14 +1162,18 @@ func (s *service) postSystemReset(w http.ResponseWriter, r *http.Request) {
 		// Reset all folders.
 		for folder := range s.cfg.Folders() {
 			if err := s.model.ResetFolder(folder); err != nil {
+				s.userAction("database.reset", r, nil, err)
 				http.Error(w, err.Error(), http.StatusInternalServerError)
 				return
 			}
 		}
+		s.userAction("database.reset", r, nil, nil)
 		s.flushResponse(`{"ok": "resetting database"}`, w)
 	} else {
 		// Reset a specific folder, assuming it's supposed to exist.
-		if err := s.model.ResetFolder(folder); err != nil {
+		err := s.model.ResetFolder(folder)
+		s.userAction("folder.reset", r, map[string]any{"folder": folder}, err)
+		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -1411,15 +1526,29 @@ func (s *service) postDBIgnores(w http.ResponseWriter, r *http.Request) {
 func (s *service) getIndexEvents(w http.ResponseWriter, r *http.Request) {
 	mask := s.getEventMask(r.URL.Query().Get("events"))
 	sub := s.getEventSub(mask)
-	s.getEvents(w, r, sub)
+	redact := requestPrincipal(r, s.cfg.GUI(), s.apiTokens).role != config.GUIRoleAdmin
+	s.getEvents(w, r, sub, redact)
 }
 
 func (s *service) getDiskEvents(w http.ResponseWriter, r *http.Request) {
 	sub := s.getEventSub(DiskEventMask)
-	s.getEvents(w, r, sub)
+	s.getEvents(w, r, sub, false)
 }
 
-func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub events.BufferedSubscription) {
+// redactedEvents returns the events without the secrets only admins may
+// see, which are in the saved config. The events are shared between
+// subscribers, so the config is redacted in a copy.
+func redactedEvents(evs []events.Event) []events.Event {
+	evs = slices.Clone(evs)
+	for i, ev := range evs {
+		if cfg, ok := ev.Data.(config.Configuration); ok {
+			evs[i].Data = redactedConfig(cfg.Copy())
+		}
+	}
+	return evs
+}
+
+func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub events.BufferedSubscription, redact bool) {
 	qs := r.URL.Query()
 	sinceStr := qs.Get("since")
 	limitStr := qs.Get("limit")
@@ -1444,6 +1573,9 @@ func (*service) getEvents(w http.ResponseWriter, r *http.Request, eventSub event
 	if 0 < limit && limit < len(evs) {
 		evs = evs[len(evs)-limit:]
 	}
+	if redact {
+		evs = redactedEvents(evs)
+	}
 
 	sendJSON(w, evs)
 }
@@ -1694,6 +1826,20 @@ func (s *service) getFolderVersions(w http.ResponseWriter, r *http.Request) {
 	sendJSON(w, versions)
 }
 
+func (s *service) getFolderExpiredVersions(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	versions, err := s.model.GetExpiredFolderVersions(qs.Get("folder"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if isFolderNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	sendJSON(w, versions)
+}
+
 func (s *service) postFolderVersionsRestore(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 
@@ -1712,6 +1858,7 @@ func (s *service) postFolderVersionsRestore(w http.ResponseWriter, r *http.Reque
 	}
 
 	ferr, err := s.model.RestoreFolderVersions(qs.Get("folder"), versions)
+	s.userAction("folder.restoreVersions", r, map[string]any{"folder": qs.Get("folder"), "files": len(versions)}, err)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1719,6 +1866,27 @@ func (s *service) postFolderVersionsRestore(w http.ResponseWriter, r *http.Reque
 	sendJSON(w, errorStringMap(ferr))
 }
 
+func (s *service) postFolderRollback(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+
+	at, err := time.Parse(time.RFC3339, qs.Get("time"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dryRun := qs.Get("dryrun") == "true"
+
+	changes, err := s.model.RollbackFolder(qs.Get("folder"), qs.Get("sub"), at, dryRun)
+	if !dryRun {
+		s.userAction("folder.rollback", r, map[string]any{"folder": qs.Get("folder"), "sub": qs.Get("sub"), "time": at}, err)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sendJSON(w, changes)
+}
+
 func (s *service) getFolderErrors(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	folder := qs.Get("folder")
@@ -1748,6 +1916,35 @@ func (s *service) getFolderErrors(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (s *service) getFolderConflicts(w http.ResponseWriter, r *http.Request) {
+	qs := r.URL.Query()
+	folder := qs.Get("folder")
+	page, perpage := getPagingParams(qs)
+
+	conflicts, err := s.model.FolderConflicts(folder)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+
+	start := (page - 1) * perpage
+	if start >= len(conflicts) {
+		conflicts = nil
+	} else {
+		conflicts = conflicts[start:]
+		if perpage < len(conflicts) {
+			conflicts = conflicts[:perpage]
+		}
+	}
+
+	sendJSON(w, map[string]interface{}{
+		"folder":    folder,
+		"conflicts": conflicts,
+		"page":      page,
+		"perpage":   perpage,
+	})
+}
+
 func (*service) getSystemBrowse(w http.ResponseWriter, r *http.Request) {
 	qs := r.URL.Query()
 	current := qs.Get("current")
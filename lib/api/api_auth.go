@@ -9,6 +9,7 @@ package api
 import (
 	"crypto/tls"
 	"fmt"
+	"maps"
 	"net"
 	"net/http"
 	"slices"
@@ -28,13 +29,16 @@ const (
 	randomTokenLength  = 64
 )
 
-func emitLoginAttempt(success bool, username string, r *http.Request, evLogger events.Logger) {
+func emitLoginAttempt(success bool, p principal, r *http.Request, evLogger events.Logger) {
 	remoteAddress, proxy := remoteAddress(r)
 	evData := map[string]any{
 		"success":       success,
-		"username":      username,
+		"username":      p.username,
 		"remoteAddress": remoteAddress,
 	}
+	if success {
+		evData["role"] = p.role.String()
+	}
 	if proxy != "" {
 		evData["proxy"] = proxy
 	}
@@ -50,6 +54,27 @@ func emitLoginAttempt(success bool, username string, r *http.Request, evLogger e
 	}
 }
 
+// emitUserAction records that the principal did something, successfully
+// unless there is an error, for the audit log.
+func emitUserAction(action string, p principal, r *http.Request, data map[string]any, err error, evLogger events.Logger) {
+	remoteAddress, proxy := remoteAddress(r)
+	evData := map[string]any{
+		"action":        action,
+		"success":       err == nil,
+		"username":      p.username,
+		"role":          p.role.String(),
+		"remoteAddress": remoteAddress,
+	}
+	if proxy != "" {
+		evData["proxy"] = proxy
+	}
+	if err != nil {
+		evData["error"] = err.Error()
+	}
+	maps.Copy(evData, data)
+	evLogger.Log(events.UserAction, evData)
+}
+
 func remoteAddress(r *http.Request) (remoteAddr, proxy string) {
 	remoteAddr = r.RemoteAddr
 	remoteIP := osutil.IPFromString(r.RemoteAddr)
@@ -126,35 +151,37 @@ type basicAuthAndSessionMiddleware struct {
 	tokenCookieManager *tokenCookieManager
 	guiCfg             config.GUIConfiguration
 	ldapCfg            config.LDAPConfiguration
+	apiTokens          *apiTokenManager
 	next               http.Handler
 	evLogger           events.Logger
 }
 
-func newBasicAuthAndSessionMiddleware(tokenCookieManager *tokenCookieManager, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, next http.Handler, evLogger events.Logger) *basicAuthAndSessionMiddleware {
+func newBasicAuthAndSessionMiddleware(tokenCookieManager *tokenCookieManager, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, apiTokens *apiTokenManager, next http.Handler, evLogger events.Logger) *basicAuthAndSessionMiddleware {
 	return &basicAuthAndSessionMiddleware{
 		tokenCookieManager: tokenCookieManager,
 		guiCfg:             guiCfg,
 		ldapCfg:            ldapCfg,
+		apiTokens:          apiTokens,
 		next:               next,
 		evLogger:           evLogger,
 	}
 }
 
 func (m *basicAuthAndSessionMiddleware) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if hasValidAPIKeyHeader(r, m.guiCfg) {
-		m.next.ServeHTTP(w, r)
+	if p, ok := apiKeyPrincipal(r, m.guiCfg, m.apiTokens); ok {
+		m.next.ServeHTTP(w, withPrincipal(r, p))
 		return
 	}
 
-	if m.tokenCookieManager.hasValidSession(r) {
-		m.next.ServeHTTP(w, r)
+	if p, ok := m.tokenCookieManager.validSession(r); ok {
+		m.next.ServeHTTP(w, withPrincipal(r, p))
 		return
 	}
 
 	// Fall back to Basic auth if provided
-	if username, ok := attemptBasicAuth(r, m.guiCfg, m.ldapCfg, m.evLogger); ok {
-		m.tokenCookieManager.createSession(username, false, w, r)
-		m.next.ServeHTTP(w, r)
+	if p, ok := attemptBasicAuth(r, m.guiCfg, m.ldapCfg, m.evLogger); ok {
+		m.tokenCookieManager.createSession(p, false, w, r)
+		m.next.ServeHTTP(w, withPrincipal(r, p))
 		return
 	}
 
@@ -186,38 +213,38 @@ func (m *basicAuthAndSessionMiddleware) passwordAuthHandler(w http.ResponseWrite
 		return
 	}
 
-	if auth(req.Username, req.Password, m.guiCfg, m.ldapCfg) {
-		m.tokenCookieManager.createSession(req.Username, req.StayLoggedIn, w, r)
+	if p, ok := auth(req.Username, req.Password, m.guiCfg, m.ldapCfg); ok {
+		m.tokenCookieManager.createSession(p, req.StayLoggedIn, w, r)
 		w.WriteHeader(http.StatusNoContent)
 		return
 	}
 
-	emitLoginAttempt(false, req.Username, r, m.evLogger)
+	emitLoginAttempt(false, principal{username: req.Username}, r, m.evLogger)
 	antiBruteForceSleep()
 	forbidden(w)
 }
 
-func attemptBasicAuth(r *http.Request, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, evLogger events.Logger) (string, bool) {
+func attemptBasicAuth(r *http.Request, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration, evLogger events.Logger) (principal, bool) {
 	username, password, ok := r.BasicAuth()
 	if !ok {
-		return "", false
+		return principal{}, false
 	}
 
 	l.Debugln("Sessionless HTTP request with authentication; this is expensive.")
 
-	if auth(username, password, guiCfg, ldapCfg) {
-		return username, true
+	if p, ok := auth(username, password, guiCfg, ldapCfg); ok {
+		return p, true
 	}
 
 	usernameFromIso := string(iso88591ToUTF8([]byte(username)))
 	passwordFromIso := string(iso88591ToUTF8([]byte(password)))
-	if auth(usernameFromIso, passwordFromIso, guiCfg, ldapCfg) {
-		return usernameFromIso, true
+	if p, ok := auth(usernameFromIso, passwordFromIso, guiCfg, ldapCfg); ok {
+		return p, true
 	}
 
-	emitLoginAttempt(false, username, r, evLogger)
+	emitLoginAttempt(false, principal{username: username}, r, evLogger)
 	antiBruteForceSleep()
-	return "", false
+	return principal{}, false
 }
 
 func (m *basicAuthAndSessionMiddleware) handleLogout(w http.ResponseWriter, r *http.Request) {
@@ -225,18 +252,48 @@ func (m *basicAuthAndSessionMiddleware) handleLogout(w http.ResponseWriter, r *h
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func auth(username string, password string, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration) bool {
+// handleMethods tells the login page which ways of logging in there are.
+func (m *basicAuthAndSessionMiddleware) handleMethods(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, map[string]bool{
+		// With OIDC, the static user is a fallback for when the identity
+		// provider is unavailable, if there is one.
+		"password": m.guiCfg.AuthMode != config.AuthModeOIDC || m.guiCfg.User != "" && m.guiCfg.Password != "",
+		"oidc":     m.guiCfg.AuthMode == config.AuthModeOIDC,
+	})
+}
+
+// auth returns who the user is, if the password is right. Additional users
+// from the configuration come after the main user, whether that is a
+// static or an LDAP one.
+func auth(username string, password string, guiCfg config.GUIConfiguration, ldapCfg config.LDAPConfiguration) (principal, bool) {
+	var ok bool
 	if guiCfg.AuthMode == config.AuthModeLDAP {
-		return authLDAP(username, password, ldapCfg)
+		ok = authLDAP(username, password, ldapCfg)
 	} else {
-		return authStatic(username, password, guiCfg)
+		ok = authStatic(username, password, guiCfg)
 	}
+	if ok {
+		return principal{username: username, role: config.GUIRoleAdmin}, true
+	}
+	if user, ok := authUsers(username, password, guiCfg); ok {
+		return userPrincipal(user), true
+	}
+	return principal{}, false
 }
 
 func authStatic(username string, password string, guiCfg config.GUIConfiguration) bool {
 	return guiCfg.CompareHashedPassword(password) == nil && username == guiCfg.User
 }
 
+func authUsers(username string, password string, guiCfg config.GUIConfiguration) (config.GUIUser, bool) {
+	for _, user := range guiCfg.Users {
+		if user.Name == username && user.Password != "" && user.CompareHashedPassword(password) == nil {
+			return user, true
+		}
+	}
+	return config.GUIUser{}, false
+}
+
 func authLDAP(username string, password string, cfg config.LDAPConfiguration) bool {
 	address := cfg.Address
 	hostname, _, err := net.SplitHostPort(address)
@@ -0,0 +1,299 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/rand"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const (
+	oidcCallbackPath      = "/rest/noauth/auth/oidc/callback"
+	oidcLoginTimeout      = 10 * time.Minute
+	oidcRequestTimeout    = 30 * time.Second
+	maxPendingOIDCLogins  = 100
+	oidcStateTokenLength  = 32
+	oidcStateCookiePrefix = "oidcstate-"
+	oidcUsernamePrefix    = "oidc:"
+)
+
+// oidcAuthenticator logs users in with an OpenID Connect identity provider,
+// using the authorization code flow.
+type oidcAuthenticator struct {
+	tokenCookieManager *tokenCookieManager
+	guiCfg             config.GUIConfiguration
+	evLogger           events.Logger
+	stateCookieName    string
+
+	mut      sync.Mutex
+	provider *oidc.Provider              // discovered on first use
+	pending  map[string]oidcPendingLogin // by state
+}
+
+// An oidcPendingLogin is a login that the browser has been sent to the
+// identity provider for.
+type oidcPendingLogin struct {
+	nonce       string
+	verifier    string
+	redirectURL string
+	persistent  bool
+	expires     time.Time
+}
+
+func newOIDCAuthenticator(tokenCookieManager *tokenCookieManager, guiCfg config.GUIConfiguration, evLogger events.Logger) *oidcAuthenticator {
+	return &oidcAuthenticator{
+		tokenCookieManager: tokenCookieManager,
+		guiCfg:             guiCfg,
+		evLogger:           evLogger,
+		stateCookieName:    oidcStateCookiePrefix + tokenCookieManager.shortID,
+		mut:                sync.NewMutex(),
+		pending:            make(map[string]oidcPendingLogin),
+	}
+}
+
+// loginHandler sends the browser to the identity provider.
+func (a *oidcAuthenticator) loginHandler(w http.ResponseWriter, r *http.Request) {
+	provider, err := a.getProvider(r.Context())
+	if err != nil {
+		l.Warnln("OIDC discovery:", err)
+		http.Error(w, "Identity provider unavailable", http.StatusBadGateway)
+		return
+	}
+
+	state := rand.String(oidcStateTokenLength)
+	login := oidcPendingLogin{
+		nonce:       rand.String(oidcStateTokenLength),
+		verifier:    oauth2.GenerateVerifier(),
+		redirectURL: a.redirectURL(r),
+		persistent:  r.URL.Query().Get("stayLoggedIn") == "true",
+		expires:     time.Now().Add(oidcLoginTimeout),
+	}
+	if !a.addPending(state, login) {
+		http.Error(w, "Too many pending logins", http.StatusTooManyRequests)
+		return
+	}
+
+	// The state is tied to the browser, so that nobody can log it in
+	// with a login they started themselves.
+	http.SetCookie(w, &http.Cookie{
+		Name:     a.stateCookieName,
+		Value:    state,
+		MaxAge:   int(oidcLoginTimeout.Seconds()),
+		Secure:   useSecureCookie(r, a.guiCfg),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Path:     "/",
+	})
+
+	authURL := a.oauth2Config(provider, login.redirectURL).AuthCodeURL(state, oidc.Nonce(login.nonce), oauth2.S256ChallengeOption(login.verifier))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// callbackHandler handles the browser coming back from the identity
+// provider, creating a session if the user may log in.
+func (a *oidcAuthenticator) callbackHandler(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, &http.Cookie{
+		Name:   a.stateCookieName,
+		Value:  "",
+		MaxAge: -1,
+		Path:   "/",
+	})
+
+	p, persistent, err := a.completeLogin(r)
+	if err != nil {
+		l.Infoln("OIDC login failed:", err)
+		emitLoginAttempt(false, p, r, a.evLogger)
+		antiBruteForceSleep()
+		http.Error(w, "Login failed, see Syncthing logs for details.", http.StatusForbidden)
+		return
+	}
+
+	a.tokenCookieManager.createSession(p, persistent, w, r)
+	// Relative to the callback path, so that it works behind a reverse
+	// proxy serving the GUI below some path.
+	http.Redirect(w, r, "../../../../", http.StatusFound)
+}
+
+func (a *oidcAuthenticator) completeLogin(r *http.Request) (principal, bool, error) {
+	query := r.URL.Query()
+	state := query.Get("state")
+	cookie, err := r.Cookie(a.stateCookieName)
+	if err != nil || state == "" || cookie.Value != state {
+		return principal{}, false, errors.New("state mismatch")
+	}
+	login, ok := a.takePending(state)
+	if !ok {
+		return principal{}, false, errors.New("unknown or expired login")
+	}
+	if errCode := query.Get("error"); errCode != "" {
+		return principal{}, false, fmt.Errorf("identity provider: %s: %s", errCode, query.Get("error_description"))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), oidcRequestTimeout)
+	defer cancel()
+	provider, err := a.getProvider(ctx)
+	if err != nil {
+		return principal{}, false, err
+	}
+
+	token, err := a.oauth2Config(provider, login.redirectURL).Exchange(ctx, query.Get("code"), oauth2.VerifierOption(login.verifier))
+	if err != nil {
+		return principal{}, false, fmt.Errorf("exchanging code: %w", err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return principal{}, false, errors.New("no ID token in token response")
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: a.guiCfg.OIDC.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return principal{}, false, fmt.Errorf("verifying ID token: %w", err)
+	}
+	if idToken.Nonce != login.nonce {
+		return principal{}, false, errors.New("nonce mismatch")
+	}
+
+	var claims map[string]any
+	if err := idToken.Claims(&claims); err != nil {
+		return principal{}, false, fmt.Errorf("parsing claims: %w", err)
+	}
+	username := oidcUsername(idToken.Issuer, idToken.Subject)
+	p, ok := principalForGroups(username, a.guiCfg.OIDC.RoleMappings, claimStrings(claims[a.guiCfg.OIDC.GroupsClaim]))
+	if !ok {
+		return principal{username: username}, false, fmt.Errorf("user %q is in no group with a role", username)
+	}
+	return p, login.persistent, nil
+}
+
+// oidcUsername returns the name an OIDC user is known by. The subject is
+// the only claim the identity provider promises is unique and never
+// reassigned, and only so per issuer.
+func oidcUsername(issuer, subject string) string {
+	return oidcUsernamePrefix + issuer + "/" + subject
+}
+
+func (a *oidcAuthenticator) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+	if a.provider != nil {
+		return a.provider, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, oidcRequestTimeout)
+	defer cancel()
+	provider, err := oidc.NewProvider(ctx, a.guiCfg.OIDC.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	a.provider = provider
+	return provider, nil
+}
+
+func (a *oidcAuthenticator) oauth2Config(provider *oidc.Provider, redirectURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     a.guiCfg.OIDC.ClientID,
+		ClientSecret: a.guiCfg.OIDC.ClientSecret,
+		Endpoint:     provider.Endpoint(),
+		RedirectURL:  redirectURL,
+		Scopes:       append([]string{oidc.ScopeOpenID}, a.guiCfg.OIDC.Scopes...),
+	}
+}
+
+func (a *oidcAuthenticator) redirectURL(r *http.Request) string {
+	if a.guiCfg.OIDC.RedirectURL != "" {
+		return a.guiCfg.OIDC.RedirectURL
+	}
+	scheme := "http"
+	if useSecureCookie(r, a.guiCfg) {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host + oidcCallbackPath
+}
+
+func (a *oidcAuthenticator) addPending(state string, login oidcPendingLogin) bool {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	now := time.Now()
+	for state, login := range a.pending {
+		if now.After(login.expires) {
+			delete(a.pending, state)
+		}
+	}
+	if len(a.pending) >= maxPendingOIDCLogins {
+		return false
+	}
+	a.pending[state] = login
+	return true
+}
+
+func (a *oidcAuthenticator) takePending(state string) (oidcPendingLogin, bool) {
+	a.mut.Lock()
+	defer a.mut.Unlock()
+
+	login, ok := a.pending[state]
+	delete(a.pending, state)
+	return login, ok && time.Now().Before(login.expires)
+}
+
+// principalForGroups returns the user with the most privileged role given
+// to any of the groups, or false if the user may not log in at all. An
+// operator may operate on the folders of all their operator groups. Without
+// role mappings nobody may log in, as the identity provider may well let in
+// anyone with an account.
+func principalForGroups(username string, mappings []config.OIDCRoleMapping, groups []string) (principal, bool) {
+	for _, role := range guiRolesByPrivilege {
+		p := principal{username: username, role: role}
+		found := false
+		for _, mapping := range mappings {
+			if mapping.Role != role || !slices.Contains(groups, mapping.Group) {
+				continue
+			}
+			if found && (len(p.folders) == 0 || len(mapping.Folders) == 0) {
+				// One of them has all folders.
+				p.folders = nil
+			} else {
+				p.folders = append(p.folders, mapping.Folders...)
+			}
+			found = true
+		}
+		if found {
+			return p, true
+		}
+	}
+	return principal{}, false
+}
+
+// claimStrings returns the strings of a claim that may be either a single
+// string or a list of them.
+func claimStrings(claim any) []string {
+	switch claim := claim.(type) {
+	case string:
+		return []string{claim}
+	case []any:
+		strs := make([]string, 0, len(claim))
+		for _, v := range claim {
+			if s, ok := v.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,243 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func TestOIDCLogin(t *testing.T) {
+	t.Parallel()
+
+	idp := newFakeOIDCProvider(t)
+	cfg := newMockedConfig()
+	cfg.GUIReturns(config.GUIConfiguration{
+		RawAddress: "127.0.0.1:0",
+		AuthMode:   config.AuthModeOIDC,
+		OIDC: config.OIDCConfiguration{
+			Issuer:       idp.URL,
+			ClientID:     "syncthing",
+			ClientSecret: "secret",
+			GroupsClaim:  "groups",
+			RoleMappings: []config.OIDCRoleMapping{
+				{Group: "admins", Role: config.GUIRoleAdmin},
+				{Group: "staff", Role: config.GUIRoleMonitor},
+			},
+		},
+	})
+	baseURL, cancel, err := startHTTP(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+
+	csrfName := "CSRF-Token-" + protocol.LocalDeviceID.Short().String()
+	login := func(t *testing.T, groups ...string) (*http.Client, int) {
+		t.Helper()
+		jar, _ := cookiejar.New(nil)
+		client := &http.Client{Jar: jar, Timeout: 15 * time.Second}
+		idp.setGroups(groups)
+		resp, err := client.Get(baseURL + "/rest/noauth/auth/oidc/login")
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return client, resp.StatusCode
+	}
+	get := func(t *testing.T, client *http.Client, path string) int {
+		t.Helper()
+		req, _ := http.NewRequest(http.MethodGet, baseURL+path, nil)
+		u, _ := url.Parse(baseURL)
+		for _, cookie := range client.Jar.Cookies(u) {
+			if cookie.Name == csrfName {
+				req.Header.Set("X-"+csrfName, cookie.Value)
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// The subtests don't run in parallel, as the groups are set on the
+	// identity provider.
+	t.Run("admin", func(t *testing.T) {
+		client, status := login(t, "staff", "admins")
+		if status != http.StatusOK {
+			t.Fatalf("Unexpected status %d after login", status)
+		}
+		if status := get(t, client, "/rest/config"); status != http.StatusOK {
+			t.Errorf("Unexpected status %d for admin getting config", status)
+		}
+	})
+
+	t.Run("monitor", func(t *testing.T) {
+		client, status := login(t, "staff")
+		if status != http.StatusOK {
+			t.Fatalf("Unexpected status %d after login", status)
+		}
+		if status := get(t, client, "/rest/system/version"); status != http.StatusOK {
+			t.Errorf("Unexpected status %d for monitor getting version", status)
+		}
+		if status := get(t, client, "/rest/config/gui"); status != http.StatusForbidden {
+			t.Errorf("Unexpected status %d for monitor getting GUI config", status)
+		}
+	})
+
+	t.Run("no role", func(t *testing.T) {
+		client, status := login(t, "others")
+		if status != http.StatusForbidden {
+			t.Fatalf("Unexpected status %d after login without role", status)
+		}
+		u, _ := url.Parse(baseURL)
+		if hasSessionCookie(client.Jar.Cookies(u)) {
+			t.Error("Unexpected session cookie after login without role")
+		}
+	})
+}
+
+// fakeOIDCProvider is an identity provider that logs everyone in right
+// away, as a member of the configured groups.
+type fakeOIDCProvider struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mut    sync.Mutex
+	groups []string
+	codes  map[string]fakeOIDCCode
+}
+
+type fakeOIDCCode struct {
+	nonce     string
+	challenge string
+	groups    []string
+}
+
+func newFakeOIDCProvider(t *testing.T) *fakeOIDCProvider {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &fakeOIDCProvider{
+		key:   key,
+		mut:   sync.NewMutex(),
+		codes: make(map[string]fakeOIDCCode),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("/keys", p.keys)
+	mux.HandleFunc("/authorize", p.authorize)
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+func (p *fakeOIDCProvider) setGroups(groups []string) {
+	p.mut.Lock()
+	p.groups = groups
+	p.mut.Unlock()
+}
+
+func (p *fakeOIDCProvider) discovery(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, map[string]any{
+		"issuer":                                p.URL,
+		"authorization_endpoint":                p.URL + "/authorize",
+		"token_endpoint":                        p.URL + "/token",
+		"jwks_uri":                              p.URL + "/keys",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+	})
+}
+
+func (p *fakeOIDCProvider) keys(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+		{Key: &p.key.PublicKey, KeyID: "test", Algorithm: "RS256", Use: "sig"},
+	}})
+}
+
+func (p *fakeOIDCProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "PKCE required", http.StatusBadRequest)
+		return
+	}
+	code := "code-" + query.Get("state")
+	p.mut.Lock()
+	p.codes[code] = fakeOIDCCode{
+		nonce:     query.Get("nonce"),
+		challenge: query.Get("code_challenge"),
+		groups:    p.groups,
+	}
+	p.mut.Unlock()
+
+	redirect, _ := url.Parse(query.Get("redirect_uri"))
+	redirect.RawQuery = url.Values{"code": {code}, "state": {query.Get("state")}}.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (p *fakeOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	p.mut.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mut.Unlock()
+	verifierHash := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(verifierHash[:]) != code.challenge {
+		http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+		return
+	}
+
+	clientID, _, _ := r.BasicAuth()
+	claims, _ := json.Marshal(map[string]any{
+		"iss":                p.URL,
+		"sub":                "1234",
+		"aud":                clientID,
+		"iat":                time.Now().Unix(),
+		"exp":                time.Now().Add(time.Hour).Unix(),
+		"nonce":              code.nonce,
+		"preferred_username": "jane",
+		"groups":             code.groups,
+	})
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: p.key, KeyID: "test"}}, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	sig, err := signer.Sign(claims)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	idToken, _ := sig.CompactSerialize()
+	sendJSON(w, map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+		"id_token":     idToken,
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/db"
 )
 
@@ -20,34 +21,32 @@ const (
 )
 
 type csrfManager struct {
-	unique          string
-	prefix          string
-	apiKeyValidator apiKeyValidator
-	next            http.Handler
-	tokens          *tokenManager
-}
-
-type apiKeyValidator interface {
-	IsValidAPIKey(key string) bool
+	unique    string
+	prefix    string
+	guiCfg    config.GUIConfiguration
+	apiTokens *apiTokenManager
+	next      http.Handler
+	tokens    *tokenManager
 }
 
 // Check for CSRF token on /rest/ URLs. If a correct one is not given, reject
 // the request with 403. For / and /index.html, set a new CSRF cookie if none
 // is currently set.
-func newCsrfManager(unique string, prefix string, apiKeyValidator apiKeyValidator, next http.Handler, miscDB *db.NamespacedKV) *csrfManager {
+func newCsrfManager(unique string, prefix string, guiCfg config.GUIConfiguration, apiTokens *apiTokenManager, next http.Handler, miscDB *db.NamespacedKV) *csrfManager {
 	m := &csrfManager{
-		unique:          unique,
-		prefix:          prefix,
-		apiKeyValidator: apiKeyValidator,
-		next:            next,
-		tokens:          newTokenManager("csrfTokens", miscDB, maxCSRFTokenLifetime, maxActiveCSRFTokens),
+		unique:    unique,
+		prefix:    prefix,
+		guiCfg:    guiCfg,
+		apiTokens: apiTokens,
+		next:      next,
+		tokens:    newTokenManager("csrfTokens", miscDB, maxCSRFTokenLifetime, maxActiveCSRFTokens),
 	}
 	return m
 }
 
 func (m *csrfManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	// Allow requests carrying a valid API key
-	if hasValidAPIKeyHeader(r, m.apiKeyValidator) {
+	// Allow requests carrying a valid API key or token
+	if _, ok := apiKeyPrincipal(r, m.guiCfg, m.apiTokens); ok {
 		// Set the access-control-allow-origin header for CORS requests
 		// since a valid API key has been provided
 		w.Header().Add("Access-Control-Allow-Origin", "*")
@@ -95,13 +94,12 @@ func (m *csrfManager) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	m.next.ServeHTTP(w, r)
 }
 
-func hasValidAPIKeyHeader(r *http.Request, validator apiKeyValidator) bool {
-	if key := r.Header.Get("X-API-Key"); validator.IsValidAPIKey(key) {
-		return true
-	}
+// requestAPIKeys returns the API keys given in the request headers, that
+// is, the X-API-Key header and the bearer token.
+func requestAPIKeys(r *http.Request) []string {
+	keys := []string{r.Header.Get("X-API-Key")}
 	if auth := r.Header.Get("Authorization"); strings.HasPrefix(strings.ToLower(auth), "bearer ") {
-		bearerToken := auth[len("bearer "):]
-		return validator.IsValidAPIKey(bearerToken)
+		keys = append(keys, auth[len("bearer "):])
 	}
-	return false
+	return keys
 }
@@ -0,0 +1,146 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/julienschmidt/httprouter"
+
+	"github.com/syncthing/syncthing/internal/gen/apiproto"
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+// GUI roles, from most to least privileged.
+var guiRolesByPrivilege = []config.GUIRole{config.GUIRoleAdmin, config.GUIRoleOperator, config.GUIRoleMonitor}
+
+// A principal is whoever makes a request, as far as what they may do is
+// concerned.
+type principal struct {
+	username string
+	role     config.GUIRole
+	// The folders an operator may operate on, all of them if empty.
+	folders []string
+}
+
+var adminPrincipal = principal{role: config.GUIRoleAdmin}
+
+func userPrincipal(user config.GUIUser) principal {
+	return principal{username: user.Name, role: user.Role, folders: user.Folders}
+}
+
+func ownerPrincipal(owner *apiproto.TokenOwner) principal {
+	p := principal{username: owner.Username, folders: owner.Folders}
+	_ = p.role.UnmarshalText([]byte(owner.Role))
+	return p
+}
+
+func (p principal) owner() *apiproto.TokenOwner {
+	return &apiproto.TokenOwner{Username: p.username, Role: p.role.String(), Folders: p.folders}
+}
+
+// allows returns whether the principal may make a request to a route that
+// requires the given role. Operator routes are about the folder given in
+// the request.
+func (p principal) allows(role config.GUIRole, r *http.Request) bool {
+	if slices.Index(guiRolesByPrivilege, p.role) > slices.Index(guiRolesByPrivilege, role) {
+		return false
+	}
+	if p.role != config.GUIRoleOperator || role != config.GUIRoleOperator || len(p.folders) == 0 {
+		return true
+	}
+	return slices.Contains(p.folders, r.URL.Query().Get("folder"))
+}
+
+type principalKey struct{}
+
+func withPrincipal(r *http.Request, p principal) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), principalKey{}, p))
+}
+
+// requestPrincipal returns whoever makes the request, as determined by the
+// authentication middleware or else by the API key in the request. Without
+// either there is no authentication to speak of, or the request is for one
+// of the paths that don't need any, so it is an admin.
+func requestPrincipal(r *http.Request, guiCfg config.GUIConfiguration, apiTokens *apiTokenManager) principal {
+	if p, ok := r.Context().Value(principalKey{}).(principal); ok {
+		return p
+	}
+	if p, ok := apiKeyPrincipal(r, guiCfg, apiTokens); ok {
+		return p
+	}
+	return adminPrincipal
+}
+
+// apiKeyPrincipal returns who the API key or token given in the request
+// belongs to.
+func apiKeyPrincipal(r *http.Request, guiCfg config.GUIConfiguration, apiTokens *apiTokenManager) (principal, bool) {
+	for _, key := range requestAPIKeys(r) {
+		if key == "" {
+			continue
+		}
+		if user, ok := guiCfg.APIKeyUser(key); ok {
+			return userPrincipal(user), true
+		}
+		if guiCfg.IsValidAPIKey(key) {
+			return adminPrincipal, true
+		}
+		if p, ok := apiTokens.principal(key, r); ok {
+			return p, true
+		}
+	}
+	return principal{}, false
+}
+
+// A roleMux registers routes that only let through requests by principals
+// with at least the given role.
+type roleMux struct {
+	*httprouter.Router
+	guiCfg    config.GUIConfiguration
+	apiTokens *apiTokenManager
+	role      config.GUIRole
+}
+
+// newRoleMux returns a roleMux for admin only routes.
+func newRoleMux(guiCfg config.GUIConfiguration, apiTokens *apiTokenManager) roleMux {
+	return roleMux{Router: httprouter.New(), guiCfg: guiCfg, apiTokens: apiTokens, role: config.GUIRoleAdmin}
+}
+
+// withRole returns a roleMux registering routes on the same router that
+// require the given role instead.
+func (m roleMux) withRole(role config.GUIRole) roleMux {
+	m.role = role
+	return m
+}
+
+func (m roleMux) Handle(method, path string, handle httprouter.Handle) {
+	role := m.role
+	m.Router.Handle(method, path, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if !requestPrincipal(r, m.guiCfg, m.apiTokens).allows(role, r) {
+			forbidden(w)
+			return
+		}
+		handle(w, r, ps)
+	})
+}
+
+func (m roleMux) Handler(method, path string, handler http.Handler) {
+	role := m.role
+	m.Router.Handler(method, path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !requestPrincipal(r, m.guiCfg, m.apiTokens).allows(role, r) {
+			forbidden(w)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+}
+
+func (m roleMux) HandlerFunc(method, path string, handler http.HandlerFunc) {
+	m.Handler(method, path, handler)
+}
@@ -0,0 +1,265 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	eventmocks "github.com/syncthing/syncthing/lib/events/mocks"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	guiCfg := config.GUIConfiguration{
+		RawAddress: "127.0.0.1:0",
+		APIKey:     testAPIKey,
+		Users: []config.GUIUser{
+			{Name: "monitor", APIKey: "monitorkey", Role: config.GUIRoleMonitor},
+			{Name: "operator", APIKey: "operatorkey", Role: config.GUIRoleOperator, Folders: []string{"allowed"}},
+		},
+	}
+	folder := config.FolderConfiguration{
+		ID:               "allowed",
+		FilesystemSecret: "secret",
+		Devices:          []config.FolderDeviceConfiguration{{DeviceID: protocol.LocalDeviceID, EncryptionPassword: "secret"}},
+	}
+	cfg := newMockedConfig()
+	cfg.GUIReturns(guiCfg)
+	cfg.RawCopyReturns(config.Configuration{GUI: guiCfg, Folders: []config.FolderConfiguration{folder}})
+	cfg.FolderListReturns([]config.FolderConfiguration{folder})
+	baseURL, cancel, err := startHTTP(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+
+	cases := []struct {
+		method string
+		path   string
+		apiKey string
+		status int
+	}{
+		{http.MethodGet, "/rest/system/version", "monitorkey", http.StatusOK},
+		{http.MethodGet, "/rest/config", "monitorkey", http.StatusOK},
+		{http.MethodGet, "/rest/config/gui", "monitorkey", http.StatusForbidden},
+		{http.MethodGet, "/rest/system/browse", "monitorkey", http.StatusForbidden},
+		{http.MethodPost, "/rest/db/scan?folder=allowed", "monitorkey", http.StatusForbidden},
+		{http.MethodPost, "/rest/system/restart", "monitorkey", http.StatusForbidden},
+		{http.MethodGet, "/rest/system/version", "operatorkey", http.StatusOK},
+		{http.MethodPost, "/rest/db/scan?folder=allowed", "operatorkey", http.StatusOK},
+		{http.MethodPost, "/rest/db/scan?folder=other", "operatorkey", http.StatusForbidden},
+		{http.MethodPost, "/rest/system/shutdown", "operatorkey", http.StatusForbidden},
+		{http.MethodGet, "/rest/config/gui", testAPIKey, http.StatusOK},
+		{http.MethodPost, "/rest/db/scan?folder=other", testAPIKey, http.StatusOK},
+		{http.MethodGet, "/rest/system/version", "unknownkey", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		resp := httpRequest(tc.method, baseURL+tc.path, nil, "", "", tc.apiKey, "", "", "", nil, t)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s with %s: got status %d, expected %d", tc.method, tc.path, tc.apiKey, resp.StatusCode, tc.status)
+		}
+	}
+
+	// Monitors don't get to see the API keys in the config.
+	resp := httpGet(baseURL+"/rest/config", "", "", "monitorkey", "", nil, t)
+	defer resp.Body.Close()
+	var got config.Configuration
+	if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if got.GUI.APIKey != "" || len(got.GUI.Users) != 0 {
+		t.Error("Unexpected GUI secrets in config for monitor")
+	}
+	if pw := got.Folders[0].Devices[0].EncryptionPassword; pw != redactedSecret {
+		t.Errorf("Unexpected encryption password %q in config for monitor", pw)
+	}
+	if secret := got.Folders[0].FilesystemSecret; secret != redactedSecret {
+		t.Errorf("Unexpected filesystem secret %q in config for monitor", secret)
+	}
+
+	resp = httpGet(baseURL+"/rest/config/folders", "", "", "monitorkey", "", nil, t)
+	defer resp.Body.Close()
+	var folders []config.FolderConfiguration
+	if err := json.NewDecoder(resp.Body).Decode(&folders); err != nil {
+		t.Fatal(err)
+	}
+	if pw := folders[0].Devices[0].EncryptionPassword; pw != redactedSecret {
+		t.Errorf("Unexpected encryption password %q in folders for monitor", pw)
+	}
+	if secret := folders[0].FilesystemSecret; secret != redactedSecret {
+		t.Errorf("Unexpected filesystem secret %q in folders for monitor", secret)
+	}
+}
+
+func TestEventsRedacted(t *testing.T) {
+	t.Parallel()
+
+	// The config as saved, in a ConfigSaved event.
+	saved := config.Configuration{
+		GUI: config.GUIConfiguration{
+			APIKey: testAPIKey,
+			Users:  []config.GUIUser{{Name: "monitor", APIKey: "monitorkey", Role: config.GUIRoleMonitor}},
+		},
+		Folders: []config.FolderConfiguration{{
+			ID:      "default",
+			Devices: []config.FolderDeviceConfiguration{{DeviceID: protocol.LocalDeviceID, EncryptionPassword: "secret"}},
+		}},
+	}
+	sub := new(eventmocks.BufferedSubscription)
+	sub.SinceReturns([]events.Event{{SubscriptionID: 1, Type: events.ConfigSaved, Data: saved}})
+	cfg := newMockedConfig()
+	cfg.GUIReturns(saved.GUI)
+	svc := &service{
+		cfg:          cfg,
+		eventSubs:    map[events.EventType]events.BufferedSubscription{DefaultEventMask: sub},
+		eventSubsMut: sync.NewMutex(),
+	}
+
+	poll := func(p principal) config.Configuration {
+		t.Helper()
+		r := withPrincipal(httptest.NewRequest(http.MethodGet, "/rest/events?timeout=0", nil), p)
+		w := httptest.NewRecorder()
+		svc.getIndexEvents(w, r)
+		var evs []struct {
+			Data config.Configuration `json:"data"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&evs); err != nil {
+			t.Fatal(err)
+		}
+		if len(evs) != 1 {
+			t.Fatalf("Expected one event, got %d", len(evs))
+		}
+		return evs[0].Data
+	}
+
+	got := poll(principal{username: "monitor", role: config.GUIRoleMonitor})
+	if got.GUI.APIKey != "" || len(got.GUI.Users) != 0 {
+		t.Error("Unexpected GUI secrets in event for monitor")
+	}
+	if pw := got.Folders[0].Devices[0].EncryptionPassword; pw != redactedSecret {
+		t.Errorf("Unexpected encryption password %q in event for monitor", pw)
+	}
+
+	// The event itself is left alone for everyone else.
+	got = poll(adminPrincipal)
+	if got.GUI.APIKey != testAPIKey || got.Folders[0].Devices[0].EncryptionPassword != "secret" {
+		t.Error("Expected secrets in event for admin")
+	}
+}
+
+func TestSessionsRevokedOnUserChange(t *testing.T) {
+	t.Parallel()
+
+	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	svc := &service{
+		sessions:      newSessionManager(db.NewMiscDataNamespace(mdb)),
+		configChanged: make(chan struct{}, 1),
+	}
+	from := config.Configuration{GUI: config.GUIConfiguration{Users: []config.GUIUser{
+		{Name: "alice", Role: config.GUIRoleMonitor},
+		{Name: "bob", Role: config.GUIRoleMonitor},
+		{Name: "carol", Role: config.GUIRoleMonitor},
+	}}}
+	alice := svc.sessions.NewOwned(userPrincipal(from.GUI.Users[0]).owner())
+	bob := svc.sessions.NewOwned(userPrincipal(from.GUI.Users[1]).owner())
+	carol := svc.sessions.NewOwned(userPrincipal(from.GUI.Users[2]).owner())
+
+	// Alice becomes an admin and Carol is removed.
+	to := from.Copy()
+	to.GUI.Users[0].Role = config.GUIRoleAdmin
+	to.GUI.Users = to.GUI.Users[:2]
+	svc.CommitConfiguration(from, to)
+
+	if svc.sessions.Check(alice) {
+		t.Error("Session of changed user is still valid")
+	}
+	if !svc.sessions.Check(bob) {
+		t.Error("Session of unchanged user was revoked")
+	}
+	if svc.sessions.Check(carol) {
+		t.Error("Session of removed user is still valid")
+	}
+}
+
+func TestSessionsRevokedOnOIDCChange(t *testing.T) {
+	t.Parallel()
+
+	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	svc := &service{
+		sessions:      newSessionManager(db.NewMiscDataNamespace(mdb)),
+		configChanged: make(chan struct{}, 1),
+	}
+	from := config.Configuration{GUI: config.GUIConfiguration{
+		AuthMode: config.AuthModeOIDC,
+		Users:    []config.GUIUser{{Name: "alice", Role: config.GUIRoleMonitor}},
+		OIDC: config.OIDCConfiguration{
+			Issuer:       "https://idp.example.com",
+			RoleMappings: []config.OIDCRoleMapping{{Group: "staff", Role: config.GUIRoleMonitor}},
+		},
+	}}
+	alice := svc.sessions.NewOwned(userPrincipal(from.GUI.Users[0]).owner())
+	jane := svc.sessions.NewOwned(principal{username: oidcUsername(from.GUI.OIDC.Issuer, "1234"), role: config.GUIRoleMonitor}.owner())
+
+	// Staff become operators.
+	to := from.Copy()
+	to.GUI.OIDC.RoleMappings[0].Role = config.GUIRoleOperator
+	svc.CommitConfiguration(from, to)
+
+	if svc.sessions.Check(jane) {
+		t.Error("Session of OIDC user is still valid")
+	}
+	if !svc.sessions.Check(alice) {
+		t.Error("Session of unchanged local user was revoked")
+	}
+}
+
+func TestPrincipalForGroups(t *testing.T) {
+	t.Parallel()
+
+	mappings := []config.OIDCRoleMapping{
+		{Group: "admins", Role: config.GUIRoleAdmin},
+		{Group: "photos", Role: config.GUIRoleOperator, Folders: []string{"photos"}},
+		{Group: "docs", Role: config.GUIRoleOperator, Folders: []string{"docs"}},
+		{Group: "ops", Role: config.GUIRoleOperator},
+		{Group: "staff", Role: config.GUIRoleMonitor},
+	}
+	cases := []struct {
+		groups  []string
+		role    config.GUIRole
+		folders []string
+		ok      bool
+	}{
+		{[]string{"staff", "admins"}, config.GUIRoleAdmin, nil, true},
+		{[]string{"staff", "photos", "docs"}, config.GUIRoleOperator, []string{"photos", "docs"}, true},
+		{[]string{"photos", "ops"}, config.GUIRoleOperator, nil, true},
+		{[]string{"staff"}, config.GUIRoleMonitor, nil, true},
+		{[]string{"others"}, 0, nil, false},
+	}
+	for _, tc := range cases {
+		p, ok := principalForGroups("jane", mappings, tc.groups)
+		if ok != tc.ok || ok && (p.role != tc.role || !slices.Equal(p.folders, tc.folders)) {
+			t.Errorf("%v: got %v %v %v, expected %v %v %v", tc.groups, p.role, p.folders, ok, tc.role, tc.folders, tc.ok)
+		}
+	}
+
+	// Without mappings nobody gets in, whatever their groups.
+	if _, ok := principalForGroups("jane", nil, []string{"admins"}); ok {
+		t.Error("Login allowed without role mappings")
+	}
+}
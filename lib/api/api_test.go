@@ -266,6 +266,26 @@ func TestAPIServiceRequests(t *testing.T) {
 			Type:   "application/json",
 			Prefix: "",
 		},
+		{
+			URL:    "/rest/db/verify",
+			Code:   200,
+			Type:   "application/json",
+			Prefix: "null",
+		},
+
+		// /rest/folder
+		{
+			URL:    "/rest/folder/conflicts?folder=default",
+			Code:   200,
+			Type:   "application/json",
+			Prefix: "{",
+		},
+		{
+			URL:    "/rest/folder/versions/expired?folder=default",
+			Code:   200,
+			Type:   "application/json",
+			Prefix: "null",
+		},
 
 		// /rest/stats
 		{
@@ -325,7 +345,7 @@ func TestAPIServiceRequests(t *testing.T) {
 			URL:    "/rest/system/connections",
 			Code:   200,
 			Type:   "application/json",
-			Prefix: "null",
+			Prefix: "{",
 		},
 		{
 			URL:    "/rest/system/discovery",
@@ -1022,6 +1042,20 @@ func TestHtmlFormLogin(t *testing.T) {
 	})
 }
 
+func TestFolderExpiredVersionsUnknownFolder(t *testing.T) {
+	t.Parallel()
+
+	m := new(modelmocks.Model)
+	m.GetExpiredFolderVersionsReturns(nil, model.ErrFolderMissing)
+	svc := &service{model: m}
+
+	w := httptest.NewRecorder()
+	svc.getFolderExpiredVersions(w, httptest.NewRequest(http.MethodGet, "/rest/folder/versions/expired?folder=missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Unexpected non-404 return code %d for unknown folder", w.Code)
+	}
+}
+
 func TestApiCache(t *testing.T) {
 	t.Parallel()
 
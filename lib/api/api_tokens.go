@@ -0,0 +1,252 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/internal/gen/apiproto"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/rand"
+)
+
+const (
+	maxAPITokens       = 100
+	apiTokenIDLength   = 16
+	maxAPITokenNameLen = 100
+)
+
+// apiTokenManager keeps the API tokens, which are like the API key but
+// named, expiring and limited to some endpoints, methods and source
+// networks.
+type apiTokenManager struct {
+	tokens *tokenManager
+}
+
+func newAPITokenManager(miscDB *db.NamespacedKV) *apiTokenManager {
+	// Scoped tokens don't use the lifetime, and there is a limit on
+	// creating them instead of dropping the oldest.
+	return &apiTokenManager{tokens: newTokenManager("apiTokens", miscDB, 0, 0)}
+}
+
+// An apiToken is how an API token is shown, without the token itself
+// except when it was just created.
+type apiToken struct {
+	ID           string         `json:"id"`
+	Token        string         `json:"token,omitempty"`
+	Name         string         `json:"name"`
+	Role         config.GUIRole `json:"role"`
+	Folders      []string       `json:"folders"`
+	Endpoints    []string       `json:"endpoints"`
+	Methods      []string       `json:"methods"`
+	Networks     []string       `json:"networks"`
+	Created      time.Time      `json:"created"`
+	Expires      *time.Time     `json:"expires"`
+	LastUsed     *time.Time     `json:"lastUsed"`
+	LastUsedFrom string         `json:"lastUsedFrom"`
+}
+
+// An apiTokenRequest asks for a new API token.
+type apiTokenRequest struct {
+	Name      string          `json:"name"`
+	Role      *config.GUIRole `json:"role"` // must be given
+	Folders   []string        `json:"folders"`
+	Endpoints []string        `json:"endpoints"`
+	Methods   []string        `json:"methods"`
+	Networks  []string        `json:"networks"`
+	Expires   time.Time       `json:"expires"` // never if zero
+}
+
+var errTooManyAPITokens = errors.New("too many API tokens")
+
+// create makes a new API token on behalf of the caller, who can't give it
+// more than they may do themselves.
+func (m *apiTokenManager) create(caller principal, req apiTokenRequest) (apiToken, error) {
+	if req.Name == "" || len(req.Name) > maxAPITokenNameLen {
+		return apiToken{}, errors.New("name must be given and at most 100 characters")
+	}
+	if req.Role == nil {
+		return apiToken{}, errors.New("role must be given")
+	}
+	if slices.Index(guiRolesByPrivilege, *req.Role) < slices.Index(guiRolesByPrivilege, caller.role) {
+		return apiToken{}, fmt.Errorf("role %v is above the caller's role %v", *req.Role, caller.role)
+	}
+	if caller.role == config.GUIRoleOperator && len(caller.folders) > 0 {
+		if len(req.Folders) == 0 {
+			req.Folders = caller.folders
+		}
+		for _, folder := range req.Folders {
+			if !slices.Contains(caller.folders, folder) {
+				return apiToken{}, fmt.Errorf("folder %q is not one of the caller's", folder)
+			}
+		}
+	}
+	if !req.Expires.IsZero() && req.Expires.Before(time.Now()) {
+		return apiToken{}, errors.New("expiry must be in the future")
+	}
+	for _, network := range req.Networks {
+		if _, _, err := net.ParseCIDR(network); err != nil {
+			return apiToken{}, fmt.Errorf("network: %w", err)
+		}
+	}
+	for _, endpoint := range req.Endpoints {
+		if !strings.HasPrefix(endpoint, "/rest/") {
+			return apiToken{}, fmt.Errorf("endpoint %q is not below /rest/", endpoint)
+		}
+	}
+	for i, method := range req.Methods {
+		req.Methods[i] = strings.ToUpper(method)
+	}
+	if len(m.tokens.Scoped()) >= maxAPITokens {
+		return apiToken{}, errTooManyAPITokens
+	}
+
+	owner := principal{username: req.Name, role: *req.Role, folders: req.Folders}.owner()
+	scope := &apiproto.TokenScope{
+		Id:        rand.String(apiTokenIDLength),
+		Name:      req.Name,
+		Created:   time.Now().UnixNano(),
+		Endpoints: req.Endpoints,
+		Methods:   req.Methods,
+		Networks:  req.Networks,
+	}
+	token := m.tokens.NewScoped(owner, scope, req.Expires)
+
+	expires := int64(math.MaxInt64)
+	if !req.Expires.IsZero() {
+		expires = req.Expires.UnixNano()
+	}
+	tok := newAPIToken(scopedToken{expires: expires, owner: owner, scope: scope})
+	tok.Token = token
+	return tok, nil
+}
+
+// list returns the valid API tokens, oldest first.
+func (m *apiTokenManager) list() []apiToken {
+	scoped := m.tokens.Scoped()
+	tokens := make([]apiToken, 0, len(scoped))
+	for _, tok := range scoped {
+		tokens = append(tokens, newAPIToken(tok))
+	}
+	slices.SortFunc(tokens, func(a, b apiToken) int {
+		return a.Created.Compare(b.Created)
+	})
+	return tokens
+}
+
+// revoke removes the API token with the given ID, returning false if there
+// is none.
+func (m *apiTokenManager) revoke(id string) bool {
+	for _, tok := range m.tokens.Scoped() {
+		if tok.scope.Id == id {
+			m.tokens.Delete(tok.token)
+			return true
+		}
+	}
+	return false
+}
+
+// principal returns who the API token belongs to, if it is valid for the
+// request, and records its use.
+func (m *apiTokenManager) principal(token string, r *http.Request) (principal, bool) {
+	scope, ok := m.tokens.Scope(token)
+	if !ok || !m.tokens.Check(token) || !scopeAllows(scope, r) {
+		return principal{}, false
+	}
+	owner, _ := m.tokens.Owner(token)
+	remoteAddr, _ := remoteAddress(r)
+	m.tokens.Used(token, remoteAddr)
+	return ownerPrincipal(owner), true
+}
+
+func scopeAllows(scope *apiproto.TokenScope, r *http.Request) bool {
+	if len(scope.Methods) > 0 && !slices.Contains(scope.Methods, r.Method) {
+		return false
+	}
+	if len(scope.Endpoints) > 0 && !slices.ContainsFunc(scope.Endpoints, func(endpoint string) bool {
+		return r.URL.Path == endpoint || strings.HasPrefix(r.URL.Path, strings.TrimSuffix(endpoint, "/")+"/")
+	}) {
+		return false
+	}
+	if len(scope.Networks) > 0 {
+		// The actual address of the connection, as anyone may claim
+		// to be forwarding for someone else.
+		ip := osutil.IPFromString(r.RemoteAddr)
+		if ip == nil {
+			return false
+		}
+		return slices.ContainsFunc(scope.Networks, func(network string) bool {
+			_, ipnet, err := net.ParseCIDR(network)
+			return err == nil && ipnet.Contains(ip)
+		})
+	}
+	return true
+}
+
+func newAPIToken(tok scopedToken) apiToken {
+	p := ownerPrincipal(tok.owner)
+	at := apiToken{
+		ID:           tok.scope.Id,
+		Name:         tok.scope.Name,
+		Role:         p.role,
+		Folders:      p.folders,
+		Endpoints:    tok.scope.Endpoints,
+		Methods:      tok.scope.Methods,
+		Networks:     tok.scope.Networks,
+		Created:      time.Unix(0, tok.scope.Created),
+		LastUsedFrom: tok.scope.LastUsedFrom,
+	}
+	if tok.expires != math.MaxInt64 {
+		expires := time.Unix(0, tok.expires)
+		at.Expires = &expires
+	}
+	if tok.scope.LastUsed != 0 {
+		lastUsed := time.Unix(0, tok.scope.LastUsed)
+		at.LastUsed = &lastUsed
+	}
+	return at
+}
+
+func (s *service) getAPITokens(w http.ResponseWriter, _ *http.Request) {
+	sendJSON(w, s.apiTokens.list())
+}
+
+func (s *service) postAPIToken(w http.ResponseWriter, r *http.Request) {
+	var req apiTokenRequest
+	if err := unmarshalTo(r.Body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	tok, err := s.apiTokens.create(requestPrincipal(r, s.cfg.GUI(), s.apiTokens), req)
+	s.userAction("apiToken.create", r, map[string]any{"id": tok.ID, "name": req.Name}, err)
+	if errors.Is(err, errTooManyAPITokens) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	sendJSON(w, tok)
+}
+
+func (s *service) deleteAPIToken(w http.ResponseWriter, r *http.Request) {
+	id := r.URL.Query().Get("id")
+	if !s.apiTokens.revoke(id) {
+		http.Error(w, "No such API token", http.StatusNotFound)
+		return
+	}
+	s.userAction("apiToken.revoke", r, map[string]any{"id": id}, nil)
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+func TestAPITokens(t *testing.T) {
+	t.Parallel()
+
+	cfg := newMockedConfig()
+	cfg.GUIReturns(config.GUIConfiguration{RawAddress: "127.0.0.1:0", APIKey: testAPIKey})
+	baseURL, cancel, err := startHTTP(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cancel)
+
+	create := func(req apiTokenRequest) apiToken {
+		t.Helper()
+		resp := httpRequest(http.MethodPost, baseURL+"/rest/system/apitokens", req, "", "", testAPIKey, "", "", "", nil, t)
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Unexpected status %d creating token", resp.StatusCode)
+		}
+		var tok apiToken
+		if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+			t.Fatal(err)
+		}
+		if tok.Token == "" || tok.ID == "" {
+			t.Fatal("Missing token or ID")
+		}
+		return tok
+	}
+	list := func() []apiToken {
+		t.Helper()
+		resp := httpGet(baseURL+"/rest/system/apitokens", "", "", testAPIKey, "", nil, t)
+		defer resp.Body.Close()
+		var tokens []apiToken
+		if err := json.NewDecoder(resp.Body).Decode(&tokens); err != nil {
+			t.Fatal(err)
+		}
+		return tokens
+	}
+	status := func(method, path, token string) int {
+		t.Helper()
+		resp := httpRequest(method, baseURL+path, nil, "", "", "", token, "", "", nil, t)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	admin, monitorRole := config.GUIRoleAdmin, config.GUIRoleMonitor
+	scoped := create(apiTokenRequest{
+		Name:      "scripts",
+		Role:      &admin,
+		Endpoints: []string{"/rest/system/"},
+		Methods:   []string{"get"},
+		Networks:  []string{"127.0.0.0/8", "::1/128"},
+		Expires:   time.Now().Add(time.Hour),
+	})
+	elsewhere := create(apiTokenRequest{Name: "elsewhere", Role: &admin, Networks: []string{"192.0.2.0/24"}})
+	monitor := create(apiTokenRequest{Name: "monitor", Role: &monitorRole})
+
+	cases := []struct {
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{http.MethodGet, "/rest/system/version", scoped.Token, http.StatusOK},
+		{http.MethodGet, "/rest/config", scoped.Token, http.StatusForbidden},
+		{http.MethodPost, "/rest/system/ping", scoped.Token, http.StatusForbidden},
+		{http.MethodGet, "/rest/system/version", elsewhere.Token, http.StatusForbidden},
+		{http.MethodGet, "/rest/system/version", monitor.Token, http.StatusOK},
+		{http.MethodGet, "/rest/system/apitokens", monitor.Token, http.StatusForbidden},
+		{http.MethodGet, "/rest/system/version", "nosuchtoken", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		if got := status(tc.method, tc.path, tc.token); got != tc.status {
+			t.Errorf("%s %s: got status %d, expected %d", tc.method, tc.path, got, tc.status)
+		}
+	}
+
+	tokens := list()
+	if len(tokens) != 3 {
+		t.Fatalf("Expected 3 tokens, got %d", len(tokens))
+	}
+	for _, tok := range tokens {
+		if tok.Token != "" {
+			t.Error("Token revealed in list")
+		}
+		switch tok.ID {
+		case scoped.ID:
+			if tok.LastUsed == nil || tok.LastUsedFrom == "" || tok.Expires == nil {
+				t.Errorf("Unexpected scoped token %+v", tok)
+			}
+		case elsewhere.ID:
+			if tok.LastUsed != nil || tok.Expires != nil {
+				t.Errorf("Unexpected token from elsewhere %+v", tok)
+			}
+		}
+	}
+
+	resp := httpRequest(http.MethodDelete, baseURL+"/rest/system/apitokens?id="+scoped.ID, nil, "", "", testAPIKey, "", "", "", nil, t)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Unexpected status %d revoking token", resp.StatusCode)
+	}
+	if got := status(http.MethodGet, "/rest/system/version", scoped.Token); got != http.StatusForbidden {
+		t.Errorf("Revoked token got status %d", got)
+	}
+	if len(list()) != 2 {
+		t.Error("Revoked token still listed")
+	}
+}
+
+func TestAPITokenRoleLimits(t *testing.T) {
+	t.Parallel()
+
+	mdb, _ := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	t.Cleanup(func() { mdb.Close() })
+	m := newAPITokenManager(db.NewMiscDataNamespace(mdb))
+
+	admin, operator, monitor := config.GUIRoleAdmin, config.GUIRoleOperator, config.GUIRoleMonitor
+	op := principal{username: "op", role: config.GUIRoleOperator, folders: []string{"default"}}
+
+	cases := []struct {
+		name   string
+		caller principal
+		req    apiTokenRequest
+		ok     bool
+	}{
+		{"no role", adminPrincipal, apiTokenRequest{Name: "a"}, false},
+		{"admin grants admin", adminPrincipal, apiTokenRequest{Name: "b", Role: &admin}, true},
+		{"operator grants admin", op, apiTokenRequest{Name: "c", Role: &admin}, false},
+		{"operator grants operator", op, apiTokenRequest{Name: "d", Role: &operator}, true},
+		{"operator grants other folder", op, apiTokenRequest{Name: "e", Role: &operator, Folders: []string{"other"}}, false},
+		{"operator grants monitor", op, apiTokenRequest{Name: "f", Role: &monitor}, true},
+	}
+	for _, tc := range cases {
+		tok, err := m.create(tc.caller, tc.req)
+		if (err == nil) != tc.ok {
+			t.Errorf("%s: got error %v, expected ok %v", tc.name, err, tc.ok)
+		}
+		if tc.name == "operator grants operator" && err == nil && len(tok.Folders) != 1 {
+			t.Errorf("%s: token not limited to the caller's folders: %v", tc.name, tok.Folders)
+		}
+	}
+}
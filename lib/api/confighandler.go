@@ -10,23 +10,86 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+	"slices"
 
 	"github.com/julienschmidt/httprouter"
 
 	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
 	"github.com/syncthing/syncthing/lib/protocol"
 	"github.com/syncthing/syncthing/lib/structutil"
 )
 
+// redactedSecret replaces secrets that whoever makes the request may not
+// see.
+const redactedSecret = "<redacted>"
+
+// configMuxBuilder registers the config routes. Monitors may look at the
+// config, apart from the GUI and LDAP settings and the encryption passwords,
+// but only admins may change it.
 type configMuxBuilder struct {
-	*httprouter.Router
-	id  protocol.DeviceID
-	cfg config.Wrapper
+	roleMux
+	id       protocol.DeviceID
+	cfg      config.Wrapper
+	evLogger events.Logger
+}
+
+func (c *configMuxBuilder) monitor() roleMux {
+	return c.withRole(config.GUIRoleMonitor)
+}
+
+func (c *configMuxBuilder) isAdmin(r *http.Request) bool {
+	return requestPrincipal(r, c.guiCfg, c.apiTokens).role == config.GUIRoleAdmin
+}
+
+// rawCopyFor returns the config as whoever makes the request may see it.
+func (c *configMuxBuilder) rawCopyFor(r *http.Request) config.Configuration {
+	cfg := c.cfg.RawCopy()
+	if !c.isAdmin(r) {
+		cfg = redactedConfig(cfg)
+	}
+	return cfg
+}
+
+// folderFor returns the folder as whoever makes the request may see it.
+func (c *configMuxBuilder) folderFor(r *http.Request, folder config.FolderConfiguration) config.FolderConfiguration {
+	if !c.isAdmin(r) {
+		folder = redactedFolder(folder)
+	}
+	return folder
+}
+
+// redactedConfig returns the config without the secrets only admins may
+// see. The config is modified in place.
+func redactedConfig(cfg config.Configuration) config.Configuration {
+	cfg.GUI = config.GUIConfiguration{}
+	cfg.LDAP = config.LDAPConfiguration{}
+	for i := range cfg.Folders {
+		cfg.Folders[i] = redactedFolder(cfg.Folders[i])
+	}
+	cfg.Defaults.Folder = redactedFolder(cfg.Defaults.Folder)
+	return cfg
+}
+
+// redactedFolder returns the folder with the filesystem secret and the
+// encryption passwords masked, so that it's still visible that there are
+// any.
+func redactedFolder(folder config.FolderConfiguration) config.FolderConfiguration {
+	if folder.FilesystemSecret != "" {
+		folder.FilesystemSecret = redactedSecret
+	}
+	folder.Devices = slices.Clone(folder.Devices)
+	for i := range folder.Devices {
+		if folder.Devices[i].EncryptionPassword != "" {
+			folder.Devices[i].EncryptionPassword = redactedSecret
+		}
+	}
+	return folder
 }
 
 func (c *configMuxBuilder) registerConfig(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.RawCopy())
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		sendJSON(w, c.rawCopyFor(r))
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -35,8 +98,8 @@ func (c *configMuxBuilder) registerConfig(path string) {
 }
 
 func (c *configMuxBuilder) registerConfigDeprecated(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.RawCopy())
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		sendJSON(w, c.rawCopyFor(r))
 	})
 
 	c.HandlerFunc(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
@@ -45,20 +108,24 @@ func (c *configMuxBuilder) registerConfigDeprecated(path string) {
 }
 
 func (c *configMuxBuilder) registerConfigInsync(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, map[string]bool{"configInSync": !c.cfg.RequiresRestart()})
 	})
 }
 
 func (c *configMuxBuilder) registerConfigRequiresRestart(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, map[string]bool{"requiresRestart": c.cfg.RequiresRestart()})
 	})
 }
 
 func (c *configMuxBuilder) registerFolders(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.FolderList())
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		folders := c.cfg.FolderList()
+		for i := range folders {
+			folders[i] = c.folderFor(r, folders[i])
+		}
+		sendJSON(w, folders)
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -83,7 +150,7 @@ func (c *configMuxBuilder) registerFolders(path string) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		c.finish(w, waiter)
+		c.finish(w, r, waiter)
 	})
 
 	c.HandlerFunc(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
@@ -92,7 +159,7 @@ func (c *configMuxBuilder) registerFolders(path string) {
 }
 
 func (c *configMuxBuilder) registerDevices(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, c.cfg.DeviceList())
 	})
 
@@ -118,7 +185,7 @@ func (c *configMuxBuilder) registerDevices(path string) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		c.finish(w, waiter)
+		c.finish(w, r, waiter)
 	})
 
 	c.HandlerFunc(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
@@ -127,13 +194,13 @@ func (c *configMuxBuilder) registerDevices(path string) {
 }
 
 func (c *configMuxBuilder) registerFolder(path string) {
-	c.Handle(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.monitor().Handle(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		folder, ok := c.cfg.Folder(p.ByName("id"))
 		if !ok {
 			http.Error(w, "No folder with given ID", http.StatusNotFound)
 			return
 		}
-		sendJSON(w, folder)
+		sendJSON(w, c.folderFor(r, folder))
 	})
 
 	c.Handle(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
@@ -149,13 +216,13 @@ func (c *configMuxBuilder) registerFolder(path string) {
 		c.adjustFolder(w, r, folder, false)
 	})
 
-	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		waiter, err := c.cfg.RemoveFolder(p.ByName("id"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		c.finish(w, waiter)
+		c.finish(w, r, waiter)
 	})
 }
 
@@ -174,7 +241,7 @@ func (c *configMuxBuilder) registerDevice(path string) {
 		return device, true
 	}
 
-	c.Handle(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.monitor().Handle(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
 		if device, ok := deviceFromParams(w, p); ok {
 			sendJSON(w, device)
 		}
@@ -190,7 +257,7 @@ func (c *configMuxBuilder) registerDevice(path string) {
 		}
 	})
 
-	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, _ *http.Request, p httprouter.Params) {
+	c.Handle(http.MethodDelete, path, func(w http.ResponseWriter, r *http.Request, p httprouter.Params) {
 		id, err := protocol.DeviceIDFromString(p.ByName("id"))
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
@@ -201,13 +268,13 @@ func (c *configMuxBuilder) registerDevice(path string) {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
-		c.finish(w, waiter)
+		c.finish(w, r, waiter)
 	})
 }
 
 func (c *configMuxBuilder) registerDefaultFolder(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
-		sendJSON(w, c.cfg.DefaultFolder())
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, r *http.Request) {
+		sendJSON(w, c.folderFor(r, c.cfg.DefaultFolder()))
 	})
 
 	c.HandlerFunc(http.MethodPut, path, func(w http.ResponseWriter, r *http.Request) {
@@ -222,7 +289,7 @@ func (c *configMuxBuilder) registerDefaultFolder(path string) {
 }
 
 func (c *configMuxBuilder) registerDefaultDevice(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, c.cfg.DefaultDevice())
 	})
 
@@ -238,7 +305,7 @@ func (c *configMuxBuilder) registerDefaultDevice(path string) {
 }
 
 func (c *configMuxBuilder) registerDefaultIgnores(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, c.cfg.DefaultIgnores())
 	})
 
@@ -255,12 +322,12 @@ func (c *configMuxBuilder) registerDefaultIgnores(path string) {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
-		c.finish(w, waiter)
+		c.finish(w, r, waiter)
 	})
 }
 
 func (c *configMuxBuilder) registerOptions(path string) {
-	c.HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
+	c.monitor().HandlerFunc(http.MethodGet, path, func(w http.ResponseWriter, _ *http.Request) {
 		sendJSON(w, c.cfg.Options())
 	})
 
@@ -331,7 +398,7 @@ func (c *configMuxBuilder) adjustConfig(w http.ResponseWriter, r *http.Request)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 func (c *configMuxBuilder) adjustFolder(w http.ResponseWriter, r *http.Request, folder config.FolderConfiguration, defaults bool) {
@@ -350,7 +417,7 @@ func (c *configMuxBuilder) adjustFolder(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 func (c *configMuxBuilder) adjustDevice(w http.ResponseWriter, r *http.Request, device config.DeviceConfiguration, defaults bool) {
@@ -369,7 +436,7 @@ func (c *configMuxBuilder) adjustDevice(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 func (c *configMuxBuilder) adjustOptions(w http.ResponseWriter, r *http.Request, opts config.OptionsConfiguration) {
@@ -384,7 +451,7 @@ func (c *configMuxBuilder) adjustOptions(w http.ResponseWriter, r *http.Request,
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 func (c *configMuxBuilder) adjustGUI(w http.ResponseWriter, r *http.Request, gui config.GUIConfiguration) {
@@ -409,7 +476,7 @@ func (c *configMuxBuilder) adjustGUI(w http.ResponseWriter, r *http.Request, gui
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 func (c *configMuxBuilder) postAdjustGui(from *config.GUIConfiguration, to *config.GUIConfiguration) error {
@@ -419,6 +486,13 @@ func (c *configMuxBuilder) postAdjustGui(from *config.GUIConfiguration, to *conf
 			return err
 		}
 	}
+	for i := range to.Users {
+		// Hashes are kept as they are.
+		if err := to.Users[i].SetPassword(to.Users[i].Password); err != nil {
+			l.Warnln("hashing password:", err)
+			return err
+		}
+	}
 	return nil
 }
 
@@ -434,7 +508,7 @@ func (c *configMuxBuilder) adjustLDAP(w http.ResponseWriter, r *http.Request, ld
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	c.finish(w, waiter)
+	c.finish(w, r, waiter)
 }
 
 // Unmarshals the content of the given body and stores it in to (i.e. to must be a pointer).
@@ -453,10 +527,14 @@ func unmarshalToRawMessages(body io.ReadCloser) ([]json.RawMessage, error) {
 	return data, err
 }
 
-func (c *configMuxBuilder) finish(w http.ResponseWriter, waiter config.Waiter) {
+func (c *configMuxBuilder) finish(w http.ResponseWriter, r *http.Request, waiter config.Waiter) {
 	waiter.Wait()
-	if err := c.cfg.Save(); err != nil {
+	err := c.cfg.Save()
+	if err != nil {
 		l.Warnln("Saving config:", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 	}
+	// After saving, so that the audit log can tell who made the change it
+	// has just seen.
+	emitUserAction("config.change", requestPrincipal(r, c.guiCfg, c.apiTokens), r, nil, err, c.evLogger)
 }
@@ -7,6 +7,7 @@
 package api
 
 import (
+	"math"
 	"net/http"
 	"slices"
 	"strings"
@@ -43,6 +44,12 @@ func newTokenManager(key string, miscDB *db.NamespacedKV, lifetime time.Duration
 	if tokens.Tokens == nil {
 		tokens.Tokens = make(map[string]int64)
 	}
+	if tokens.Owners == nil {
+		tokens.Owners = make(map[string]*apiproto.TokenOwner)
+	}
+	if tokens.Scopes == nil {
+		tokens.Scopes = make(map[string]*apiproto.TokenScope)
+	}
 	return &tokenManager{
 		key:      key,
 		miscDB:   miscDB,
@@ -55,7 +62,7 @@ func newTokenManager(key string, miscDB *db.NamespacedKV, lifetime time.Duration
 }
 
 // Check returns true if the token is valid, and updates the token's expiry
-// time. The token is removed if it is expired.
+// time unless it is a scoped one. The token is removed if it is expired.
 func (m *tokenManager) Check(token string) bool {
 	m.mut.Lock()
 	defer m.mut.Unlock()
@@ -68,35 +75,156 @@ func (m *tokenManager) Check(token string) bool {
 			return false
 		}
 
-		// Give the token further life.
-		m.tokens.Tokens[token] = m.timeNow().Add(m.lifetime).UnixNano()
-		m.saveLocked()
+		if _, scoped := m.tokens.Scopes[token]; !scoped {
+			// Give the token further life.
+			m.tokens.Tokens[token] = m.timeNow().Add(m.lifetime).UnixNano()
+			m.saveLocked()
+		}
 	}
 	return ok
 }
 
 // New creates a new token and returns it.
 func (m *tokenManager) New() string {
+	return m.NewOwned(nil)
+}
+
+// NewOwned creates a new token issued to the given owner and returns it.
+func (m *tokenManager) NewOwned(owner *apiproto.TokenOwner) string {
 	token := rand.String(randomTokenLength)
 
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
 	m.tokens.Tokens[token] = m.timeNow().Add(m.lifetime).UnixNano()
+	if owner != nil {
+		m.tokens.Owners[token] = owner
+	}
 	m.saveLocked()
 
 	return token
 }
 
+// NewScoped creates a new token with the given limits, that expires at the
+// given time instead of after the lifetime, never if the time is zero, and
+// returns it.
+func (m *tokenManager) NewScoped(owner *apiproto.TokenOwner, scope *apiproto.TokenScope, expires time.Time) string {
+	token := rand.String(randomTokenLength)
+
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	m.tokens.Tokens[token] = math.MaxInt64
+	if !expires.IsZero() {
+		m.tokens.Tokens[token] = expires.UnixNano()
+	}
+	if owner != nil {
+		m.tokens.Owners[token] = owner
+	}
+	m.tokens.Scopes[token] = scope
+	m.saveLocked()
+
+	return token
+}
+
+// A scopedToken is a token created with NewScoped, as it is now.
+type scopedToken struct {
+	token   string
+	expires int64 // epoch nanoseconds, math.MaxInt64 for never
+	owner   *apiproto.TokenOwner
+	scope   *apiproto.TokenScope
+}
+
+// Scoped returns the valid scoped tokens.
+func (m *tokenManager) Scoped() []scopedToken {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	now := m.timeNow().UnixNano()
+	var tokens []scopedToken
+	for token, scope := range m.tokens.Scopes {
+		expires := m.tokens.Tokens[token]
+		if expires < now {
+			continue
+		}
+		tokens = append(tokens, scopedToken{
+			token:   token,
+			expires: expires,
+			owner:   proto.Clone(m.tokens.Owners[token]).(*apiproto.TokenOwner),
+			scope:   proto.Clone(scope).(*apiproto.TokenScope),
+		})
+	}
+	return tokens
+}
+
+// Scope returns the limits of a scoped token.
+func (m *tokenManager) Scope(token string) (*apiproto.TokenScope, bool) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	scope, ok := m.tokens.Scopes[token]
+	if !ok {
+		return nil, false
+	}
+	return proto.Clone(scope).(*apiproto.TokenScope), true
+}
+
+// Used records that a scoped token was just used, from the given address.
+func (m *tokenManager) Used(token, from string) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	if scope, ok := m.tokens.Scopes[token]; ok {
+		scope.LastUsed = m.timeNow().UnixNano()
+		scope.LastUsedFrom = from
+		m.saveLocked()
+	}
+}
+
+// Owner returns who the token was issued to, if it was created with an
+// owner.
+func (m *tokenManager) Owner(token string) (*apiproto.TokenOwner, bool) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	owner, ok := m.tokens.Owners[token]
+	return owner, ok
+}
+
 // Delete removes a token.
 func (m *tokenManager) Delete(token string) {
 	m.mut.Lock()
 	defer m.mut.Unlock()
 
 	delete(m.tokens.Tokens, token)
+	delete(m.tokens.Owners, token)
+	delete(m.tokens.Scopes, token)
 	m.saveLocked()
 }
 
+// DeleteOwnedBy removes the tokens issued to the given user.
+func (m *tokenManager) DeleteOwnedBy(username string) {
+	m.deleteOwnedFunc(func(owner string) bool { return owner == username })
+}
+
+// DeleteOwnedWithPrefix removes the tokens issued to any user whose name
+// starts with the prefix.
+func (m *tokenManager) DeleteOwnedWithPrefix(prefix string) {
+	m.deleteOwnedFunc(func(owner string) bool { return strings.HasPrefix(owner, prefix) })
+}
+
+func (m *tokenManager) deleteOwnedFunc(match func(username string) bool) {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	for token, owner := range m.tokens.Owners {
+		if match(owner.Username) {
+			delete(m.tokens.Tokens, token)
+		}
+	}
+	m.saveLocked() // forgets the owners of removed tokens
+}
+
 func (m *tokenManager) saveLocked() {
 	// Remove expired tokens.
 	now := m.timeNow().UnixNano()
@@ -126,6 +254,18 @@ func (m *tokenManager) saveLocked() {
 		}
 	}
 
+	// Forget the owners and scopes of removed tokens.
+	for token := range m.tokens.Owners {
+		if _, ok := m.tokens.Tokens[token]; !ok {
+			delete(m.tokens.Owners, token)
+		}
+	}
+	for token := range m.tokens.Scopes {
+		if _, ok := m.tokens.Tokens[token]; !ok {
+			delete(m.tokens.Scopes, token)
+		}
+	}
+
 	// Postpone saving until one second of inactivity.
 	if m.saveTimer == nil {
 		m.saveTimer = time.AfterFunc(time.Second, m.scheduledSave)
@@ -152,28 +292,22 @@ type tokenCookieManager struct {
 	tokens     *tokenManager
 }
 
-func newTokenCookieManager(shortID string, guiCfg config.GUIConfiguration, evLogger events.Logger, miscDB *db.NamespacedKV) *tokenCookieManager {
+func newSessionManager(miscDB *db.NamespacedKV) *tokenManager {
+	return newTokenManager("sessions", miscDB, maxSessionLifetime, maxActiveSessions)
+}
+
+func newTokenCookieManager(shortID string, guiCfg config.GUIConfiguration, evLogger events.Logger, sessions *tokenManager) *tokenCookieManager {
 	return &tokenCookieManager{
 		cookieName: "sessionid-" + shortID,
 		shortID:    shortID,
 		guiCfg:     guiCfg,
 		evLogger:   evLogger,
-		tokens:     newTokenManager("sessions", miscDB, maxSessionLifetime, maxActiveSessions),
+		tokens:     sessions,
 	}
 }
 
-func (m *tokenCookieManager) createSession(username string, persistent bool, w http.ResponseWriter, r *http.Request) {
-	sessionid := m.tokens.New()
-
-	// Best effort detection of whether the connection is HTTPS --
-	// either directly to us, or as used by the client towards a reverse
-	// proxy who sends us headers.
-	connectionIsHTTPS := r.TLS != nil ||
-		strings.ToLower(r.Header.Get("x-forwarded-proto")) == "https" ||
-		strings.Contains(strings.ToLower(r.Header.Get("forwarded")), "proto=https")
-	// If the connection is HTTPS, or *should* be HTTPS, set the Secure
-	// bit in cookies.
-	useSecureCookie := connectionIsHTTPS || m.guiCfg.UseTLS()
+func (m *tokenCookieManager) createSession(p principal, persistent bool, w http.ResponseWriter, r *http.Request) {
+	sessionid := m.tokens.NewOwned(p.owner())
 
 	maxAge := 0
 	if persistent {
@@ -185,14 +319,16 @@ func (m *tokenCookieManager) createSession(username string, persistent bool, w h
 		// In HTTP spec Max-Age <= 0 means delete immediately,
 		// but in http.Cookie MaxAge = 0 means unspecified (session) and MaxAge < 0 means delete immediately
 		MaxAge: maxAge,
-		Secure: useSecureCookie,
+		Secure: useSecureCookie(r, m.guiCfg),
 		Path:   "/",
 	})
 
-	emitLoginAttempt(true, username, r, m.evLogger)
+	emitLoginAttempt(true, p, r, m.evLogger)
 }
 
-func (m *tokenCookieManager) hasValidSession(r *http.Request) bool {
+// validSession returns who is logged in to the session the request belongs
+// to, if any.
+func (m *tokenCookieManager) validSession(r *http.Request) (principal, bool) {
 	for _, cookie := range r.Cookies() {
 		// We iterate here since there may, historically, be multiple
 		// cookies with the same name but different path. Any "old" ones
@@ -200,11 +336,16 @@ func (m *tokenCookieManager) hasValidSession(r *http.Request) bool {
 		// later removed on logout or when timing out.
 		if cookie.Name == m.cookieName {
 			if m.tokens.Check(cookie.Value) {
-				return true
+				if owner, ok := m.tokens.Owner(cookie.Value); ok {
+					return ownerPrincipal(owner), true
+				}
+				// Sessions from before roles were a thing are admin
+				// sessions.
+				return adminPrincipal, true
 			}
 		}
 	}
-	return false
+	return principal{}, false
 }
 
 func (m *tokenCookieManager) destroySession(w http.ResponseWriter, r *http.Request) {
@@ -226,3 +367,15 @@ func (m *tokenCookieManager) destroySession(w http.ResponseWriter, r *http.Reque
 		}
 	}
 }
+
+// useSecureCookie returns whether cookies should have the Secure bit set,
+// i.e., whether the connection is HTTPS or *should* be HTTPS.
+func useSecureCookie(r *http.Request, guiCfg config.GUIConfiguration) bool {
+	// Best effort detection of whether the connection is HTTPS --
+	// either directly to us, or as used by the client towards a reverse
+	// proxy who sends us headers.
+	connectionIsHTTPS := r.TLS != nil ||
+		strings.ToLower(r.Header.Get("x-forwarded-proto")) == "https" ||
+		strings.Contains(strings.ToLower(r.Header.Get("forwarded")), "proto=https")
+	return connectionIsHTTPS || guiCfg.UseTLS()
+}
@@ -0,0 +1,294 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package audit keeps a log of who did what and when: config changes,
+// logins, actions taken on folders and devices, and file deletions. The log
+// has one JSON record per line, in the schema given by Record.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+)
+
+// SchemaVersion is increased whenever the meaning of an existing field of
+// Record changes. Fields may be added without changing it.
+const SchemaVersion = 1
+
+// The kinds of actors.
+const (
+	ActorUser   = "user"   // someone using the GUI or REST API
+	ActorDevice = "device" // a remote device
+	ActorLocal  = "local"  // someone or something changing files on disk
+	ActorSystem = "system" // Syncthing itself, or someone editing the config file
+)
+
+// The outcomes of an action.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// The actions that are recorded, besides the ones the REST API reports as
+// user actions, such as "folder.override".
+const (
+	ActionLogin        = "login"
+	ActionConfigChange = "config.change"
+	ActionDeviceAccept = "device.accept" // the device was added to the config
+	ActionFileDelete   = "file.delete"
+)
+
+// A Record is an entry in the audit log.
+type Record struct {
+	Schema  int       `json:"schema"`
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Outcome string    `json:"outcome"`
+	Actor   Actor     `json:"actor"`
+	// What the action was about, where that applies.
+	Folder string `json:"folder,omitempty"`
+	Device string `json:"device,omitempty"`
+	Path   string `json:"path,omitempty"`
+	// For config changes, the changed values.
+	Changes []Change       `json:"changes,omitempty"`
+	Error   string         `json:"error,omitempty"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+// An Actor is who did something.
+type Actor struct {
+	Type          string `json:"type"`
+	Username      string `json:"username,omitempty"`
+	Role          string `json:"role,omitempty"`
+	RemoteAddress string `json:"remoteAddress,omitempty"`
+	Proxy         string `json:"proxy,omitempty"`
+	Device        string `json:"device,omitempty"`
+}
+
+// A Change is a value in the config that changed, by its path in the JSON
+// form of the config. Secrets are redacted.
+type Change struct {
+	Path   string `json:"path"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// The REST API reports the config change it made right after saving, so a
+// config change is attributed to the user if that happens within this time.
+const configAttributionTimeout = time.Second
+
+// The Service subscribes to events and writes audit records for them to
+// the specified writer.
+type Service struct {
+	w        io.Writer
+	evLogger events.Logger
+	cfg      config.Wrapper
+}
+
+func NewService(w io.Writer, evLogger events.Logger, cfg config.Wrapper) *Service {
+	return &Service{
+		w:        w,
+		evLogger: evLogger,
+		cfg:      cfg,
+	}
+}
+
+func (s *Service) Serve(ctx context.Context) error {
+	sub := s.evLogger.Subscribe(events.ConfigSaved | events.LoginAttempt | events.UserAction | events.LocalChangeDetected | events.RemoteChangeDetected)
+	defer sub.Unsubscribe()
+
+	enc := json.NewEncoder(s.w)
+	write := func(rec Record) {
+		rec.Schema = SchemaVersion
+		if err := enc.Encode(rec); err != nil {
+			l.Warnln("Writing audit record:", err)
+		}
+	}
+
+	prevCfg := s.cfg.RawCopy()
+	// Config changes waiting to be attributed to a user.
+	var pending []Record
+	pendingTimer := time.NewTimer(configAttributionTimeout)
+	pendingTimer.Stop()
+	flush := func(actor Actor) {
+		for _, rec := range pending {
+			rec.Actor = actor
+			write(rec)
+		}
+		pending = nil
+		pendingTimer.Stop()
+	}
+	defer flush(Actor{Type: ActorSystem})
+
+	for {
+		select {
+		case ev, ok := <-sub.C():
+			if !ok {
+				<-ctx.Done()
+				return ctx.Err()
+			}
+			switch ev.Type {
+			case events.ConfigSaved:
+				cfg, ok := ev.Data.(config.Configuration)
+				if !ok {
+					continue
+				}
+				flush(Actor{Type: ActorSystem})
+				pending = configRecords(ev.Time, prevCfg, cfg)
+				prevCfg = cfg
+				if len(pending) > 0 {
+					pendingTimer.Reset(configAttributionTimeout)
+				}
+			case events.UserAction:
+				rec, ok := userActionRecord(ev)
+				if !ok {
+					continue
+				}
+				if rec.Action == ActionConfigChange {
+					// The changes have been recorded from the config.
+					flush(rec.Actor)
+					if rec.Outcome == OutcomeSuccess {
+						continue
+					}
+				}
+				write(rec)
+			case events.LoginAttempt:
+				if rec, ok := loginRecord(ev); ok {
+					write(rec)
+				}
+			case events.LocalChangeDetected, events.RemoteChangeDetected:
+				if rec, ok := fileDeleteRecord(ev); ok {
+					write(rec)
+				}
+			}
+		case <-pendingTimer.C:
+			flush(Actor{Type: ActorSystem})
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *Service) String() string {
+	return fmt.Sprintf("audit.Service@%p", s)
+}
+
+// configRecords returns the records for a config change, without actor.
+func configRecords(t time.Time, before, after config.Configuration) []Record {
+	changes := diffConfig(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	recs := []Record{{
+		Time:    t,
+		Action:  ActionConfigChange,
+		Outcome: OutcomeSuccess,
+		Changes: changes,
+	}}
+	for _, dev := range after.Devices {
+		if _, _, ok := before.Device(dev.DeviceID); !ok {
+			recs = append(recs, Record{
+				Time:    t,
+				Action:  ActionDeviceAccept,
+				Outcome: OutcomeSuccess,
+				Device:  dev.DeviceID.String(),
+				Details: map[string]any{"name": dev.Name},
+			})
+		}
+	}
+	return recs
+}
+
+func userActionRecord(ev events.Event) (Record, bool) {
+	data, ok := ev.Data.(map[string]any)
+	if !ok {
+		return Record{}, false
+	}
+	rec := Record{
+		Time:    ev.Time,
+		Outcome: OutcomeSuccess,
+		Actor:   Actor{Type: ActorUser},
+	}
+	details := make(map[string]any)
+	for key, value := range data {
+		str, _ := value.(string)
+		switch key {
+		case "action":
+			rec.Action = str
+		case "success":
+			if success, _ := value.(bool); !success {
+				rec.Outcome = OutcomeFailure
+			}
+		case "error":
+			rec.Error = str
+		case "username":
+			rec.Actor.Username = str
+		case "role":
+			rec.Actor.Role = str
+		case "remoteAddress":
+			rec.Actor.RemoteAddress = str
+		case "proxy":
+			rec.Actor.Proxy = str
+		case "folder":
+			rec.Folder = str
+		case "device":
+			rec.Device = str
+		default:
+			details[key] = value
+		}
+	}
+	if len(details) > 0 {
+		rec.Details = details
+	}
+	return rec, rec.Action != ""
+}
+
+func loginRecord(ev events.Event) (Record, bool) {
+	data, ok := ev.Data.(map[string]any)
+	if !ok {
+		return Record{}, false
+	}
+	rec := Record{
+		Time:    ev.Time,
+		Action:  ActionLogin,
+		Outcome: OutcomeFailure,
+		Actor:   Actor{Type: ActorUser},
+	}
+	if success, _ := data["success"].(bool); success {
+		rec.Outcome = OutcomeSuccess
+	}
+	rec.Actor.Username, _ = data["username"].(string)
+	rec.Actor.Role, _ = data["role"].(string)
+	rec.Actor.RemoteAddress, _ = data["remoteAddress"].(string)
+	rec.Actor.Proxy, _ = data["proxy"].(string)
+	return rec, true
+}
+
+func fileDeleteRecord(ev events.Event) (Record, bool) {
+	data, ok := ev.Data.(map[string]string)
+	if !ok || data["action"] != "deleted" {
+		return Record{}, false
+	}
+	rec := Record{
+		Time:    ev.Time,
+		Action:  ActionFileDelete,
+		Outcome: OutcomeSuccess,
+		Actor:   Actor{Type: ActorLocal},
+		Folder:  data["folder"],
+		Path:    data["path"],
+		Details: map[string]any{"type": data["type"]},
+	}
+	if ev.Type == events.RemoteChangeDetected {
+		rec.Actor = Actor{Type: ActorDevice, Device: data["modifiedBy"]}
+	}
+	return rec, true
+}
@@ -0,0 +1,244 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/config/mocks"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func TestService(t *testing.T) {
+	evLogger := events.NewLogger()
+	ctx, cancel := context.WithCancel(context.Background())
+	go evLogger.Serve(ctx)
+	defer cancel()
+
+	before := config.Configuration{
+		GUI:     config.GUIConfiguration{APIKey: "secret"},
+		Folders: []config.FolderConfiguration{{ID: "default", Path: "/a"}},
+	}
+	cfg := &mocks.Wrapper{}
+	cfg.RawCopyReturns(before)
+
+	buf := &lockedBuffer{mut: sync.NewMutex()}
+	service := NewService(buf, evLogger, cfg)
+	auditCtx, auditCancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		service.Serve(auditCtx)
+		close(done)
+	}()
+
+	// Subscription needs to happen in service.Serve
+	time.Sleep(10 * time.Millisecond)
+
+	evLogger.Log(events.LoginAttempt, map[string]any{
+		"success":       true,
+		"username":      "jane",
+		"role":          "admin",
+		"remoteAddress": "192.0.2.1",
+	})
+	after := before.Copy()
+	after.Folders[0].Path = "/b"
+	after.GUI.APIKey = "newsecret"
+	after.Devices = []config.DeviceConfiguration{{DeviceID: protocol.LocalDeviceID, Name: "dev"}}
+	evLogger.Log(events.ConfigSaved, after)
+	evLogger.Log(events.UserAction, map[string]any{
+		"action":   "config.change",
+		"success":  true,
+		"username": "jane",
+		"role":     "admin",
+	})
+	evLogger.Log(events.UserAction, map[string]any{
+		"action":   "folder.override",
+		"success":  true,
+		"username": "joe",
+		"role":     "operator",
+		"folder":   "default",
+	})
+	evLogger.Log(events.RemoteChangeDetected, map[string]string{
+		"folder":     "default",
+		"action":     "deleted",
+		"type":       "file",
+		"path":       "foo",
+		"modifiedBy": "ABCDEFG",
+	})
+	// Not a deletion, not recorded.
+	evLogger.Log(events.LocalChangeDetected, map[string]string{
+		"folder": "default",
+		"action": "modified",
+		"type":   "file",
+		"path":   "bar",
+	})
+
+	// We need to give the events time to arrive, since the channels are buffered etc.
+	time.Sleep(50 * time.Millisecond)
+	auditCancel()
+	<-done
+
+	dec := json.NewDecoder(bytes.NewReader(buf.Bytes()))
+	var recs []Record
+	for dec.More() {
+		var rec Record
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatal(err)
+		}
+		recs = append(recs, rec)
+	}
+	t.Log(buf.String())
+
+	expected := []struct {
+		action string
+		actor  string
+		folder string
+	}{
+		{ActionLogin, "jane", ""},
+		{ActionConfigChange, "jane", ""},
+		{ActionDeviceAccept, "jane", ""},
+		{"folder.override", "joe", "default"},
+		{ActionFileDelete, "", "default"},
+	}
+	if len(recs) != len(expected) {
+		t.Fatalf("Got %d records, expected %d", len(recs), len(expected))
+	}
+	for i, exp := range expected {
+		rec := recs[i]
+		if rec.Schema != SchemaVersion || rec.Action != exp.action || rec.Actor.Username != exp.actor || rec.Folder != exp.folder {
+			t.Errorf("Record %d: got %+v, expected %+v", i, rec, exp)
+		}
+	}
+	if recs[4].Actor.Type != ActorDevice || recs[4].Actor.Device != "ABCDEFG" || recs[4].Path != "foo" {
+		t.Errorf("Unexpected file deletion record %+v", recs[4])
+	}
+
+	changes := make(map[string]Change)
+	for _, change := range recs[1].Changes {
+		changes[change.Path] = change
+	}
+	if change := changes["folders[default].path"]; change.Before != "/a" || change.After != "/b" {
+		t.Errorf("Unexpected folder path change %+v", change)
+	}
+	if change := changes["gui.apiKey"]; change.Before != redacted || change.After != redacted {
+		t.Errorf("Unexpected API key change %+v", change)
+	}
+}
+
+func TestDiffConfig(t *testing.T) {
+	before := config.Configuration{
+		GUI: config.GUIConfiguration{
+			Users: []config.GUIUser{{Name: "jane", Password: "hash1"}},
+		},
+		Folders: []config.FolderConfiguration{{ID: "a", Label: "A"}, {ID: "b"}},
+	}
+	after := before.Copy()
+	after.Folders = []config.FolderConfiguration{{ID: "b"}, {ID: "c"}}
+	after.GUI.Users = append(after.GUI.Users, config.GUIUser{Name: "joe", Password: "hash2"})
+
+	changes := make(map[string]Change)
+	for _, change := range diffConfig(before, after) {
+		changes[change.Path] = change
+	}
+	if len(changes) != 3 {
+		t.Errorf("Expected 3 changes, got %v", changes)
+	}
+	if change, ok := changes["folders[a]"]; !ok || change.After != nil {
+		t.Errorf("Unexpected change for removed folder %+v", change)
+	}
+	if change, ok := changes["folders[c]"]; !ok || change.Before != nil {
+		t.Errorf("Unexpected change for added folder %+v", change)
+	}
+	user, _ := changes["gui.users[joe]"].After.(map[string]any)
+	if user["password"] != redacted {
+		t.Errorf("Unredacted password of added user %+v", user)
+	}
+}
+
+func TestDiffConfigEncryptionPassword(t *testing.T) {
+	dev1 := protocol.DeviceID{1}
+	dev2 := protocol.DeviceID{2}
+	before := config.Configuration{
+		Folders: []config.FolderConfiguration{{
+			ID:      "a",
+			Devices: []config.FolderDeviceConfiguration{{DeviceID: dev1, EncryptionPassword: "secret1"}},
+		}},
+	}
+	after := before.Copy()
+	after.Folders[0].Devices[0].EncryptionPassword = "secret2"
+	after.Folders[0].Devices = append(after.Folders[0].Devices, config.FolderDeviceConfiguration{DeviceID: dev2, EncryptionPassword: "secret3"})
+	after.Folders = append(after.Folders, config.FolderConfiguration{
+		ID:      "b",
+		Devices: []config.FolderDeviceConfiguration{{DeviceID: dev1, EncryptionPassword: "secret4"}},
+	})
+
+	changes := diffConfig(before, after)
+	bs, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(bs, []byte("secret")) {
+		t.Errorf("Unredacted encryption password in %s", bs)
+	}
+	path := fmt.Sprintf("folders[a].devices[%s].encryptionPassword", dev1)
+	if !slices.ContainsFunc(changes, func(c Change) bool {
+		return c.Path == path && c.Before == redacted && c.After == redacted
+	}) {
+		t.Errorf("Missing redacted change of %s in %s", path, bs)
+	}
+}
+
+func TestDiffConfigFilesystemSecret(t *testing.T) {
+	before := config.Configuration{
+		Folders: []config.FolderConfiguration{{ID: "a", FilesystemSecret: "secret1"}},
+	}
+	after := before.Copy()
+	after.Folders[0].FilesystemSecret = "secret2"
+	if changes := diffConfig(before, after); len(changes) != 0 {
+		t.Errorf("Expected filesystem secret changes to be left out, got %+v", changes)
+	}
+
+	after.Folders = append(after.Folders, config.FolderConfiguration{ID: "b", FilesystemSecret: "secret3"})
+	changes := diffConfig(before, after)
+	bs, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || bytes.Contains(bs, []byte("ilesystemSecret")) {
+		t.Errorf("Expected the added folder without its filesystem secret, got %s", bs)
+	}
+}
+
+type lockedBuffer struct {
+	mut sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(bs []byte) (int, error) {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return b.buf.Write(bs)
+}
+
+func (b *lockedBuffer) Bytes() []byte {
+	b.mut.Lock()
+	defer b.mut.Unlock()
+	return bytes.Clone(b.buf.Bytes())
+}
+
+func (b *lockedBuffer) String() string {
+	return string(b.Bytes())
+}
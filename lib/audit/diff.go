@@ -0,0 +1,205 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+const redacted = "<redacted>"
+
+// Keys ending in one of these, regardless of case, are secrets in the JSON
+// form of the config. This covers "password", "encryptionPassword",
+// "apiKey" and "clientSecret".
+var secretKeySuffixes = []string{"password", "apikey", "secret"}
+
+// Keys that are left out of the diff entirely, as not even the fact that
+// they changed is for the audit log.
+var excludedKeys = []string{"filesystemSecret"}
+
+// Lists of objects with one of these keys, unique within the list, are
+// compared element by element, the elements being identified by the key.
+var listElementKeys = []string{"id", "deviceID", "name", "group"}
+
+// diffConfig returns the values that differ between two configs, by their
+// paths in the JSON form of the config, such as "folders[default].path".
+func diffConfig(before, after config.Configuration) []Change {
+	changes := diffValues("", jsonValue(before), jsonValue(after), nil)
+	for i := range changes {
+		changes[i].Before = redact(changes[i].Path, changes[i].Before)
+		changes[i].After = redact(changes[i].Path, changes[i].After)
+	}
+	return changes
+}
+
+// jsonValue returns the value as it would be decoded from JSON into an
+// interface value.
+func jsonValue(v any) any {
+	bs, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var res any
+	_ = json.Unmarshal(bs, &res)
+	return res
+}
+
+func diffValues(path string, before, after any, changes []Change) []Change {
+	switch b := before.(type) {
+	case map[string]any:
+		a, ok := after.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(a)+len(b))
+		for key := range b {
+			keys = append(keys, key)
+		}
+		for key := range a {
+			if _, ok := b[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		keys = slices.DeleteFunc(keys, func(key string) bool {
+			return slices.Contains(excludedKeys, key)
+		})
+		slices.Sort(keys)
+		for _, key := range keys {
+			changes = diffValues(joinPath(path, key), b[key], a[key], changes)
+		}
+		return changes
+
+	case []any:
+		a, ok := after.([]any)
+		if !ok {
+			break
+		}
+		key, ok := listElementKey(b, a)
+		if !ok {
+			break
+		}
+		bByKey := make(map[string]any, len(b))
+		for _, elem := range b {
+			bByKey[elemKey(elem, key)] = elem
+		}
+		aKeys := make(map[string]bool, len(a))
+		for _, elem := range a {
+			k := elemKey(elem, key)
+			aKeys[k] = true
+			changes = diffValues(fmt.Sprintf("%s[%s]", path, k), bByKey[k], elem, changes)
+		}
+		for _, elem := range b {
+			if k := elemKey(elem, key); !aKeys[k] {
+				changes = append(changes, Change{Path: fmt.Sprintf("%s[%s]", path, k), Before: elem})
+			}
+		}
+		return changes
+	}
+
+	if !reflect.DeepEqual(before, after) && !(isEmpty(before) && isEmpty(after)) {
+		changes = append(changes, Change{Path: path, Before: before, After: after})
+	}
+	return changes
+}
+
+// listElementKey returns the key identifying the elements of the lists, if
+// there is one.
+func listElementKey(lists ...[]any) (string, bool) {
+	if len(lists[0]) == 0 && len(lists[1]) == 0 {
+		return "", false
+	}
+nextKey:
+	for _, key := range listElementKeys {
+		for _, list := range lists {
+			seen := make(map[string]bool, len(list))
+			for _, elem := range list {
+				obj, ok := elem.(map[string]any)
+				if !ok {
+					return "", false
+				}
+				k, ok := obj[key].(string)
+				if !ok || seen[k] {
+					continue nextKey
+				}
+				seen[k] = true
+			}
+		}
+		return key, true
+	}
+	return "", false
+}
+
+func elemKey(elem any, key string) string {
+	k, _ := elem.(map[string]any)[key].(string)
+	return k
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// isEmpty returns true for nil and empty lists and objects, which are the
+// same as far as the config is concerned.
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	}
+	return false
+}
+
+// redact returns the value with the secrets in it replaced.
+func redact(path string, v any) any {
+	if isSecretKey(path) {
+		if s, ok := v.(string); ok && s != "" {
+			return redacted
+		}
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		res := make(map[string]any, len(v))
+		for key, elem := range v {
+			if slices.Contains(excludedKeys, key) {
+				continue
+			}
+			res[key] = redact(joinPath(path, key), elem)
+		}
+		return res
+	case []any:
+		res := make([]any, len(v))
+		for i, elem := range v {
+			res[i] = redact(path, elem)
+		}
+		return res
+	}
+	return v
+}
+
+// isSecretKey returns whether the last key of the path is that of a secret.
+func isSecretKey(path string) bool {
+	key := strings.ToLower(path[strings.LastIndex(path, ".")+1:])
+	for _, suffix := range secretKeySuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const rotatedTimeFormat = "20060102-150405"
+
+var rotatedSuffixExp = regexp.MustCompile(`^\.\d{8}-\d{6}(\.\d+)?$`)
+
+// A RotatingFile is a file that is rotated when writing to it would make it
+// larger than the maximum size, or when it is older than the maximum age.
+// Rotated files get the time of rotation appended to their name, and only
+// the newest few of them are kept.
+type RotatingFile struct {
+	path     string
+	maxSize  int64         // zero for no limit
+	maxAge   time.Duration // zero for no limit
+	maxFiles int           // rotated files to keep, zero for all
+	timeNow  func() time.Time
+
+	mut    sync.Mutex
+	fd     *os.File
+	size   int64
+	opened time.Time
+}
+
+// OpenRotatingFile opens the file for appending, creating it if it doesn't
+// exist.
+func OpenRotatingFile(path string, maxSize int64, maxAge time.Duration, maxFiles int) (*RotatingFile, error) {
+	f := &RotatingFile{
+		path:     filepath.Clean(path),
+		maxSize:  maxSize,
+		maxAge:   maxAge,
+		maxFiles: maxFiles,
+		timeNow:  time.Now,
+		mut:      sync.NewMutex(),
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *RotatingFile) Write(bs []byte) (int, error) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.fd == nil {
+		return 0, os.ErrClosed
+	}
+	tooLarge := f.maxSize > 0 && f.size > 0 && f.size+int64(len(bs)) > f.maxSize
+	tooOld := f.maxAge > 0 && f.timeNow().Sub(f.opened) > f.maxAge
+	if tooLarge || tooOld {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.fd.Write(bs)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *RotatingFile) Close() error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	if f.fd == nil {
+		return os.ErrClosed
+	}
+	err := f.fd.Close()
+	f.fd = nil
+	return err
+}
+
+func (f *RotatingFile) open() error {
+	fd, err := os.OpenFile(f.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := fd.Stat()
+	if err != nil {
+		fd.Close()
+		return err
+	}
+	f.fd = fd
+	f.size = info.Size()
+	f.opened = f.timeNow()
+	return nil
+}
+
+func (f *RotatingFile) rotate() error {
+	if err := f.fd.Close(); err != nil {
+		l.Debugln("Closing audit file for rotation:", err)
+	}
+	f.fd = nil
+
+	rotated := f.path + "." + f.timeNow().Format(rotatedTimeFormat)
+	for i := 1; ; i++ {
+		if _, err := os.Lstat(rotated); os.IsNotExist(err) {
+			break
+		}
+		rotated = fmt.Sprintf("%s.%s.%d", f.path, f.timeNow().Format(rotatedTimeFormat), i)
+	}
+	if err := os.Rename(f.path, rotated); err != nil {
+		// Keep writing to the same file rather than losing records.
+		l.Warnln("Rotating audit file:", err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.removeOld()
+	return nil
+}
+
+// removeOld removes the oldest rotated files beyond the number to keep.
+func (f *RotatingFile) removeOld() {
+	if f.maxFiles <= 0 {
+		return
+	}
+	matches, err := filepath.Glob(globEscape(f.path) + ".*")
+	if err != nil {
+		return
+	}
+	var rotated []string
+	for _, path := range matches {
+		if rotatedSuffixExp.MatchString(path[len(f.path):]) {
+			rotated = append(rotated, path)
+		}
+	}
+	if len(rotated) <= f.maxFiles {
+		return
+	}
+	// The names sort by the time of rotation, oldest first.
+	slices.Sort(rotated)
+	for _, path := range rotated[:len(rotated)-f.maxFiles] {
+		if err := os.Remove(path); err != nil {
+			l.Debugln("Removing rotated audit file:", err)
+		}
+	}
+}
+
+// globEscape escapes the glob metacharacters in a path.
+func globEscape(path string) string {
+	var escaped []rune
+	for _, r := range path {
+		switch r {
+		case '*', '?', '[':
+			escaped = append(escaped, '[', r, ']')
+		default:
+			escaped = append(escaped, r)
+		}
+	}
+	return string(escaped)
+}
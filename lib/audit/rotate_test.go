@@ -0,0 +1,65 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "audit.log")
+	// Not a rotated file, must be left alone.
+	if err := os.WriteFile(path+".bak", nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenRotatingFile(path, 10, time.Hour, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	f.timeNow = func() time.Time { return now }
+	f.opened = now
+
+	write := func(s string) {
+		t.Helper()
+		if _, err := f.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("12345")
+	write("12345") // fills the file exactly
+	write("1")     // by size
+	now = now.Add(time.Minute)
+	write("2")
+	now = now.Add(2 * time.Hour)
+	write("3") // by age
+	now = now.Add(2 * time.Hour)
+	write("4") // by age, dropping the oldest
+
+	bs, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bs) != "4" {
+		t.Errorf("Unexpected current file content %q", bs)
+	}
+	for _, name := range []string{"audit.log.20260601-140100", "audit.log.20260601-160100", "audit.log.bak"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "audit.log.20260601-120000")); !os.IsNotExist(err) {
+		t.Error("Oldest rotated file not removed")
+	}
+}
@@ -0,0 +1,184 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// Facility "log audit" and severity "notice", as of RFC 5424.
+	syslogPriority = 13*8 + 5
+	syslogAppName  = "syncthing"
+	syslogMsgID    = "audit"
+	syslogTimeout  = 10 * time.Second
+	// Messages waiting to be sent while the server is slow or can't be
+	// reached.
+	syslogQueueSize = 1000
+)
+
+// A SyslogWriter sends what is written to it to a syslog server in the
+// RFC 5424 format, a message per write. Sending is best effort and happens
+// in the background, so that writes never wait for the network: messages
+// that can't be sent, or that don't fit in the queue, are dropped.
+type SyslogWriter struct {
+	network  string // "udp", "tcp" or "tls"
+	address  string
+	hostname string
+	procID   string
+	timeNow  func() time.Time
+
+	queue   chan []byte
+	dropped atomic.Int64
+	ctx     context.Context
+	cancel  context.CancelFunc
+	done    chan struct{}
+	conn    net.Conn // only used by the sender
+}
+
+// NewSyslogWriter returns a writer for the syslog server at the address,
+// given as udp://host:port, tcp://host:port or tls://host:port.
+func NewSyslogWriter(address string) (*SyslogWriter, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "udp", "tcp", "tls":
+	default:
+		return nil, fmt.Errorf("syslog address %q: unsupported scheme %q", address, u.Scheme)
+	}
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		return nil, fmt.Errorf("syslog address %q: %w", address, err)
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &SyslogWriter{
+		network:  u.Scheme,
+		address:  u.Host,
+		hostname: hostname,
+		procID:   fmt.Sprint(os.Getpid()),
+		timeNow:  time.Now,
+		queue:    make(chan []byte, syslogQueueSize),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+	go w.sender()
+	return w, nil
+}
+
+// Write queues the message for sending. It never fails.
+func (w *SyslogWriter) Write(bs []byte) (int, error) {
+	msg := w.format(bytes.TrimRight(bs, "\n"))
+	select {
+	case w.queue <- msg:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(bs), nil
+}
+
+// Close stops sending, dropping the messages that haven't been sent yet.
+func (w *SyslogWriter) Close() error {
+	w.cancel()
+	<-w.done
+	return nil
+}
+
+func (w *SyslogWriter) sender() {
+	defer close(w.done)
+	defer func() {
+		if w.conn != nil {
+			w.conn.Close()
+		}
+	}()
+
+	for {
+		select {
+		case msg := <-w.queue:
+			w.sendRetry(msg)
+		case <-w.ctx.Done():
+			return
+		}
+		if n := w.dropped.Swap(0); n > 0 {
+			l.Debugf("Dropped %d audit records for syslog, the queue was full", n)
+		}
+	}
+}
+
+// sendRetry sends the message, retrying once on a new connection as the
+// server may have closed the old one.
+func (w *SyslogWriter) sendRetry(msg []byte) {
+	var err error
+	for range 2 {
+		if err = w.send(msg); err == nil {
+			return
+		}
+		if w.conn != nil {
+			w.conn.Close()
+			w.conn = nil
+		}
+	}
+	l.Debugln("Sending audit record to syslog:", err)
+}
+
+// format returns the message in the RFC 5424 format, without structured
+// data.
+func (w *SyslogWriter) format(msg []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<%d>1 %s %s %s %s %s - ", syslogPriority, w.timeNow().UTC().Format(time.RFC3339Nano), w.hostname, syslogAppName, w.procID, syslogMsgID)
+	buf.Write(msg)
+	return buf.Bytes()
+}
+
+func (w *SyslogWriter) send(msg []byte) error {
+	if w.conn == nil {
+		if err := w.dial(); err != nil {
+			return err
+		}
+	}
+	if err := w.conn.SetWriteDeadline(time.Now().Add(syslogTimeout)); err != nil {
+		return err
+	}
+	if w.network != "udp" {
+		// Octet counting framing, as of RFC 5425 and 6587.
+		msg = append([]byte(fmt.Sprintf("%d ", len(msg))), msg...)
+	}
+	_, err := w.conn.Write(msg)
+	return err
+}
+
+func (w *SyslogWriter) dial() error {
+	dialer := &net.Dialer{Timeout: syslogTimeout}
+	if w.network == "tls" {
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{MinVersion: tls.VersionTLS12}}
+		conn, err := tlsDialer.DialContext(w.ctx, "tcp", w.address)
+		if err != nil {
+			return err
+		}
+		w.conn = conn
+		return nil
+	}
+	conn, err := dialer.DialContext(w.ctx, w.network, w.address)
+	if err != nil {
+		return err
+	}
+	w.conn = conn
+	return nil
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package audit
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSyslogWriter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	w, err := NewSyslogWriter("tcp://" + listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Nobody is accepting the connection yet, which must not keep writes
+	// from returning.
+	for i := range 2 {
+		if _, err := fmt.Fprintf(w, "{\"record\":%d}\n", i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	br := bufio.NewReader(conn)
+	for i := range 2 {
+		// Octet counted frames, "<length> <message>"
+		var n int
+		if _, err := fmt.Fscanf(br, "%d ", &n); err != nil {
+			t.Fatal(err)
+		}
+		msg := make([]byte, n)
+		if _, err := io.ReadFull(br, msg); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(string(msg), "<109>1 ") || !strings.HasSuffix(string(msg), fmt.Sprintf(" syncthing %s audit - {\"record\":%d}", w.procID, i)) {
+			t.Errorf("Unexpected message %q", msg)
+		}
+	}
+}
+
+func TestSyslogWriterUnreachable(t *testing.T) {
+	// A listener that's closed right away leaves an address nobody
+	// answers on.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	w, err := NewSyslogWriter("tcp://" + addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// More writes than fit in the queue return right away, the excess
+	// being dropped.
+	t0 := time.Now()
+	for range 2 * syslogQueueSize {
+		if _, err := w.Write([]byte("{}\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if d := time.Since(t0); d > time.Second {
+		t.Errorf("Writing took %v", d)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
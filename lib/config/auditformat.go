@@ -0,0 +1,43 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+type AuditFormat int32
+
+const (
+	// Audit records in the schema of lib/audit.
+	AuditFormatRecords AuditFormat = 0
+	// Every event, as written before there were audit records.
+	AuditFormatEvents AuditFormat = 1
+)
+
+func (f AuditFormat) String() string {
+	switch f {
+	case AuditFormatRecords:
+		return "records"
+	case AuditFormatEvents:
+		return "events"
+	default:
+		return "unknown"
+	}
+}
+
+func (f AuditFormat) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+func (f *AuditFormat) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "records":
+		*f = AuditFormatRecords
+	case "events":
+		*f = AuditFormatEvents
+	default:
+		*f = AuditFormatRecords
+	}
+	return nil
+}
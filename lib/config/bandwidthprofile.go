@@ -0,0 +1,51 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// A BandwidthProfile replaces the static send and receive rate limits while
+// its schedule is active. The rates are in KiB/s, zero or less meaning
+// unlimited, same as for the static limits.
+type BandwidthProfile struct {
+	Name        string   `json:"name" xml:"name,attr"`
+	Schedule    Schedule `json:"schedule" xml:"schedule"`
+	MaxSendKbps int      `json:"maxSendKbps" xml:"maxSendKbps"`
+	MaxRecvKbps int      `json:"maxRecvKbps" xml:"maxRecvKbps"`
+}
+
+// ActiveBandwidthProfile returns the first of the given profiles that is
+// active at the given time, if any.
+func ActiveBandwidthProfile(profiles []BandwidthProfile, t time.Time) (BandwidthProfile, bool) {
+	for _, p := range profiles {
+		if p.Schedule.Active(t) {
+			return p, true
+		}
+	}
+	return BandwidthProfile{}, false
+}
+
+func copyBandwidthProfiles(profiles []BandwidthProfile) []BandwidthProfile {
+	c := make([]BandwidthProfile, len(profiles))
+	for i, p := range profiles {
+		c[i] = p
+		c[i].Schedule = p.Schedule.Copy()
+	}
+	return c
+}
+
+func prepareBandwidthProfiles(profiles []BandwidthProfile, what string) {
+	for i := range profiles {
+		if profiles[i].Name == "" {
+			profiles[i].Name = fmt.Sprintf("profile %d", i+1)
+		}
+		profiles[i].Schedule.prepare(fmt.Sprintf("bandwidth profile %q of %s", profiles[i].Name, what))
+	}
+}
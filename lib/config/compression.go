@@ -16,12 +16,20 @@ const (
 	CompressionMetadata Compression = 0
 	CompressionNever    Compression = 1
 	CompressionAlways   Compression = 2
+	// The Zstandard variants compress the same messages as their LZ4
+	// counterparts, falling back to LZ4 towards devices not supporting
+	// Zstandard.
+	CompressionZstdMetadata Compression = 3
+	CompressionZstdAlways   Compression = 4
 )
 
 var compressionMarshal = map[Compression]string{
 	CompressionNever:    "never",
 	CompressionMetadata: "metadata",
 	CompressionAlways:   "always",
+
+	CompressionZstdMetadata: "zstd-metadata",
+	CompressionZstdAlways:   "zstd-always",
 }
 
 var compressionUnmarshal = map[string]Compression{
@@ -33,6 +41,9 @@ var compressionUnmarshal = map[string]Compression{
 	"never":    CompressionNever,
 	"metadata": CompressionMetadata,
 	"always":   CompressionAlways,
+
+	"zstd-metadata": CompressionZstdMetadata,
+	"zstd-always":   CompressionZstdAlways,
 }
 
 func (c Compression) MarshalText() ([]byte, error) {
@@ -52,6 +63,10 @@ func (c Compression) ToProtocol() protocol.Compression {
 		return protocol.CompressionAlways
 	case CompressionMetadata:
 		return protocol.CompressionMetadata
+	case CompressionZstdMetadata:
+		return protocol.CompressionZstdMetadata
+	case CompressionZstdAlways:
+		return protocol.CompressionZstdAlways
 	default:
 		return protocol.CompressionMetadata
 	}
@@ -14,6 +14,8 @@ func TestCompressionMarshal(t *testing.T) {
 		{"never", CompressionNever},
 		{"metadata", CompressionMetadata},
 		{"always", CompressionAlways},
+		{"zstd-metadata", CompressionZstdMetadata},
+		{"zstd-always", CompressionZstdAlways},
 		{"whatever", CompressionMetadata},
 	}
 
@@ -24,6 +26,8 @@ func TestCompressionMarshal(t *testing.T) {
 		{"never", CompressionNever},
 		{"metadata", CompressionMetadata},
 		{"always", CompressionAlways},
+		{"zstd-metadata", CompressionZstdMetadata},
+		{"zstd-always", CompressionZstdAlways},
 	}
 
 	var c Compression
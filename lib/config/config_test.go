@@ -91,14 +91,21 @@ func TestDefaultValues(t *testing.T) {
 			StunKeepaliveMinS:         20,
 			RawStunServers:            []string{"default"},
 			AnnounceLANAddresses:      true,
+			DatabaseVerifyIntervalH:   24,
+			LocalAnnMDNSEnabled:       true,
 			FeatureFlags:              []string{},
 			AuditEnabled:              false,
 			AuditFile:                 "",
+			AuditMaxSizeMiB:           10,
+			AuditMaxAgeH:              168,
+			AuditMaxFiles:             10,
+			AuditFormat:               AuditFormatRecords,
 			ConnectionPriorityTCPLAN:  10,
 			ConnectionPriorityQUICLAN: 20,
 			ConnectionPriorityTCPWAN:  30,
 			ConnectionPriorityQUICWAN: 40,
 			ConnectionPriorityRelay:   50,
+			BandwidthProfiles:         []BandwidthProfile{},
 		},
 		Defaults: Defaults{
 			Folder: FolderConfiguration{
@@ -126,12 +133,16 @@ func TestDefaultValues(t *testing.T) {
 					MaxSingleEntrySize: 1024,
 					MaxTotalSize:       4096,
 				},
+				Schedule:              Schedule{Windows: []string{}},
+				ConflictMergePatterns: []string{},
 			},
 			Device: DeviceConfiguration{
-				Addresses:       []string{"dynamic"},
-				AllowedNetworks: []string{},
-				Compression:     CompressionMetadata,
-				IgnoredFolders:  []ObservedFolder{},
+				Addresses:         []string{"dynamic"},
+				AllowedNetworks:   []string{},
+				Compression:       CompressionMetadata,
+				IgnoredFolders:    []ObservedFolder{},
+				Schedule:          Schedule{Windows: []string{}},
+				BandwidthProfiles: []BandwidthProfile{},
 			},
 			Ignores: Ignores{
 				Lines: []string{},
@@ -204,25 +215,31 @@ func TestDeviceConfig(t *testing.T) {
 					MaxTotalSize:       4096,
 					Entries:            []XattrFilterEntry{},
 				},
+				Schedule:              Schedule{Windows: []string{}},
+				ConflictMergePatterns: []string{},
 			},
 		}
 
 		expectedDevices := []DeviceConfiguration{
 			{
-				DeviceID:        device1,
-				Name:            "node one",
-				Addresses:       []string{"tcp://a"},
-				Compression:     CompressionMetadata,
-				AllowedNetworks: []string{},
-				IgnoredFolders:  []ObservedFolder{},
+				DeviceID:          device1,
+				Name:              "node one",
+				Addresses:         []string{"tcp://a"},
+				Compression:       CompressionMetadata,
+				AllowedNetworks:   []string{},
+				IgnoredFolders:    []ObservedFolder{},
+				Schedule:          Schedule{Windows: []string{}},
+				BandwidthProfiles: []BandwidthProfile{},
 			},
 			{
-				DeviceID:        device4,
-				Name:            "node two",
-				Addresses:       []string{"tcp://b"},
-				Compression:     CompressionMetadata,
-				AllowedNetworks: []string{},
-				IgnoredFolders:  []ObservedFolder{},
+				DeviceID:          device4,
+				Name:              "node two",
+				Addresses:         []string{"tcp://b"},
+				Compression:       CompressionMetadata,
+				AllowedNetworks:   []string{},
+				IgnoredFolders:    []ObservedFolder{},
+				Schedule:          Schedule{Windows: []string{}},
+				BandwidthProfiles: []BandwidthProfile{},
 			},
 		}
 		expectedDeviceIDs := []protocol.DeviceID{device1, device4}
@@ -296,14 +313,30 @@ func TestOverriddenValues(t *testing.T) {
 		StunKeepaliveStartS:       9000,
 		StunKeepaliveMinS:         900,
 		RawStunServers:            []string{"foo"},
+		DatabaseBackend:           DatabaseBackendSQLite,
+		DatabaseVerifyIntervalH:   6,
+		LocalAnnMDNSEnabled:       false,
 		FeatureFlags:              []string{"feature"},
 		AuditEnabled:              true,
 		AuditFile:                 "nggyu",
+		AuditMaxSizeMiB:           20,
+		AuditMaxAgeH:              24,
+		AuditMaxFiles:             3,
+		AuditSyslogAddress:        "udp://localhost:514",
+		AuditFormat:               AuditFormatEvents,
 		ConnectionPriorityTCPLAN:  40,
 		ConnectionPriorityQUICLAN: 45,
 		ConnectionPriorityTCPWAN:  50,
 		ConnectionPriorityQUICWAN: 55,
 		ConnectionPriorityRelay:   9000,
+		BandwidthProfiles: []BandwidthProfile{
+			{
+				Name:        "office hours",
+				Schedule:    Schedule{Windows: []string{"Mon-Fri 08:00-17:00"}},
+				MaxSendKbps: 128,
+				MaxRecvKbps: 256,
+			},
+		},
 	}
 	expectedPath := "/media/syncthing"
 
@@ -332,30 +365,38 @@ func TestDeviceAddressesDynamic(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"dynamic"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"dynamic"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 	}
 
@@ -375,33 +416,41 @@ func TestDeviceCompression(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionNever,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionNever,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 	}
 
@@ -421,30 +470,38 @@ func TestDeviceAddressesStatic(t *testing.T) {
 	name, _ := os.Hostname()
 	expected := map[protocol.DeviceID]DeviceConfiguration{
 		device1: {
-			DeviceID:        device1,
-			Addresses:       []string{"tcp://192.0.2.1", "tcp://192.0.2.2"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device1,
+			Addresses:         []string{"tcp://192.0.2.1", "tcp://192.0.2.2"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device2: {
-			DeviceID:        device2,
-			Addresses:       []string{"tcp://192.0.2.3:6070", "tcp://[2001:db8::42]:4242"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device2,
+			Addresses:         []string{"tcp://192.0.2.3:6070", "tcp://[2001:db8::42]:4242"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device3: {
-			DeviceID:        device3,
-			Addresses:       []string{"tcp://[2001:db8::44]:4444", "tcp://192.0.2.4:6090"},
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device3,
+			Addresses:         []string{"tcp://[2001:db8::44]:4444", "tcp://192.0.2.4:6090"},
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 		device4: {
-			DeviceID:        device4,
-			Name:            name, // Set when auto created
-			Addresses:       []string{"dynamic"},
-			Compression:     CompressionMetadata,
-			AllowedNetworks: []string{},
-			IgnoredFolders:  []ObservedFolder{},
+			DeviceID:          device4,
+			Name:              name, // Set when auto created
+			Addresses:         []string{"dynamic"},
+			Compression:       CompressionMetadata,
+			AllowedNetworks:   []string{},
+			IgnoredFolders:    []ObservedFolder{},
+			Schedule:          Schedule{Windows: []string{}},
+			BandwidthProfiles: []BandwidthProfile{},
 		},
 	}
 
@@ -1682,3 +1739,32 @@ func TestCopyMatching(t *testing.T) {
 		t.Error("NoCopy")
 	}
 }
+
+func TestGUIRoleMissing(t *testing.T) {
+	// Users and role mappings without a role are monitors, not admins.
+	r := strings.NewReader(`<configuration version="38"><gui>
+		<users>
+			<user name="jane"><apikey>janekey</apikey></user>
+			<user name="joe" role="admin"></user>
+		</users>
+		<oidc><roleMapping group="staff"></roleMapping></oidc>
+	</gui></configuration>`)
+	cfg, _, err := ReadXML(r, device1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.GUI.Users) != 2 || cfg.GUI.Users[0].Role != GUIRoleMonitor || cfg.GUI.Users[1].Role != GUIRoleAdmin {
+		t.Errorf("Unexpected users %+v", cfg.GUI.Users)
+	}
+	if len(cfg.GUI.OIDC.RoleMappings) != 1 || cfg.GUI.OIDC.RoleMappings[0].Role != GUIRoleMonitor {
+		t.Errorf("Unexpected role mappings %+v", cfg.GUI.OIDC.RoleMappings)
+	}
+
+	var mapping OIDCRoleMapping
+	if err := json.Unmarshal([]byte(`{"group": "staff"}`), &mapping); err != nil {
+		t.Fatal(err)
+	}
+	if mapping.Role != GUIRoleMonitor {
+		t.Errorf("Unexpected role %v for mapping without role", mapping.Role)
+	}
+}
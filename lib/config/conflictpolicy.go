@@ -0,0 +1,66 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+// ConflictPolicy determines which side of a conflicting change wins and what
+// happens to the losing side.
+type ConflictPolicy int32
+
+const (
+	// The newer change wins, the older one is kept as a conflict copy next
+	// to the file.
+	ConflictPolicyNewer ConflictPolicy = 0
+	// Changes by the configured device win, otherwise as for newer.
+	ConflictPolicyPreferDevice ConflictPolicy = 1
+	// The larger file wins, otherwise as for newer.
+	ConflictPolicyLarger ConflictPolicy = 2
+	// The newer change wins, the older one is kept as a conflict copy in a
+	// conflicts directory at the root of the folder.
+	ConflictPolicyConflictDirectory ConflictPolicy = 3
+	// The newer change wins, the older one is archived by the versioner
+	// instead of creating a conflict copy.
+	ConflictPolicyVersioner ConflictPolicy = 4
+)
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictPolicyNewer:
+		return "newer"
+	case ConflictPolicyPreferDevice:
+		return "preferDevice"
+	case ConflictPolicyLarger:
+		return "larger"
+	case ConflictPolicyConflictDirectory:
+		return "conflictDirectory"
+	case ConflictPolicyVersioner:
+		return "versioner"
+	default:
+		return "unknown"
+	}
+}
+
+func (p ConflictPolicy) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+func (p *ConflictPolicy) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "newer":
+		*p = ConflictPolicyNewer
+	case "preferDevice":
+		*p = ConflictPolicyPreferDevice
+	case "larger":
+		*p = ConflictPolicyLarger
+	case "conflictDirectory":
+		*p = ConflictPolicyConflictDirectory
+	case "versioner":
+		*p = ConflictPolicyVersioner
+	default:
+		*p = ConflictPolicyNewer
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ type DeviceConfiguration struct {
 	SkipIntroductionRemovals bool              `json:"skipIntroductionRemovals" xml:"skipIntroductionRemovals,attr"`
 	IntroducedBy             protocol.DeviceID `json:"introducedBy" xml:"introducedBy,attr" nodefault:"true"`
 	Paused                   bool              `json:"paused" xml:"paused"`
+	Schedule                 Schedule          `json:"schedule" xml:"schedule"`
 	AllowedNetworks          []string          `json:"allowedNetworks" xml:"allowedNetwork,omitempty"`
 	AutoAcceptFolders        bool              `json:"autoAcceptFolders" xml:"autoAcceptFolders"`
 	MaxSendKbps              int               `json:"maxSendKbps" xml:"maxSendKbps"`
@@ -35,6 +36,9 @@ type DeviceConfiguration struct {
 	Untrusted                bool              `json:"untrusted" xml:"untrusted"`
 	RemoteGUIPort            int               `json:"remoteGUIPort" xml:"remoteGUIPort"`
 	RawNumConnections        int               `json:"numConnections" xml:"numConnections"`
+	// Bandwidth profiles replace MaxSendKbps and MaxRecvKbps while one of
+	// them is active, taking precedence over the global profiles.
+	BandwidthProfiles []BandwidthProfile `json:"bandwidthProfiles" xml:"bandwidthProfile"`
 }
 
 func (cfg DeviceConfiguration) Copy() DeviceConfiguration {
@@ -45,6 +49,8 @@ func (cfg DeviceConfiguration) Copy() DeviceConfiguration {
 	copy(c.AllowedNetworks, cfg.AllowedNetworks)
 	c.IgnoredFolders = make([]ObservedFolder, len(cfg.IgnoredFolders))
 	copy(c.IgnoredFolders, cfg.IgnoredFolders)
+	c.Schedule = cfg.Schedule.Copy()
+	c.BandwidthProfiles = copyBandwidthProfiles(cfg.BandwidthProfiles)
 	return c
 }
 
@@ -61,6 +67,9 @@ func (cfg *DeviceConfiguration) prepare(sharedFolders []string) {
 
 	cfg.IgnoredFolders = sortedObservedFolderSlice(ignoredFolders)
 
+	cfg.Schedule.prepare(fmt.Sprintf("device %s (%s)", cfg.DeviceID.Short(), cfg.Name))
+	prepareBandwidthProfiles(cfg.BandwidthProfiles, fmt.Sprintf("device %s (%s)", cfg.DeviceID.Short(), cfg.Name))
+
 	// A device cannot be simultaneously untrusted and an introducer, nor
 	// auto accept folders.
 	if cfg.Untrusted {
@@ -52,6 +52,7 @@ type FolderConfiguration struct {
 	Label                   string                      `json:"label" xml:"label,attr" restart:"false"`
 	FilesystemType          FilesystemType              `json:"filesystemType" xml:"filesystemType" default:"basic"`
 	Path                    string                      `json:"path" xml:"path,attr"`
+	FilesystemSecret        string                      `json:"filesystemSecret" xml:"filesystemSecret,omitempty"` // SFTP password, S3 secret access key; admins only
 	Type                    FolderType                  `json:"type" xml:"type,attr"`
 	Devices                 []FolderDeviceConfiguration `json:"devices" xml:"device"`
 	RescanIntervalS         int                         `json:"rescanIntervalS" xml:"rescanIntervalS,attr" default:"3600"`
@@ -73,6 +74,7 @@ type FolderConfiguration struct {
 	DisableSparseFiles      bool                        `json:"disableSparseFiles" xml:"disableSparseFiles"`
 	DisableTempIndexes      bool                        `json:"disableTempIndexes" xml:"disableTempIndexes"`
 	Paused                  bool                        `json:"paused" xml:"paused"`
+	Schedule                Schedule                    `json:"schedule" xml:"schedule"`
 	WeakHashThresholdPct    int                         `json:"weakHashThresholdPct" xml:"weakHashThresholdPct"`
 	MarkerName              string                      `json:"markerName" xml:"markerName"`
 	CopyOwnershipFromParent bool                        `json:"copyOwnershipFromParent" xml:"copyOwnershipFromParent"`
@@ -88,6 +90,13 @@ type FolderConfiguration struct {
 	SyncXattrs              bool                        `json:"syncXattrs" xml:"syncXattrs"`
 	SendXattrs              bool                        `json:"sendXattrs" xml:"sendXattrs"`
 	XattrFilter             XattrFilter                 `json:"xattrFilter" xml:"xattrFilter"`
+	ContentDefinedChunking  bool                        `json:"contentDefinedChunking" xml:"contentDefinedChunking"`
+	HashAlgorithm           HashAlgorithm               `json:"hashAlgorithm" xml:"hashAlgorithm"`
+	ConflictMergePatterns   []string                    `json:"conflictMergePatterns" xml:"conflictMergePattern"`
+	ConflictPolicy          ConflictPolicy              `json:"conflictPolicy" xml:"conflictPolicy"`
+	ConflictPreferDevice    protocol.DeviceID           `json:"conflictPreferDevice" xml:"conflictPreferDevice"`
+	MassChangeMaxFiles      int                         `json:"massChangeMaxFiles" xml:"massChangeMaxFiles"`
+	MassChangeMaxPct        int                         `json:"massChangeMaxPct" xml:"massChangeMaxPct"`
 	// Legacy deprecated
 	DeprecatedReadOnly       bool    `json:"-" xml:"ro,attr,omitempty"`        // Deprecated: Do not use.
 	DeprecatedMinDiskFreePct float64 `json:"-" xml:"minDiskFreePct,omitempty"` // Deprecated: Do not use.
@@ -117,6 +126,9 @@ func (f FolderConfiguration) Copy() FolderConfiguration {
 	c.Devices = make([]FolderDeviceConfiguration, len(f.Devices))
 	copy(c.Devices, f.Devices)
 	c.Versioning = f.Versioning.Copy()
+	c.Schedule = f.Schedule.Copy()
+	c.ConflictMergePatterns = make([]string, len(f.ConflictMergePatterns))
+	copy(c.ConflictMergePatterns, f.ConflictMergePatterns)
 	return c
 }
 
@@ -126,7 +138,10 @@ func (f FolderConfiguration) Copy() FolderConfiguration {
 func (f FolderConfiguration) Filesystem(fset *db.FileSet) fs.Filesystem {
 	// This is intentionally not a pointer method, because things like
 	// cfg.Folders["default"].Filesystem(nil) should be valid.
-	opts := make([]fs.Option, 0, 3)
+	opts := make([]fs.Option, 0, 4)
+	if f.FilesystemSecret != "" {
+		opts = append(opts, fs.OptionSecret(f.FilesystemSecret))
+	}
 	if f.FilesystemType == FilesystemTypeBasic && f.JunctionsAsDirs {
 		opts = append(opts, new(fs.OptionJunctionsAsDirs))
 	}
@@ -322,6 +337,32 @@ func (f *FolderConfiguration) prepare(myID protocol.DeviceID, existingDevices ma
 		f.MarkerName = DefaultMarkerName
 	}
 
+	f.Schedule.prepare(fmt.Sprintf("folder %s", f.Description()))
+
+	patterns := f.ConflictMergePatterns[:0]
+	for _, pattern := range f.ConflictMergePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			l.Warnf("Removing invalid conflict merge pattern %q for folder %s: %v", pattern, f.Description(), err)
+			continue
+		}
+		patterns = append(patterns, pattern)
+	}
+	f.ConflictMergePatterns = patterns
+
+	if f.ConflictPolicy == ConflictPolicyPreferDevice && f.ConflictPreferDevice == protocol.EmptyDeviceID {
+		l.Warnf("No preferred device set for conflict policy of folder %s; using %v", f.Description(), ConflictPolicyNewer)
+		f.ConflictPolicy = ConflictPolicyNewer
+	}
+
+	if f.MassChangeMaxFiles < 0 {
+		f.MassChangeMaxFiles = 0
+	}
+	if f.MassChangeMaxPct < 0 {
+		f.MassChangeMaxPct = 0
+	} else if f.MassChangeMaxPct > 100 {
+		f.MassChangeMaxPct = 100
+	}
+
 	if f.MaxConcurrentWrites <= 0 {
 		f.MaxConcurrentWrites = maxConcurrentWritesDefault
 	} else if f.MaxConcurrentWrites > maxConcurrentWritesLimit {
@@ -13,6 +13,7 @@ const (
 	FolderTypeSendOnly         FolderType = 1
 	FolderTypeReceiveOnly      FolderType = 2
 	FolderTypeReceiveEncrypted FolderType = 3
+	FolderTypeOnDemand         FolderType = 4
 )
 
 func (t FolderType) String() string {
@@ -25,6 +26,8 @@ func (t FolderType) String() string {
 		return "receiveonly"
 	case FolderTypeReceiveEncrypted:
 		return "receiveencrypted"
+	case FolderTypeOnDemand:
+		return "ondemand"
 	default:
 		return "unknown"
 	}
@@ -44,6 +47,8 @@ func (t *FolderType) UnmarshalText(bs []byte) error {
 		*t = FolderTypeReceiveOnly
 	case "receiveencrypted":
 		*t = FolderTypeReceiveEncrypted
+	case "ondemand":
+		*t = FolderTypeOnDemand
 	default:
 		*t = FolderTypeSendReceive
 	}
@@ -9,7 +9,9 @@ package config
 import (
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -19,26 +21,31 @@ import (
 )
 
 type GUIConfiguration struct {
-	Enabled                   bool     `json:"enabled" xml:"enabled,attr" default:"true"`
-	RawAddress                string   `json:"address" xml:"address" default:"127.0.0.1:8384"`
-	RawUnixSocketPermissions  string   `json:"unixSocketPermissions" xml:"unixSocketPermissions,omitempty"`
-	User                      string   `json:"user" xml:"user,omitempty"`
-	Password                  string   `json:"password" xml:"password,omitempty"`
-	AuthMode                  AuthMode `json:"authMode" xml:"authMode,omitempty"`
-	MetricsWithoutAuth        bool     `json:"metricsWithoutAuth" xml:"metricsWithoutAuth" default:"false"`
-	RawUseTLS                 bool     `json:"useTLS" xml:"tls,attr"`
-	APIKey                    string   `json:"apiKey" xml:"apikey,omitempty"`
-	InsecureAdminAccess       bool     `json:"insecureAdminAccess" xml:"insecureAdminAccess,omitempty"`
-	Theme                     string   `json:"theme" xml:"theme" default:"default"`
-	Debugging                 bool     `json:"debugging" xml:"debugging,attr"`
-	InsecureSkipHostCheck     bool     `json:"insecureSkipHostcheck" xml:"insecureSkipHostcheck,omitempty"`
-	InsecureAllowFrameLoading bool     `json:"insecureAllowFrameLoading" xml:"insecureAllowFrameLoading,omitempty"`
-	SendBasicAuthPrompt       bool     `json:"sendBasicAuthPrompt" xml:"sendBasicAuthPrompt,attr"`
+	Enabled                   bool              `json:"enabled" xml:"enabled,attr" default:"true"`
+	RawAddress                string            `json:"address" xml:"address" default:"127.0.0.1:8384"`
+	RawUnixSocketPermissions  string            `json:"unixSocketPermissions" xml:"unixSocketPermissions,omitempty"`
+	User                      string            `json:"user" xml:"user,omitempty"`
+	Password                  string            `json:"password" xml:"password,omitempty"`
+	AuthMode                  AuthMode          `json:"authMode" xml:"authMode,omitempty"`
+	MetricsWithoutAuth        bool              `json:"metricsWithoutAuth" xml:"metricsWithoutAuth" default:"false"`
+	RawUseTLS                 bool              `json:"useTLS" xml:"tls,attr"`
+	APIKey                    string            `json:"apiKey" xml:"apikey,omitempty"`
+	InsecureAdminAccess       bool              `json:"insecureAdminAccess" xml:"insecureAdminAccess,omitempty"`
+	Theme                     string            `json:"theme" xml:"theme" default:"default"`
+	Debugging                 bool              `json:"debugging" xml:"debugging,attr"`
+	InsecureSkipHostCheck     bool              `json:"insecureSkipHostcheck" xml:"insecureSkipHostcheck,omitempty"`
+	InsecureAllowFrameLoading bool              `json:"insecureAllowFrameLoading" xml:"insecureAllowFrameLoading,omitempty"`
+	SendBasicAuthPrompt       bool              `json:"sendBasicAuthPrompt" xml:"sendBasicAuthPrompt,attr"`
+	OIDC                      OIDCConfiguration `json:"oidc" xml:"oidc"`
+	Users                     []GUIUser         `json:"users" xml:"users>user"`
 }
 
 func (c GUIConfiguration) IsAuthEnabled() bool {
 	// This function should match isAuthEnabled() in syncthingController.js
-	return c.AuthMode == AuthModeLDAP || (len(c.User) > 0 && len(c.Password) > 0)
+	return c.AuthMode == AuthModeLDAP || c.AuthMode == AuthModeOIDC || (len(c.User) > 0 && len(c.Password) > 0) ||
+		slices.ContainsFunc(c.Users, func(u GUIUser) bool {
+			return len(u.Name) > 0 && len(u.Password) > 0
+		})
 }
 
 func (GUIConfiguration) IsOverridden() bool {
@@ -169,16 +176,63 @@ func (c GUIConfiguration) IsValidAPIKey(apiKey string) bool {
 		return true
 
 	default:
-		return false
+		_, ok := c.APIKeyUser(apiKey)
+		return ok
 	}
 }
 
+// APIKeyUser returns the user the given API key belongs to, if it isn't
+// the main API key.
+func (c GUIConfiguration) APIKeyUser(apiKey string) (GUIUser, bool) {
+	if apiKey == "" {
+		return GUIUser{}, false
+	}
+	for _, user := range c.Users {
+		if user.APIKey == apiKey {
+			return user, true
+		}
+	}
+	return GUIUser{}, false
+}
+
 func (c *GUIConfiguration) prepare() {
 	if c.APIKey == "" {
 		c.APIKey = rand.String(32)
 	}
 }
 
+// Equal returns whether the configurations are the same, not minding the
+// difference between nil and empty lists.
+func (c GUIConfiguration) Equal(other GUIConfiguration) bool {
+	return reflect.DeepEqual(c.withNilEmptyLists(), other.withNilEmptyLists())
+}
+
+func (c GUIConfiguration) withNilEmptyLists() GUIConfiguration {
+	c = c.Copy()
+	c.OIDC.Scopes = nilIfEmpty(c.OIDC.Scopes)
+	c.OIDC.RoleMappings = nilIfEmpty(c.OIDC.RoleMappings)
+	for i := range c.OIDC.RoleMappings {
+		c.OIDC.RoleMappings[i].Folders = nilIfEmpty(c.OIDC.RoleMappings[i].Folders)
+	}
+	c.Users = nilIfEmpty(c.Users)
+	for i := range c.Users {
+		c.Users[i].Folders = nilIfEmpty(c.Users[i].Folders)
+	}
+	return c
+}
+
+func nilIfEmpty[T any](s []T) []T {
+	if len(s) == 0 {
+		return nil
+	}
+	return s
+}
+
 func (c GUIConfiguration) Copy() GUIConfiguration {
+	c.OIDC = c.OIDC.Copy()
+	c.Users = slices.Clone(c.Users)
+	for i := range c.Users {
+		c.Users[i] = c.Users[i].Copy()
+	}
 	return c
 }
@@ -0,0 +1,51 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+// GUIRole is what a user of the GUI or REST API may do. The zero value is
+// the least privileged role, so that a role left out means monitor.
+type GUIRole int32
+
+const (
+	// Look, but not touch.
+	GUIRoleMonitor GUIRole = 0
+	// Look, and scan, override, revert and restore versions of some
+	// folders.
+	GUIRoleOperator GUIRole = 1
+	// Everything.
+	GUIRoleAdmin GUIRole = 2
+)
+
+func (t GUIRole) String() string {
+	switch t {
+	case GUIRoleAdmin:
+		return "admin"
+	case GUIRoleMonitor:
+		return "monitor"
+	case GUIRoleOperator:
+		return "operator"
+	default:
+		return "unknown"
+	}
+}
+
+func (t GUIRole) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *GUIRole) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "admin":
+		*t = GUIRoleAdmin
+	case "operator":
+		*t = GUIRoleOperator
+	default:
+		// Err on the side of caution.
+		*t = GUIRoleMonitor
+	}
+	return nil
+}
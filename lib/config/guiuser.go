@@ -0,0 +1,56 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"slices"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// A GUIUser is a user of the GUI or REST API besides the main one, who
+// logs in with a password or uses an API key.
+type GUIUser struct {
+	Name     string  `json:"name" xml:"name,attr"`
+	Password string  `json:"password" xml:"password,omitempty"`
+	APIKey   string  `json:"apiKey" xml:"apikey,omitempty"`
+	Role     GUIRole `json:"role" xml:"role,attr"`
+	// The folders operators may operate on, all of them if empty.
+	Folders []string `json:"folders" xml:"folder"`
+}
+
+// SetPassword takes a bcrypt hash or a plaintext password and stores it.
+// Plaintext passwords are hashed.
+func (u *GUIUser) SetPassword(password string) error {
+	if bcryptExpr.MatchString(password) {
+		// Already hashed
+		u.Password = password
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	u.Password = string(hash)
+	return nil
+}
+
+// CompareHashedPassword returns nil when the given plaintext password
+// matches the stored hash.
+func (u GUIUser) CompareHashedPassword(password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(u.Password), []byte(password))
+}
+
+func (u GUIUser) Equal(other GUIUser) bool {
+	return u.Name == other.Name && u.Password == other.Password && u.APIKey == other.APIKey &&
+		u.Role == other.Role && slices.Equal(u.Folders, other.Folders)
+}
+
+func (u GUIUser) Copy() GUIUser {
+	u.Folders = slices.Clone(u.Folders)
+	return u
+}
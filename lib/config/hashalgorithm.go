@@ -0,0 +1,53 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+type HashAlgorithm int32
+
+const (
+	HashAlgorithmSHA256 HashAlgorithm = 0
+	HashAlgorithmBLAKE3 HashAlgorithm = 1
+)
+
+var hashAlgorithmMarshal = map[HashAlgorithm]string{
+	HashAlgorithmSHA256: "sha256",
+	HashAlgorithmBLAKE3: "blake3",
+}
+
+var hashAlgorithmUnmarshal = map[string]HashAlgorithm{
+	"sha256": HashAlgorithmSHA256,
+	"blake3": HashAlgorithmBLAKE3,
+}
+
+func (h HashAlgorithm) String() string {
+	if s, ok := hashAlgorithmMarshal[h]; ok {
+		return s
+	}
+	return "unknown"
+}
+
+func (h HashAlgorithm) MarshalText() ([]byte, error) {
+	return []byte(h.String()), nil
+}
+
+func (h *HashAlgorithm) UnmarshalText(bs []byte) error {
+	*h = hashAlgorithmUnmarshal[string(bs)]
+	return nil
+}
+
+func (h HashAlgorithm) ToProtocol() protocol.HashAlgorithm {
+	switch h {
+	case HashAlgorithmBLAKE3:
+		return protocol.HashAlgorithmBLAKE3
+	default:
+		return protocol.HashAlgorithmSHA256
+	}
+}
@@ -27,6 +27,7 @@ import (
 // put the newest on top for readability.
 var (
 	migrations = migrationSet{
+		{38, migrateToConfigV38},
 		{37, migrateToConfigV37},
 		{36, migrateToConfigV36},
 		{35, migrateToConfigV35},
@@ -96,6 +97,14 @@ func (m migration) apply(cfg *Configuration) {
 	cfg.Version = m.targetVersion
 }
 
+func migrateToConfigV38(cfg *Configuration) {
+	// Audit logs were a stream of all events before there were audit
+	// records; keep them that way for whoever is using them. That includes
+	// those enabling the audit log on the command line, so it's not enough
+	// to look at the option.
+	cfg.Options.AuditFormat = AuditFormatEvents
+}
+
 func migrateToConfigV37(cfg *Configuration) {
 	// "scan ownership" changed name to "send ownership"
 	for i := range cfg.Folders {
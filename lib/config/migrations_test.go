@@ -6,7 +6,11 @@
 
 package config
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
 
 func TestMigrateCrashReporting(t *testing.T) {
 	// When migrating from pre-crash-reporting configs, crash reporting is
@@ -34,3 +38,22 @@ func TestMigrateCrashReporting(t *testing.T) {
 		}
 	}
 }
+
+func TestMigrateAuditFormat(t *testing.T) {
+	// Whoever had an audit log keeps getting all events in it, whether it
+	// was enabled in the config or on the command line.
+	for _, enabled := range []bool{true, false} {
+		cfg := Configuration{Version: 37, Options: OptionsConfiguration{AuditEnabled: enabled}}
+		migrationsMut.Lock()
+		migrations.apply(&cfg)
+		migrationsMut.Unlock()
+		if cfg.Options.AuditFormat != AuditFormatEvents {
+			t.Errorf("Audit enabled %v: unexpected format %v", enabled, cfg.Options.AuditFormat)
+		}
+	}
+
+	// New configs get audit records.
+	if cfg := New(protocol.EmptyDeviceID); cfg.Options.AuditFormat != AuditFormatRecords {
+		t.Errorf("Unexpected format %v for a new config", cfg.Options.AuditFormat)
+	}
+}
@@ -0,0 +1,41 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import "slices"
+
+type OIDCConfiguration struct {
+	Issuer       string `json:"issuer" xml:"issuer,omitempty"`
+	ClientID     string `json:"clientID" xml:"clientID,omitempty"`
+	ClientSecret string `json:"clientSecret" xml:"clientSecret,omitempty"`
+	// The URL the identity provider sends the browser back to. Derived from
+	// the request when empty, which doesn't work behind a reverse proxy
+	// that changes the path.
+	RedirectURL string `json:"redirectURL" xml:"redirectURL,omitempty"`
+	// Scopes to request in addition to "openid".
+	Scopes       []string          `json:"scopes" xml:"scope"`
+	GroupsClaim  string            `json:"groupsClaim" xml:"groupsClaim,omitempty" default:"groups"`
+	RoleMappings []OIDCRoleMapping `json:"roleMappings" xml:"roleMapping"`
+}
+
+// An OIDCRoleMapping gives the members of a group the role. Without any
+// mappings, nobody may log in.
+type OIDCRoleMapping struct {
+	Group string  `json:"group" xml:"group,attr"`
+	Role  GUIRole `json:"role" xml:"role,attr"`
+	// The folders operators may operate on, all of them if empty.
+	Folders []string `json:"folders" xml:"folder"`
+}
+
+func (c OIDCConfiguration) Copy() OIDCConfiguration {
+	c.Scopes = slices.Clone(c.Scopes)
+	c.RoleMappings = slices.Clone(c.RoleMappings)
+	for i := range c.RoleMappings {
+		c.RoleMappings[i].Folders = slices.Clone(c.RoleMappings[i].Folders)
+	}
+	return c
+}
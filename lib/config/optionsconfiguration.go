@@ -64,12 +64,31 @@ type OptionsConfiguration struct {
 	StunKeepaliveMinS           int      `json:"stunKeepaliveMinS" xml:"stunKeepaliveMinS" default:"20"`
 	RawStunServers              []string `json:"stunServers" xml:"stunServer" default:"default"`
 	DatabaseTuning              Tuning   `json:"databaseTuning" xml:"databaseTuning" restart:"true"`
-	RawMaxCIRequestKiB          int      `json:"maxConcurrentIncomingRequestKiB" xml:"maxConcurrentIncomingRequestKiB"`
-	AnnounceLANAddresses        bool     `json:"announceLANAddresses" xml:"announceLANAddresses" default:"true"`
-	SendFullIndexOnUpgrade      bool     `json:"sendFullIndexOnUpgrade" xml:"sendFullIndexOnUpgrade"`
-	FeatureFlags                []string `json:"featureFlags" xml:"featureFlag"`
-	AuditEnabled                bool     `json:"auditEnabled" xml:"auditEnabled" default:"false" restart:"true"`
-	AuditFile                   string   `json:"auditFile" xml:"auditFile" restart:"true"`
+	// An existing database of the other backend is migrated on startup.
+	DatabaseBackend        DatabaseBackend `json:"databaseBackend" xml:"databaseBackend" restart:"true"`
+	RawMaxCIRequestKiB     int             `json:"maxConcurrentIncomingRequestKiB" xml:"maxConcurrentIncomingRequestKiB"`
+	AnnounceLANAddresses   bool            `json:"announceLANAddresses" xml:"announceLANAddresses" default:"true"`
+	SendFullIndexOnUpgrade bool            `json:"sendFullIndexOnUpgrade" xml:"sendFullIndexOnUpgrade"`
+	FeatureFlags           []string        `json:"featureFlags" xml:"featureFlag"`
+	AuditEnabled           bool            `json:"auditEnabled" xml:"auditEnabled" default:"false" restart:"true"`
+	AuditFile              string          `json:"auditFile" xml:"auditFile" restart:"true"`
+	// The audit file is rotated when it grows beyond the size or gets
+	// older than the age, keeping as many files. Zero disables each.
+	AuditMaxSizeMiB int `json:"auditMaxSizeMiB" xml:"auditMaxSizeMiB" default:"10" restart:"true"`
+	AuditMaxAgeH    int `json:"auditMaxAgeH" xml:"auditMaxAgeH" default:"168" restart:"true"`
+	AuditMaxFiles   int `json:"auditMaxFiles" xml:"auditMaxFiles" default:"10" restart:"true"`
+	// Audit records are also sent to this syslog (RFC 5424) endpoint, as
+	// udp://host:port, tcp://host:port or tls://host:port.
+	AuditSyslogAddress string `json:"auditSyslogAddress" xml:"auditSyslogAddress" restart:"true"`
+	// Whether audit records or, like before there were any, all events
+	// are written.
+	AuditFormat AuditFormat `json:"auditFormat" xml:"auditFormat" restart:"true"`
+	// Every folder's database is verified and repaired in the background
+	// once per interval, zero disabling it.
+	DatabaseVerifyIntervalH int `json:"databaseVerifyIntervalH" xml:"databaseVerifyIntervalH" default:"24"`
+	// Devices are also announced and discovered over DNS-SD/mDNS when local
+	// discovery is enabled.
+	LocalAnnMDNSEnabled bool `json:"localAnnounceMDNSEnabled" xml:"localAnnounceMDNSEnabled" default:"true"`
 	// The number of connections at which we stop trying to connect to more
 	// devices, zero meaning no limit. Does not affect incoming connections.
 	ConnectionLimitEnough int `json:"connectionLimitEnough" xml:"connectionLimitEnough"`
@@ -83,6 +102,9 @@ type OptionsConfiguration struct {
 	ConnectionPriorityQUICWAN          int `json:"connectionPriorityQuicWan" xml:"connectionPriorityQuicWan" default:"40"`
 	ConnectionPriorityRelay            int `json:"connectionPriorityRelay" xml:"connectionPriorityRelay" default:"50"`
 	ConnectionPriorityUpgradeThreshold int `json:"connectionPriorityUpgradeThreshold" xml:"connectionPriorityUpgradeThreshold" default:"0"`
+	// Bandwidth profiles replace MaxSendKbps and MaxRecvKbps while one of
+	// them is active; the first active one wins.
+	BandwidthProfiles []BandwidthProfile `json:"bandwidthProfiles" xml:"bandwidthProfile"`
 	// Legacy deprecated
 	DeprecatedUPnPEnabled        bool     `json:"-" xml:"upnpEnabled,omitempty"`        // Deprecated: Do not use.
 	DeprecatedUPnPLeaseM         int      `json:"-" xml:"upnpLeaseMinutes,omitempty"`   // Deprecated: Do not use.
@@ -103,6 +125,7 @@ func (opts OptionsConfiguration) Copy() OptionsConfiguration {
 	copy(optsCopy.AlwaysLocalNets, opts.AlwaysLocalNets)
 	optsCopy.UnackedNotificationIDs = make([]string, len(opts.UnackedNotificationIDs))
 	copy(optsCopy.UnackedNotificationIDs, opts.UnackedNotificationIDs)
+	optsCopy.BandwidthProfiles = copyBandwidthProfiles(opts.BandwidthProfiles)
 	return optsCopy
 }
 
@@ -126,6 +149,8 @@ func (opts *OptionsConfiguration) prepare(guiPWIsSet bool) {
 		}
 	}
 
+	prepareBandwidthProfiles(opts.BandwidthProfiles, "options")
+
 	// Negative limits are meaningless, zero means unlimited.
 	if opts.ConnectionLimitEnough < 0 {
 		opts.ConnectionLimitEnough = 0
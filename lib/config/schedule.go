@@ -0,0 +1,206 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Schedule restricts a folder or device to a set of time windows.
+// Depending on the mode, outside of the windows either nothing is pulled
+// into the folder or from the device, or the folder or device is paused
+// and then resumed inside of them. An empty schedule means the folder or
+// device is left alone. Bandwidth profiles only use the windows.
+//
+// Each window has the form "[days] HH:MM-HH:MM", in local time. Days are a
+// comma separated list of weekdays or weekday ranges, e.g. "Mon-Fri" or
+// "Sat,Sun", or "*" for every day, which is also the default. A window
+// ending before it starts extends into the next day, i.e. "Mon-Fri
+// 20:00-06:00" ends on Saturday morning.
+type Schedule struct {
+	Windows []string     `json:"windows" xml:"window"`
+	Mode    ScheduleMode `json:"mode" xml:"mode,attr"`
+}
+
+func (s Schedule) Copy() Schedule {
+	c := s
+	c.Windows = make([]string, len(s.Windows))
+	copy(c.Windows, s.Windows)
+	return c
+}
+
+// IsSet returns true if the schedule has any windows.
+func (s Schedule) IsSet() bool {
+	return len(s.Windows) > 0
+}
+
+// AllowsPull returns true if the schedule lets the folder pull, or lets
+// files be pulled from the device, at the given time.
+func (s Schedule) AllowsPull(t time.Time) bool {
+	return !s.IsSet() || s.Mode != ScheduleModePullOnly || s.Active(t)
+}
+
+// Active returns true if the given time is within one of the windows.
+// Invalid windows never match.
+func (s Schedule) Active(t time.Time) bool {
+	return activeIn(s.parsedWindows(), t)
+}
+
+// NextChange returns the first time after t at which Active changes, or the
+// zero time if it never does. Windows have a resolution of one minute and
+// repeat every week, so that's as far as it needs to look.
+func (s Schedule) NextChange(t time.Time) time.Time {
+	windows := s.parsedWindows()
+	active := activeIn(windows, t)
+	for next := t.Truncate(time.Minute).Add(time.Minute); next.Sub(t) <= 8*24*time.Hour; next = next.Add(time.Minute) {
+		if activeIn(windows, next) != active {
+			return next
+		}
+	}
+	return time.Time{}
+}
+
+func (s Schedule) parsedWindows() []scheduleWindow {
+	windows := make([]scheduleWindow, 0, len(s.Windows))
+	for _, str := range s.Windows {
+		if w, err := parseScheduleWindow(str); err == nil {
+			windows = append(windows, w)
+		}
+	}
+	return windows
+}
+
+func activeIn(windows []scheduleWindow, t time.Time) bool {
+	for _, w := range windows {
+		if w.contains(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Schedule) prepare(what string) {
+	windows := s.Windows[:0]
+	for _, str := range s.Windows {
+		if _, err := parseScheduleWindow(str); err != nil {
+			l.Warnf("Removing invalid schedule window for %s: %v", what, err)
+			continue
+		}
+		windows = append(windows, str)
+	}
+	s.Windows = windows
+}
+
+type scheduleWindow struct {
+	days       [7]bool // indexed by time.Weekday
+	start, end int     // minutes since midnight, end may be 24*60
+}
+
+var scheduleWeekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+func parseScheduleWindow(str string) (scheduleWindow, error) {
+	var w scheduleWindow
+
+	fields := strings.Fields(str)
+	var days, times string
+	switch len(fields) {
+	case 1:
+		days, times = "*", fields[0]
+	case 2:
+		days, times = fields[0], fields[1]
+	default:
+		return w, fmt.Errorf("%q: expected \"[days] HH:MM-HH:MM\"", str)
+	}
+
+	for _, part := range strings.Split(days, ",") {
+		if part == "*" {
+			for i := range w.days {
+				w.days[i] = true
+			}
+			continue
+		}
+		first, last, isRange := strings.Cut(part, "-")
+		from, ok := scheduleWeekdays[strings.ToLower(first)]
+		if !ok {
+			return w, fmt.Errorf("%q: unknown weekday %q", str, first)
+		}
+		to := from
+		if isRange {
+			if to, ok = scheduleWeekdays[strings.ToLower(last)]; !ok {
+				return w, fmt.Errorf("%q: unknown weekday %q", str, last)
+			}
+		}
+		// Ranges may wrap around the end of the week, e.g. "Fri-Mon".
+		for d := from; ; d = (d + 1) % 7 {
+			w.days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+
+	startStr, endStr, ok := strings.Cut(times, "-")
+	if !ok {
+		return w, fmt.Errorf("%q: expected time range HH:MM-HH:MM", str)
+	}
+	var err error
+	if w.start, err = parseScheduleTime(startStr); err != nil || w.start == 24*60 {
+		return w, fmt.Errorf("%q: invalid start time %q", str, startStr)
+	}
+	if w.end, err = parseScheduleTime(endStr); err != nil {
+		return w, fmt.Errorf("%q: invalid end time %q", str, endStr)
+	}
+	if w.start == w.end {
+		return w, fmt.Errorf("%q: window is empty", str)
+	}
+
+	return w, nil
+}
+
+// parseScheduleTime parses HH:MM into minutes since midnight, accepting
+// 24:00 as the end of the day.
+func parseScheduleTime(str string) (int, error) {
+	hs, ms, ok := strings.Cut(str, ":")
+	if !ok || len(ms) != 2 {
+		return 0, fmt.Errorf("invalid time %q", str)
+	}
+	h, err := strconv.Atoi(hs)
+	if err != nil {
+		return 0, err
+	}
+	m, err := strconv.Atoi(ms)
+	if err != nil {
+		return 0, err
+	}
+	if h < 0 || m < 0 || m > 59 || h > 24 || h == 24 && m != 0 {
+		return 0, fmt.Errorf("invalid time %q", str)
+	}
+	return h*60 + m, nil
+}
+
+func (w scheduleWindow) contains(t time.Time) bool {
+	minute := t.Hour()*60 + t.Minute()
+	day := t.Weekday()
+	if w.start < w.end {
+		return w.days[day] && minute >= w.start && minute < w.end
+	}
+	// The window wraps past midnight; the part after midnight belongs to
+	// the day the window started on.
+	prev := (day + 6) % 7
+	return w.days[day] && minute >= w.start || w.days[prev] && minute < w.end
+}
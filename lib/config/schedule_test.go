@@ -0,0 +1,137 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+import (
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestParseScheduleWindow(t *testing.T) {
+	cases := []struct {
+		in string
+		ok bool
+	}{
+		{"20:00-06:00", true},
+		{"* 20:00-06:00", true},
+		{"Mon-Fri 20:00-06:00", true},
+		{"sat,sun 00:00-24:00", true},
+		{"Fri-Mon,Wed 9:30-17:00", true},
+		{"", false},
+		{"Mon-Fri", false},
+		{"Mon-Fri 20:00", false},
+		{"Funday 20:00-06:00", false},
+		{"Mon-Fri 20:00-20:00", false},
+		{"Mon-Fri 24:00-06:00", false},
+		{"Mon-Fri 20:60-06:00", false},
+		{"Mon-Fri 25:00-06:00", false},
+		{"Mon-Fri 20:00-06:00 extra", false},
+	}
+
+	for _, tc := range cases {
+		_, err := parseScheduleWindow(tc.in)
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("parseScheduleWindow(%q): got error %v, expected ok %v", tc.in, err, tc.ok)
+		}
+	}
+}
+
+func TestScheduleActive(t *testing.T) {
+	s := Schedule{Windows: []string{"Mon-Fri 20:00-06:00", "Sun 12:00-13:00", "invalid"}}
+
+	// 2026-10-12 is a Monday.
+	day := func(d, h, m int) time.Time {
+		return time.Date(2026, 10, 12+d, h, m, 0, 0, time.Local)
+	}
+	cases := []struct {
+		at     time.Time
+		active bool
+	}{
+		{day(0, 5, 59), false}, // Monday morning belongs to Sunday evening
+		{day(0, 19, 59), false},
+		{day(0, 20, 0), true},
+		{day(1, 5, 59), true},
+		{day(1, 6, 0), false},
+		{day(4, 23, 0), true}, // Friday night
+		{day(5, 3, 0), true},  // ... into Saturday
+		{day(5, 21, 0), false},
+		{day(6, 12, 30), true},
+		{day(6, 13, 0), false},
+	}
+
+	for _, tc := range cases {
+		if active := s.Active(tc.at); active != tc.active {
+			t.Errorf("Active(%v) = %v, expected %v", tc.at, active, tc.active)
+		}
+	}
+
+	if (Schedule{}).Active(day(0, 12, 0)) {
+		t.Error("Empty schedule should not be active")
+	}
+}
+
+func TestScheduleNextChange(t *testing.T) {
+	s := Schedule{Windows: []string{"Mon-Fri 20:00-06:00"}}
+
+	// 2026-10-12 is a Monday.
+	day := func(d, h, m int) time.Time {
+		return time.Date(2026, 10, 12+d, h, m, 0, 0, time.Local)
+	}
+	cases := []struct {
+		at, next time.Time
+	}{
+		{day(0, 12, 30), day(0, 20, 0)},
+		{day(0, 20, 0), day(1, 6, 0)},
+		{day(4, 23, 0), day(5, 6, 0)},
+		{day(5, 6, 0), day(7, 20, 0)}, // over the weekend
+	}
+	for _, tc := range cases {
+		if next := s.NextChange(tc.at); !next.Equal(tc.next) {
+			t.Errorf("NextChange(%v) = %v, expected %v", tc.at, next, tc.next)
+		}
+	}
+
+	if next := (Schedule{Windows: []string{"00:00-24:00"}}).NextChange(day(0, 12, 0)); !next.IsZero() {
+		t.Errorf("Expected a schedule that's always active never to change, got %v", next)
+	}
+}
+
+func TestScheduleAllowsPull(t *testing.T) {
+	windows := []string{"Mon-Fri 20:00-06:00"}
+	// 2026-10-12 is a Monday.
+	inside := time.Date(2026, 10, 12, 21, 0, 0, 0, time.Local)
+	outside := time.Date(2026, 10, 12, 12, 0, 0, 0, time.Local)
+
+	pullOnly := Schedule{Windows: windows}
+	if !pullOnly.AllowsPull(inside) || pullOnly.AllowsPull(outside) {
+		t.Error("Pull-only schedule should only allow pulling inside the windows")
+	}
+	// Pausing schedules pause instead, and no schedule means no restriction.
+	for _, s := range []Schedule{{Windows: windows, Mode: ScheduleModePause}, {}} {
+		if !s.AllowsPull(outside) {
+			t.Errorf("Schedule %+v should allow pulling", s)
+		}
+	}
+}
+
+func TestScheduleModeXML(t *testing.T) {
+	var s Schedule
+	if err := xml.Unmarshal([]byte(`<schedule mode="pause"><window>20:00-06:00</window></schedule>`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode != ScheduleModePause || len(s.Windows) != 1 {
+		t.Errorf("Unexpected schedule %+v", s)
+	}
+	s = Schedule{}
+	if err := xml.Unmarshal([]byte(`<schedule><window>20:00-06:00</window></schedule>`), &s); err != nil {
+		t.Fatal(err)
+	}
+	if s.Mode != ScheduleModePullOnly {
+		t.Errorf("Expected pull-only by default, got %v", s.Mode)
+	}
+}
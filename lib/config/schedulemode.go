@@ -0,0 +1,44 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package config
+
+type ScheduleMode int32
+
+const (
+	// Outside of the windows nothing is pulled from the device, or into
+	// the folder, while everything else goes on as usual.
+	ScheduleModePullOnly ScheduleMode = 0
+	// Outside of the windows the device or folder is paused.
+	ScheduleModePause ScheduleMode = 1
+)
+
+func (m ScheduleMode) String() string {
+	switch m {
+	case ScheduleModePullOnly:
+		return "pullOnly"
+	case ScheduleModePause:
+		return "pause"
+	default:
+		return "unknown"
+	}
+}
+
+func (m ScheduleMode) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *ScheduleMode) UnmarshalText(bs []byte) error {
+	switch string(bs) {
+	case "pullOnly":
+		*m = ScheduleModePullOnly
+	case "pause":
+		*m = ScheduleModePause
+	default:
+		*m = ScheduleModePullOnly
+	}
+	return nil
+}
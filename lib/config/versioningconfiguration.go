@@ -16,11 +16,12 @@ import (
 
 // VersioningConfiguration is used in the code and for JSON serialization
 type VersioningConfiguration struct {
-	Type             string            `json:"type" xml:"type,attr"`
-	Params           map[string]string `json:"params" xml:"parameter" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
-	CleanupIntervalS int               `json:"cleanupIntervalS" xml:"cleanupIntervalS" default:"3600"`
-	FSPath           string            `json:"fsPath" xml:"fsPath"`
-	FSType           FilesystemType    `json:"fsType" xml:"fsType" default:"basic"`
+	Type                string            `json:"type" xml:"type,attr"`
+	Params              map[string]string `json:"params" xml:"parameter" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	CleanupIntervalS    int               `json:"cleanupIntervalS" xml:"cleanupIntervalS" default:"3600"`
+	FSPath              string            `json:"fsPath" xml:"fsPath"`
+	FSType              FilesystemType    `json:"fsType" xml:"fsType" default:"basic"`
+	VersionLocalChanges bool              `json:"versionLocalChanges" xml:"versionLocalChanges"`
 }
 
 func (c *VersioningConfiguration) Reset() {
@@ -29,11 +30,12 @@ func (c *VersioningConfiguration) Reset() {
 
 // internalVersioningConfiguration is used in XML serialization
 type internalVersioningConfiguration struct {
-	Type             string          `xml:"type,attr,omitempty"`
-	Params           []internalParam `xml:"param"`
-	CleanupIntervalS int             `xml:"cleanupIntervalS" default:"3600"`
-	FSPath           string          `xml:"fsPath"`
-	FSType           FilesystemType  `xml:"fsType" default:"basic"`
+	Type                string          `xml:"type,attr,omitempty"`
+	Params              []internalParam `xml:"param"`
+	CleanupIntervalS    int             `xml:"cleanupIntervalS" default:"3600"`
+	FSPath              string          `xml:"fsPath"`
+	FSType              FilesystemType  `xml:"fsType" default:"basic"`
+	VersionLocalChanges bool            `xml:"versionLocalChanges"`
 }
 
 type internalParam struct {
@@ -81,6 +83,7 @@ func (c *VersioningConfiguration) toInternal() internalVersioningConfiguration {
 	tmp.CleanupIntervalS = c.CleanupIntervalS
 	tmp.FSPath = c.FSPath
 	tmp.FSType = c.FSType
+	tmp.VersionLocalChanges = c.VersionLocalChanges
 	for k, v := range c.Params {
 		tmp.Params = append(tmp.Params, internalParam{k, v})
 	}
@@ -95,6 +98,7 @@ func (c *VersioningConfiguration) fromInternal(intCfg internalVersioningConfigur
 	c.CleanupIntervalS = intCfg.CleanupIntervalS
 	c.FSPath = intCfg.FSPath
 	c.FSType = intCfg.FSType
+	c.VersionLocalChanges = intCfg.VersionLocalChanges
 	c.Params = make(map[string]string, len(intCfg.Params))
 	for _, p := range intCfg.Params {
 		c.Params[p.Key] = p.Val
@@ -10,7 +10,9 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"sync/atomic"
+	"time"
 
 	"golang.org/x/time/rate"
 
@@ -29,6 +31,17 @@ type limiter struct {
 	limitsLAN           atomic.Bool
 	deviceReadLimiters  map[protocol.DeviceID]*rate.Limiter
 	deviceWriteLimiters map[protocol.DeviceID]*rate.Limiter
+	cfg                 config.Configuration // as last committed
+	applied             config.Configuration // with active bandwidth profiles applied
+	profiles            BandwidthProfileStatus
+	now                 func() time.Time
+}
+
+// BandwidthProfileStatus contains the names of the currently active
+// bandwidth profiles, empty meaning the static limits apply.
+type BandwidthProfileStatus struct {
+	Active  string                       `json:"active"`
+	Devices map[protocol.DeviceID]string `json:"devices"`
 }
 
 type waiter interface {
@@ -49,15 +62,68 @@ func newLimiter(myId protocol.DeviceID, cfg config.Wrapper) *limiter {
 		mu:                  sync.NewMutex(),
 		deviceReadLimiters:  make(map[protocol.DeviceID]*rate.Limiter),
 		deviceWriteLimiters: make(map[protocol.DeviceID]*rate.Limiter),
+		now:                 time.Now,
 	}
 
 	cfg.Subscribe(l)
-	prev := config.Configuration{Options: config.OptionsConfiguration{MaxRecvKbps: -1, MaxSendKbps: -1}}
+	l.applied = config.Configuration{Options: config.OptionsConfiguration{MaxRecvKbps: -1, MaxSendKbps: -1}}
 
-	l.CommitConfiguration(prev, cfg.RawCopy())
+	l.CommitConfiguration(l.applied, cfg.RawCopy())
 	return l
 }
 
+// serve re-evaluates the bandwidth profiles periodically, so that rates
+// change as profiles become active or inactive.
+func (lim *limiter) serve(ctx context.Context) error {
+	for {
+		// Profile schedules have a resolution of one minute.
+		now := lim.now()
+		select {
+		case <-time.After(now.Truncate(time.Minute).Add(time.Minute).Sub(now)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		lim.mu.Lock()
+		lim.applyLocked(lim.now())
+		lim.mu.Unlock()
+	}
+}
+
+func (lim *limiter) bandwidthProfiles() BandwidthProfileStatus {
+	lim.mu.Lock()
+	defer lim.mu.Unlock()
+	res := BandwidthProfileStatus{
+		Active:  lim.profiles.Active,
+		Devices: make(map[protocol.DeviceID]string, len(lim.profiles.Devices)),
+	}
+	for id, name := range lim.profiles.Devices {
+		res.Devices[id] = name
+	}
+	return res
+}
+
+// withActiveProfiles returns a copy of the configuration with the rate
+// limits of the active bandwidth profiles in place of the static ones, and
+// the names of the profiles that were used.
+func withActiveProfiles(cfg config.Configuration, now time.Time) (config.Configuration, BandwidthProfileStatus) {
+	status := BandwidthProfileStatus{Devices: make(map[protocol.DeviceID]string)}
+	if p, ok := config.ActiveBandwidthProfile(cfg.Options.BandwidthProfiles, now); ok {
+		cfg.Options.MaxSendKbps = p.MaxSendKbps
+		cfg.Options.MaxRecvKbps = p.MaxRecvKbps
+		status.Active = p.Name
+	}
+	cfg.Devices = slices.Clone(cfg.Devices)
+	for i, dev := range cfg.Devices {
+		if p, ok := config.ActiveBandwidthProfile(dev.BandwidthProfiles, now); ok {
+			cfg.Devices[i].MaxSendKbps = p.MaxSendKbps
+			cfg.Devices[i].MaxRecvKbps = p.MaxRecvKbps
+			status.Devices[dev.DeviceID] = p.Name
+		}
+	}
+	return cfg, status
+}
+
 // This function sets limiters according to corresponding DeviceConfiguration
 func (lim *limiter) setLimitsLocked(device config.DeviceConfiguration) bool {
 	readLimiter := lim.getReadLimiterLocked(device.DeviceID)
@@ -122,18 +188,45 @@ func (lim *limiter) processDevicesConfigurationLocked(from, to config.Configurat
 	}
 }
 
-func (lim *limiter) CommitConfiguration(from, to config.Configuration) bool {
+func (lim *limiter) CommitConfiguration(_, to config.Configuration) bool {
 	// to ensure atomic update of configuration
 	lim.mu.Lock()
 	defer lim.mu.Unlock()
 
+	lim.cfg = to
+	lim.applyLocked(lim.now())
+
+	return true
+}
+
+// applyLocked sets the limiters according to the last committed
+// configuration and the bandwidth profiles active at the given time.
+func (lim *limiter) applyLocked(now time.Time) {
+	from := lim.applied
+	to, profiles := withActiveProfiles(lim.cfg, now)
+	lim.applied = to
+
+	if profiles.Active != lim.profiles.Active {
+		if profiles.Active != "" {
+			l.Infof("Bandwidth profile %q is active", profiles.Active)
+		} else {
+			l.Infof("Bandwidth profile %q is no longer active", lim.profiles.Active)
+		}
+	}
+	for id, name := range profiles.Devices {
+		if lim.profiles.Devices[id] != name {
+			l.Infof("Bandwidth profile %q is active for device %s", name, id)
+		}
+	}
+	lim.profiles = profiles
+
 	// Delete, add or update limiters for devices
 	lim.processDevicesConfigurationLocked(from, to)
 
 	if from.Options.MaxRecvKbps == to.Options.MaxRecvKbps &&
 		from.Options.MaxSendKbps == to.Options.MaxSendKbps &&
 		from.Options.LimitBandwidthInLan == to.Options.LimitBandwidthInLan {
-		return true
+		return
 	}
 
 	limited := false
@@ -169,8 +262,6 @@ func (lim *limiter) CommitConfiguration(from, to config.Configuration) bool {
 			l.Infoln("Rate limits do not apply to LAN connections")
 		}
 	}
-
-	return true
 }
 
 func (*limiter) String() string {
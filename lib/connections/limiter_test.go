@@ -14,6 +14,7 @@ import (
 	"math/rand"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"golang.org/x/time/rate"
 
@@ -217,6 +218,54 @@ func TestAddAndRemove(t *testing.T) {
 	checkActualAndExpected(t, actualR, actualW, expectedR, expectedW)
 }
 
+func TestBandwidthProfiles(t *testing.T) {
+	wrapper, wrapperCancel := initConfig()
+	defer wrapperCancel()
+	lim := newLimiter(device1, wrapper)
+
+	// 2026-10-12 is a Monday.
+	night := time.Date(2026, 10, 12, 23, 0, 0, 0, time.Local)
+	day := time.Date(2026, 10, 13, 10, 0, 0, 0, time.Local)
+	lim.now = func() time.Time { return day }
+
+	dev3Conf.BandwidthProfiles = []config.BandwidthProfile{
+		{Name: "night", Schedule: config.Schedule{Windows: []string{"22:00-06:00"}}, MaxRecvKbps: 42},
+	}
+	waiter, _ := wrapper.Modify(func(cfg *config.Configuration) {
+		cfg.Options.MaxSendKbps = 100
+		cfg.Options.BandwidthProfiles = []config.BandwidthProfile{
+			{Name: "office", Schedule: config.Schedule{Windows: []string{"Mon-Fri 08:00-17:00"}}, MaxSendKbps: 10},
+		}
+		cfg.SetDevices([]config.DeviceConfiguration{dev1Conf, dev2Conf, dev3Conf, dev4Conf})
+	})
+	waiter.Wait()
+
+	if limit := lim.write.Limit(); limit != 10*1024 {
+		t.Errorf("Expected office hours send limit, got %v", limit)
+	}
+	if limit := lim.deviceReadLimiters[device3].Limit(); limit != rate.Inf {
+		t.Errorf("Expected static device receive limit, got %v", limit)
+	}
+	if status := lim.bandwidthProfiles(); status.Active != "office" || len(status.Devices) != 0 {
+		t.Errorf("Unexpected profile status %v", status)
+	}
+
+	// The limiters are updated in place, without new connections.
+	lim.mu.Lock()
+	lim.applyLocked(night)
+	lim.mu.Unlock()
+
+	if limit := lim.write.Limit(); limit != 100*1024 {
+		t.Errorf("Expected static send limit, got %v", limit)
+	}
+	if limit := lim.deviceReadLimiters[device3].Limit(); limit != 42*1024 {
+		t.Errorf("Expected night device receive limit, got %v", limit)
+	}
+	if status := lim.bandwidthProfiles(); status.Active != "" || status.Devices[device3] != "night" {
+		t.Errorf("Unexpected profile status %v", status)
+	}
+}
+
 func TestLimitedWriterWrite(t *testing.T) {
 	// Check that the limited writer writes the correct data in the correct manner.
 
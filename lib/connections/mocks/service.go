@@ -19,6 +19,16 @@ type Service struct {
 	allAddressesReturnsOnCall map[int]struct {
 		result1 []string
 	}
+	BandwidthProfilesStub        func() connections.BandwidthProfileStatus
+	bandwidthProfilesMutex       sync.RWMutex
+	bandwidthProfilesArgsForCall []struct {
+	}
+	bandwidthProfilesReturns struct {
+		result1 connections.BandwidthProfileStatus
+	}
+	bandwidthProfilesReturnsOnCall map[int]struct {
+		result1 connections.BandwidthProfileStatus
+	}
 	ConnectionStatusStub        func() map[string]connections.ConnectionStatusEntry
 	connectionStatusMutex       sync.RWMutex
 	connectionStatusArgsForCall []struct {
@@ -127,6 +137,59 @@ func (fake *Service) AllAddressesReturnsOnCall(i int, result1 []string) {
 	}{result1}
 }
 
+func (fake *Service) BandwidthProfiles() connections.BandwidthProfileStatus {
+	fake.bandwidthProfilesMutex.Lock()
+	ret, specificReturn := fake.bandwidthProfilesReturnsOnCall[len(fake.bandwidthProfilesArgsForCall)]
+	fake.bandwidthProfilesArgsForCall = append(fake.bandwidthProfilesArgsForCall, struct {
+	}{})
+	stub := fake.BandwidthProfilesStub
+	fakeReturns := fake.bandwidthProfilesReturns
+	fake.recordInvocation("BandwidthProfiles", []interface{}{})
+	fake.bandwidthProfilesMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Service) BandwidthProfilesCallCount() int {
+	fake.bandwidthProfilesMutex.RLock()
+	defer fake.bandwidthProfilesMutex.RUnlock()
+	return len(fake.bandwidthProfilesArgsForCall)
+}
+
+func (fake *Service) BandwidthProfilesCalls(stub func() connections.BandwidthProfileStatus) {
+	fake.bandwidthProfilesMutex.Lock()
+	defer fake.bandwidthProfilesMutex.Unlock()
+	fake.BandwidthProfilesStub = stub
+}
+
+func (fake *Service) BandwidthProfilesReturns(result1 connections.BandwidthProfileStatus) {
+	fake.bandwidthProfilesMutex.Lock()
+	defer fake.bandwidthProfilesMutex.Unlock()
+	fake.BandwidthProfilesStub = nil
+	fake.bandwidthProfilesReturns = struct {
+		result1 connections.BandwidthProfileStatus
+	}{result1}
+}
+
+func (fake *Service) BandwidthProfilesReturnsOnCall(i int, result1 connections.BandwidthProfileStatus) {
+	fake.bandwidthProfilesMutex.Lock()
+	defer fake.bandwidthProfilesMutex.Unlock()
+	fake.BandwidthProfilesStub = nil
+	if fake.bandwidthProfilesReturnsOnCall == nil {
+		fake.bandwidthProfilesReturnsOnCall = make(map[int]struct {
+			result1 connections.BandwidthProfileStatus
+		})
+	}
+	fake.bandwidthProfilesReturnsOnCall[i] = struct {
+		result1 connections.BandwidthProfileStatus
+	}{result1}
+}
+
 func (fake *Service) ConnectionStatus() map[string]connections.ConnectionStatusEntry {
 	fake.connectionStatusMutex.Lock()
 	ret, specificReturn := fake.connectionStatusReturnsOnCall[len(fake.connectionStatusArgsForCall)]
@@ -405,6 +468,8 @@ func (fake *Service) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.allAddressesMutex.RLock()
 	defer fake.allAddressesMutex.RUnlock()
+	fake.bandwidthProfilesMutex.RLock()
+	defer fake.bandwidthProfilesMutex.RUnlock()
 	fake.connectionStatusMutex.RLock()
 	defer fake.connectionStatusMutex.RUnlock()
 	fake.externalAddressesMutex.RLock()
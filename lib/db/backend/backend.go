@@ -116,6 +116,12 @@ type Backend interface {
 	Location() string
 }
 
+// The IntegrityChecker interface is implemented by backends that can verify
+// the integrity of their storage, independently of the data stored.
+type IntegrityChecker interface {
+	CheckIntegrity() error
+}
+
 type Tuning int
 
 const (
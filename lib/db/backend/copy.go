@@ -0,0 +1,45 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+// Copy copies all keys and values from src into dst, as when migrating a
+// database to another backend, and returns the number of keys copied. The
+// keys are copied as they are, whatever their type.
+func Copy(dst, src Backend) (int, error) {
+	snap, err := src.NewReadTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer snap.Release()
+
+	it, err := snap.NewPrefixIterator(nil)
+	if err != nil {
+		return 0, err
+	}
+	defer it.Release()
+
+	tx, err := dst.NewWriteTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Release()
+
+	n := 0
+	for it.Next() {
+		if err := tx.Put(it.Key(), it.Value()); err != nil {
+			return n, err
+		}
+		n++
+	}
+	if err := it.Error(); err != nil {
+		return n, err
+	}
+	if err := tx.Commit(); err != nil {
+		return n, err
+	}
+	return n, nil
+}
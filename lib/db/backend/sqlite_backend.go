@@ -0,0 +1,454 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// sqliteBackend implements Backend on top of a single key/value table in an
+// SQLite database in WAL mode. Read transactions are SQL transactions, which
+// see a snapshot of the database from when they were created. Like for the
+// leveldb backend, write transactions buffer their writes in a batch that is
+// written in a separate SQL transaction on Checkpoint and Commit.
+type sqliteBackend struct {
+	sdb      *sql.DB
+	closeWG  *closeWaitGroup
+	location string
+	// Writes are serialized here rather than by SQLite's busy handling,
+	// which would otherwise stall and retry.
+	writeMut sync.Mutex
+	// For databases opened by OpenSQLiteMemory, removed on close.
+	tempDir string
+}
+
+func newSQLiteBackend(sdb *sql.DB, location string) *sqliteBackend {
+	return &sqliteBackend{
+		sdb:      sdb,
+		closeWG:  &closeWaitGroup{},
+		location: location,
+	}
+}
+
+func (b *sqliteBackend) NewReadTransaction() (ReadTransaction, error) {
+	return b.newSnapshot()
+}
+
+func (b *sqliteBackend) newSnapshot() (*sqliteSnapshot, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.sdb.Begin()
+	if err != nil {
+		rel.Release()
+		return nil, wrapSQLiteErr(err)
+	}
+	// A transaction only gets its snapshot with the first read.
+	var n int
+	if err := tx.QueryRow("SELECT count(*) FROM (SELECT 1 FROM kv LIMIT 1)").Scan(&n); err != nil {
+		_ = tx.Rollback()
+		rel.Release()
+		return nil, wrapSQLiteErr(err)
+	}
+	return &sqliteSnapshot{
+		tx:  tx,
+		rel: rel,
+	}, nil
+}
+
+func (b *sqliteBackend) NewWriteTransaction(hooks ...CommitHook) (WriteTransaction, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	snap, err := b.newSnapshot()
+	if err != nil {
+		rel.Release()
+		return nil, err // already wrapped
+	}
+	return &sqliteTransaction{
+		sqliteSnapshot: snap,
+		backend:        b,
+		rel:            rel,
+		commitHooks:    hooks,
+	}, nil
+}
+
+func (b *sqliteBackend) Close() error {
+	b.closeWG.CloseWait()
+	err := b.sdb.Close()
+	if b.tempDir != "" {
+		_ = os.RemoveAll(b.tempDir)
+	}
+	return wrapSQLiteErr(err)
+}
+
+func (b *sqliteBackend) Get(key []byte) ([]byte, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	defer rel.Release()
+	return sqliteGet(b.sdb, key)
+}
+
+func (b *sqliteBackend) NewPrefixIterator(prefix []byte) (Iterator, error) {
+	first, last := prefixRange(prefix)
+	return b.newIterator(first, last)
+}
+
+func (b *sqliteBackend) NewRangeIterator(first, last []byte) (Iterator, error) {
+	return b.newIterator(first, last)
+}
+
+func (b *sqliteBackend) newIterator(first, last []byte) (Iterator, error) {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return nil, err
+	}
+	it, err := newSQLiteIterator(b.sdb, first, last)
+	if err != nil {
+		rel.Release()
+		return nil, err
+	}
+	it.rel = rel
+	return it, nil
+}
+
+func (b *sqliteBackend) Put(key, val []byte) error {
+	return b.write([]sqliteOp{{key: key, val: val}})
+}
+
+func (b *sqliteBackend) Delete(key []byte) error {
+	return b.write([]sqliteOp{{key: key, delete: true}})
+}
+
+func (b *sqliteBackend) Compact() error {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return err
+	}
+	defer rel.Release()
+	// Return the free pages to the file system and shrink the WAL. This
+	// is much cheaper than a full VACUUM, which would rewrite the whole
+	// database.
+	if _, err := b.sdb.Exec("PRAGMA incremental_vacuum"); err != nil {
+		return wrapSQLiteErr(err)
+	}
+	_, err = b.sdb.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	return wrapSQLiteErr(err)
+}
+
+func (b *sqliteBackend) Location() string {
+	return b.location
+}
+
+// CheckIntegrity runs SQLite's integrity check on the whole database, which
+// takes time in proportion to the size of the database.
+func (b *sqliteBackend) CheckIntegrity() error {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return err
+	}
+	defer rel.Release()
+
+	rows, err := b.sdb.Query("PRAGMA integrity_check(100)")
+	if err != nil {
+		return wrapSQLiteErr(err)
+	}
+	defer rows.Close()
+	var problems []string
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return wrapSQLiteErr(err)
+		}
+		if msg != "ok" {
+			problems = append(problems, msg)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return wrapSQLiteErr(err)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("database integrity check failed: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// write applies the operations in a single SQL transaction.
+func (b *sqliteBackend) write(ops []sqliteOp) error {
+	rel, err := newReleaser(b.closeWG)
+	if err != nil {
+		return err
+	}
+	defer rel.Release()
+
+	b.writeMut.Lock()
+	defer b.writeMut.Unlock()
+
+	tx, err := b.sdb.Begin()
+	if err != nil {
+		return wrapSQLiteErr(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	put, err := tx.Prepare("INSERT INTO kv (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value")
+	if err != nil {
+		return wrapSQLiteErr(err)
+	}
+	defer put.Close()
+	del, err := tx.Prepare("DELETE FROM kv WHERE key = ?")
+	if err != nil {
+		return wrapSQLiteErr(err)
+	}
+	defer del.Close()
+
+	for _, op := range ops {
+		if op.delete {
+			_, err = del.Exec(op.key)
+		} else {
+			_, err = put.Exec(op.key, op.val)
+		}
+		if err != nil {
+			return wrapSQLiteErr(err)
+		}
+	}
+	return wrapSQLiteErr(tx.Commit())
+}
+
+// sqliteSnapshot implements backend.ReadTransaction
+type sqliteSnapshot struct {
+	tx   *sql.Tx
+	rel  *releaser
+	once sync.Once
+}
+
+func (s *sqliteSnapshot) Get(key []byte) ([]byte, error) {
+	return sqliteGet(s.tx, key)
+}
+
+func (s *sqliteSnapshot) NewPrefixIterator(prefix []byte) (Iterator, error) {
+	first, last := prefixRange(prefix)
+	return newSQLiteIterator(s.tx, first, last)
+}
+
+func (s *sqliteSnapshot) NewRangeIterator(first, last []byte) (Iterator, error) {
+	return newSQLiteIterator(s.tx, first, last)
+}
+
+func (s *sqliteSnapshot) Release() {
+	s.once.Do(func() {
+		// Read only, there is nothing to roll back but the snapshot.
+		_ = s.tx.Rollback()
+		s.rel.Release()
+	})
+}
+
+type sqliteOp struct {
+	key, val []byte
+	delete   bool
+}
+
+// sqliteTransaction implements backend.WriteTransaction using a batch of
+// operations that is written in an SQL transaction when flushed.
+type sqliteTransaction struct {
+	*sqliteSnapshot
+	backend     *sqliteBackend
+	batch       []sqliteOp
+	batchSize   int
+	rel         *releaser
+	commitHooks []CommitHook
+	inFlush     bool
+}
+
+func (t *sqliteTransaction) Delete(key []byte) error {
+	t.batch = append(t.batch, sqliteOp{key: append([]byte(nil), key...), delete: true})
+	t.batchSize += len(key)
+	return t.checkFlush(dbFlushBatchMax)
+}
+
+func (t *sqliteTransaction) Put(key, val []byte) error {
+	t.batch = append(t.batch, sqliteOp{key: append([]byte(nil), key...), val: append([]byte(nil), val...)})
+	t.batchSize += len(key) + len(val)
+	return t.checkFlush(dbFlushBatchMax)
+}
+
+func (t *sqliteTransaction) Checkpoint() error {
+	return t.checkFlush(dbFlushBatchMin)
+}
+
+func (t *sqliteTransaction) Commit() error {
+	err := t.flush()
+	t.sqliteSnapshot.Release()
+	t.rel.Release()
+	return err
+}
+
+func (t *sqliteTransaction) Release() {
+	t.sqliteSnapshot.Release()
+	t.rel.Release()
+}
+
+// checkFlush flushes and resets the batch if its size exceeds the given size.
+func (t *sqliteTransaction) checkFlush(size int) error {
+	// Hooks might put values in the database, which triggers a checkFlush which might trigger a flush,
+	// which might trigger the hooks.
+	// Don't recurse...
+	if t.inFlush || t.batchSize < size {
+		return nil
+	}
+	return t.flush()
+}
+
+func (t *sqliteTransaction) flush() error {
+	t.inFlush = true
+	defer func() { t.inFlush = false }()
+
+	for _, hook := range t.commitHooks {
+		if err := hook(t); err != nil {
+			return err
+		}
+	}
+	if len(t.batch) == 0 {
+		return nil
+	}
+	if err := t.backend.write(t.batch); err != nil {
+		return err
+	}
+	t.batch = nil
+	t.batchSize = 0
+	return nil
+}
+
+// sqliteQuerier is implemented by both *sql.DB and *sql.Tx.
+type sqliteQuerier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func sqliteGet(q sqliteQuerier, key []byte) ([]byte, error) {
+	var val []byte
+	if err := q.QueryRow("SELECT value FROM kv WHERE key = ?", key).Scan(&val); err != nil {
+		return nil, wrapSQLiteErr(err)
+	}
+	if val == nil {
+		val = []byte{}
+	}
+	return val, nil
+}
+
+// sqliteIterator implements backend.Iterator on the rows of a query.
+// Empty values are stored as NULL by the driver and returned as empty
+// slices.
+type sqliteIterator struct {
+	rows       *sql.Rows
+	key, value []byte
+	err        error
+	rel        *releaser // for iterators on the database, not in a transaction
+	once       sync.Once
+}
+
+// newSQLiteIterator returns an iterator over the keys from first
+// (inclusive) to last (exclusive), with nil meaning no limit.
+func newSQLiteIterator(q sqliteQuerier, first, last []byte) (*sqliteIterator, error) {
+	query := "SELECT key, value FROM kv"
+	var conds []string
+	var args []any
+	if first != nil {
+		conds = append(conds, "key >= ?")
+		args = append(args, first)
+	}
+	if last != nil {
+		conds = append(conds, "key < ?")
+		args = append(args, last)
+	}
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY key"
+
+	rows, err := q.QueryContext(context.Background(), query, args...)
+	if err != nil {
+		return nil, wrapSQLiteErr(err)
+	}
+	return &sqliteIterator{rows: rows}, nil
+}
+
+func (it *sqliteIterator) Next() bool {
+	if it.err != nil || !it.rows.Next() {
+		return false
+	}
+	it.key, it.value = nil, nil
+	if err := it.rows.Scan(&it.key, &it.value); err != nil {
+		it.err = err
+		return false
+	}
+	if it.value == nil {
+		it.value = []byte{}
+	}
+	return true
+}
+
+func (it *sqliteIterator) Key() []byte {
+	return it.key
+}
+
+func (it *sqliteIterator) Value() []byte {
+	return it.value
+}
+
+func (it *sqliteIterator) Error() error {
+	if it.err != nil {
+		return wrapSQLiteErr(it.err)
+	}
+	return wrapSQLiteErr(it.rows.Err())
+}
+
+func (it *sqliteIterator) Release() {
+	it.once.Do(func() {
+		_ = it.rows.Close()
+		if it.rel != nil {
+			it.rel.Release()
+		}
+	})
+}
+
+// prefixRange returns the range of keys with the given prefix, as for
+// NewRangeIterator.
+func prefixRange(prefix []byte) (first, last []byte) {
+	if len(prefix) == 0 {
+		return nil, nil
+	}
+	first = prefix
+	for i := len(prefix) - 1; i >= 0; i-- {
+		if prefix[i] != 0xff {
+			last = append([]byte(nil), prefix[:i+1]...)
+			last[i]++
+			return first, last
+		}
+	}
+	// All 0xff, there is no upper limit.
+	return first, nil
+}
+
+// wrapSQLiteErr wraps errors so that the backend package can recognize them
+func wrapSQLiteErr(err error) error {
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return errNotFound
+	case errors.Is(err, sql.ErrConnDone), err != nil && strings.Contains(err.Error(), "sql: database is closed"):
+		return errClosed
+	}
+	return err
+}
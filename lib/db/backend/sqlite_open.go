@@ -0,0 +1,145 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	_ "modernc.org/sqlite" // register the "sqlite" driver
+)
+
+const (
+	// Page cache per connection, in KiB, when using small or large
+	// database tuning.
+	sqliteCacheSmall = 2 << (MiB - KiB)
+	sqliteCacheLarge = 64 << (MiB - KiB)
+	// Memory mapped I/O when using large database tuning.
+	sqliteMmapLarge = 256 << MiB
+)
+
+// OpenSQLite opens or creates the SQLite database file at the given
+// location.
+func OpenSQLite(location string, tuning Tuning) (Backend, error) {
+	large := false
+	switch tuning {
+	case TuningLarge:
+		large = true
+	case TuningAuto:
+		large = sqliteIsLarge(location)
+	}
+
+	pragmas := []string{
+		// Has to come before any table is created to have an effect.
+		"auto_vacuum(INCREMENTAL)",
+		"journal_mode(WAL)",
+		"synchronous(NORMAL)",
+		"foreign_keys(OFF)",
+		"busy_timeout(30000)",
+	}
+	if large {
+		l.Infoln("Using large-database tuning")
+		pragmas = append(pragmas,
+			fmt.Sprintf("cache_size(-%d)", debugEnvValue("SQLiteCacheSize", sqliteCacheLarge)),
+			fmt.Sprintf("mmap_size(%d)", debugEnvValue("SQLiteMmapSize", sqliteMmapLarge)),
+		)
+	} else {
+		pragmas = append(pragmas, fmt.Sprintf("cache_size(-%d)", debugEnvValue("SQLiteCacheSize", sqliteCacheSmall)))
+	}
+
+	sdb, err := openSQLite(location, pragmas, "rwc")
+	if err != nil {
+		return nil, &errorSuggestion{err, "is another instance of Syncthing running?"}
+	}
+	if _, err := sdb.Exec("CREATE TABLE IF NOT EXISTS kv (key BLOB NOT NULL PRIMARY KEY, value BLOB) STRICT, WITHOUT ROWID"); err != nil {
+		sdb.Close()
+		return nil, err
+	}
+	return newSQLiteBackend(sdb, location), nil
+}
+
+// OpenSQLiteRO opens the SQLite database file at the given location, read
+// only.
+func OpenSQLiteRO(location string) (Backend, error) {
+	if _, err := os.Stat(location); err != nil {
+		return nil, err
+	}
+	sdb, err := openSQLite(location, []string{"busy_timeout(30000)"}, "ro")
+	if err != nil {
+		return nil, err
+	}
+	return newSQLiteBackend(sdb, location), nil
+}
+
+// OpenSQLiteMemory returns a new Backend referencing a temporary SQLite
+// database. It is backed by a file, as concurrent transactions on a shared
+// in-memory SQLite database would lock each other out, and removed on
+// close.
+func OpenSQLiteMemory() Backend {
+	dir, err := os.MkdirTemp("", "syncthing-sqlite-")
+	if err != nil {
+		panic(err)
+	}
+	b, err := OpenSQLite(filepath.Join(dir, "index.sqlite"), TuningSmall)
+	if err != nil {
+		panic(err)
+	}
+	sb := b.(*sqliteBackend)
+	sb.location = ""
+	sb.tempDir = dir
+	return sb
+}
+
+// RemoveAll removes the database at location, of either backend. For
+// SQLite that includes the write-ahead log and shared memory files, which
+// would otherwise be replayed into a new database created at the same
+// location.
+func RemoveAll(location string) error {
+	for _, path := range []string{location, location + "-wal", location + "-shm"} {
+		if err := os.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func openSQLite(location string, pragmas []string, mode string) (*sql.DB, error) {
+	params := url.Values{"mode": []string{mode}}
+	for _, pragma := range pragmas {
+		params.Add("_pragma", pragma)
+	}
+	path := (&url.URL{Path: filepath.ToSlash(location)}).EscapedPath()
+	sdb, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	// Connections are held by open read transactions and iterators; keep
+	// a few around for reuse.
+	sdb.SetMaxIdleConns(4)
+	if err := sdb.Ping(); err != nil {
+		sdb.Close()
+		return nil, err
+	}
+	return sdb, nil
+}
+
+// sqliteIsLarge returns whether the database file at location is large
+// enough to warrant optimization for large databases.
+func sqliteIsLarge(location string) bool {
+	if ^uint(0)>>63 == 0 {
+		// 32 bit architecture, see dbIsLarge.
+		return false
+	}
+	info, err := os.Stat(location)
+	if err != nil {
+		return false
+	}
+	return info.Size() > dbLargeThreshold
+}
@@ -0,0 +1,97 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteBackendBehavior(t *testing.T) {
+	testBackendBehavior(t, OpenSQLiteMemory)
+}
+
+func TestCopyLevelDBToSQLite(t *testing.T) {
+	src := OpenLevelDBMemory()
+	defer src.Close()
+	dst := OpenSQLiteMemory()
+	defer dst.Close()
+
+	keys := map[string]string{"a": "1", "b": "", "c\xff": "3"}
+	for k, v := range keys {
+		if err := src.Put([]byte(k), []byte(v)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n, err := Copy(dst, src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(keys) {
+		t.Errorf("Copied %d keys, expected %d", n, len(keys))
+	}
+	for k, v := range keys {
+		got, err := dst.Get([]byte(k))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != v {
+			t.Errorf("Key %q: got %q, expected %q", k, got, v)
+		}
+	}
+	if err := dst.(IntegrityChecker).CheckIntegrity(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSQLitePrefixIterator(t *testing.T) {
+	db := OpenSQLiteMemory()
+	defer db.Close()
+
+	for _, k := range []string{"a", "b\xff", "b\xff\x00", "b\xff\xff", "c"} {
+		if err := db.Put([]byte(k), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	it, err := db.NewPrefixIterator([]byte("b\xff"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Release()
+	var got []string
+	for it.Next() {
+		got = append(got, string(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 3 || got[0] != "b\xff" || got[2] != "b\xff\xff" {
+		t.Errorf("Unexpected keys %q", got)
+	}
+}
+
+func TestRemoveAll(t *testing.T) {
+	location := filepath.Join(t.TempDir(), "index.sqlite")
+	paths := []string{location, location + "-wal", location + "-shm"}
+	for _, path := range paths {
+		if err := os.WriteFile(path, []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := RemoveAll(location); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", path)
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"fmt"
 
 	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 type BlockFinder struct {
@@ -28,11 +29,12 @@ func (f *BlockFinder) String() string {
 }
 
 // Iterate takes an iterator function which iterates over all matching blocks
-// for the given hash. The iterator function has to return either true (if
-// they are happy with the block) or false to continue iterating for whatever
-// reason. The iterator finally returns the result, whether or not a
-// satisfying block was eventually found.
-func (f *BlockFinder) Iterate(folders []string, hash []byte, iterFn func(string, string, int32) bool) bool {
+// for the given hash, as calculated by the given algorithm. The iterator
+// function has to return either true (if they are happy with the block) or
+// false to continue iterating for whatever reason. The iterator finally
+// returns the result, whether or not a satisfying block was eventually
+// found.
+func (f *BlockFinder) Iterate(folders []string, algo protocol.HashAlgorithm, hash []byte, iterFn func(folder, file string, index int32, offset int64) bool) bool {
 	t, err := f.db.newReadOnlyTransaction()
 	if err != nil {
 		return false
@@ -41,7 +43,7 @@ func (f *BlockFinder) Iterate(folders []string, hash []byte, iterFn func(string,
 
 	var key []byte
 	for _, folder := range folders {
-		key, err = f.db.keyer.GenerateBlockMapKey(key, []byte(folder), hash, nil)
+		key, err = f.db.keyer.GenerateBlockMapKey(key, []byte(folder), algo, hash, nil)
 		if err != nil {
 			return false
 		}
@@ -52,8 +54,10 @@ func (f *BlockFinder) Iterate(folders []string, hash []byte, iterFn func(string,
 
 		for iter.Next() && iter.Error() == nil {
 			file := string(f.db.keyer.NameFromBlockMapKey(iter.Key()))
-			index := int32(binary.BigEndian.Uint32(iter.Value()))
-			if iterFn(folder, osutil.NativeFilename(file), index) {
+			val := iter.Value()
+			index := int32(binary.BigEndian.Uint32(val))
+			offset := int64(binary.BigEndian.Uint64(val[4:]))
+			if iterFn(folder, osutil.NativeFilename(file), index, offset) {
 				iter.Release()
 				return true
 			}
@@ -60,13 +60,14 @@ func addToBlockMap(db *Lowlevel, folder []byte, fs []protocol.FileInfo) error {
 	defer t.close()
 
 	var keyBuf []byte
-	blockBuf := make([]byte, 4)
+	blockBuf := make([]byte, 12)
 	for _, f := range fs {
 		if !f.IsDirectory() && !f.IsDeleted() && !f.IsInvalid() {
 			name := []byte(f.Name)
 			for i, block := range f.Blocks {
 				binary.BigEndian.PutUint32(blockBuf, uint32(i))
-				keyBuf, err = t.keyer.GenerateBlockMapKey(keyBuf, folder, block.Hash, name)
+				binary.BigEndian.PutUint64(blockBuf[4:], uint64(block.Offset))
+				keyBuf, err = t.keyer.GenerateBlockMapKey(keyBuf, folder, f.HashAlgorithm, block.Hash, name)
 				if err != nil {
 					return err
 				}
@@ -91,7 +92,7 @@ func discardFromBlockMap(db *Lowlevel, folder []byte, fs []protocol.FileInfo) er
 		if !ef.IsDirectory() && !ef.IsDeleted() && !ef.IsInvalid() {
 			name := []byte(ef.Name)
 			for _, block := range ef.Blocks {
-				keyBuf, err = t.keyer.GenerateBlockMapKey(keyBuf, folder, block.Hash, name)
+				keyBuf, err = t.keyer.GenerateBlockMapKey(keyBuf, folder, ef.HashAlgorithm, block.Hash, name)
 				if err != nil {
 					return err
 				}
@@ -120,21 +121,21 @@ func TestBlockMapAddUpdateWipe(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	f.Iterate(folders, f1.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f1.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		if folder != "folder1" || file != "f1" || index != 0 {
 			t.Fatal("Mismatch")
 		}
 		return true
 	})
 
-	f.Iterate(folders, f2.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f2.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		if folder != "folder1" || file != "f2" || index != 0 {
 			t.Fatal("Mismatch")
 		}
 		return true
 	})
 
-	f.Iterate(folders, f3.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f3.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		t.Fatal("Unexpected block")
 		return true
 	})
@@ -150,17 +151,17 @@ func TestBlockMapAddUpdateWipe(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	f.Iterate(folders, f1.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f1.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		t.Fatal("Unexpected block")
 		return false
 	})
 
-	f.Iterate(folders, f2.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f2.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		t.Fatal("Unexpected block")
 		return false
 	})
 
-	f.Iterate(folders, f3.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f3.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		if folder != "folder1" || file != "f3" || index != 0 {
 			t.Fatal("Mismatch")
 		}
@@ -207,7 +208,7 @@ func TestBlockFinderLookup(t *testing.T) {
 	}
 
 	counter := 0
-	f.Iterate(folders, f1.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f1.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		counter++
 		switch counter {
 		case 1:
@@ -239,7 +240,7 @@ func TestBlockFinderLookup(t *testing.T) {
 	}
 
 	counter = 0
-	f.Iterate(folders, f1.Blocks[0].Hash, func(folder, file string, index int32) bool {
+	f.Iterate(folders, protocol.HashAlgorithmSHA256, f1.Blocks[0].Hash, func(folder, file string, index int32, _ int64) bool {
 		counter++
 		switch counter {
 		case 1:
@@ -0,0 +1,121 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/syncthing/syncthing/internal/gen/dbproto"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+const conflictLogKey = "log"
+
+// ConflictRecord describes how a conflict on a file was resolved.
+type ConflictRecord struct {
+	Time       time.Time `json:"time"`
+	Name       string    `json:"name"`
+	Policy     string    `json:"policy"`
+	Resolution string    `json:"resolution"`
+	// The short IDs of the devices that made the winning and the losing
+	// change.
+	Winner string `json:"winner"`
+	Loser  string `json:"loser"`
+	// Where the losing side was kept, if anywhere.
+	ConflictCopy string `json:"conflictCopy,omitempty"`
+}
+
+func (r *ConflictRecord) toWire() *dbproto.ConflictRecord {
+	return &dbproto.ConflictRecord{
+		Time:         timestamppb.New(r.Time),
+		Name:         r.Name,
+		Policy:       r.Policy,
+		Resolution:   r.Resolution,
+		Winner:       r.Winner,
+		Loser:        r.Loser,
+		ConflictCopy: r.ConflictCopy,
+	}
+}
+
+func (r *ConflictRecord) fromWire(w *dbproto.ConflictRecord) {
+	r.Time = w.GetTime().AsTime()
+	r.Name = w.GetName()
+	r.Policy = w.GetPolicy()
+	r.Resolution = w.GetResolution()
+	r.Winner = w.GetWinner()
+	r.Loser = w.GetLoser()
+	r.ConflictCopy = w.GetConflictCopy()
+}
+
+// ConflictLog keeps the latest conflict resolutions in a folder.
+type ConflictLog struct {
+	kv   *NamespacedKV
+	keep int
+	mut  sync.Mutex
+}
+
+// NewConflictLog returns the conflict log of the given folder, keeping at
+// most keep records.
+func NewConflictLog(db backend.Backend, folder string, keep int) *ConflictLog {
+	return &ConflictLog{
+		kv:   NewFolderConflictLogNamespace(db, folder),
+		keep: keep,
+		mut:  sync.NewMutex(),
+	}
+}
+
+// Add records a conflict resolution, dropping the oldest record if there
+// are too many.
+func (c *ConflictLog) Add(rec ConflictRecord) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	log, err := c.load()
+	if err != nil {
+		return err
+	}
+	records := append(log.GetRecords(), rec.toWire())
+	if len(records) > c.keep {
+		records = records[len(records)-c.keep:]
+	}
+	return c.kv.PutBytes(conflictLogKey, mustMarshal(&dbproto.ConflictLog{Records: records}))
+}
+
+// Records returns the recorded conflict resolutions, newest first.
+func (c *ConflictLog) Records() ([]ConflictRecord, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	log, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	wire := log.GetRecords()
+	records := make([]ConflictRecord, len(wire))
+	for i, w := range wire {
+		records[len(wire)-1-i].fromWire(w)
+	}
+	return records, nil
+}
+
+func (c *ConflictLog) load() (*dbproto.ConflictLog, error) {
+	bs, ok, err := c.kv.Bytes(conflictLogKey)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var log dbproto.ConflictLog
+	if err := proto.Unmarshal(bs, &log); err != nil {
+		// Nothing worth keeping, start over.
+		l.Debugf("Dropping unreadable conflict log: %v", err)
+		return nil, nil
+	}
+	return &log, nil
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConflictLog(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	c := NewConflictLog(ldb, "folder", 2)
+	if recs, err := c.Records(); err != nil || len(recs) != 0 {
+		t.Fatalf("got %v, %v, expected empty log", recs, err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	for i, name := range []string{"a", "b", "c"} {
+		rec := ConflictRecord{
+			Time:         now.Add(time.Duration(i) * time.Second),
+			Name:         name,
+			Policy:       "newer",
+			Resolution:   "conflictCopy",
+			Winner:       "AAAAAAA",
+			Loser:        "BBBBBBB",
+			ConflictCopy: name + ".sync-conflict",
+		}
+		if err := c.Add(rec); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the latest two are kept, newest first.
+	recs, err := NewConflictLog(ldb, "folder", 2).Records()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Name != "c" || recs[1].Name != "b" {
+		t.Fatalf("unexpected records %v", recs)
+	}
+	if !recs[0].Time.Equal(now.Add(2*time.Second)) || recs[0].ConflictCopy != "c.sync-conflict" || recs[0].Loser != "BBBBBBB" {
+		t.Errorf("record not round tripped: %+v", recs[0])
+	}
+
+	if recs, err := NewConflictLog(ldb, "other", 2).Records(); err != nil || len(recs) != 0 {
+		t.Errorf("got %v, %v, expected empty log for other folder", recs, err)
+	}
+}
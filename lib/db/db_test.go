@@ -9,8 +9,10 @@ package db
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/syncthing/syncthing/lib/db/backend"
 	"github.com/syncthing/syncthing/lib/events"
@@ -497,6 +499,69 @@ func TestUpdateTo14(t *testing.T) {
 	}
 }
 
+func TestRebuildBlockMapMigration(t *testing.T) {
+	db := newLowlevelMemory(t)
+	defer db.Close()
+
+	folderStr := "default"
+	folder := []byte(folderStr)
+	file := protocol.FileInfo{Name: "foo", Size: 3 * protocol.MinBlockSize, Version: protocol.Vector{Counters: []protocol.Counter{{ID: myID, Value: 1000}}}, Blocks: genBlocks(3), HashAlgorithm: protocol.HashAlgorithmBLAKE3}
+	for i := range file.Blocks {
+		file.Blocks[i].Offset = int64(i) * protocol.MinBlockSize
+	}
+	meta, err := db.loadMetadataTracker(folderStr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.updateLocalFiles(folder, []protocol.FileInfo{file}, meta); err != nil {
+		t.Fatal(err)
+	}
+
+	// Replace the block map with an entry in the old format, without hash
+	// algorithm and offset.
+	trans, err := db.newReadWriteTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.close()
+	if err := trans.deleteKeyPrefix([]byte{KeyTypeBlock}); err != nil {
+		t.Fatal(err)
+	}
+	folderID, err := db.folderIdx.ID(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldKey := []byte{KeyTypeBlock}
+	oldKey = binary.BigEndian.AppendUint32(oldKey, folderID)
+	oldKey = append(oldKey, file.Blocks[1].Hash...)
+	oldKey = append(oldKey, file.Name...)
+	if err := trans.Put(oldKey, binary.BigEndian.AppendUint32(nil, 1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	trans.close()
+
+	if err := (&schemaUpdater{db}).rebuildBlockMapMigration(14); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := db.Get(oldKey); !backend.IsNotFound(err) {
+		t.Error("old block map entry should be gone, got", err)
+	}
+	f := NewBlockFinder(db)
+	if f.Iterate([]string{folderStr}, protocol.HashAlgorithmSHA256, file.Blocks[1].Hash, func(string, string, int32, int64) bool { return true }) {
+		t.Error("block should not be found with another hash algorithm")
+	}
+	found := f.Iterate([]string{folderStr}, protocol.HashAlgorithmBLAKE3, file.Blocks[1].Hash, func(_, name string, index int32, offset int64) bool {
+		return name == file.Name && index == 1 && offset == file.Blocks[1].Offset
+	})
+	if !found {
+		t.Error("block missing after rebuilding the block map")
+	}
+}
+
 func TestFlushRecursion(t *testing.T) {
 	// Verify that a commit hook can write to the transaction without
 	// causing another flush and thus recursion.
@@ -699,3 +764,43 @@ func numBlockLists(db *Lowlevel) (int, error) {
 	}
 	return n, nil
 }
+
+func TestDropFolderNamespaces(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	// Folder "ab" starts like folder "a" and must be left alone.
+	v := protocol.Vector{Counters: []protocol.Counter{{ID: 1, Value: 1}}}
+	now := time.Now()
+	for _, folder := range []string{"a", "ab"} {
+		if err := NewFolderPinsNamespace(ldb, folder).PutBytes(FolderPinsKey, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewMergeAncestors(ldb, folder, 1).Put("file", v, []byte("x")); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewConflictLog(ldb, folder, 1).Add(ConflictRecord{Time: now, Name: "file"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := NewLocalVersions(ldb, folder, 1).Add("file", now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	DropFolder(ldb, "a")
+
+	for folder, kept := range map[string]bool{"a": false, "ab": true} {
+		if _, ok, err := NewFolderPinsNamespace(ldb, folder).Bytes(FolderPinsKey); err != nil || ok != kept {
+			t.Errorf("%s: pins kept %v, %v", folder, ok, err)
+		}
+		if _, ok, err := NewMergeAncestors(ldb, folder, 1).Get("file", v, v); err != nil || ok != kept {
+			t.Errorf("%s: merge ancestors kept %v, %v", folder, ok, err)
+		}
+		if recs, err := NewConflictLog(ldb, folder, 1).Records(); err != nil || (len(recs) > 0) != kept {
+			t.Errorf("%s: conflict log kept %v, %v", folder, recs, err)
+		}
+		if versions, err := NewLocalVersions(ldb, folder, 1).Get(); err != nil || (len(versions) > 0) != kept {
+			t.Errorf("%s: local versions kept %v, %v", folder, versions, err)
+		}
+	}
+}
@@ -8,6 +8,8 @@ package db
 
 import (
 	"encoding/binary"
+
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 const (
@@ -16,6 +18,7 @@ const (
 	keyDeviceLen   = 4 // indexed
 	keySequenceLen = 8
 	keyHashLen     = 32
+	keyHashAlgoLen = 1
 
 	maxInt64 int64 = 1<<63 - 1
 )
@@ -27,7 +30,7 @@ const (
 	// KeyTypeGlobal <int32 folder ID> <file name> = VersionList
 	KeyTypeGlobal byte = 1
 
-	// KeyTypeBlock <int32 folder ID> <32 bytes hash> <§file name> = int32 (block index)
+	// KeyTypeBlock <int32 folder ID> <byte hash algorithm> <32 bytes hash> <§file name> = int32 (block index) int64 (block offset)
 	KeyTypeBlock byte = 2
 
 	// KeyTypeDeviceStatistic <device ID as string> <some string> = some value
@@ -74,6 +77,18 @@ const (
 
 	// KeyTypePendingDevice <device ID in wire format> = ObservedDevice
 	KeyTypePendingDevice byte = 17
+
+	// KeyTypeFolderPins <folder ID as string> "paths" = some value
+	KeyTypeFolderPins byte = 18
+
+	// KeyTypeFolderMergeAncestors <folder ID as string> <zero byte> <file name> = MergeAncestors
+	KeyTypeFolderMergeAncestors byte = 19
+
+	// KeyTypeFolderConflictLog <folder ID as string> "log" = ConflictLog
+	KeyTypeFolderConflictLog byte = 20
+
+	// KeyTypeFolderLocalVersions <folder ID as string> <zero byte> <int64 version time> <file name> = nothing
+	KeyTypeFolderLocalVersions byte = 21
 )
 
 type keyer interface {
@@ -88,7 +103,7 @@ type keyer interface {
 	NameFromGlobalVersionKey(key []byte) []byte
 
 	// block map key stuff (former BlockMap)
-	GenerateBlockMapKey(key, folder, hash, name []byte) (blockMapKey, error)
+	GenerateBlockMapKey(key, folder []byte, algo protocol.HashAlgorithm, hash, name []byte) (blockMapKey, error)
 	NameFromBlockMapKey(key []byte) []byte
 	GenerateBlockListMapKey(key, folder, hash, name []byte) (blockListMapKey, error)
 	NameFromBlockListMapKey(key []byte) []byte
@@ -203,21 +218,22 @@ func (defaultKeyer) NameFromGlobalVersionKey(key []byte) []byte {
 
 type blockMapKey []byte
 
-func (k defaultKeyer) GenerateBlockMapKey(key, folder, hash, name []byte) (blockMapKey, error) {
+func (k defaultKeyer) GenerateBlockMapKey(key, folder []byte, algo protocol.HashAlgorithm, hash, name []byte) (blockMapKey, error) {
 	folderID, err := k.folderIdx.ID(folder)
 	if err != nil {
 		return nil, err
 	}
-	key = resize(key, keyPrefixLen+keyFolderLen+keyHashLen+len(name))
+	key = resize(key, keyPrefixLen+keyFolderLen+keyHashAlgoLen+keyHashLen+len(name))
 	key[0] = KeyTypeBlock
 	binary.BigEndian.PutUint32(key[keyPrefixLen:], folderID)
-	copy(key[keyPrefixLen+keyFolderLen:], hash)
-	copy(key[keyPrefixLen+keyFolderLen+keyHashLen:], name)
+	key[keyPrefixLen+keyFolderLen] = byte(algo)
+	copy(key[keyPrefixLen+keyFolderLen+keyHashAlgoLen:], hash)
+	copy(key[keyPrefixLen+keyFolderLen+keyHashAlgoLen+keyHashLen:], name)
 	return key, nil
 }
 
 func (defaultKeyer) NameFromBlockMapKey(key []byte) []byte {
-	return key[keyPrefixLen+keyFolderLen+keyHashLen:]
+	return key[keyPrefixLen+keyFolderLen+keyHashAlgoLen+keyHashLen:]
 }
 
 func (k blockMapKey) WithoutHashAndName() []byte {
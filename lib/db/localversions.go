@@ -0,0 +1,161 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// Each local version is a key of its own, below this prefix in the
+// namespace, followed by the big endian version time in nanoseconds and the
+// file name. Keys thus sort oldest first. The zero byte can't be part of a
+// folder ID, so the keys of one folder never fall under the prefix of
+// another.
+const localVersionsPrefix = "\x00"
+
+// LocalVersions records which versions in the version archive of a folder
+// hold contents changed locally. All other versions were archived because
+// of changes by other devices.
+type LocalVersions struct {
+	kv   *NamespacedKV
+	keep int
+	// The number of recorded versions, or -1 until counted.
+	count int
+	mut   sync.Mutex
+}
+
+// NewLocalVersions returns the record of local versions of the given
+// folder, keeping at most keep entries.
+func NewLocalVersions(db backend.Backend, folder string, keep int) *LocalVersions {
+	return &LocalVersions{
+		kv:    NewFolderLocalVersionsNamespace(db, folder),
+		keep:  keep,
+		count: -1,
+		mut:   sync.NewMutex(),
+	}
+}
+
+// Add records the version of the named file at the given time as local,
+// dropping the oldest entries if there are too many.
+func (v *LocalVersions) Add(name string, versionTime time.Time) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if v.count < 0 {
+		count := 0
+		if err := v.iterate(func(string, time.Time, []byte) bool {
+			count++
+			return true
+		}); err != nil {
+			return err
+		}
+		v.count = count
+	}
+
+	key := localVersionKey(name, versionTime)
+	if _, ok, err := v.kv.Bytes(key); err != nil {
+		return err
+	} else if ok {
+		return nil
+	}
+	if err := v.kv.PutBytes(key, nil); err != nil {
+		return err
+	}
+	v.count++
+
+	if v.count <= v.keep {
+		return nil
+	}
+	var drop [][]byte
+	if err := v.iterate(func(_ string, _ time.Time, key []byte) bool {
+		drop = append(drop, key)
+		return len(drop) < v.count-v.keep
+	}); err != nil {
+		return err
+	}
+	return v.delete(drop)
+}
+
+// Get returns the times of the recorded local versions, per file.
+func (v *LocalVersions) Get() (map[string][]time.Time, error) {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	res := make(map[string][]time.Time)
+	err := v.iterate(func(name string, versionTime time.Time, _ []byte) bool {
+		res[name] = append(res[name], versionTime)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Retain forgets the recorded local versions for which keep returns false,
+// such as those removed from the archive.
+func (v *LocalVersions) Retain(keep func(name string, versionTime time.Time) bool) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	var drop [][]byte
+	if err := v.iterate(func(name string, versionTime time.Time, key []byte) bool {
+		if !keep(name, versionTime) {
+			drop = append(drop, key)
+		}
+		return true
+	}); err != nil {
+		return err
+	}
+	return v.delete(drop)
+}
+
+// iterate calls fn for the recorded versions, oldest first, with the full
+// database key of each, until fn returns false.
+func (v *LocalVersions) iterate(fn func(name string, versionTime time.Time, key []byte) bool) error {
+	prefix := v.kv.prefixedKey(localVersionsPrefix)
+	it, err := v.kv.db.NewPrefixIterator(prefix)
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+	for it.Next() {
+		rest := it.Key()[len(prefix):]
+		if len(rest) < 8 {
+			continue
+		}
+		versionTime := time.Unix(0, int64(binary.BigEndian.Uint64(rest)))
+		if !fn(string(rest[8:]), versionTime, append([]byte(nil), it.Key()...)) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// delete removes the given database keys. Iterating must have finished, as
+// not all backends allow writes while an iterator is open.
+func (v *LocalVersions) delete(keys [][]byte) error {
+	for _, key := range keys {
+		if err := v.kv.db.Delete(key); err != nil {
+			return err
+		}
+	}
+	if v.count >= 0 {
+		v.count -= len(keys)
+	}
+	return nil
+}
+
+func localVersionKey(name string, versionTime time.Time) string {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], uint64(versionTime.UnixNano()))
+	return localVersionsPrefix + string(key[:]) + name
+}
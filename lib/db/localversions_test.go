@@ -0,0 +1,92 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLocalVersions(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	v := NewLocalVersions(ldb, "folder", 2)
+	now := time.Now().Truncate(time.Second)
+	for i, name := range []string{"a", "b", "b"} {
+		if err := v.Add(name, now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the latest two are kept.
+	versions, err := NewLocalVersions(ldb, "folder", 2).Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 1 || len(versions["b"]) != 2 || !versions["b"][1].Equal(now.Add(2*time.Second)) {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+
+	err = v.Retain(func(name string, versionTime time.Time) bool {
+		return versionTime.Equal(now.Add(2 * time.Second))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := v.Get(); err != nil || len(versions["b"]) != 1 {
+		t.Fatalf("got %v, %v, expected one version after retain", versions, err)
+	}
+
+	if versions, err := NewLocalVersions(ldb, "other", 2).Get(); err != nil || len(versions) != 0 {
+		t.Errorf("got %v, %v, expected no versions for other folder", versions, err)
+	}
+
+	// Nor do the versions of a folder whose ID starts with this one's
+	// show up here.
+	if err := NewLocalVersions(ldb, "folder2", 2).Add("c", now); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := v.Get(); err != nil || len(versions) != 1 {
+		t.Errorf("got %v, %v, expected only this folder's versions", versions, err)
+	}
+}
+
+func TestLocalVersionsTrim(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	now := time.Now().Truncate(time.Second)
+	v := NewLocalVersions(ldb, "folder", 10)
+	for i := 0; i < 5; i++ {
+		if err := v.Add("a", now.Add(time.Duration(i)*time.Second)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A fresh instance counts what is there, and trims to its own limit
+	// dropping the oldest.
+	v = NewLocalVersions(ldb, "folder", 3)
+	if err := v.Add("b", now.Add(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := v.Get()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["a"]) != 2 || !versions["a"][0].Equal(now.Add(3*time.Second)) || len(versions["b"]) != 1 {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+
+	// Recording the same version twice doesn't count twice.
+	if err := v.Add("b", now.Add(10*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := v.Get(); err != nil || len(versions["a"]) != 2 {
+		t.Fatalf("got %v, %v, expected the versions to be unchanged", versions, err)
+	}
+}
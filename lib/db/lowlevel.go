@@ -10,7 +10,6 @@ import (
 	"bytes"
 	"context"
 	"crypto/sha256"
-	"encoding/binary"
 	"errors"
 	"fmt"
 	"hash/maphash"
@@ -203,7 +202,6 @@ func (db *Lowlevel) updateLocalFiles(folder []byte, fs []protocol.FileInfo, meta
 	defer t.close()
 
 	var dk, gk, keyBuf []byte
-	blockBuf := make([]byte, 4)
 	for _, f := range fs {
 		name := []byte(f.Name)
 		dk, err = db.keyer.GenerateDeviceFileKey(dk, folder, protocol.LocalDeviceID[:], name)
@@ -255,15 +253,9 @@ func (db *Lowlevel) updateLocalFiles(folder []byte, fs []protocol.FileInfo, meta
 		l.Debugf("adding sequence; folder=%q sequence=%v %v", folder, f.Sequence, f.Name)
 
 		if len(f.Blocks) != 0 && !f.IsInvalid() && f.Size > 0 {
-			for i, block := range f.Blocks {
-				binary.BigEndian.PutUint32(blockBuf, uint32(i))
-				keyBuf, err = db.keyer.GenerateBlockMapKey(keyBuf, folder, block.Hash, name)
-				if err != nil {
-					return err
-				}
-				if err := t.Put(keyBuf, blockBuf); err != nil {
-					return err
-				}
+			keyBuf, err = t.putBlockMapEntries(keyBuf, folder, name, f)
+			if err != nil {
+				return err
 			}
 			if !blocksHashSame {
 				keyBuf, err := db.keyer.GenerateBlockListMapKey(keyBuf, folder, f.BlocksHash, name)
@@ -344,7 +336,7 @@ func (db *Lowlevel) removeLocalBlockAndSequenceInfo(keyBuf, folder, name []byte,
 	var err error
 	if len(ef.Blocks) != 0 && !ef.IsInvalid() && ef.Size > 0 {
 		for _, block := range ef.Blocks {
-			keyBuf, err = db.keyer.GenerateBlockMapKey(keyBuf, folder, block.Hash, name)
+			keyBuf, err = db.keyer.GenerateBlockMapKey(keyBuf, folder, ef.HashAlgorithm, block.Hash, name)
 			if err != nil {
 				return nil, err
 			}
@@ -421,7 +413,7 @@ func (db *Lowlevel) dropFolder(folder []byte) error {
 	}
 
 	// Remove the blockmap of the folder
-	k4, err := db.keyer.GenerateBlockMapKey(k3, folder, nil, nil)
+	k4, err := db.keyer.GenerateBlockMapKey(k3, folder, protocol.HashAlgorithmSHA256, nil, nil)
 	if err != nil {
 		return err
 	}
@@ -437,6 +429,26 @@ func (db *Lowlevel) dropFolder(folder []byte) error {
 		return err
 	}
 
+	// Remove what's kept in the namespaces of the folder. Their keys are
+	// fixed or start with a zero byte, so that the folders whose IDs start
+	// with this one are left alone.
+	for _, key := range []string{
+		string(KeyTypeFolderPins) + string(folder) + FolderPinsKey,
+		string(KeyTypeFolderConflictLog) + string(folder) + conflictLogKey,
+	} {
+		if err := t.Delete([]byte(key)); err != nil {
+			return err
+		}
+	}
+	for _, prefix := range []string{
+		string(KeyTypeFolderMergeAncestors) + string(folder) + mergeAncestorsPrefix,
+		string(KeyTypeFolderLocalVersions) + string(folder) + localVersionsPrefix,
+	} {
+		if err := t.deleteKeyPrefix([]byte(prefix)); err != nil {
+			return err
+		}
+	}
+
 	return t.Commit()
 }
 
@@ -484,7 +496,7 @@ func (db *Lowlevel) dropDeviceFolder(device, folder []byte, meta *metadataTracke
 	}
 
 	if bytes.Equal(device, protocol.LocalDeviceID[:]) {
-		key, err := db.keyer.GenerateBlockMapKey(nil, folder, nil, nil)
+		key, err := db.keyer.GenerateBlockMapKey(nil, folder, protocol.HashAlgorithmSHA256, nil, nil)
 		if err != nil {
 			return err
 		}
@@ -521,48 +533,12 @@ func (db *Lowlevel) checkGlobals(folderStr string) (int, error) {
 	defer dbi.Release()
 
 	fixed := 0
-	var dk []byte
-	ro := t.readOnlyTransaction
 	for dbi.Next() {
-		var vl dbproto.VersionList
-		if err := proto.Unmarshal(dbi.Value(), &vl); err != nil || len(vl.Versions) == 0 {
-			if err := t.Delete(dbi.Key()); err != nil && !backend.IsNotFound(err) {
-				return 0, err
-			}
-			continue
-		}
-
-		// Check the global version list for consistency. An issue in previous
-		// versions of goleveldb could result in reordered writes so that
-		// there are global entries pointing to no longer existing files. Here
-		// we find those and clear them out.
-
-		name := db.keyer.NameFromGlobalVersionKey(dbi.Key())
-		newVL := &dbproto.VersionList{}
-		var changed, changedHere bool
-		for _, fv := range vl.Versions {
-			changedHere, err = checkGlobalsFilterDevices(dk, folder, name, fv.Devices, newVL, ro)
-			if err != nil {
-				return 0, err
-			}
-			changed = changed || changedHere
-
-			changedHere, err = checkGlobalsFilterDevices(dk, folder, name, fv.InvalidDevices, newVL, ro)
-			if err != nil {
-				return 0, err
-			}
-			changed = changed || changedHere
+		changed, err := db.checkGlobal(&t, folder, dbi.Key(), dbi.Value())
+		if err != nil {
+			return 0, err
 		}
-
-		if len(newVL.Versions) == 0 {
-			if err := t.Delete(dbi.Key()); err != nil && !backend.IsNotFound(err) {
-				return 0, err
-			}
-			fixed++
-		} else if changed {
-			if err := t.Put(dbi.Key(), mustMarshal(newVL)); err != nil {
-				return 0, err
-			}
+		if changed {
 			fixed++
 		}
 	}
@@ -575,6 +551,56 @@ func (db *Lowlevel) checkGlobals(folderStr string) (int, error) {
 	return fixed, t.Commit()
 }
 
+// checkGlobal drops the devices without a matching file from the global
+// version list at key, and the list itself if that leaves it empty. It
+// returns whether the list was repaired.
+func (db *Lowlevel) checkGlobal(t *readWriteTransaction, folder, key, val []byte) (bool, error) {
+	var vl dbproto.VersionList
+	if err := proto.Unmarshal(val, &vl); err != nil || len(vl.Versions) == 0 {
+		if err := t.Delete(key); err != nil && !backend.IsNotFound(err) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	// Check the global version list for consistency. An issue in previous
+	// versions of goleveldb could result in reordered writes so that
+	// there are global entries pointing to no longer existing files. Here
+	// we find those and clear them out.
+
+	name := db.keyer.NameFromGlobalVersionKey(key)
+	newVL := &dbproto.VersionList{}
+	var dk []byte
+	var changed, changedHere bool
+	var err error
+	for _, fv := range vl.Versions {
+		changedHere, err = checkGlobalsFilterDevices(dk, folder, name, fv.Devices, newVL, t.readOnlyTransaction)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || changedHere
+
+		changedHere, err = checkGlobalsFilterDevices(dk, folder, name, fv.InvalidDevices, newVL, t.readOnlyTransaction)
+		if err != nil {
+			return false, err
+		}
+		changed = changed || changedHere
+	}
+
+	if len(newVL.Versions) == 0 {
+		if err := t.Delete(key); err != nil && !backend.IsNotFound(err) {
+			return false, err
+		}
+		return true, nil
+	} else if changed {
+		if err := t.Put(key, mustMarshal(newVL)); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
 func checkGlobalsFilterDevices(dk, folder, name []byte, devices [][]byte, vl *dbproto.VersionList, t readOnlyTransaction) (bool, error) {
 	var changed bool
 	var err error
@@ -1073,14 +1099,35 @@ func (db *Lowlevel) recalcMeta(folderStr string) (*metadataTracker, error) {
 	}
 	defer t.close()
 
+	if err := countMeta(&t, folder, meta); err != nil {
+		return nil, err
+	}
+
+	meta.SetCreated()
+	if err := t.Commit(); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
+
+// A metaCountingTransaction is either a read-only transaction or a
+// read-write one, which also drops invalid files it comes across.
+type metaCountingTransaction interface {
+	withAllFolderTruncated(folder []byte, fn func(device []byte, f protocol.FileInfo) bool) error
+	withGlobal(folder, prefix []byte, truncate bool, fn Iterator) error
+	withNeed(folder, device []byte, truncate bool, fn Iterator) error
+}
+
+// countMeta adds up the metadata of the folder as seen by t.
+func countMeta(t metaCountingTransaction, folder []byte, meta *metadataTracker) error {
 	var deviceID protocol.DeviceID
-	err = t.withAllFolderTruncated(folder, func(device []byte, f protocol.FileInfo) bool {
+	err := t.withAllFolderTruncated(folder, func(device []byte, f protocol.FileInfo) bool {
 		copy(deviceID[:], device)
 		meta.addFile(deviceID, f)
 		return true
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	err = t.withGlobal(folder, nil, true, func(f protocol.FileInfo) bool {
@@ -1088,7 +1135,7 @@ func (db *Lowlevel) recalcMeta(folderStr string) (*metadataTracker, error) {
 		return true
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	meta.emptyNeeded(protocol.LocalDeviceID)
@@ -1097,7 +1144,7 @@ func (db *Lowlevel) recalcMeta(folderStr string) (*metadataTracker, error) {
 		return true
 	})
 	if err != nil {
-		return nil, err
+		return err
 	}
 	for _, device := range meta.devices() {
 		meta.emptyNeeded(device)
@@ -1106,15 +1153,10 @@ func (db *Lowlevel) recalcMeta(folderStr string) (*metadataTracker, error) {
 			return true
 		})
 		if err != nil {
-			return nil, err
+			return err
 		}
 	}
-
-	meta.SetCreated()
-	if err := t.Commit(); err != nil {
-		return nil, err
-	}
-	return meta, nil
+	return nil
 }
 
 // Verify the local sequence number from actual sequence entries. Returns
@@ -1173,61 +1215,13 @@ func (db *Lowlevel) repairSequenceGCLocked(folderStr string, meta *metadataTrack
 	}
 	defer it.Release()
 
-	var sk sequenceKey
 	for it.Next() {
-		intf, err := t.unmarshalTrunc(it.Value(), false)
+		repaired, err := db.repairFileSequence(&t, folder, meta, it.Key(), it.Value())
 		if err != nil {
-			// Delete local items with invalid indirected blocks/versions.
-			// They will be rescanned.
-			var ierr *blocksIndirectionError
-			if ok := errors.As(err, &ierr); ok && backend.IsNotFound(err) {
-				intf, err = t.unmarshalTrunc(it.Value(), true)
-				if err != nil {
-					return 0, err
-				}
-				name := []byte(intf.FileName())
-				gk, err := t.keyer.GenerateGlobalVersionKey(nil, folder, name)
-				if err != nil {
-					return 0, err
-				}
-				_, err = t.removeFromGlobal(gk, nil, folder, protocol.LocalDeviceID[:], name, nil)
-				if err != nil {
-					return 0, err
-				}
-				sk, err = db.keyer.GenerateSequenceKey(sk, folder, intf.SequenceNo())
-				if err != nil {
-					return 0, err
-				}
-				if err := t.Delete(sk); err != nil {
-					return 0, err
-				}
-				if err := t.Delete(it.Key()); err != nil {
-					return 0, err
-				}
-			}
-			return 0, err
-		}
-		if sk, err = t.keyer.GenerateSequenceKey(sk, folder, intf.Sequence); err != nil {
 			return 0, err
 		}
-		switch dk, err = t.Get(sk); {
-		case err != nil:
-			if !backend.IsNotFound(err) {
-				return 0, err
-			}
-			fallthrough
-		case !bytes.Equal(it.Key(), dk):
+		if repaired {
 			fixed++
-			intf.Sequence = meta.nextLocalSeq()
-			if sk, err = t.keyer.GenerateSequenceKey(sk, folder, intf.Sequence); err != nil {
-				return 0, err
-			}
-			if err := t.Put(sk, it.Key()); err != nil {
-				return 0, err
-			}
-			if err := t.putFile(it.Key(), intf); err != nil {
-				return 0, err
-			}
 		}
 		if err := t.Checkpoint(); err != nil {
 			return 0, err
@@ -1241,7 +1235,7 @@ func (db *Lowlevel) repairSequenceGCLocked(folderStr string, meta *metadataTrack
 
 	// Secondly check there's no sequence entries pointing at incorrect things.
 
-	sk, err = t.keyer.GenerateSequenceKey(sk, folder, 0)
+	sk, err := t.keyer.GenerateSequenceKey(nil, folder, 0)
 	if err != nil {
 		return 0, err
 	}
@@ -1253,21 +1247,12 @@ func (db *Lowlevel) repairSequenceGCLocked(folderStr string, meta *metadataTrack
 	defer it.Release()
 
 	for it.Next() {
-		// Check that the sequence from the key matches the
-		// sequence in the file.
-		fi, ok, err := t.getFileTrunc(it.Value(), true)
+		removed, err := db.checkSequenceKey(&t, it.Key(), it.Value())
 		if err != nil {
 			return 0, err
 		}
-		if ok {
-			if seq := t.keyer.SequenceFromSequenceKey(it.Key()); seq == fi.SequenceNo() {
-				continue
-			}
-		}
-		// Either the file is missing or has a different sequence number
-		fixed++
-		if err := t.Delete(it.Key()); err != nil {
-			return 0, err
+		if removed {
+			fixed++
 		}
 	}
 	if err := it.Error(); err != nil {
@@ -1279,6 +1264,89 @@ func (db *Lowlevel) repairSequenceGCLocked(folderStr string, meta *metadataTrack
 	return fixed, t.Commit()
 }
 
+// repairFileSequence makes sure the local file entry at key has a matching
+// sequence entry, giving the file a new sequence number otherwise. It
+// returns whether that was necessary.
+func (db *Lowlevel) repairFileSequence(t *readWriteTransaction, folder []byte, meta *metadataTracker, key, val []byte) (bool, error) {
+	intf, err := t.unmarshalTrunc(val, false)
+	if err != nil {
+		// Delete local items with invalid indirected blocks/versions.
+		// They will be rescanned.
+		var ierr *blocksIndirectionError
+		if ok := errors.As(err, &ierr); ok && backend.IsNotFound(err) {
+			intf, err = t.unmarshalTrunc(val, true)
+			if err != nil {
+				return false, err
+			}
+			name := []byte(intf.FileName())
+			gk, err := t.keyer.GenerateGlobalVersionKey(nil, folder, name)
+			if err != nil {
+				return false, err
+			}
+			_, err = t.removeFromGlobal(gk, nil, folder, protocol.LocalDeviceID[:], name, nil)
+			if err != nil {
+				return false, err
+			}
+			sk, err := db.keyer.GenerateSequenceKey(nil, folder, intf.SequenceNo())
+			if err != nil {
+				return false, err
+			}
+			if err := t.Delete(sk); err != nil {
+				return false, err
+			}
+			if err := t.Delete(key); err != nil {
+				return false, err
+			}
+		}
+		return false, err
+	}
+	sk, err := t.keyer.GenerateSequenceKey(nil, folder, intf.Sequence)
+	if err != nil {
+		return false, err
+	}
+	switch dk, err := t.Get(sk); {
+	case err != nil:
+		if !backend.IsNotFound(err) {
+			return false, err
+		}
+		fallthrough
+	case !bytes.Equal(key, dk):
+		intf.Sequence = meta.nextLocalSeq()
+		if sk, err = t.keyer.GenerateSequenceKey(sk, folder, intf.Sequence); err != nil {
+			return false, err
+		}
+		if err := t.Put(sk, key); err != nil {
+			return false, err
+		}
+		if err := t.putFile(key, intf); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// checkSequenceKey removes the sequence entry at key if it doesn't point
+// at a file with that sequence number. It returns whether it was removed.
+func (db *Lowlevel) checkSequenceKey(t *readWriteTransaction, key, val []byte) (bool, error) {
+	// Check that the sequence from the key matches the
+	// sequence in the file.
+	fi, ok, err := t.getFileTrunc(val, true)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		if seq := t.keyer.SequenceFromSequenceKey(key); seq == fi.SequenceNo() {
+			return false, nil
+		}
+	}
+	// Either the file is missing or has a different sequence number
+	if err := t.Delete(key); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // Does not take care of metadata - if anything is repaired, the need count
 // needs to be recalculated.
 func (db *Lowlevel) checkLocalNeed(folder []byte) (int, error) {
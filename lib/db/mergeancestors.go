@@ -0,0 +1,96 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/syncthing/syncthing/internal/gen/dbproto"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The ancestors of each file are a key of their own, below this prefix in
+// the namespace and followed by the file name. The zero byte can't be part
+// of a folder ID, so the keys of one folder never fall under the prefix of
+// another.
+const mergeAncestorsPrefix = "\x00"
+
+// MergeAncestors keeps the contents of the latest few versions of files in
+// a folder, to serve as the common ancestor when merging conflicting
+// changes.
+type MergeAncestors struct {
+	kv   *NamespacedKV
+	keep int
+}
+
+// NewMergeAncestors returns the ancestor cache of the given folder, keeping
+// at most keep versions per file.
+func NewMergeAncestors(db backend.Backend, folder string, keep int) *MergeAncestors {
+	return &MergeAncestors{
+		kv:   NewFolderMergeAncestorsNamespace(db, folder),
+		keep: keep,
+	}
+}
+
+// Put records the contents of the given version of a file, dropping the
+// oldest recorded version if there are too many.
+func (a *MergeAncestors) Put(name string, version protocol.Vector, data []byte) error {
+	ancs, err := a.load(name)
+	if err != nil {
+		return err
+	}
+
+	kept := make([]*dbproto.MergeAncestor, 0, a.keep)
+	kept = append(kept, &dbproto.MergeAncestor{Version: version.ToWire(), Data: data})
+	for _, anc := range ancs.GetAncestors() {
+		if len(kept) == a.keep {
+			break
+		}
+		if protocol.VectorFromWire(anc.Version).Equal(version) {
+			continue
+		}
+		kept = append(kept, anc)
+	}
+
+	return a.kv.PutBytes(mergeAncestorsPrefix+name, mustMarshal(&dbproto.MergeAncestors{Ancestors: kept}))
+}
+
+// Get returns the contents of the latest recorded version of a file that
+// both given versions are based on.
+func (a *MergeAncestors) Get(name string, v1, v2 protocol.Vector) ([]byte, bool, error) {
+	ancs, err := a.load(name)
+	if err != nil {
+		return nil, false, err
+	}
+	for _, anc := range ancs.GetAncestors() {
+		version := protocol.VectorFromWire(anc.Version)
+		if version.LesserEqual(v1) && version.LesserEqual(v2) {
+			return anc.Data, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// Delete forgets all recorded versions of a file.
+func (a *MergeAncestors) Delete(name string) error {
+	return a.kv.Delete(mergeAncestorsPrefix + name)
+}
+
+func (a *MergeAncestors) load(name string) (*dbproto.MergeAncestors, error) {
+	bs, ok, err := a.kv.Bytes(mergeAncestorsPrefix + name)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var ancs dbproto.MergeAncestors
+	if err := proto.Unmarshal(bs, &ancs); err != nil {
+		// Nothing worth keeping, start over.
+		l.Debugf("Dropping unreadable merge ancestors for %s: %v", name, err)
+		return nil, nil
+	}
+	return &ancs, nil
+}
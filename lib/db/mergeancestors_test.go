@@ -0,0 +1,68 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestMergeAncestors(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	a := NewMergeAncestors(ldb, "folder", 2)
+	var local, remote protocol.ShortID = 1, 2
+	vector := func(localValue, remoteValue uint64) protocol.Vector {
+		return protocol.Vector{Counters: []protocol.Counter{{ID: local, Value: localValue}, {ID: remote, Value: remoteValue}}}
+	}
+
+	v1 := vector(1, 0)
+	v2 := vector(2, 0)
+	v3 := vector(3, 0)
+	for i, v := range []protocol.Vector{v1, v2, v3} {
+		if err := a.Put("file", v, []byte{byte('1' + i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The latest version that both are based on.
+	ours := vector(4, 0)
+	theirs := vector(2, 1)
+	if data, ok, err := a.Get("file", ours, theirs); err != nil || !ok || string(data) != "2" {
+		t.Errorf("got %q, %v, %v, expected version 2", data, ok, err)
+	}
+
+	// The first version has been dropped, as only two are kept.
+	theirs = vector(1, 1)
+	if _, ok, err := a.Get("file", ours, theirs); err != nil || ok {
+		t.Errorf("got %v, %v, expected no ancestor", ok, err)
+	}
+
+	if err := a.Delete("file"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := a.Get("file", v3, v3); err != nil || ok {
+		t.Errorf("got %v, %v, expected no ancestor after delete", ok, err)
+	}
+}
+
+func TestMergeAncestorsFolderPrefix(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	// Folder "a" with file "bfile" must not meet folder "ab" with file
+	// "file".
+	v := protocol.Vector{Counters: []protocol.Counter{{ID: 1, Value: 1}}}
+	if err := NewMergeAncestors(ldb, "a", 2).Put("bfile", v, []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, err := NewMergeAncestors(ldb, "ab", 2).Get("file", v, v); err != nil || ok {
+		t.Errorf("got %v, %v, expected no ancestor in another folder", ok, err)
+	}
+}
@@ -371,6 +371,57 @@ func (m *metadataTracker) resetCounts(dev protocol.DeviceID) {
 	m.mut.Unlock()
 }
 
+// replaceCounts replaces the counts with the recalculated ones from other,
+// retaining higher sequence numbers and need counts for devices not
+// covered by other. It returns the number of counts that changed.
+func (m *metadataTracker) replaceCounts(other *metadataTracker) int {
+	other.mut.RLock()
+	defer other.mut.RUnlock()
+	m.mut.Lock()
+	defer m.mut.Unlock()
+
+	changed := 0
+	counts := make([]Counts, 0, len(m.counts.Counts)+len(other.counts.Counts))
+	indexes := make(map[metaKey]int, len(m.indexes)+len(other.indexes))
+	for _, c := range other.counts.Counts {
+		key := metaKey{c.DeviceID, c.LocalFlags}
+		old := Counts{DeviceID: c.DeviceID, LocalFlags: c.LocalFlags}
+		if idx, ok := m.indexes[key]; ok {
+			old = m.counts.Counts[idx]
+		}
+		if old.Sequence > c.Sequence {
+			c.Sequence = old.Sequence
+		}
+		if c != old {
+			changed++
+		}
+		indexes[key] = len(counts)
+		counts = append(counts, c)
+	}
+	for _, c := range m.counts.Counts {
+		key := metaKey{c.DeviceID, c.LocalFlags}
+		if _, ok := indexes[key]; ok {
+			continue
+		}
+		if c.LocalFlags != needFlag {
+			// Nothing left in this bucket.
+			empty := Counts{DeviceID: c.DeviceID, LocalFlags: c.LocalFlags, Sequence: c.Sequence}
+			if c != empty {
+				changed++
+			}
+			c = empty
+		}
+		indexes[key] = len(counts)
+		counts = append(counts, c)
+	}
+
+	m.counts.Counts = counts
+	m.counts.Created = other.counts.Created
+	m.indexes = indexes
+	m.dirty = true
+	return changed
+}
+
 func (m *countsMap) Counts(dev protocol.DeviceID, flag uint32) Counts {
 	if bits.OnesCount32(flag) > 1 {
 		panic("incorrect usage: set at most one bit in flag")
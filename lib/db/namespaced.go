@@ -143,6 +143,34 @@ func NewFolderStatisticsNamespace(db backend.Backend, folder string) *Namespaced
 	return NewNamespacedKV(db, string(KeyTypeFolderStatistic)+folder)
 }
 
+// FolderPinsKey is the key of the pinned paths in the pins namespace of a
+// folder.
+const FolderPinsKey = "paths"
+
+// NewFolderPinsNamespace creates a KV namespace for the paths requested to
+// be present locally in an on-demand folder.
+func NewFolderPinsNamespace(db backend.Backend, folder string) *NamespacedKV {
+	return NewNamespacedKV(db, string(KeyTypeFolderPins)+folder)
+}
+
+// NewFolderMergeAncestorsNamespace creates a KV namespace for the file
+// contents kept as base for merging conflicting changes in the given folder.
+func NewFolderMergeAncestorsNamespace(db backend.Backend, folder string) *NamespacedKV {
+	return NewNamespacedKV(db, string(KeyTypeFolderMergeAncestors)+folder)
+}
+
+// NewFolderConflictLogNamespace creates a KV namespace for the log of
+// resolved conflicts in the given folder.
+func NewFolderConflictLogNamespace(db backend.Backend, folder string) *NamespacedKV {
+	return NewNamespacedKV(db, string(KeyTypeFolderConflictLog)+folder)
+}
+
+// NewFolderLocalVersionsNamespace creates a KV namespace for the record of
+// locally changed versions in the version archive of the given folder.
+func NewFolderLocalVersionsNamespace(db backend.Backend, folder string) *NamespacedKV {
+	return NewNamespacedKV(db, string(KeyTypeFolderLocalVersions)+folder)
+}
+
 // NewMiscDataNamespace creates a KV namespace for miscellaneous metadata.
 func NewMiscDataNamespace(db backend.Backend) *NamespacedKV {
 	return NewNamespacedKV(db, string(KeyTypeMiscData))
@@ -18,9 +18,9 @@ import (
 // dbMigrationVersion is for migrations that do not change the schema and thus
 // do not put restrictions on downgrades (e.g. for repairs after a bugfix).
 const (
-	dbVersion             = 14
-	dbMigrationVersion    = 20
-	dbMinSyncthingVersion = "v1.9.0"
+	dbVersion             = 15
+	dbMigrationVersion    = 21
+	dbMinSyncthingVersion = "v1.30.0"
 )
 
 type migration struct {
@@ -99,6 +99,7 @@ func (db *schemaUpdater) updateSchema() error {
 		{14, 17, "v1.9.0", db.migration17},
 		{14, 19, "v1.9.0", db.dropAllIndexIDsMigration},
 		{14, 20, "v1.9.0", db.dropOutgoingIndexIDsMigration},
+		{15, 21, "v1.30.0", db.rebuildBlockMapMigration},
 	}
 
 	for _, m := range migrations {
@@ -269,3 +270,41 @@ func (db *schemaUpdater) dropAllIndexIDsMigration(_ int) error {
 func (db *schemaUpdater) dropOutgoingIndexIDsMigration(_ int) error {
 	return db.dropOtherDeviceIndexIDs()
 }
+
+// rebuildBlockMapMigration recreates the block map from the local files, as
+// its keys now include the hash algorithm and its values the block offset.
+func (db *schemaUpdater) rebuildBlockMapMigration(_ int) error {
+	t, err := db.newReadWriteTransaction()
+	if err != nil {
+		return err
+	}
+	defer t.close()
+
+	if err := t.deleteKeyPrefix([]byte{KeyTypeBlock}); err != nil {
+		return err
+	}
+
+	var keyBuf []byte
+	for _, folderStr := range db.ListFolders() {
+		folder := []byte(folderStr)
+		var innerErr error
+		err := t.withHave(folder, protocol.LocalDeviceID[:], nil, false, func(fi protocol.FileInfo) bool {
+			if len(fi.Blocks) == 0 || fi.IsInvalid() || fi.Size <= 0 {
+				return true
+			}
+			keyBuf, innerErr = t.putBlockMapEntries(keyBuf, folder, []byte(fi.Name), fi)
+			if innerErr == nil {
+				innerErr = t.Checkpoint()
+			}
+			return innerErr == nil
+		})
+		if innerErr != nil {
+			return innerErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return t.Commit()
+}
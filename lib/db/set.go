@@ -425,6 +425,41 @@ func (s *FileSet) MtimeOption() fs.Option {
 	return fs.NewMtimeOption(kv)
 }
 
+// MtimeMappings calls fn for the mtime mapping of each file that has one,
+// see MtimeOption.
+func (s *FileSet) MtimeMappings(fn func(name string, mapping fs.MtimeMapping) bool) error {
+	prefix, err := s.db.keyer.GenerateMtimesKey(nil, []byte(s.folder))
+	if err != nil {
+		return err
+	}
+	it, err := s.db.NewPrefixIterator(prefix)
+	if err != nil {
+		return err
+	}
+	defer it.Release()
+	for it.Next() {
+		var mapping fs.MtimeMapping
+		if err := mapping.Unmarshal(it.Value()); err != nil {
+			return err
+		}
+		if !fn(string(it.Key()[len(prefix):]), mapping) {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// SetMtimeMapping stores the mtime mapping for the given file, see
+// MtimeOption.
+func (s *FileSet) SetMtimeMapping(name string, mapping fs.MtimeMapping) error {
+	prefix, err := s.db.keyer.GenerateMtimesKey(nil, []byte(s.folder))
+	if err != nil {
+		return err
+	}
+	bs, _ := mapping.Marshal() // Can't fail
+	return NewNamespacedKV(s.db, string(prefix)).PutBytes(name, bs)
+}
+
 func (s *FileSet) ListDevices() []protocol.DeviceID {
 	return s.meta.devices()
 }
@@ -526,7 +526,7 @@ func TestUpdateToInvalid(t *testing.T) {
 		t.Errorf("Have incorrect after invalidation;\n A: %v !=\n E: %v", have, localHave)
 	}
 
-	f.Iterate([]string{folder}, oldBlockHash, func(folder, file string, index int32) bool {
+	f.Iterate([]string{folder}, protocol.HashAlgorithmSHA256, oldBlockHash, func(folder, file string, index int32, _ int64) bool {
 		if file == localHave[1].Name {
 			t.Errorf("Found unexpected block in blockmap for invalidated file")
 			return true
@@ -534,8 +534,8 @@ func TestUpdateToInvalid(t *testing.T) {
 		return false
 	})
 
-	if !f.Iterate([]string{folder}, localHave[4].Blocks[0].Hash, func(folder, file string, index int32) bool {
-		return file == localHave[4].Name
+	if !f.Iterate([]string{folder}, protocol.HashAlgorithmSHA256, localHave[4].Blocks[0].Hash, func(folder, file string, index int32, offset int64) bool {
+		return file == localHave[4].Name && offset == localHave[4].Blocks[0].Offset
 	}) {
 		t.Errorf("First block of un-invalidated file is missing from blockmap")
 	}
@@ -93,6 +93,9 @@ func (c Counts) String() string {
 	if c.LocalFlags&protocol.FlagLocalUnsupported != 0 {
 		flags.WriteString("Unsupported")
 	}
+	if c.LocalFlags&protocol.FlagLocalPlaceholder != 0 {
+		flags.WriteString("Placeholder")
+	}
 	if c.LocalFlags != 0 {
 		flags.WriteString(fmt.Sprintf("(%x)", c.LocalFlags))
 	}
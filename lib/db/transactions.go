@@ -8,6 +8,7 @@ package db
 
 import (
 	"bytes"
+	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -578,6 +579,25 @@ func (t readWriteTransaction) close() {
 	t.WriteTransaction.Release()
 }
 
+// putBlockMapEntries adds the blocks of the file to the block map, keyed
+// by hash algorithm and hash, with the block index and offset as value.
+func (t readWriteTransaction) putBlockMapEntries(keyBuf, folder, name []byte, fi protocol.FileInfo) ([]byte, error) {
+	var err error
+	var val [12]byte
+	for i, block := range fi.Blocks {
+		binary.BigEndian.PutUint32(val[:], uint32(i))
+		binary.BigEndian.PutUint64(val[4:], uint64(block.Offset))
+		keyBuf, err = t.keyer.GenerateBlockMapKey(keyBuf, folder, fi.HashAlgorithm, block.Hash, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Put(keyBuf, val[:]); err != nil {
+			return nil, err
+		}
+	}
+	return keyBuf, nil
+}
+
 // putFile stores a file in the database, taking care of indirected fields.
 func (t readWriteTransaction) putFile(fkey []byte, fi protocol.FileInfo) error {
 	var bkey []byte
@@ -947,6 +967,40 @@ func (t readWriteTransaction) deleteKeyPrefixMatching(prefix []byte, match func(
 	return dbi.Error()
 }
 
+// withAllFolderTruncated calls fn for the files of all devices in the
+// folder, skipping those the read-write variant would drop from the
+// database.
+func (t *readOnlyTransaction) withAllFolderTruncated(folder []byte, fn func(device []byte, f protocol.FileInfo) bool) error {
+	key, err := t.keyer.GenerateDeviceFileKey(nil, folder, nil, nil)
+	if err != nil {
+		return err
+	}
+	dbi, err := t.NewPrefixIterator(key.WithoutNameAndDevice())
+	if err != nil {
+		return err
+	}
+	defer dbi.Release()
+
+	for dbi.Next() {
+		device, ok := t.keyer.DeviceFromDeviceFileKey(dbi.Key())
+		if !ok {
+			continue
+		}
+		f, err := t.unmarshalTrunc(dbi.Value(), true)
+		if err != nil {
+			return err
+		}
+		switch f.Name {
+		case "", ".", "..", "/":
+			continue
+		}
+		if !fn(device, f) {
+			return nil
+		}
+	}
+	return dbi.Error()
+}
+
 func (t *readWriteTransaction) withAllFolderTruncated(folder []byte, fn func(device []byte, f protocol.FileInfo) bool) error {
 	key, err := t.keyer.GenerateDeviceFileKey(nil, folder, nil, nil)
 	if err != nil {
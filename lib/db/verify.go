@@ -0,0 +1,564 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/syncthing/syncthing/internal/gen/dbproto"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The checks performed by FileSet.Verify, in order.
+const (
+	VerifyCheckNeed     = "need"
+	VerifyCheckGlobal   = "global"
+	VerifyCheckSequence = "sequence"
+	VerifyCheckBlockMap = "blockMap"
+	VerifyCheckMetadata = "metadata"
+)
+
+// Number of entries checked, or orphaned block map entries removed, at a
+// time. Updates to the folder are held off while that happens.
+var verifyChunkSize = 1000
+
+const (
+	// Number of local block lists kept in memory while checking the block
+	// map.
+	verifyBlockCacheSize = 256
+	// Maximum number of orphaned block map entries removed in one pass.
+	// Anything beyond that is picked up by the next verification.
+	verifyMaxBlockMapOrphans = 10000
+)
+
+// A VerifyFinding is an inconsistency found, and repaired, by
+// FileSet.Verify.
+type VerifyFinding struct {
+	Check string `json:"check"`
+	Count int    `json:"count"`
+}
+
+// Verify checks the database invariants of the folder and repairs any
+// inconsistencies found in place. The database is checked a chunk at a
+// time and updates to the folder are only held off while each chunk is,
+// so this can be done while the folder is in use.
+func (s *FileSet) Verify(ctx context.Context) ([]VerifyFinding, error) {
+	checks := []struct {
+		name string
+		fn   func(context.Context) (int, error)
+	}{
+		{VerifyCheckNeed, s.verifyChunked(s.verifyNeedChunk)},
+		{VerifyCheckGlobal, s.verifyChunked(s.verifyGlobalChunk)},
+		{VerifyCheckSequence, s.verifyChunked(s.verifySequenceChunk)},
+		{VerifyCheckBlockMap, s.verifyBlockMap},
+		{VerifyCheckMetadata, s.verifyMetadata},
+	}
+
+	var findings []VerifyFinding
+	for _, check := range checks {
+		if err := ctx.Err(); err != nil {
+			return findings, err
+		}
+		n, err := check.fn(ctx)
+		if err != nil {
+			return findings, fmt.Errorf("%s check: %w", check.name, err)
+		}
+		if n > 0 {
+			l.Infof("Repaired %d %s entries for folder %v in database", n, check.name, s.folder)
+			findings = append(findings, VerifyFinding{Check: check.name, Count: n})
+		}
+	}
+	return findings, nil
+}
+
+// verifyChunked wraps fn to be called with the update and GC locks held
+// until it has checked everything. Each call checks a chunk starting at the
+// given key, or at the beginning if nil, and returns where the next one
+// starts, or nil when done.
+func (s *FileSet) verifyChunked(fn func(start []byte) ([]byte, int, error)) func(context.Context) (int, error) {
+	return func(ctx context.Context) (int, error) {
+		fixed := 0
+		var start []byte
+		for {
+			if err := ctx.Err(); err != nil {
+				return fixed, err
+			}
+			s.updateAndGCMutexLock()
+			next, n, err := fn(start)
+			s.updateMutex.Unlock()
+			s.db.gcMut.RUnlock()
+			fixed += n
+			if err != nil || next == nil {
+				return fixed, err
+			}
+			start = next
+		}
+	}
+}
+
+// verifyNeedChunk makes sure there are need entries for exactly the files
+// in a chunk of the global list that the local device needs.
+func (s *FileSet) verifyNeedChunk(start []byte) ([]byte, int, error) {
+	t, err := s.db.newReadWriteTransaction()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer t.close()
+
+	folder := []byte(s.folder)
+	gk, err := t.keyer.GenerateGlobalVersionKey(nil, folder, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	nk, err := t.keyer.GenerateNeedFileKey(nil, folder, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	needPrefix := nk.WithoutName()
+
+	fixed := 0
+	next, err := verifyIterate(t.readOnlyTransaction, gk.WithoutName(), start, func(key, val []byte) error {
+		name := t.keyer.NameFromGlobalVersionKey(key)
+		need := false
+		var vl dbproto.VersionList
+		if err := proto.Unmarshal(val, &vl); err == nil {
+			if globalFV, ok := vlGetGlobal(&vl); ok {
+				haveFV, have := vlGet(&vl, protocol.LocalDeviceID[:])
+				need = Need(globalFV, have, protocol.VectorFromWire(haveFV.Version))
+			}
+		}
+		nk, err = t.keyer.GenerateNeedFileKey(nk, folder, name)
+		if err != nil {
+			return err
+		}
+		_, err := t.Get(nk)
+		if err != nil && !backend.IsNotFound(err) {
+			return err
+		}
+		switch have := err == nil; {
+		case need && !have:
+			l.Debugln("check local need: adding", string(name))
+			fixed++
+			return t.Put(nk, nil)
+		case !need && have:
+			l.Debugln("check local need: removing", string(name))
+			fixed++
+			return t.Delete(nk)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Need entries for files without a global entry, in the range of names
+	// covered by this chunk.
+	first, last := needPrefix, prefixEnd(needPrefix)
+	if start != nil {
+		if first, err = t.keyer.GenerateNeedFileKey(nil, folder, t.keyer.NameFromGlobalVersionKey(start)); err != nil {
+			return nil, 0, err
+		}
+	}
+	if next != nil {
+		if last, err = t.keyer.GenerateNeedFileKey(nil, folder, t.keyer.NameFromGlobalVersionKey(next)); err != nil {
+			return nil, 0, err
+		}
+	}
+	it, err := t.NewRangeIterator(first, last)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer it.Release()
+	for it.Next() {
+		name := t.keyer.NameFromGlobalVersionKey(it.Key())
+		if gk, err = t.keyer.GenerateGlobalVersionKey(gk, folder, name); err != nil {
+			return nil, 0, err
+		}
+		if _, err := t.Get(gk); err == nil {
+			continue
+		} else if !backend.IsNotFound(err) {
+			return nil, 0, err
+		}
+		l.Debugln("check local need: removing", string(name))
+		if err := t.Delete(it.Key()); err != nil {
+			return nil, 0, err
+		}
+		fixed++
+	}
+	if err := it.Error(); err != nil {
+		return nil, 0, err
+	}
+	it.Release()
+
+	return next, fixed, t.Commit()
+}
+
+// verifyGlobalChunk drops the devices without a matching file from a chunk
+// of the global version lists.
+func (s *FileSet) verifyGlobalChunk(start []byte) ([]byte, int, error) {
+	t, err := s.db.newReadWriteTransaction()
+	if err != nil {
+		return nil, 0, err
+	}
+	defer t.close()
+
+	folder := []byte(s.folder)
+	gk, err := t.keyer.GenerateGlobalVersionKey(nil, folder, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	fixed := 0
+	next, err := verifyIterate(t.readOnlyTransaction, gk.WithoutName(), start, func(key, val []byte) error {
+		changed, err := s.db.checkGlobal(&t, folder, key, val)
+		if changed {
+			fixed++
+		}
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return next, fixed, t.Commit()
+}
+
+// verifySequenceChunk checks a chunk of the local files for matching
+// sequence entries and, once through those, a chunk of the sequence
+// entries for matching files.
+func (s *FileSet) verifySequenceChunk(start []byte) ([]byte, int, error) {
+	folder := []byte(s.folder)
+	t, err := s.db.newReadWriteTransaction(s.meta.CommitHook(folder))
+	if err != nil {
+		return nil, 0, err
+	}
+	defer t.close()
+
+	dk, err := t.keyer.GenerateDeviceFileKey(nil, folder, protocol.LocalDeviceID[:], nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	sk, err := t.keyer.GenerateSequenceKey(nil, folder, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	fixed := 0
+	var next []byte
+	if start == nil || start[0] == KeyTypeDevice {
+		next, err = verifyIterate(t.readOnlyTransaction, dk.WithoutName(), start, func(key, val []byte) error {
+			repaired, err := s.db.repairFileSequence(&t, folder, s.meta, key, val)
+			if repaired {
+				fixed++
+			}
+			return err
+		})
+		if next == nil {
+			// On to the sequence entries with the next chunk.
+			next = sk.WithoutSequence()
+		}
+	} else {
+		next, err = verifyIterate(t.readOnlyTransaction, sk.WithoutSequence(), start, func(key, val []byte) error {
+			removed, err := s.db.checkSequenceKey(&t, key, val)
+			if removed {
+				fixed++
+			}
+			return err
+		})
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	return next, fixed, t.Commit()
+}
+
+// verifyIterate calls fn for up to verifyChunkSize entries with the given
+// prefix, starting at start if not nil. It returns the key to continue
+// from, or nil if there are no more entries.
+func verifyIterate(t readOnlyTransaction, prefix, start []byte, fn func(key, val []byte) error) ([]byte, error) {
+	if start == nil {
+		start = prefix
+	}
+	it, err := t.NewRangeIterator(start, prefixEnd(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Release()
+
+	for n := 0; it.Next(); n++ {
+		if n == verifyChunkSize {
+			return bytes.Clone(it.Key()), nil
+		}
+		if err := fn(it.Key(), it.Value()); err != nil {
+			return nil, err
+		}
+	}
+	return nil, it.Error()
+}
+
+// prefixEnd returns the first key after all those with the given prefix.
+func prefixEnd(prefix []byte) []byte {
+	end := bytes.Clone(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] != 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+// verifyMetadata recalculates the metadata from the database and, if any
+// counts are wrong, replaces those currently in use. The recalculation
+// happens without holding off updates, against a snapshot of the database
+// and the counts at the same point, and is only repeated with updates held
+// off if it turns out that something needs replacing. It returns the
+// number of counts that were wrong.
+func (s *FileSet) verifyMetadata(context.Context) (int, error) {
+	s.updateAndGCMutexLock()
+	current := newMetadataTracker(s.db.keyer, events.NoopLogger)
+	current.countsMap = *s.meta.Snapshot()
+	t, err := s.db.newReadOnlyTransaction()
+	s.updateMutex.Unlock()
+	s.db.gcMut.RUnlock()
+	if err != nil {
+		return 0, err
+	}
+	recalced := newMetadataTracker(s.db.keyer, events.NoopLogger)
+	err = countMeta(&t, []byte(s.folder), recalced)
+	t.close()
+	if err != nil || current.replaceCounts(recalced) == 0 {
+		return 0, err
+	}
+
+	s.updateAndGCMutexLock()
+	defer s.updateMutex.Unlock()
+	defer s.db.gcMut.RUnlock()
+	return s.verifyMetadataLocked()
+}
+
+// verifyMetadataLocked recalculates the metadata from the database and
+// replaces the counts currently in use with the result. It returns the
+// number of counts that were wrong.
+func (s *FileSet) verifyMetadataLocked() (int, error) {
+	meta, err := s.db.recalcMeta(s.folder)
+	if err != nil {
+		return 0, err
+	}
+	fixed := s.meta.replaceCounts(meta)
+
+	t, err := s.db.newReadWriteTransaction(s.meta.CommitHook([]byte(s.folder)))
+	if err != nil {
+		return 0, err
+	}
+	defer t.close()
+	return fixed, t.Commit()
+}
+
+// verifyBlockMap removes block map and block list map entries that don't
+// correspond to a block of the current local file. The database is
+// scanned without holding any locks and the entries found are checked
+// again under lock, a chunk at a time, before removing them.
+func (s *FileSet) verifyBlockMap(ctx context.Context) (int, error) {
+	orphans, err := s.db.findBlockMapOrphans(ctx, []byte(s.folder))
+	if err != nil || len(orphans) == 0 {
+		return 0, err
+	}
+
+	fixed := 0
+	for len(orphans) > 0 {
+		if err := ctx.Err(); err != nil {
+			return fixed, err
+		}
+		chunk := orphans[:min(len(orphans), verifyChunkSize)]
+		orphans = orphans[len(chunk):]
+		s.updateAndGCMutexLock()
+		n, err := s.db.dropBlockMapOrphans([]byte(s.folder), chunk)
+		s.updateMutex.Unlock()
+		s.db.gcMut.RUnlock()
+		fixed += n
+		if err != nil {
+			return fixed, err
+		}
+	}
+	return fixed, nil
+}
+
+func (db *Lowlevel) findBlockMapOrphans(ctx context.Context, folder []byte) ([][]byte, error) {
+	t, err := db.newReadOnlyTransaction()
+	if err != nil {
+		return nil, err
+	}
+	defer t.close()
+
+	checker, err := newBlockMapChecker(t, folder)
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans [][]byte
+	for _, prefix := range checker.prefixes() {
+		it, err := t.NewPrefixIterator(prefix)
+		if err != nil {
+			return nil, err
+		}
+		for it.Next() && len(orphans) < verifyMaxBlockMapOrphans {
+			if err := ctx.Err(); err != nil {
+				it.Release()
+				return nil, err
+			}
+			orphan, err := checker.isOrphan(it.Key(), it.Value())
+			if err != nil {
+				it.Release()
+				return nil, err
+			}
+			if orphan {
+				orphans = append(orphans, bytes.Clone(it.Key()))
+			}
+		}
+		err = it.Error()
+		it.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return orphans, nil
+}
+
+// dropBlockMapOrphans deletes the given entries if they are still present
+// and orphaned. Must be called with the update and GC locks held.
+func (db *Lowlevel) dropBlockMapOrphans(folder []byte, orphans [][]byte) (int, error) {
+	t, err := db.newReadWriteTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer t.close()
+
+	checker, err := newBlockMapChecker(t.readOnlyTransaction, folder)
+	if err != nil {
+		return 0, err
+	}
+
+	fixed := 0
+	for _, orphan := range orphans {
+		val, err := t.Get(orphan)
+		if backend.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+		if ok, err := checker.isOrphan(orphan, val); err != nil {
+			return 0, err
+		} else if !ok {
+			continue
+		}
+		l.Debugf("check block map: removing entry for %q", checker.name(orphan))
+		if err := t.Delete(orphan); err != nil {
+			return 0, err
+		}
+		fixed++
+		if err := t.Checkpoint(); err != nil {
+			return 0, err
+		}
+	}
+	return fixed, t.Commit()
+}
+
+// blockMapChecker decides whether block map and block list map entries
+// match the local files they refer to.
+type blockMapChecker struct {
+	t            readOnlyTransaction
+	folder       []byte
+	blockPrefix  []byte
+	blockListMap []byte
+	dk, kb       []byte
+	files        *lru.Cache[string, protocol.FileInfo]
+}
+
+func newBlockMapChecker(t readOnlyTransaction, folder []byte) (*blockMapChecker, error) {
+	bk, err := t.keyer.GenerateBlockMapKey(nil, folder, protocol.HashAlgorithmSHA256, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	blk, err := t.keyer.GenerateBlockListMapKey(nil, folder, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	files, err := lru.New[string, protocol.FileInfo](verifyBlockCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	return &blockMapChecker{
+		t:            t,
+		folder:       folder,
+		blockPrefix:  bk.WithoutHashAndName(),
+		blockListMap: blk.WithoutHashAndName(),
+		files:        files,
+	}, nil
+}
+
+func (c *blockMapChecker) prefixes() [][]byte {
+	return [][]byte{c.blockPrefix, c.blockListMap}
+}
+
+func (c *blockMapChecker) name(key []byte) []byte {
+	if key[0] == KeyTypeBlockListMap {
+		return c.t.keyer.NameFromBlockListMapKey(key)
+	}
+	return c.t.keyer.NameFromBlockMapKey(key)
+}
+
+func (c *blockMapChecker) isOrphan(key, val []byte) (bool, error) {
+	name := c.name(key)
+	f, ok, err := c.localFile(name)
+	if err != nil {
+		return false, err
+	}
+	if !ok || len(f.Blocks) == 0 || f.IsInvalid() || f.Size <= 0 {
+		// There are no block map entries for missing or invalid files.
+		return true, nil
+	}
+
+	// Compare with the entry we would have created for the current file.
+	if key[0] == KeyTypeBlockListMap {
+		c.kb, err = c.t.keyer.GenerateBlockListMapKey(c.kb, c.folder, f.BlocksHash, name)
+		return err == nil && !bytes.Equal(key, c.kb), err
+	}
+	if len(val) != 12 {
+		return true, nil
+	}
+	index := int(binary.BigEndian.Uint32(val))
+	offset := int64(binary.BigEndian.Uint64(val[4:]))
+	if index >= len(f.Blocks) || f.Blocks[index].Offset != offset {
+		return true, nil
+	}
+	c.kb, err = c.t.keyer.GenerateBlockMapKey(c.kb, c.folder, f.HashAlgorithm, f.Blocks[index].Hash, name)
+	return err == nil && !bytes.Equal(key, c.kb), err
+}
+
+func (c *blockMapChecker) localFile(name []byte) (protocol.FileInfo, bool, error) {
+	if f, ok := c.files.Get(string(name)); ok {
+		return f, f.Name != "", nil
+	}
+	var err error
+	c.dk, err = c.t.keyer.GenerateDeviceFileKey(c.dk, c.folder, protocol.LocalDeviceID[:], name)
+	if err != nil {
+		return protocol.FileInfo{}, false, err
+	}
+	f, ok, err := c.t.getFileByKey(c.dk)
+	if err != nil {
+		return protocol.FileInfo{}, false, err
+	}
+	c.files.Add(string(name), f)
+	return f, ok, nil
+}
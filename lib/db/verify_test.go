@@ -0,0 +1,226 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package db
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestVerify(t *testing.T) {
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	folderStr := "test"
+	folder := []byte(folderStr)
+	s := newFileSet(t, folderStr, ldb)
+
+	blocks := genBlocks(3)
+	files := []protocol.FileInfo{
+		{Name: "a", Size: 3, Blocks: blocks, BlocksHash: protocol.BlocksHash(blocks), Version: protocol.Vector{}.Update(myID)},
+		{Name: "b", Size: 3, Blocks: blocks[1:], BlocksHash: protocol.BlocksHash(blocks[1:]), Version: protocol.Vector{}.Update(myID)},
+		{Name: "c", Version: protocol.Vector{}.Update(myID)},
+	}
+	s.Update(protocol.LocalDeviceID, files)
+	files[2].Version = files[2].Version.Update(remoteDevice0.Short())
+	s.Update(remoteDevice0, files)
+
+	if findings, err := s.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if len(findings) != 0 {
+		t.Fatal("Unexpected findings on consistent database:", findings)
+	}
+	blockEntries := countBlockMapEntries(t, ldb, folder)
+
+	trans, err := ldb.newReadWriteTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.close()
+
+	// "a" is in sync and thus not needed.
+	nk, err := trans.keyer.GenerateNeedFileKey(nil, folder, []byte("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Put(nk, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// Block map entries for a file that doesn't exist, with a block index
+	// that doesn't match and for a stale block list.
+	var val [12]byte
+	bk, err := trans.keyer.GenerateBlockMapKey(nil, folder, protocol.HashAlgorithmSHA256, blocks[0].Hash, []byte("gone"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Put(bk, val[:]); err != nil {
+		t.Fatal(err)
+	}
+	binary.BigEndian.PutUint32(val[:], 1)
+	bk, err = trans.keyer.GenerateBlockMapKey(bk, folder, protocol.HashAlgorithmSHA256, blocks[0].Hash, []byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Put(bk, val[:]); err != nil {
+		t.Fatal(err)
+	}
+	blk, err := trans.keyer.GenerateBlockListMapKey(nil, folder, protocol.BlocksHash(blocks), []byte("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Put(blk, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := trans.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Counts that don't match the database contents.
+	s.meta.addFile(protocol.LocalDeviceID, protocol.FileInfo{Name: "x", Size: 10})
+
+	findings, err := s.Verify(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := make(map[string]int)
+	for _, f := range findings {
+		counts[f.Check] = f.Count
+	}
+	if counts[VerifyCheckNeed] != 1 {
+		t.Errorf("Expected 1 repaired need entry, got %v", counts[VerifyCheckNeed])
+	}
+	if counts[VerifyCheckBlockMap] != 3 {
+		t.Errorf("Expected 3 repaired block map entries, got %v", counts[VerifyCheckBlockMap])
+	}
+	if counts[VerifyCheckMetadata] == 0 {
+		t.Error("Expected repaired metadata")
+	}
+	if n := countBlockMapEntries(t, ldb, folder); n != blockEntries {
+		t.Errorf("Expected %d block map entries after repair, got %d", blockEntries, n)
+	}
+
+	snap := snapshot(t, s)
+	if c := snap.LocalSize(); c.Files != 3 || c.Bytes != 6 {
+		t.Errorf("Unexpected local size after repair: %v", c)
+	}
+	if c := snap.NeedSize(protocol.LocalDeviceID); c.Files != 1 {
+		t.Errorf("Unexpected need size after repair: %v", c)
+	}
+	snap.Release()
+
+	if findings, err := s.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if len(findings) != 0 {
+		t.Error("Unexpected findings after repair:", findings)
+	}
+}
+
+func TestVerifyChunks(t *testing.T) {
+	defer func(size int) { verifyChunkSize = size }(verifyChunkSize)
+	verifyChunkSize = 2
+
+	ldb := newLowlevelMemory(t)
+	defer ldb.Close()
+
+	folderStr := "test"
+	folder := []byte(folderStr)
+	s := newFileSet(t, folderStr, ldb)
+
+	var files []protocol.FileInfo
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		files = append(files, protocol.FileInfo{Name: name, Version: protocol.Vector{}.Update(myID)})
+	}
+	s.Update(protocol.LocalDeviceID, files)
+	for i := range files {
+		files[i].Version = files[i].Version.Update(remoteDevice0.Short())
+	}
+	s.Update(remoteDevice0, files)
+
+	// Need entries missing for files in different chunks, and left over
+	// for files that aren't in the global list, before, between and after
+	// those that are.
+	trans, err := ldb.newReadWriteTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.close()
+	for _, name := range []string{"a", "d"} {
+		nk, err := trans.keyer.GenerateNeedFileKey(nil, folder, []byte(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := trans.Delete(nk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, name := range []string{"0", "bb", "cc", "f"} {
+		nk, err := trans.keyer.GenerateNeedFileKey(nil, folder, []byte(name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := trans.Put(nk, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := trans.Commit(); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := s.Verify(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(findings) == 0 || findings[0].Check != VerifyCheckNeed || findings[0].Count != 6 {
+		t.Errorf("Expected 6 repaired need entries, got %v", findings)
+	}
+
+	snap := snapshot(t, s)
+	var need []string
+	snap.WithNeedTruncated(protocol.LocalDeviceID, func(f protocol.FileInfo) bool {
+		need = append(need, f.Name)
+		return true
+	})
+	snap.Release()
+	if len(need) != len(files) {
+		t.Errorf("Expected all files to be needed, got %v", need)
+	}
+
+	if findings, err := s.Verify(context.Background()); err != nil {
+		t.Fatal(err)
+	} else if len(findings) != 0 {
+		t.Error("Unexpected findings after repair:", findings)
+	}
+}
+
+func countBlockMapEntries(t *testing.T, ldb *Lowlevel, folder []byte) int {
+	t.Helper()
+	trans, err := ldb.newReadOnlyTransaction()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer trans.close()
+	checker, err := newBlockMapChecker(trans, folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, prefix := range checker.prefixes() {
+		it, err := trans.NewPrefixIterator(prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for it.Next() {
+			n++
+		}
+		it.Release()
+	}
+	return n
+}
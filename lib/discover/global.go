@@ -473,6 +473,8 @@ func ipv6Identity(addr string) string {
 	return fmt.Sprintf("IPv6 local multicast discovery on address %s", addr)
 }
 
+const mdnsIdentity = "DNS-SD/mDNS local discovery"
+
 func http2EnabledTransport(t *http.Transport) *http.Transport {
 	_ = http2.ConfigureTransport(t)
 	return t
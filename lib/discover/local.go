@@ -238,12 +238,32 @@ func (c *localClient) registerDevice(src net.Addr, device *discoproto.Announce)
 	ce, existsAlready := c.Get(id)
 	isNewDevice := !existsAlready || time.Since(ce.when) > CacheLifeTime || ce.instanceID != device.InstanceId
 
-	// Any empty or unspecified addresses should be set to the source address
-	// of the announcement. We also skip any addresses we can't parse.
-
 	l.Debugln("discover: Registering addresses for", id)
+	validAddresses := resolveLocalAddresses(src, device.Addresses)
+
+	c.Set(id, CacheEntry{
+		Addresses:  validAddresses,
+		when:       time.Now(),
+		found:      true,
+		instanceID: device.InstanceId,
+	})
+
+	if isNewDevice {
+		c.evLogger.Log(events.DeviceDiscovered, map[string]interface{}{
+			"device": id.String(),
+			"addrs":  validAddresses,
+		})
+	}
+
+	return isNewDevice
+}
+
+// resolveLocalAddresses returns the addresses announced by a device on the
+// LAN. Any empty or unspecified addresses are set to the source address of
+// the announcement. We also skip any addresses we can't parse.
+func resolveLocalAddresses(src net.Addr, addrs []string) []string {
 	var validAddresses []string
-	for _, addr := range device.Addresses {
+	for _, addr := range addrs {
 		u, err := url.Parse(addr)
 		if err != nil {
 			continue
@@ -283,22 +303,7 @@ func (c *localClient) registerDevice(src net.Addr, device *discoproto.Announce)
 			l.Debugf("discover: Accepted address %s verbatim", addr)
 		}
 	}
-
-	c.Set(id, CacheEntry{
-		Addresses:  validAddresses,
-		when:       time.Now(),
-		found:      true,
-		instanceID: device.InstanceId,
-	})
-
-	if isNewDevice {
-		c.evLogger.Log(events.DeviceDiscovered, map[string]interface{}{
-			"device": id.String(),
-			"addrs":  validAddresses,
-		})
-	}
-
-	return isNewDevice
+	return validAddresses
 }
 
 // filterUndialableLocal returns the list of addresses after removing any
@@ -243,6 +243,9 @@ func (m *manager) CommitConfiguration(_, to config.Configuration) (handled bool)
 	if to.Options.LocalAnnEnabled {
 		toIdentities[ipv4Identity(to.Options.LocalAnnPort)] = struct{}{}
 		toIdentities[ipv6Identity(to.Options.LocalAnnMCAddr)] = struct{}{}
+		if to.Options.LocalAnnMDNSEnabled {
+			toIdentities[mdnsIdentity] = struct{}{}
+		}
 	}
 
 	// Remove things that we're not expected to have.
@@ -295,6 +298,11 @@ func (m *manager) CommitConfiguration(_, to config.Configuration) (handled bool)
 				m.addLocked(v6Identity, mcd, 0, 0)
 			}
 		}
+
+		// DNS-SD/mDNS
+		if _, ok := m.finders[mdnsIdentity]; !ok && to.Options.LocalAnnMDNSEnabled {
+			m.addLocked(mdnsIdentity, NewMDNS(m.myID, m.addressLister, m.evLogger), 0, 0)
+		}
 	}
 
 	return true
@@ -0,0 +1,515 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package discover
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thejerf/suture/v4"
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/rand"
+	"github.com/syncthing/syncthing/lib/svcutil"
+)
+
+// Devices are announced as DNS-SD service instances of type
+// _syncthing._tcp, named after the device ID. The TXT record carries the
+// device ID, a random instance ID like the local discovery announcement and
+// the addresses as addr0, addr1, etc. The SRV and A/AAAA records point at
+// the first TCP address for the benefit of generic DNS-SD browsers.
+const (
+	mdnsServiceName      = "_syncthing._tcp.local."
+	mdnsServicesName     = "_services._dns-sd._udp.local."
+	mdnsTXTVersion       = "txtvers=1"
+	mdnsCacheFlush       = 1 << 15
+	mdnsMaxPacketSize    = 9000
+	mdnsLookupQueryDelay = 10 * time.Second
+
+	// Answers to queries are sent after a random delay in this range, and
+	// the same answer not more often than once per interval, as RFC 6762
+	// section 6 asks of responders.
+	mdnsAnswerDelayMin = 20 * time.Millisecond
+	mdnsAnswerDelayMax = 120 * time.Millisecond
+	mdnsAnswerInterval = time.Second
+)
+
+// What we answer a query with
+type mdnsAnswer int
+
+const (
+	mdnsAnswerAnnouncement mdnsAnswer = iota // the records describing us
+	mdnsAnswerServices                       // the service type enumeration
+	mdnsAnswerKinds
+)
+
+var mdnsTTL = uint32(CacheLifeTime / time.Second)
+
+type mdnsClient struct {
+	*suture.Supervisor
+	myID     protocol.DeviceID
+	addrList AddressLister
+	evLogger events.Logger
+	open     func() ([]mdnsTransport, error)
+	svc      svcutil.ServiceWithError
+
+	instanceID int64
+	lookups    chan protocol.DeviceID
+	queried    map[protocol.DeviceID]time.Time // outstanding lookup queries, only used by serve
+
+	*cache
+}
+
+// A received mDNS packet
+type mdnsPacket struct {
+	data []byte
+	src  net.Addr
+}
+
+// NewMDNS returns a Finder announcing and discovering devices over
+// DNS-SD/mDNS on all multicast capable interfaces.
+func NewMDNS(id protocol.DeviceID, addrList AddressLister, evLogger events.Logger) FinderService {
+	return newMDNS(id, addrList, evLogger, openMDNSTransports)
+}
+
+func newMDNS(id protocol.DeviceID, addrList AddressLister, evLogger events.Logger, open func() ([]mdnsTransport, error)) *mdnsClient {
+	c := &mdnsClient{
+		Supervisor: suture.New("mdns", svcutil.SpecWithDebugLogger(l)),
+		myID:       id,
+		addrList:   addrList,
+		evLogger:   evLogger,
+		open:       open,
+		instanceID: rand.Int63(),
+		lookups:    make(chan protocol.DeviceID, 16),
+		queried:    make(map[protocol.DeviceID]time.Time),
+		cache:      newCache(),
+	}
+	c.svc = svcutil.AsService(c.serve, fmt.Sprintf("%s/serve", c))
+	c.Add(c.svc)
+	return c
+}
+
+// Lookup returns a list of addresses the device is available at. If we
+// haven't heard from the device recently we ask for it, so that the answer
+// is there the next time.
+func (c *mdnsClient) Lookup(_ context.Context, device protocol.DeviceID) (addresses []string, err error) {
+	if cache, ok := c.Get(device); ok && time.Since(cache.when) < CacheLifeTime {
+		return cache.Addresses, nil
+	}
+	select {
+	case c.lookups <- device:
+	default:
+	}
+	return nil, nil
+}
+
+func (*mdnsClient) String() string {
+	return "DNS-SD/mDNS local"
+}
+
+func (c *mdnsClient) Error() error {
+	return c.svc.Error()
+}
+
+func (c *mdnsClient) serve(ctx context.Context) error {
+	transports, err := c.open()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, t := range transports {
+			t.Close()
+		}
+	}()
+
+	recv := make(chan mdnsPacket, 16)
+	errs := make(chan error, len(transports))
+	for _, t := range transports {
+		go c.recvPackets(ctx, t, recv, errs)
+	}
+
+	send := func(msg []byte) {
+		for _, t := range transports {
+			if err := t.Send(msg); err != nil {
+				l.Debugln("discover: mDNS send:", err)
+			}
+		}
+	}
+
+	// When each answer was last sent and whether it's about to be. At most
+	// one of each is pending, so the channel never blocks.
+	var lastAnswered [mdnsAnswerKinds]time.Time
+	var pending [mdnsAnswerKinds]bool
+	answers := make(chan mdnsAnswer, mdnsAnswerKinds)
+	sendAnswer := func(ans mdnsAnswer) {
+		lastAnswered[ans] = time.Now()
+		if msg, ok := c.answerMessage(ans); ok {
+			send(msg)
+		}
+	}
+
+	// Find the devices already present, then tell them about us.
+	if msg, err := mdnsQuery(mdnsServiceName, dnsmessage.TypePTR); err == nil {
+		send(msg)
+	}
+	sendAnswer(mdnsAnswerAnnouncement)
+
+	ticker := time.NewTicker(BroadcastInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case pkt := <-recv:
+			ans, ok := c.handlePacket(pkt)
+			if !ok || pending[ans] {
+				continue
+			}
+			delay := mdnsAnswerDelayMin + time.Duration(rand.Intn(int(mdnsAnswerDelayMax-mdnsAnswerDelayMin)))
+			if next := time.Until(lastAnswered[ans].Add(mdnsAnswerInterval)); next > delay {
+				delay = next
+			}
+			pending[ans] = true
+			time.AfterFunc(delay, func() { answers <- ans })
+
+		case ans := <-answers:
+			pending[ans] = false
+			sendAnswer(ans)
+
+		case <-ticker.C:
+			sendAnswer(mdnsAnswerAnnouncement)
+
+		case device := <-c.lookups:
+			if time.Since(c.queried[device]) < mdnsLookupQueryDelay {
+				continue
+			}
+			c.queried[device] = time.Now()
+			if msg, err := mdnsQuery(mdnsInstanceName(device), dnsmessage.TypeTXT); err == nil {
+				send(msg)
+			}
+
+		case err := <-errs:
+			return err
+
+		case <-ctx.Done():
+			// Say goodbye, so that others forget about us right away.
+			if msg, ok := c.announcement(0); ok {
+				send(msg)
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *mdnsClient) recvPackets(ctx context.Context, t mdnsTransport, recv chan<- mdnsPacket, errs chan<- error) {
+	buf := make([]byte, mdnsMaxPacketSize)
+	for {
+		n, src, err := t.Recv(buf)
+		if err != nil {
+			if ctx.Err() == nil {
+				errs <- err
+			}
+			return
+		}
+		data := make([]byte, n)
+		copy(data, buf)
+		select {
+		case recv <- mdnsPacket{data, src}:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// handlePacket handles a received query or response. If we should answer,
+// what to answer with is returned.
+func (c *mdnsClient) handlePacket(pkt mdnsPacket) (mdnsAnswer, bool) {
+	var p dnsmessage.Parser
+	hdr, err := p.Start(pkt.data)
+	if err != nil {
+		l.Debugf("discover: Bad mDNS packet from %s: %v", pkt.src, err)
+		return 0, false
+	}
+
+	if !hdr.Response {
+		questions, err := p.AllQuestions()
+		if err != nil {
+			l.Debugf("discover: Bad mDNS query from %s: %v", pkt.src, err)
+			return 0, false
+		}
+		return c.answer(questions)
+	}
+
+	if err := p.SkipAllQuestions(); err != nil {
+		l.Debugf("discover: Bad mDNS response from %s: %v", pkt.src, err)
+		return 0, false
+	}
+	var resources []dnsmessage.Resource
+	for _, section := range []func() ([]dnsmessage.Resource, error){p.AllAnswers, p.AllAuthorities, p.AllAdditionals} {
+		rs, err := section()
+		if err != nil {
+			l.Debugf("discover: Bad mDNS response from %s: %v", pkt.src, err)
+			return 0, false
+		}
+		resources = append(resources, rs...)
+	}
+	c.registerResources(pkt.src, resources)
+	return 0, false
+}
+
+// answer returns what to answer with if any of the questions are about us.
+func (c *mdnsClient) answer(questions []dnsmessage.Question) (mdnsAnswer, bool) {
+	instance := mdnsInstanceName(c.myID)
+	host := mdnsHostName(c.myID)
+	for _, q := range questions {
+		name := q.Name.String()
+		switch {
+		case strings.EqualFold(name, mdnsServicesName) && (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL):
+			return mdnsAnswerServices, true
+		case strings.EqualFold(name, mdnsServiceName) && (q.Type == dnsmessage.TypePTR || q.Type == dnsmessage.TypeALL),
+			strings.EqualFold(name, instance) && (q.Type == dnsmessage.TypeSRV || q.Type == dnsmessage.TypeTXT || q.Type == dnsmessage.TypeALL),
+			strings.EqualFold(name, host) && (q.Type == dnsmessage.TypeA || q.Type == dnsmessage.TypeAAAA || q.Type == dnsmessage.TypeALL):
+			return mdnsAnswerAnnouncement, true
+		}
+	}
+	return 0, false
+}
+
+// answerMessage returns the message to send as the answer.
+func (c *mdnsClient) answerMessage(ans mdnsAnswer) ([]byte, bool) {
+	if ans == mdnsAnswerServices {
+		return mdnsServiceEnumeration()
+	}
+	return c.announcement(mdnsTTL)
+}
+
+// announcement returns the records describing us with the given TTL, zero
+// meaning goodbye. Returns false if there is nothing useful to announce.
+func (c *mdnsClient) announcement(ttl uint32) ([]byte, bool) {
+	addrs := c.addrList.AllAddresses()
+
+	// remove all addresses which are not dialable
+	addrs = filterUndialableLocal(addrs)
+
+	// do not leak relay tokens to discovery
+	addrs = sanitizeRelayAddresses(addrs)
+
+	if len(addrs) == 0 {
+		// Nothing to announce
+		return nil, false
+	}
+
+	txt := []string{
+		mdnsTXTVersion,
+		"id=" + c.myID.String(),
+		"instance=" + strconv.FormatInt(c.instanceID, 16),
+	}
+	var port uint16
+	var ips []netip.Addr
+	for _, addr := range addrs {
+		entry := fmt.Sprintf("addr%d=%s", len(txt)-3, addr)
+		if len(entry) > 255 {
+			// Doesn't fit in a TXT string
+			continue
+		}
+		txt = append(txt, entry)
+
+		u, err := url.Parse(addr)
+		if err != nil {
+			continue
+		}
+		ap, err := netip.ParseAddrPort(u.Host)
+		if err != nil {
+			continue
+		}
+		if port == 0 && strings.HasPrefix(u.Scheme, "tcp") {
+			port = ap.Port()
+		}
+		if ip := ap.Addr().Unmap(); !ip.IsUnspecified() && !slices.Contains(ips, ip) {
+			ips = append(ips, ip)
+		}
+	}
+
+	service := dnsmessage.MustNewName(mdnsServiceName)
+	instance := dnsmessage.MustNewName(mdnsInstanceName(c.myID))
+	host := dnsmessage.MustNewName(mdnsHostName(c.myID))
+
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	b.EnableCompression()
+	_ = b.StartAnswers()
+	_ = b.PTRResource(mdnsResourceHeader(service, ttl, false), dnsmessage.PTRResource{PTR: instance})
+	_ = b.TXTResource(mdnsResourceHeader(instance, ttl, true), dnsmessage.TXTResource{TXT: txt})
+	if port != 0 {
+		_ = b.SRVResource(mdnsResourceHeader(instance, ttl, true), dnsmessage.SRVResource{Port: port, Target: host})
+		for _, ip := range ips {
+			if ip.Is4() {
+				_ = b.AResource(mdnsResourceHeader(host, ttl, true), dnsmessage.AResource{A: ip.As4()})
+			} else {
+				_ = b.AAAAResource(mdnsResourceHeader(host, ttl, true), dnsmessage.AAAAResource{AAAA: ip.As16()})
+			}
+		}
+	}
+	msg, err := b.Finish()
+	if err != nil {
+		l.Debugln("discover: mDNS announcement:", err)
+		return nil, false
+	}
+	if len(msg) > mdnsMaxPacketSize {
+		l.Debugf("discover: mDNS announcement too large (%d bytes)", len(msg))
+		return nil, false
+	}
+	return msg, true
+}
+
+// registerResources registers the devices described by the resource
+// records of a response.
+func (c *mdnsClient) registerResources(src net.Addr, resources []dnsmessage.Resource) {
+	// Collect the SRV targets and their addresses, in case the TXT record
+	// lacks addresses.
+	srvs := make(map[string]dnsmessage.SRVResource)
+	hostIPs := make(map[string][]netip.Addr)
+	for _, r := range resources {
+		name := strings.ToLower(r.Header.Name.String())
+		switch body := r.Body.(type) {
+		case *dnsmessage.SRVResource:
+			srvs[name] = *body
+		case *dnsmessage.AResource:
+			hostIPs[name] = append(hostIPs[name], netip.AddrFrom4(body.A))
+		case *dnsmessage.AAAAResource:
+			hostIPs[name] = append(hostIPs[name], netip.AddrFrom16(body.AAAA))
+		}
+	}
+
+	for _, r := range resources {
+		body, ok := r.Body.(*dnsmessage.TXTResource)
+		if !ok {
+			continue
+		}
+		name := strings.ToLower(r.Header.Name.String())
+		if !strings.HasSuffix(name, "."+strings.ToLower(mdnsServiceName)) {
+			continue
+		}
+
+		var id protocol.DeviceID
+		var instanceID int64
+		var addrs []string
+		valid := false
+		for _, kv := range body.TXT {
+			key, val, _ := strings.Cut(kv, "=")
+			switch key = strings.ToLower(key); {
+			case key == "id":
+				var err error
+				id, err = protocol.DeviceIDFromString(val)
+				valid = err == nil
+			case key == "instance":
+				instanceID, _ = strconv.ParseInt(val, 16, 64)
+			case strings.HasPrefix(key, "addr"):
+				addrs = append(addrs, val)
+			}
+		}
+		if !valid {
+			l.Debugf("discover: mDNS record %s from %s without valid device ID", name, src)
+			continue
+		}
+		if name != strings.ToLower(mdnsInstanceName(id)) {
+			// The record must be about the device it names.
+			l.Debugf("discover: mDNS record %s from %s for another device %s", name, src, id)
+			continue
+		}
+		if id == c.myID {
+			continue
+		}
+
+		if r.Header.TTL == 0 {
+			// Goodbye
+			l.Debugln("discover: mDNS goodbye from", id)
+			c.Set(id, CacheEntry{when: time.Now()})
+			continue
+		}
+
+		if len(addrs) == 0 {
+			if srv, ok := srvs[name]; ok {
+				for _, ip := range hostIPs[strings.ToLower(srv.Target.String())] {
+					addrs = append(addrs, "tcp://"+netip.AddrPortFrom(ip, srv.Port).String())
+				}
+			}
+		}
+
+		c.registerDevice(src, id, instanceID, addrs)
+	}
+}
+
+func (c *mdnsClient) registerDevice(src net.Addr, id protocol.DeviceID, instanceID int64, addrs []string) {
+	ce, existsAlready := c.Get(id)
+	isNewDevice := !existsAlready || !ce.found || time.Since(ce.when) > CacheLifeTime || ce.instanceID != instanceID
+
+	l.Debugln("discover: Registering mDNS addresses for", id)
+	delete(c.queried, id)
+	validAddresses := resolveLocalAddresses(src, addrs)
+
+	c.Set(id, CacheEntry{
+		Addresses:  validAddresses,
+		when:       time.Now(),
+		found:      true,
+		instanceID: instanceID,
+	})
+
+	if isNewDevice {
+		c.evLogger.Log(events.DeviceDiscovered, map[string]interface{}{
+			"device": id.String(),
+			"addrs":  validAddresses,
+		})
+	}
+}
+
+// mdnsServiceEnumeration answers a DNS-SD service type enumeration query.
+func mdnsServiceEnumeration() ([]byte, bool) {
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{Response: true, Authoritative: true})
+	_ = b.StartAnswers()
+	_ = b.PTRResource(mdnsResourceHeader(dnsmessage.MustNewName(mdnsServicesName), mdnsTTL, false), dnsmessage.PTRResource{PTR: dnsmessage.MustNewName(mdnsServiceName)})
+	msg, err := b.Finish()
+	return msg, err == nil
+}
+
+func mdnsQuery(name string, typ dnsmessage.Type) ([]byte, error) {
+	n, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, err
+	}
+	b := dnsmessage.NewBuilder(nil, dnsmessage.Header{})
+	_ = b.StartQuestions()
+	if err := b.Question(dnsmessage.Question{Name: n, Type: typ, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, err
+	}
+	return b.Finish()
+}
+
+func mdnsResourceHeader(name dnsmessage.Name, ttl uint32, unique bool) dnsmessage.ResourceHeader {
+	class := dnsmessage.ClassINET
+	if unique {
+		class |= mdnsCacheFlush
+	}
+	return dnsmessage.ResourceHeader{Name: name, Class: class, TTL: ttl}
+}
+
+// mdnsInstanceName returns the DNS-SD service instance name of the device.
+// A device ID is exactly 63 characters, the longest allowed DNS label.
+func mdnsInstanceName(id protocol.DeviceID) string {
+	return id.String() + "." + mdnsServiceName
+}
+
+// mdnsHostName returns the host name used as SRV target for the device.
+func mdnsHostName(id protocol.DeviceID) string {
+	return "syncthing-" + strings.ToLower(id.Short().String()) + ".local."
+}
@@ -0,0 +1,309 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package discover
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestMDNSDiscovery(t *testing.T) {
+	network := newFakeMDNSNetwork()
+	idA := protocol.DeviceID{1, 2, 3}
+	idB := protocol.DeviceID{4, 5, 6}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	a := newMDNS(idA, &fakeAddressLister{}, events.NoopLogger, network.opener("192.0.2.1"))
+	go a.Serve(ctx)
+	b := newMDNS(idB, &fakeAddressLister{}, events.NoopLogger, network.opener("192.0.2.2"))
+	go b.Serve(ctx)
+
+	// The unspecified address is replaced by the source address of the
+	// announcement.
+	expected := []string{"tcp://192.0.2.1:22000", "tcp://192.168.0.1:22000"}
+	waitForMDNSLookup(t, b, idA, expected)
+	waitForMDNSLookup(t, a, idB, []string{"tcp://192.0.2.2:22000", "tcp://192.168.0.1:22000"})
+
+	// A device we've forgotten about is asked for on lookup.
+	b.Set(idA, CacheEntry{})
+	waitForMDNSLookup(t, b, idA, expected)
+}
+
+func TestMDNSResponder(t *testing.T) {
+	network := newFakeMDNSNetwork()
+	id := protocol.DeviceID{1, 2, 3}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := newMDNS(id, &fakeAddressLister{}, events.NoopLogger, network.opener("192.0.2.1"))
+	go c.Serve(ctx)
+
+	// Browse for Syncthing devices like any DNS-SD client would.
+	client := network.join("192.0.2.3")
+	query, err := mdnsQuery(mdnsServiceName, dnsmessage.TypePTR)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, mdnsMaxPacketSize)
+	var resources []dnsmessage.Resource
+	for len(resources) == 0 {
+		if err := client.Send(query); err != nil {
+			t.Fatal(err)
+		}
+		n, _, err := client.recvTimeout(buf, 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var msg dnsmessage.Message
+		if err := msg.Unpack(buf[:n]); err != nil {
+			t.Fatal(err)
+		}
+		if msg.Response {
+			resources = msg.Answers
+		}
+	}
+
+	instance := mdnsInstanceName(id)
+	var txt []string
+	var srv *dnsmessage.SRVResource
+	var ptr, a bool
+	for _, r := range resources {
+		switch body := r.Body.(type) {
+		case *dnsmessage.PTRResource:
+			ptr = r.Header.Name.String() == mdnsServiceName && body.PTR.String() == instance
+		case *dnsmessage.TXTResource:
+			txt = body.TXT
+		case *dnsmessage.SRVResource:
+			srv = body
+		case *dnsmessage.AResource:
+			a = r.Header.Name.String() == mdnsHostName(id) && body.A == [4]byte{192, 168, 0, 1}
+		}
+	}
+	if !ptr {
+		t.Error("Missing PTR record for", instance)
+	}
+	if !slices.Contains(txt, "id="+id.String()) || !slices.Contains(txt, "addr0=tcp://0.0.0.0:22000") {
+		t.Error("Unexpected TXT record", txt)
+	}
+	if srv == nil || srv.Port != 22000 || srv.Target.String() != mdnsHostName(id) {
+		t.Error("Unexpected SRV record", srv)
+	}
+	if !a {
+		t.Error("Missing A record")
+	}
+}
+
+func TestMDNSAnswerRateLimit(t *testing.T) {
+	network := newFakeMDNSNetwork()
+	id := protocol.DeviceID{1, 2, 3}
+	client := network.join("192.0.2.3")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c := newMDNS(id, &fakeAddressLister{}, events.NoopLogger, network.opener("192.0.2.1"))
+	go c.Serve(ctx)
+
+	buf := make([]byte, mdnsMaxPacketSize)
+	recvResponse := func(timeout time.Duration) bool {
+		t.Helper()
+		deadline := time.Now().Add(timeout)
+		for {
+			n, _, err := client.recvTimeout(buf, time.Until(deadline))
+			if err != nil {
+				return false
+			}
+			var msg dnsmessage.Message
+			if err := msg.Unpack(buf[:n]); err != nil {
+				t.Fatal(err)
+			}
+			if msg.Response {
+				return true
+			}
+		}
+	}
+	query, err := mdnsQuery(mdnsInstanceName(id), dnsmessage.TypeTXT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// We're announced at startup, so the same answer must wait a second,
+	// however often it's asked for.
+	if !recvResponse(5 * time.Second) {
+		t.Fatal("No announcement at startup")
+	}
+	announced := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := client.Send(query); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !recvResponse(5 * time.Second) {
+		t.Fatal("No answer")
+	}
+	if d := time.Since(announced); d < mdnsAnswerInterval-50*time.Millisecond {
+		t.Errorf("Answered %v after the announcement", d)
+	}
+	if recvResponse(mdnsAnswerInterval / 2) {
+		t.Error("Answered more than once")
+	}
+
+	// Otherwise after a short random delay.
+	time.Sleep(mdnsAnswerInterval)
+	queried := time.Now()
+	if err := client.Send(query); err != nil {
+		t.Fatal(err)
+	}
+	if !recvResponse(5 * time.Second) {
+		t.Fatal("No answer")
+	}
+	if d := time.Since(queried); d < mdnsAnswerDelayMin || d > mdnsAnswerInterval/2 {
+		t.Errorf("Answered after %v", d)
+	}
+}
+
+func TestMDNSForeignRecord(t *testing.T) {
+	idA := protocol.DeviceID{1, 2, 3}
+	idB := protocol.DeviceID{4, 5, 6}
+	c := newMDNS(protocol.DeviceID{7, 8, 9}, &fakeAddressLister{}, events.NoopLogger, nil)
+	src := &net.UDPAddr{IP: net.ParseIP("192.0.2.1"), Port: mdnsPort}
+
+	// A record for one device claiming to be another is ignored.
+	txt := func(instance, id protocol.DeviceID) dnsmessage.Resource {
+		return dnsmessage.Resource{
+			Header: mdnsResourceHeader(dnsmessage.MustNewName(mdnsInstanceName(instance)), mdnsTTL, true),
+			Body:   &dnsmessage.TXTResource{TXT: []string{mdnsTXTVersion, "id=" + id.String(), "addr0=tcp://192.0.2.1:22000"}},
+		}
+	}
+	c.registerResources(src, []dnsmessage.Resource{txt(idA, idB)})
+	if _, ok := c.Get(idB); ok {
+		t.Error("Registered a device from another device's record")
+	}
+	c.registerResources(src, []dnsmessage.Resource{txt(idA, idA)})
+	if _, ok := c.Get(idA); !ok {
+		t.Error("Device not registered from its own record")
+	}
+}
+
+func waitForMDNSLookup(t *testing.T, c *mdnsClient, device protocol.DeviceID, expected []string) {
+	t.Helper()
+	var addrs []string
+	for i := 0; i < 100; i++ {
+		var err error
+		addrs, err = c.Lookup(context.Background(), device)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if slices.Equal(addrs, expected) {
+			return
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("Lookup of %s returned %v, expected %v", device.Short(), addrs, expected)
+}
+
+// fakeMDNSNetwork delivers each packet sent by a member to all members,
+// including the sender, like multicast with loopback does.
+type fakeMDNSNetwork struct {
+	mut     sync.Mutex
+	members []*fakeMDNSTransport
+}
+
+func newFakeMDNSNetwork() *fakeMDNSNetwork {
+	return &fakeMDNSNetwork{}
+}
+
+func (n *fakeMDNSNetwork) join(ip string) *fakeMDNSTransport {
+	t := &fakeMDNSTransport{
+		network: n,
+		addr:    &net.UDPAddr{IP: net.ParseIP(ip), Port: mdnsPort},
+		inbox:   make(chan mdnsPacket, 64),
+		closed:  make(chan struct{}),
+	}
+	n.mut.Lock()
+	n.members = append(n.members, t)
+	n.mut.Unlock()
+	return t
+}
+
+func (n *fakeMDNSNetwork) opener(ip string) func() ([]mdnsTransport, error) {
+	return func() ([]mdnsTransport, error) {
+		return []mdnsTransport{n.join(ip)}, nil
+	}
+}
+
+type fakeMDNSTransport struct {
+	network   *fakeMDNSNetwork
+	addr      net.Addr
+	inbox     chan mdnsPacket
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (t *fakeMDNSTransport) Send(msg []byte) error {
+	t.network.mut.Lock()
+	defer t.network.mut.Unlock()
+	for _, m := range t.network.members {
+		select {
+		case m.inbox <- mdnsPacket{data: slices.Clone(msg), src: t.addr}:
+		default:
+		}
+	}
+	return nil
+}
+
+func (t *fakeMDNSTransport) Recv(buf []byte) (int, net.Addr, error) {
+	select {
+	case pkt := <-t.inbox:
+		return copy(buf, pkt.data), pkt.src, nil
+	case <-t.closed:
+		return 0, nil, net.ErrClosed
+	}
+}
+
+func (t *fakeMDNSTransport) recvTimeout(buf []byte, timeout time.Duration) (int, net.Addr, error) {
+	select {
+	case pkt := <-t.inbox:
+		return copy(buf, pkt.data), pkt.src, nil
+	case <-time.After(timeout):
+		return 0, nil, context.DeadlineExceeded
+	}
+}
+
+func (t *fakeMDNSTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	t.network.mut.Lock()
+	t.network.members = slices.DeleteFunc(t.network.members, func(m *fakeMDNSTransport) bool { return m == t })
+	t.network.mut.Unlock()
+	return nil
+}
+
+func TestMDNSNames(t *testing.T) {
+	id := protocol.DeviceID{1, 2, 3}
+	for _, name := range []string{mdnsInstanceName(id), mdnsHostName(id)} {
+		if _, err := dnsmessage.NewName(name); err != nil {
+			t.Errorf("Invalid name %q: %v", name, err)
+		}
+		if label, _, _ := strings.Cut(name, "."); len(label) > 63 {
+			t.Errorf("Label of %q too long", name)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package discover
+
+import (
+	"errors"
+	"net"
+	"time"
+
+	"golang.org/x/net/ipv4"
+	"golang.org/x/net/ipv6"
+)
+
+const mdnsPort = 5353
+
+var (
+	mdnsGroupIPv4 = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: mdnsPort}
+	mdnsGroupIPv6 = &net.UDPAddr{IP: net.ParseIP("ff02::fb"), Port: mdnsPort}
+)
+
+// An mdnsTransport sends and receives mDNS packets.
+type mdnsTransport interface {
+	// Send multicasts the packet on all interfaces.
+	Send(msg []byte) error
+	// Recv reads the next packet into buf.
+	Recv(buf []byte) (int, net.Addr, error)
+	Close() error
+}
+
+// openMDNSTransports opens the mDNS sockets for IPv4 and IPv6, succeeding
+// if at least one of them could be opened.
+func openMDNSTransports() ([]mdnsTransport, error) {
+	intfs, err := mdnsInterfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	var transports []mdnsTransport
+	t4, err4 := newMDNSConn4(intfs)
+	if err4 == nil {
+		transports = append(transports, t4)
+	} else {
+		l.Debugln("discover: mDNS over IPv4:", err4)
+	}
+	t6, err6 := newMDNSConn6(intfs)
+	if err6 == nil {
+		transports = append(transports, t6)
+	} else {
+		l.Debugln("discover: mDNS over IPv6:", err6)
+	}
+	if len(transports) == 0 {
+		return nil, errors.Join(err4, err6)
+	}
+	return transports, nil
+}
+
+func mdnsInterfaces() ([]net.Interface, error) {
+	intfs, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	res := intfs[:0]
+	for _, intf := range intfs {
+		if intf.Flags&net.FlagRunning != 0 && intf.Flags&net.FlagMulticast != 0 {
+			res = append(res, intf)
+		}
+	}
+	if len(res) == 0 {
+		return nil, errors.New("no multicast interfaces available")
+	}
+	return res, nil
+}
+
+// Listening on a multicast address binds the wildcard address with
+// SO_REUSEADDR set, so that we coexist with other mDNS responders on the
+// host while sending from the mDNS port as required.
+
+type mdnsConn4 struct {
+	conn  net.PacketConn
+	pconn *ipv4.PacketConn
+	intfs []net.Interface
+}
+
+func newMDNSConn4(intfs []net.Interface) (*mdnsConn4, error) {
+	conn, err := net.ListenPacket("udp4", "224.0.0.0:5353")
+	if err != nil {
+		return nil, err
+	}
+	pconn := ipv4.NewPacketConn(conn)
+	var joined []net.Interface
+	for _, intf := range intfs {
+		if err := pconn.JoinGroup(&intf, mdnsGroupIPv4); err != nil {
+			l.Debugln("discover: mDNS IPv4 join", intf.Name, "failed:", err)
+			continue
+		}
+		joined = append(joined, intf)
+	}
+	if len(joined) == 0 {
+		conn.Close()
+		return nil, errors.New("no multicast interfaces joined")
+	}
+	_ = pconn.SetMulticastTTL(255)
+	_ = pconn.SetMulticastLoopback(true)
+	return &mdnsConn4{conn: conn, pconn: pconn, intfs: joined}, nil
+}
+
+func (c *mdnsConn4) Send(msg []byte) error {
+	var err error
+	success := 0
+	for _, intf := range c.intfs {
+		if err = c.pconn.SetMulticastInterface(&intf); err != nil {
+			continue
+		}
+		_ = c.pconn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err = c.pconn.WriteTo(msg, nil, mdnsGroupIPv4); err != nil {
+			l.Debugln("discover: mDNS IPv4 send on", intf.Name, "failed:", err)
+			continue
+		}
+		success++
+	}
+	if success == 0 {
+		return err
+	}
+	return nil
+}
+
+func (c *mdnsConn4) Recv(buf []byte) (int, net.Addr, error) {
+	n, _, src, err := c.pconn.ReadFrom(buf)
+	return n, src, err
+}
+
+func (c *mdnsConn4) Close() error {
+	return c.conn.Close()
+}
+
+type mdnsConn6 struct {
+	conn  net.PacketConn
+	pconn *ipv6.PacketConn
+	intfs []net.Interface
+}
+
+func newMDNSConn6(intfs []net.Interface) (*mdnsConn6, error) {
+	conn, err := net.ListenPacket("udp6", "[ff02::]:5353")
+	if err != nil {
+		return nil, err
+	}
+	pconn := ipv6.NewPacketConn(conn)
+	var joined []net.Interface
+	for _, intf := range intfs {
+		if err := pconn.JoinGroup(&intf, mdnsGroupIPv6); err != nil {
+			l.Debugln("discover: mDNS IPv6 join", intf.Name, "failed:", err)
+			continue
+		}
+		joined = append(joined, intf)
+	}
+	if len(joined) == 0 {
+		conn.Close()
+		return nil, errors.New("no multicast interfaces joined")
+	}
+	_ = pconn.SetMulticastHopLimit(255)
+	_ = pconn.SetMulticastLoopback(true)
+	return &mdnsConn6{conn: conn, pconn: pconn, intfs: joined}, nil
+}
+
+func (c *mdnsConn6) Send(msg []byte) error {
+	var err error
+	success := 0
+	for _, intf := range c.intfs {
+		if err = c.pconn.SetMulticastInterface(&intf); err != nil {
+			continue
+		}
+		_ = c.pconn.SetWriteDeadline(time.Now().Add(time.Second))
+		if _, err = c.pconn.WriteTo(msg, nil, mdnsGroupIPv6); err != nil {
+			l.Debugln("discover: mDNS IPv6 send on", intf.Name, "failed:", err)
+			continue
+		}
+		success++
+	}
+	if success == 0 {
+		return err
+	}
+	return nil
+}
+
+func (c *mdnsConn6) Recv(buf []byte) (int, net.Addr, error) {
+	n, _, src, err := c.pconn.ReadFrom(buf)
+	return n, src, err
+}
+
+func (c *mdnsConn6) Close() error {
+	return c.conn.Close()
+}
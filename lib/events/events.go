@@ -58,6 +58,10 @@ const (
 	ListenAddressesChanged
 	LoginAttempt
 	Failure
+	ConflictMergeFinished
+	MassChangeDetected
+	UserAction
+	DatabaseInconsistency
 
 	AllEvents = (1 << iota) - 1
 )
@@ -135,6 +139,14 @@ func (t EventType) String() string {
 		return "FolderWatchStateChanged"
 	case Failure:
 		return "Failure"
+	case ConflictMergeFinished:
+		return "ConflictMergeFinished"
+	case MassChangeDetected:
+		return "MassChangeDetected"
+	case UserAction:
+		return "UserAction"
+	case DatabaseInconsistency:
+		return "DatabaseInconsistency"
 	default:
 		return "Unknown"
 	}
@@ -222,6 +234,14 @@ func UnmarshalEventType(s string) EventType {
 		return FolderWatchStateChanged
 	case "Failure":
 		return Failure
+	case "ConflictMergeFinished":
+		return ConflictMergeFinished
+	case "MassChangeDetected":
+		return MassChangeDetected
+	case "UserAction":
+		return UserAction
+	case "DatabaseInconsistency":
+		return DatabaseInconsistency
 	default:
 		return 0
 	}
@@ -0,0 +1,839 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package fs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/syncthing/syncthing/internal/blob/s3"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+const FilesystemTypeS3 FilesystemType = "s3"
+
+func init() {
+	RegisterFilesystemType(FilesystemTypeS3, func(uri string, opts ...Option) (Filesystem, error) {
+		return newS3Filesystem(uri, opts...)
+	})
+}
+
+const (
+	s3MetaMtime = "mtime" // RFC 3339 modification time, as set by Chtimes
+	s3MetaMode  = "mode"  // octal permission bits, as set by Chmod
+	s3MetaDir   = "dir"   // set on directory markers
+
+	s3DefaultRegion = "us-east-1"
+)
+
+var (
+	errS3NotSupported  = errors.New("not supported on S3")
+	errS3NotADirectory = errors.New("not a directory")
+)
+
+// s3Filesystem is a Filesystem on top of an S3 compatible bucket. The URI
+// is the endpoint URL with the bucket and an optional prefix as the path,
+// e.g. https://minio.example.com:9000/bucket/some/prefix. The access key
+// ID is the user name of the URL and the secret access key the secret
+// given with OptionSecret, never part of the URL, or otherwise both are
+// taken from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment
+// variables. The region is the "region" query parameter.
+//
+// Objects are files, with the modification time and permissions in the
+// object metadata. Directories are implied by the objects below them, or
+// exist as empty marker objects with a trailing slash. Some servers strip
+// the trailing slash, so markers are also flagged in the metadata. Objects are
+// immutable, so files opened for writing are buffered in a local temporary
+// file and uploaded when synced or closed, and renaming is done by copying
+// and deleting. Symlinks, ownership and extended attributes are not
+// supported.
+type s3Filesystem struct {
+	sess    *s3.Session
+	uri     string
+	prefix  string // within the bucket, empty or ending in a slash
+	options []Option
+}
+
+func newS3Filesystem(uri string, opts ...Option) (*s3Filesystem, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("S3 endpoint must be an http or https URL, not %q", u.Redacted())
+	}
+	bucket, prefix, _ := strings.Cut(strings.Trim(u.Path, "/"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("S3 URL %q lacks a bucket", u.Redacted())
+	}
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	if _, ok := u.User.Password(); ok {
+		return nil, fmt.Errorf("S3 URL %q must not contain the secret access key, it's given separately", u.Redacted())
+	}
+	accessKeyID := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if u.User != nil {
+		accessKeyID = u.User.Username()
+		secretKey = secretOption(opts)
+	}
+	region := u.Query().Get("region")
+	if region == "" {
+		region = s3DefaultRegion
+	}
+
+	endpoint := (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+	sess, err := s3.NewSession(endpoint, region, bucket, accessKeyID, secretKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &s3Filesystem{
+		sess:    sess,
+		uri:     u.Redacted(),
+		prefix:  prefix,
+		options: opts,
+	}, nil
+}
+
+// key returns the object key for the given name, without trailing slash.
+// The root has the empty key.
+func (*s3Filesystem) key(name string) (string, error) {
+	// Takes care that name does not try to escape
+	rel, err := Canonicalize(name)
+	if err != nil {
+		return "", err
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// objectKey returns the full key within the bucket.
+func (f *s3Filesystem) objectKey(key string) string {
+	return f.prefix + key
+}
+
+// dirPrefix returns the listing prefix for the children of the directory.
+func (f *s3Filesystem) dirPrefix(key string) string {
+	if key == "" {
+		return f.prefix
+	}
+	return f.prefix + key + "/"
+}
+
+func (f *s3Filesystem) Lstat(name string) (FileInfo, error) {
+	key, err := f.key(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.stat(key)
+	if err != nil {
+		return nil, &os.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	return fi, nil
+}
+
+func (f *s3Filesystem) Stat(name string) (FileInfo, error) {
+	return f.Lstat(name)
+}
+
+func (f *s3Filesystem) stat(key string) (*s3FileInfo, error) {
+	if key == "" {
+		return &s3FileInfo{name: ".", dir: true, mode: 0o755}, nil
+	}
+	ctx := context.Background()
+
+	info, err := f.sess.Head(ctx, f.objectKey(key))
+	if err == nil {
+		fi := newS3FileInfo(key, info.Metadata[s3MetaDir] != "", info)
+		if fi.dir {
+			fi.marker = f.objectKey(key)
+		}
+		return fi, nil
+	} else if !errors.Is(err, s3.ErrNotFound) {
+		return nil, err
+	}
+
+	// It could still be a directory, either with an explicit marker or
+	// implied by the objects below it.
+	if info, err := f.sess.Head(ctx, f.dirPrefix(key)); err == nil {
+		fi := newS3FileInfo(key, true, info)
+		fi.marker = f.dirPrefix(key)
+		return fi, nil
+	} else if !errors.Is(err, s3.ErrNotFound) {
+		return nil, err
+	}
+	found := false
+	err = f.sess.ListPrefix(ctx, f.dirPrefix(key), "/", func(*s3.Object, string) bool {
+		found = true
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fs.ErrNotExist
+	}
+	return &s3FileInfo{name: path.Base(key), dir: true, mode: 0o755}, nil
+}
+
+func (f *s3Filesystem) DirNames(name string) ([]string, error) {
+	key, err := f.key(name)
+	if err != nil {
+		return nil, err
+	}
+	if fi, err := f.stat(key); err != nil {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: err}
+	} else if !fi.IsDir() {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: errS3NotADirectory}
+	}
+
+	prefix := f.dirPrefix(key)
+	seen := make(map[string]struct{})
+	var names []string
+	err = f.sess.ListPrefix(context.Background(), prefix, "/", func(obj *s3.Object, commonPrefix string) bool {
+		child := commonPrefix
+		if obj != nil {
+			child = *obj.Key
+		}
+		child = strings.TrimSuffix(strings.TrimPrefix(child, prefix), "/")
+		if child == "" {
+			// The directory marker itself
+			return true
+		}
+		if _, ok := seen[child]; !ok {
+			seen[child] = struct{}{}
+			names = append(names, child)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, &os.PathError{Op: "readdir", Path: name, Err: err}
+	}
+	return names, nil
+}
+
+func (f *s3Filesystem) Mkdir(name string, perm FileMode) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	if _, err := f.stat(key); err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return &os.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	return f.putDirMarker(key, perm, time.Now())
+}
+
+func (f *s3Filesystem) MkdirAll(name string, perm FileMode) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	if key == "" {
+		return nil
+	}
+	if fi, err := f.stat(key); err == nil {
+		if !fi.IsDir() {
+			return &os.PathError{Op: "mkdir", Path: name, Err: errS3NotADirectory}
+		}
+		return nil
+	}
+	if parent := path.Dir(key); parent != "." {
+		if err := f.MkdirAll(filepath.FromSlash(parent), perm); err != nil {
+			return err
+		}
+	}
+	return f.putDirMarker(key, perm, time.Now())
+}
+
+func (f *s3Filesystem) putDirMarker(key string, perm FileMode, mtime time.Time) error {
+	meta := s3Metadata(perm, mtime)
+	meta[s3MetaDir] = "true"
+	return f.sess.Put(context.Background(), f.dirPrefix(key), strings.NewReader(""), meta)
+}
+
+func (f *s3Filesystem) Chmod(name string, mode FileMode) error {
+	return f.updateMetadata(name, "chmod", func(meta map[string]string) {
+		meta[s3MetaMode] = strconv.FormatUint(uint64(mode&ModePerm), 8)
+	})
+}
+
+func (f *s3Filesystem) Chtimes(name string, _ time.Time, mtime time.Time) error {
+	return f.updateMetadata(name, "chtimes", func(meta map[string]string) {
+		meta[s3MetaMtime] = mtime.Format(time.RFC3339Nano)
+	})
+}
+
+// updateMetadata changes the metadata of a file or directory. Objects are
+// immutable, so this means copying the object onto itself.
+func (f *s3Filesystem) updateMetadata(name, op string, fn func(map[string]string)) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	fi, err := f.stat(key)
+	if err != nil {
+		return &os.PathError{Op: op, Path: name, Err: err}
+	}
+	if key == "" {
+		// The root can't have metadata.
+		return nil
+	}
+	meta := s3Metadata(fi.mode, fi.mtime)
+	fn(meta)
+	if fi.dir {
+		// Directories may not have a marker object, but that's fine to
+		// create.
+		meta[s3MetaDir] = "true"
+		err = f.sess.Put(context.Background(), f.dirPrefix(key), strings.NewReader(""), meta)
+	} else {
+		err = f.sess.Copy(context.Background(), f.objectKey(key), f.objectKey(key), meta)
+	}
+	if err != nil {
+		return &os.PathError{Op: op, Path: name, Err: err}
+	}
+	return nil
+}
+
+func (f *s3Filesystem) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (f *s3Filesystem) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+func (f *s3Filesystem) OpenFile(name string, flags int, mode FileMode) (File, error) {
+	key, err := f.key(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := f.stat(key)
+	exists := err == nil
+	switch {
+	case err != nil && !errors.Is(err, fs.ErrNotExist):
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	case exists && fi.IsDir():
+		return nil, &os.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+	case exists && flags&os.O_CREATE != 0 && flags&os.O_EXCL != 0:
+		return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+	case !exists && (flags&os.O_CREATE == 0 || flags&(os.O_WRONLY|os.O_RDWR) == 0):
+		return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	file := &s3File{
+		fs:   f,
+		name: name,
+		key:  key,
+	}
+	if flags&(os.O_WRONLY|os.O_RDWR) == 0 {
+		file.info = fi
+		return file, nil
+	}
+
+	// Files opened for writing are buffered locally in full.
+	file.mode = mode & ModePerm
+	if exists {
+		file.mode = fi.mode
+	}
+	file.local, err = os.CreateTemp("", "syncthing-s3-*")
+	if err != nil {
+		return nil, err
+	}
+	if exists && flags&os.O_TRUNC == 0 && fi.size > 0 {
+		if err := f.sess.Download(context.Background(), f.objectKey(key), file.local); err != nil {
+			file.discard()
+			return nil, &os.PathError{Op: "open", Path: name, Err: err}
+		}
+	}
+	file.dirty = !exists || flags&os.O_TRUNC != 0
+	if flags&os.O_APPEND != 0 {
+		if _, err := file.local.Seek(0, io.SeekEnd); err != nil {
+			file.discard()
+			return nil, err
+		}
+	}
+	return file, nil
+}
+
+func (f *s3Filesystem) Remove(name string) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	fi, err := f.stat(key)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if !fi.IsDir() {
+		return f.sess.Delete(context.Background(), f.objectKey(key))
+	}
+	if key == "" {
+		return &os.PathError{Op: "remove", Path: name, Err: errS3NotSupported}
+	}
+	empty := true
+	prefix := f.dirPrefix(key)
+	err = f.sess.ListPrefix(context.Background(), prefix, "/", func(obj *s3.Object, _ string) bool {
+		if obj != nil && *obj.Key == prefix {
+			return true
+		}
+		empty = false
+		return false
+	})
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: err}
+	}
+	if !empty {
+		return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	if fi.marker == "" {
+		// Implied directories vanish with their last child.
+		return nil
+	}
+	return f.sess.Delete(context.Background(), fi.marker)
+}
+
+func (f *s3Filesystem) RemoveAll(name string) error {
+	key, err := f.key(name)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	var keys []string
+	err = f.sess.ListPrefix(ctx, f.dirPrefix(key), "", func(obj *s3.Object, _ string) bool {
+		keys = append(keys, *obj.Key)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if key != "" {
+		keys = append(keys, f.objectKey(key))
+	}
+	for _, k := range keys {
+		if err := f.sess.Delete(ctx, k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *s3Filesystem) Rename(oldname, newname string) error {
+	oldKey, err := f.key(oldname)
+	if err != nil {
+		return err
+	}
+	newKey, err := f.key(newname)
+	if err != nil {
+		return err
+	}
+	fi, err := f.stat(oldKey)
+	if err != nil {
+		return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+	}
+	if oldKey == newKey {
+		return nil
+	}
+
+	ctx := context.Background()
+	if !fi.IsDir() {
+		if err := f.sess.Copy(ctx, f.objectKey(oldKey), f.objectKey(newKey), nil); err != nil {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+		}
+		return f.sess.Delete(ctx, f.objectKey(oldKey))
+	}
+
+	oldPrefix, newPrefix := f.dirPrefix(oldKey), f.dirPrefix(newKey)
+	renames := make(map[string]string)
+	err = f.sess.ListPrefix(ctx, oldPrefix, "", func(obj *s3.Object, _ string) bool {
+		renames[*obj.Key] = newPrefix + strings.TrimPrefix(*obj.Key, oldPrefix)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	if fi.marker != "" && fi.marker != oldPrefix {
+		// A marker without the trailing slash isn't part of the listing.
+		renames[fi.marker] = f.objectKey(newKey)
+	}
+	for src, dst := range renames {
+		if err := f.sess.Copy(ctx, src, dst, nil); err != nil {
+			return &os.LinkError{Op: "rename", Old: oldname, New: newname, Err: err}
+		}
+	}
+	for src := range renames {
+		if err := f.sess.Delete(ctx, src); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*s3Filesystem) Walk(_ string, _ WalkFunc) error {
+	// implemented in WalkFilesystem
+	return errors.New("not implemented")
+}
+
+func (f *s3Filesystem) Glob(pattern string) ([]string, error) {
+	dir, file := filepath.Split(pattern)
+	names, err := f.DirNames(dir)
+	if err != nil {
+		return nil, nil //nolint:nilerr
+	}
+	var matches []string
+	for _, name := range names {
+		if ok, err := filepath.Match(file, name); err != nil {
+			return nil, err
+		} else if ok {
+			matches = append(matches, filepath.Join(dir, name))
+		}
+	}
+	return matches, nil
+}
+
+func (*s3Filesystem) Lchown(_, _, _ string) error {
+	return errS3NotSupported
+}
+
+func (*s3Filesystem) SymlinksSupported() bool {
+	return false
+}
+
+func (*s3Filesystem) CreateSymlink(_, _ string) error {
+	return errS3NotSupported
+}
+
+func (*s3Filesystem) ReadSymlink(_ string) (string, error) {
+	return "", errS3NotSupported
+}
+
+func (*s3Filesystem) Watch(_ string, _ Matcher, _ context.Context, _ bool) (<-chan Event, <-chan error, error) {
+	return nil, nil, ErrWatchNotSupported
+}
+
+func (*s3Filesystem) Hide(_ string) error {
+	return nil
+}
+
+func (*s3Filesystem) Unhide(_ string) error {
+	return nil
+}
+
+func (*s3Filesystem) Roots() ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (*s3Filesystem) Usage(_ string) (Usage, error) {
+	return Usage{}, errors.New("not implemented")
+}
+
+func (*s3Filesystem) Type() FilesystemType {
+	return FilesystemTypeS3
+}
+
+func (f *s3Filesystem) URI() string {
+	return f.uri
+}
+
+func (f *s3Filesystem) Options() []Option {
+	return f.options
+}
+
+func (*s3Filesystem) SameFile(fi1, fi2 FileInfo) bool {
+	f1, ok1 := fi1.(*s3FileInfo)
+	f2, ok2 := fi2.(*s3FileInfo)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return f1.key == f2.key && f1.dir == f2.dir
+}
+
+func (*s3Filesystem) PlatformData(_ string, _, _ bool, _ XattrFilter) (protocol.PlatformData, error) {
+	return protocol.PlatformData{}, nil
+}
+
+func (*s3Filesystem) GetXattr(_ string, _ XattrFilter) ([]protocol.Xattr, error) {
+	return nil, ErrXattrsNotSupported
+}
+
+func (*s3Filesystem) SetXattr(_ string, _ []protocol.Xattr, _ XattrFilter) error {
+	return ErrXattrsNotSupported
+}
+
+func (*s3Filesystem) underlying() (Filesystem, bool) {
+	return nil, false
+}
+
+func s3Metadata(mode FileMode, mtime time.Time) map[string]string {
+	return map[string]string{
+		s3MetaMode:  strconv.FormatUint(uint64(mode&ModePerm), 8),
+		s3MetaMtime: mtime.Format(time.RFC3339Nano),
+	}
+}
+
+// s3File is a file in an s3Filesystem. Files opened for reading only do
+// ranged reads from the object; files opened for writing are backed by a
+// local temporary file that replaces the object on Sync and Close.
+type s3File struct {
+	fs   *s3Filesystem
+	name string
+	key  string
+
+	mut    sync.Mutex
+	info   *s3FileInfo // read only
+	offset int64       // read only
+	local  *os.File    // writable
+	mode   FileMode    // writable
+	dirty  bool        // writable
+}
+
+func (f *s3File) Name() string {
+	return f.name
+}
+
+func (f *s3File) Read(p []byte) (int, error) {
+	if f.local != nil {
+		return f.local.Read(p)
+	}
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	n, err := f.readAt(p, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *s3File) ReadAt(p []byte, off int64) (int, error) {
+	if f.local != nil {
+		return f.local.ReadAt(p, off)
+	}
+	return f.readAt(p, off)
+}
+
+func (f *s3File) readAt(p []byte, off int64) (int, error) {
+	if off >= f.info.size {
+		return 0, io.EOF
+	}
+	length := int64(len(p))
+	if rest := f.info.size - off; rest < length {
+		length = rest
+	}
+	if length == 0 {
+		return 0, nil
+	}
+	rd, err := f.fs.sess.GetRange(context.Background(), f.fs.objectKey(f.key), off, length)
+	if err != nil {
+		return 0, &os.PathError{Op: "read", Path: f.name, Err: err}
+	}
+	defer rd.Close()
+	n, err := io.ReadFull(rd, p[:length])
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (f *s3File) Seek(offset int64, whence int) (int64, error) {
+	if f.local != nil {
+		return f.local.Seek(offset, whence)
+	}
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.info.size
+	default:
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	if offset < 0 {
+		return 0, &os.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *s3File) Write(p []byte) (int, error) {
+	if f.local == nil {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+	f.mut.Lock()
+	f.dirty = true
+	f.mut.Unlock()
+	return f.local.Write(p)
+}
+
+func (f *s3File) WriteAt(p []byte, off int64) (int, error) {
+	if f.local == nil {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: fs.ErrPermission}
+	}
+	f.mut.Lock()
+	f.dirty = true
+	f.mut.Unlock()
+	return f.local.WriteAt(p, off)
+}
+
+func (f *s3File) Truncate(size int64) error {
+	if f.local == nil {
+		return &os.PathError{Op: "truncate", Path: f.name, Err: fs.ErrPermission}
+	}
+	f.mut.Lock()
+	f.dirty = true
+	f.mut.Unlock()
+	return f.local.Truncate(size)
+}
+
+func (f *s3File) Stat() (FileInfo, error) {
+	if f.local == nil {
+		return f.info, nil
+	}
+	fi, err := f.local.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return &s3FileInfo{
+		name:  path.Base(f.key),
+		key:   f.key,
+		size:  fi.Size(),
+		mtime: fi.ModTime(),
+		mode:  f.mode,
+	}, nil
+}
+
+// Sync uploads the local copy of a file opened for writing, if it changed.
+func (f *s3File) Sync() error {
+	if f.local == nil {
+		return nil
+	}
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	if !f.dirty {
+		return nil
+	}
+	fi, err := f.local.Stat()
+	if err != nil {
+		return err
+	}
+	rd := io.NewSectionReader(f.local, 0, fi.Size())
+	if err := f.fs.sess.Put(context.Background(), f.fs.objectKey(f.key), rd, s3Metadata(f.mode, time.Now())); err != nil {
+		return &os.PathError{Op: "sync", Path: f.name, Err: err}
+	}
+	f.dirty = false
+	return nil
+}
+
+func (f *s3File) Close() error {
+	if f.local == nil {
+		return nil
+	}
+	err := f.Sync()
+	f.discard()
+	return err
+}
+
+func (f *s3File) discard() {
+	f.local.Close()
+	os.Remove(f.local.Name())
+}
+
+type s3FileInfo struct {
+	name   string
+	key    string
+	dir    bool
+	marker string // object key of the directory marker, if any
+	size   int64
+	mtime  time.Time
+	mode   FileMode // permission bits only
+}
+
+func newS3FileInfo(key string, dir bool, info s3.ObjectInfo) *s3FileInfo {
+	fi := &s3FileInfo{
+		name:  path.Base(key),
+		key:   key,
+		dir:   dir,
+		mtime: info.LastModified,
+		mode:  0o644,
+	}
+	if dir {
+		fi.mode = 0o755
+	} else {
+		fi.size = info.Size
+	}
+	if t, err := time.Parse(time.RFC3339Nano, info.Metadata[s3MetaMtime]); err == nil {
+		fi.mtime = t
+	}
+	if m, err := strconv.ParseUint(info.Metadata[s3MetaMode], 8, 32); err == nil {
+		fi.mode = FileMode(m) & ModePerm
+	}
+	return fi
+}
+
+func (fi *s3FileInfo) Name() string {
+	return fi.name
+}
+
+func (fi *s3FileInfo) Mode() FileMode {
+	if fi.dir {
+		return fi.mode | FileMode(os.ModeDir)
+	}
+	return fi.mode
+}
+
+func (fi *s3FileInfo) Size() int64 {
+	return fi.size
+}
+
+func (fi *s3FileInfo) ModTime() time.Time {
+	return fi.mtime
+}
+
+func (fi *s3FileInfo) IsDir() bool {
+	return fi.dir
+}
+
+func (*s3FileInfo) Sys() interface{} {
+	return nil
+}
+
+func (fi *s3FileInfo) IsRegular() bool {
+	return !fi.dir
+}
+
+func (*s3FileInfo) IsSymlink() bool {
+	return false
+}
+
+func (*s3FileInfo) Owner() int {
+	return -1
+}
+
+func (*s3FileInfo) Group() int {
+	return -1
+}
+
+func (*s3FileInfo) InodeChangeTime() time.Time {
+	return time.Time{}
+}
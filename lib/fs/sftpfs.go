@@ -0,0 +1,710 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package fs
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+const FilesystemTypeSFTP FilesystemType = "sftp"
+
+func init() {
+	RegisterFilesystemType(FilesystemTypeSFTP, func(uri string, opts ...Option) (Filesystem, error) {
+		return newSFTPFilesystem(uri, opts...)
+	})
+}
+
+const (
+	sftpDefaultPort       = "22"
+	sftpDefaultKnownHosts = "~/.ssh/known_hosts"
+	sftpDialTimeout       = 30 * time.Second
+	sftpIdleTimeout       = 5 * time.Minute
+)
+
+var errSFTPNotSupported = errors.New("not supported over SFTP")
+
+// sftpConns holds the connections to SFTP servers, keyed by user and
+// host. Filesystems are created and thrown away all the time, so they share
+// a connection instead of each having their own. Connections that weren't
+// used for sftpIdleTimeout are closed and forgotten.
+var (
+	sftpConns       = make(map[string]*sftpConn)
+	sftpConnsMut    sync.Mutex
+	sftpCleanerOnce sync.Once
+)
+
+// sftpFilesystem is a Filesystem on a remote host, accessed over SFTP. The
+// URI has the form sftp://user@host[:port]/path, where the path is
+// absolute on the remote host. The password, if any, is the secret given
+// with OptionSecret and never part of the URI. The query parameters are:
+//
+//   - identity: a private key file used to authenticate, in addition to
+//     the password if there is one
+//   - fingerprint: the SHA256 fingerprint of the host key, as printed by
+//     ssh-keygen -l, e.g. "SHA256:nThbg6kXUpJWGl7E1IGOCspRomTxdCARLviKw6E5SY8"
+//   - knownhosts: a known_hosts file to check the host key against
+//     instead, by default ~/.ssh/known_hosts
+//
+// The connection is established on first use, and again after it is lost.
+// The SFTP protocol has no change notifications, so the folder needs to
+// rely on periodic scans.
+type sftpFilesystem struct {
+	conn    *sftpConn
+	root    string
+	uri     string
+	options []Option
+}
+
+func newSFTPFilesystem(uri string, opts ...Option) (*sftpFilesystem, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "sftp" {
+		return nil, fmt.Errorf("SFTP URL must have the sftp scheme, not %q", u.Redacted())
+	}
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("SFTP URL %q lacks a host", u.Redacted())
+	}
+	if !path.IsAbs(u.Path) {
+		return nil, fmt.Errorf("SFTP URL %q lacks an absolute path", u.Redacted())
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, fmt.Errorf("SFTP URL %q must not contain the password, it's given separately", u.Redacted())
+	}
+	password := secretOption(opts)
+
+	// A connection made with other credentials or host key checks is
+	// replaced, as those changed in the config.
+	key := u.User.Username() + "@" + u.Host
+	auth := sha256.Sum256([]byte(u.RawQuery + "\x00" + password))
+	sftpConnsMut.Lock()
+	defer sftpConnsMut.Unlock()
+	conn, ok := sftpConns[key]
+	if !ok || conn.auth != auth {
+		newConn, err := newSFTPConn(u, password)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			conn.close()
+		}
+		conn = newConn
+		conn.auth = auth
+		sftpConns[key] = conn
+		sftpCleanerOnce.Do(func() {
+			go sftpCloseIdle()
+		})
+	}
+
+	return &sftpFilesystem{
+		conn:    conn,
+		root:    path.Clean(u.Path),
+		uri:     u.Redacted(),
+		options: opts,
+	}, nil
+}
+
+// sftpCloseIdle closes and forgets the connections that weren't used for a
+// while and have no files open. Filesystems still holding on to one connect
+// again when used.
+func sftpCloseIdle() {
+	for range time.NewTicker(time.Minute).C {
+		sftpConnsMut.Lock()
+		for key, conn := range sftpConns {
+			if conn.idleSince(time.Now().Add(-sftpIdleTimeout)) {
+				conn.close()
+				delete(sftpConns, key)
+			}
+		}
+		sftpConnsMut.Unlock()
+	}
+}
+
+// sftpConn is a lazily established SFTP session.
+type sftpConn struct {
+	addr   string
+	config *ssh.ClientConfig
+	auth   [sha256.Size]byte // what the config was made from
+
+	mut       sync.Mutex
+	client    *sftp.Client
+	lastUsed  time.Time
+	openFiles int
+}
+
+func newSFTPConn(u *url.URL, password string) (*sftpConn, error) {
+	config := &ssh.ClientConfig{
+		User:    u.User.Username(),
+		Timeout: sftpDialTimeout,
+	}
+	if config.User == "" {
+		return nil, fmt.Errorf("SFTP URL %q lacks a user name", u.Redacted())
+	}
+
+	query := u.Query()
+	if identity := query.Get("identity"); identity != "" {
+		identity, err := ExpandTilde(identity)
+		if err != nil {
+			return nil, err
+		}
+		bs, err := os.ReadFile(identity)
+		if err != nil {
+			return nil, fmt.Errorf("reading SFTP identity: %w", err)
+		}
+		signer, err := ssh.ParsePrivateKey(bs)
+		if err != nil {
+			return nil, fmt.Errorf("reading SFTP identity: %w", err)
+		}
+		config.Auth = append(config.Auth, ssh.PublicKeys(signer))
+	}
+	if password != "" {
+		config.Auth = append(config.Auth, ssh.Password(password))
+	}
+	if len(config.Auth) == 0 {
+		return nil, fmt.Errorf("SFTP filesystem %q has neither a password nor an identity", u.Redacted())
+	}
+
+	if fingerprint := query.Get("fingerprint"); fingerprint != "" {
+		config.HostKeyCallback = func(_ string, _ net.Addr, key ssh.PublicKey) error {
+			if got := ssh.FingerprintSHA256(key); got != fingerprint {
+				return fmt.Errorf("host key fingerprint %s does not match %s", got, fingerprint)
+			}
+			return nil
+		}
+	} else {
+		file := query.Get("knownhosts")
+		if file == "" {
+			file = sftpDefaultKnownHosts
+		}
+		file, err := ExpandTilde(file)
+		if err != nil {
+			return nil, err
+		}
+		// The file is read when connecting, so that it may be created
+		// or updated afterwards.
+		config.HostKeyCallback = func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			check, err := knownhosts.New(file)
+			if err != nil {
+				return err
+			}
+			return check(hostname, remote, key)
+		}
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = sftpDefaultPort
+	}
+	return &sftpConn{
+		addr:   net.JoinHostPort(u.Hostname(), port),
+		config: config,
+	}, nil
+}
+
+// get returns the SFTP client, connecting first if necessary.
+func (c *sftpConn) get() (*sftp.Client, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.lastUsed = time.Now()
+	if c.client != nil {
+		return c.client, nil
+	}
+
+	sshClient, err := ssh.Dial("tcp", c.addr, c.config)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to SFTP server: %w", err)
+	}
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		sshClient.Close()
+		return nil, fmt.Errorf("starting SFTP session: %w", err)
+	}
+	l.Debugln("connected to SFTP server", c.addr)
+	c.client = client
+
+	go func() {
+		err := client.Wait()
+		l.Debugln("SFTP connection to", c.addr, "lost:", err)
+		c.mut.Lock()
+		if c.client == client {
+			c.client = nil
+		}
+		c.mut.Unlock()
+		sshClient.Close()
+	}()
+
+	return client, nil
+}
+
+// idleSince returns whether the connection wasn't used since the given time
+// and has no files open.
+func (c *sftpConn) idleSince(t time.Time) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.openFiles == 0 && c.lastUsed.Before(t)
+}
+
+// fileOpened and fileClosed keep track of the open files, which use the
+// client without asking for it again.
+func (c *sftpConn) fileOpened() {
+	c.mut.Lock()
+	c.openFiles++
+	c.lastUsed = time.Now()
+	c.mut.Unlock()
+}
+
+func (c *sftpConn) fileClosed() {
+	c.mut.Lock()
+	c.openFiles--
+	c.lastUsed = time.Now()
+	c.mut.Unlock()
+}
+
+// close closes the connection, if there is one.
+func (c *sftpConn) close() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if c.client != nil {
+		c.client.Close()
+		c.client = nil
+	}
+}
+
+// path returns the remote path for the given name.
+func (f *sftpFilesystem) path(name string) (string, error) {
+	// Takes care that name does not try to escape
+	rel, err := Canonicalize(name)
+	if err != nil {
+		return "", err
+	}
+	return path.Join(f.root, filepath.ToSlash(rel)), nil
+}
+
+// client returns the SFTP client and the remote path for the given name.
+func (f *sftpFilesystem) client(name string) (*sftp.Client, string, error) {
+	p, err := f.path(name)
+	if err != nil {
+		return nil, "", err
+	}
+	client, err := f.conn.get()
+	if err != nil {
+		return nil, "", err
+	}
+	return client, p, nil
+}
+
+func (f *sftpFilesystem) Chmod(name string, mode FileMode) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	return client.Chmod(p, os.FileMode(mode))
+}
+
+func (f *sftpFilesystem) Lchown(name, uid, gid string) error {
+	nuid, err := strconv.Atoi(uid)
+	if err != nil {
+		return err
+	}
+	ngid, err := strconv.Atoi(gid)
+	if err != nil {
+		return err
+	}
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	// SFTP has no way to change the ownership of the link itself.
+	if fi, err := client.Lstat(p); err != nil {
+		return err
+	} else if fi.Mode()&os.ModeSymlink != 0 {
+		return &os.PathError{Op: "lchown", Path: name, Err: errSFTPNotSupported}
+	}
+	return client.Chown(p, nuid, ngid)
+}
+
+func (f *sftpFilesystem) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	return client.Chtimes(p, atime, mtime)
+}
+
+func (f *sftpFilesystem) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o666)
+}
+
+func (f *sftpFilesystem) CreateSymlink(target, name string) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	return client.Symlink(target, p)
+}
+
+func (f *sftpFilesystem) DirNames(name string) ([]string, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := client.ReadDir(p)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(infos))
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	return names, nil
+}
+
+func (f *sftpFilesystem) Lstat(name string) (FileInfo, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := client.Lstat(p)
+	if err != nil {
+		return nil, &os.PathError{Op: "lstat", Path: name, Err: err}
+	}
+	return sftpFileInfo{fi, p}, nil
+}
+
+func (f *sftpFilesystem) Stat(name string) (FileInfo, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := client.Stat(p)
+	if err != nil {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: err}
+	}
+	return sftpFileInfo{fi, p}, nil
+}
+
+func (f *sftpFilesystem) Mkdir(name string, perm FileMode) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	if err := client.Mkdir(p); err != nil {
+		// SFTP version 3 has no specific error for existing files.
+		if _, serr := client.Lstat(p); serr == nil {
+			return &os.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+		}
+		return &os.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+	return client.Chmod(p, os.FileMode(perm))
+}
+
+func (f *sftpFilesystem) MkdirAll(name string, perm FileMode) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	if fi, err := client.Stat(p); err == nil {
+		if !fi.IsDir() {
+			return &os.PathError{Op: "mkdir", Path: name, Err: errors.New("not a directory")}
+		}
+		return nil
+	}
+	if parent := filepath.Dir(name); parent != name {
+		if err := f.MkdirAll(parent, perm); err != nil {
+			return err
+		}
+	}
+	if err := f.Mkdir(name, perm); err != nil && !IsExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *sftpFilesystem) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (f *sftpFilesystem) OpenFile(name string, flags int, mode FileMode) (File, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return nil, err
+	}
+	fd, err := client.OpenFile(p, flags)
+	if err != nil {
+		if flags&os.O_EXCL != 0 {
+			if _, serr := client.Lstat(p); serr == nil {
+				return nil, &os.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+			}
+		}
+		return nil, &os.PathError{Op: "open", Path: name, Err: err}
+	}
+	if flags&os.O_CREATE != 0 {
+		// The server applies its own umask; like open(2), the mode only
+		// matters when the file is created, but there's no way to tell
+		// whether it was.
+		if err := fd.Chmod(os.FileMode(mode)); err != nil {
+			l.Debugln("chmod after open:", err)
+		}
+	}
+	f.conn.fileOpened()
+	return &sftpFile{File: fd, name: name, client: client, conn: f.conn}, nil
+}
+
+func (f *sftpFilesystem) ReadSymlink(name string) (string, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return "", err
+	}
+	return client.ReadLink(p)
+}
+
+func (f *sftpFilesystem) Remove(name string) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	return client.Remove(p)
+}
+
+func (f *sftpFilesystem) RemoveAll(name string) error {
+	client, p, err := f.client(name)
+	if err != nil {
+		return err
+	}
+	if _, err := client.Lstat(p); IsNotExist(err) {
+		return nil
+	}
+	return client.RemoveAll(p)
+}
+
+func (f *sftpFilesystem) Rename(oldname, newname string) error {
+	client, oldp, err := f.client(oldname)
+	if err != nil {
+		return err
+	}
+	newp, err := f.path(newname)
+	if err != nil {
+		return err
+	}
+	// Plain SFTP renames fail when the target exists, unlike rename(2).
+	if _, ok := client.HasExtension("posix-rename@openssh.com"); ok {
+		return client.PosixRename(oldp, newp)
+	}
+	if err := client.Rename(oldp, newp); err != nil {
+		if fi, serr := client.Lstat(newp); serr != nil || fi.IsDir() {
+			return err
+		}
+		if err := client.Remove(newp); err != nil {
+			return err
+		}
+		return client.Rename(oldp, newp)
+	}
+	return nil
+}
+
+func (*sftpFilesystem) SymlinksSupported() bool {
+	return true
+}
+
+func (*sftpFilesystem) Walk(_ string, _ WalkFunc) error {
+	// implemented in WalkFilesystem
+	return errors.New("not implemented")
+}
+
+func (*sftpFilesystem) Watch(_ string, _ Matcher, _ context.Context, _ bool) (<-chan Event, <-chan error, error) {
+	return nil, nil, ErrWatchNotSupported
+}
+
+func (*sftpFilesystem) Hide(_ string) error {
+	return nil
+}
+
+func (*sftpFilesystem) Unhide(_ string) error {
+	return nil
+}
+
+func (f *sftpFilesystem) Glob(pattern string) ([]string, error) {
+	client, p, err := f.client(pattern)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := client.Glob(p)
+	if err != nil {
+		return nil, err
+	}
+	for i, match := range matches {
+		rel, err := filepath.Rel(filepath.FromSlash(f.root), filepath.FromSlash(match))
+		if err != nil {
+			return nil, err
+		}
+		matches[i] = rel
+	}
+	return matches, nil
+}
+
+func (*sftpFilesystem) Roots() ([]string, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (f *sftpFilesystem) Usage(name string) (Usage, error) {
+	client, p, err := f.client(name)
+	if err != nil {
+		return Usage{}, err
+	}
+	stat, err := client.StatVFS(p)
+	if err != nil {
+		return Usage{}, err
+	}
+	return Usage{
+		Free:  stat.Bavail * stat.Frsize,
+		Total: stat.Blocks * stat.Frsize,
+	}, nil
+}
+
+func (*sftpFilesystem) Type() FilesystemType {
+	return FilesystemTypeSFTP
+}
+
+func (f *sftpFilesystem) URI() string {
+	return f.uri
+}
+
+func (f *sftpFilesystem) Options() []Option {
+	return f.options
+}
+
+func (*sftpFilesystem) SameFile(fi1, fi2 FileInfo) bool {
+	// SFTP has no inode numbers, so the best we can do is the path.
+	f1, ok1 := fi1.(sftpFileInfo)
+	f2, ok2 := fi2.(sftpFileInfo)
+	if !ok1 || !ok2 {
+		return false
+	}
+	return f1.path == f2.path && f1.FileInfo.Mode().Type() == f2.FileInfo.Mode().Type()
+}
+
+func (f *sftpFilesystem) PlatformData(name string, withOwnership, _ bool, _ XattrFilter) (protocol.PlatformData, error) {
+	var pd protocol.PlatformData
+	if withOwnership {
+		stat, err := f.Lstat(name)
+		if err != nil {
+			return protocol.PlatformData{}, err
+		}
+		// User and group names are those of the remote host, which we
+		// can't look up.
+		pd.Unix = &protocol.UnixData{
+			UID: stat.Owner(),
+			GID: stat.Group(),
+		}
+	}
+	return pd, nil
+}
+
+func (*sftpFilesystem) GetXattr(_ string, _ XattrFilter) ([]protocol.Xattr, error) {
+	return nil, ErrXattrsNotSupported
+}
+
+func (*sftpFilesystem) SetXattr(_ string, _ []protocol.Xattr, _ XattrFilter) error {
+	return ErrXattrsNotSupported
+}
+
+func (*sftpFilesystem) underlying() (Filesystem, bool) {
+	return nil, false
+}
+
+// sftpFile implements the fs.File interface on top of an sftp.File
+type sftpFile struct {
+	*sftp.File
+	name   string
+	client *sftp.Client
+	conn   *sftpConn
+	closed bool
+}
+
+func (f *sftpFile) Close() error {
+	if !f.closed {
+		f.closed = true
+		f.conn.fileClosed()
+	}
+	return f.File.Close()
+}
+
+func (f sftpFile) Name() string {
+	return f.name
+}
+
+func (f sftpFile) Stat() (FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return sftpFileInfo{info, f.File.Name()}, nil
+}
+
+func (f sftpFile) Sync() error {
+	if _, ok := f.client.HasExtension("fsync@openssh.com"); !ok {
+		// Nothing we can do
+		return nil
+	}
+	return f.File.Sync()
+}
+
+// sftpFileInfo implements the fs.FileInfo interface on top of the
+// os.FileInfo returned by the SFTP client.
+type sftpFileInfo struct {
+	os.FileInfo
+	path string
+}
+
+func (e sftpFileInfo) Mode() FileMode {
+	return FileMode(e.FileInfo.Mode())
+}
+
+func (e sftpFileInfo) IsSymlink() bool {
+	return e.FileInfo.Mode()&os.ModeSymlink != 0
+}
+
+func (e sftpFileInfo) IsRegular() bool {
+	return e.FileInfo.Mode().IsRegular()
+}
+
+func (e sftpFileInfo) Owner() int {
+	if st, ok := e.Sys().(*sftp.FileStat); ok {
+		return int(st.UID)
+	}
+	return -1
+}
+
+func (e sftpFileInfo) Group() int {
+	if st, ok := e.Sys().(*sftp.FileStat); ok {
+		return int(st.GID)
+	}
+	return -1
+}
+
+func (sftpFileInfo) InodeChangeTime() time.Time {
+	return time.Time{}
+}
@@ -0,0 +1,278 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package fs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/syncthing/syncthing/lib/build"
+)
+
+// newTestSFTPFilesystem starts an in-process SFTP server and returns a
+// filesystem on it, as well as the local directory it serves.
+func newTestSFTPFilesystem(t *testing.T) (Filesystem, string) {
+	t.Helper()
+	if build.IsWindows {
+		t.Skip("the served directory is not an absolute SFTP path on Windows")
+	}
+
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hostKey, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(c ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if c.User() == "user" && string(pass) == "secret" {
+				return nil, nil
+			}
+			return nil, errors.New("access denied")
+		},
+	}
+	config.AddHostKey(hostKey)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveTestSFTP(conn, config)
+		}
+	}()
+
+	dir := t.TempDir()
+	u := url.URL{
+		Scheme:   "sftp",
+		User:     url.User("user"),
+		Host:     ln.Addr().String(),
+		Path:     filepath.ToSlash(dir),
+		RawQuery: url.Values{"fingerprint": {ssh.FingerprintSHA256(hostKey.PublicKey())}}.Encode(),
+	}
+	fs := NewFilesystem(FilesystemTypeSFTP, u.String(), OptionSecret("secret"))
+	if _, ok := unwrapFilesystem[*errorFilesystem](fs); ok {
+		t.Fatal("Failed to create SFTP filesystem")
+	}
+	return fs, dir
+}
+
+func serveTestSFTP(conn net.Conn, config *ssh.ServerConfig) {
+	defer conn.Close()
+	_, chans, reqs, err := ssh.NewServerConn(conn, config)
+	if err != nil {
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+	for newChan := range chans {
+		if newChan.ChannelType() != "session" {
+			newChan.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		ch, reqs, err := newChan.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			for req := range reqs {
+				req.Reply(req.Type == "subsystem" && string(req.Payload[4:]) == "sftp", nil)
+			}
+		}()
+		srv, err := sftp.NewServer(ch)
+		if err != nil {
+			return
+		}
+		go func() {
+			srv.Serve()
+			srv.Close()
+		}()
+	}
+}
+
+func TestSFTPFS(t *testing.T) {
+	fs, dir := newTestSFTPFilesystem(t)
+
+	if err := fs.MkdirAll(filepath.Join("a", "b"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.Mkdir("a", 0o755); !IsExist(err) {
+		t.Error("Expected exists error, got", err)
+	}
+
+	name := filepath.Join("a", "b", "file")
+	fd, err := fs.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.Write([]byte("hello, world")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.WriteAt([]byte("HELLO"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := os.ReadFile(filepath.Join(dir, name)); err != nil || string(bs) != "HELLO, world" {
+		t.Errorf("Unexpected contents %q, %v", bs, err)
+	}
+
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := fs.Chtimes(name, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := fs.Lstat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.IsRegular() || fi.Size() != 12 || !fi.ModTime().Equal(mtime) {
+		t.Errorf("Unexpected file info: regular %v, size %d, mtime %v", fi.IsRegular(), fi.Size(), fi.ModTime())
+	}
+	if fi.Owner() != os.Getuid() {
+		t.Errorf("Unexpected owner %d", fi.Owner())
+	}
+
+	fd, err = fs.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs, err := io.ReadAll(fd); err != nil || string(bs) != "HELLO, world" {
+		t.Errorf("Unexpected contents %q, %v", bs, err)
+	}
+	fd.Close()
+
+	if err := fs.CreateSymlink("file", filepath.Join("a", "b", "link")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := fs.Lstat(filepath.Join("a", "b", "link")); err != nil || !fi.IsSymlink() {
+		t.Error("Expected symlink, got", fi, err)
+	}
+	if target, err := fs.ReadSymlink(filepath.Join("a", "b", "link")); err != nil || target != "file" {
+		t.Errorf("Unexpected symlink target %q, %v", target, err)
+	}
+
+	names, err := fs.DirNames(filepath.Join("a", "b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "file" || names[1] != "link" {
+		t.Error("Unexpected directory entries", names)
+	}
+
+	// Renaming replaces an existing target, like on a local filesystem.
+	other, err := fs.Create("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Close()
+	if err := fs.Rename(name, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.Lstat(name); !IsNotExist(err) {
+		t.Error("Expected renamed file to be gone, got", err)
+	}
+	if fi, err := fs.Lstat("other"); err != nil || fi.Size() != 12 {
+		t.Error("Expected renamed file to replace target, got", fi, err)
+	}
+
+	if usage, err := fs.Usage("."); err != nil || usage.Total == 0 {
+		t.Error("Unexpected usage", usage, err)
+	}
+
+	if err := fs.RemoveAll("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Error("Expected removed directory to be gone, got", err)
+	}
+
+	if _, err := fs.Lstat(filepath.FromSlash("../escape")); err == nil {
+		t.Error("Expected paths outside the root to be rejected")
+	}
+	if _, _, err := fs.Watch(".", nil, nil, false); err != ErrWatchNotSupported {
+		t.Error("Expected watching to be unsupported, got", err)
+	}
+}
+
+func TestSFTPFSHostKey(t *testing.T) {
+	fs, _ := newTestSFTPFilesystem(t)
+
+	u, err := url.Parse(fs.URI())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.RawQuery = url.Values{"fingerprint": {"SHA256:wrong"}}.Encode()
+	other := NewFilesystem(FilesystemTypeSFTP, u.String(), OptionSecret("secret"))
+	if _, err := other.Lstat("."); err == nil {
+		t.Error("Expected mismatching host key to be rejected")
+	}
+}
+
+func TestSFTPFSConnections(t *testing.T) {
+	fs, _ := newTestSFTPFilesystem(t)
+	if _, err := fs.Lstat("."); err != nil {
+		t.Fatal(err)
+	}
+	sftpFS, _ := unwrapFilesystem[*sftpFilesystem](fs)
+	conn := sftpFS.conn
+
+	// The password is never part of the URL.
+	u, err := url.Parse(fs.URI())
+	if err != nil {
+		t.Fatal(err)
+	}
+	u.User = url.UserPassword("user", "secret")
+	if _, ok := unwrapFilesystem[*errorFilesystem](NewFilesystem(FilesystemTypeSFTP, u.String())); !ok {
+		t.Error("Expected a password in the URL to be rejected")
+	}
+
+	// Other paths on the same host share the connection.
+	u.User = url.User("user")
+	u.Path += "/sub"
+	other := NewFilesystem(FilesystemTypeSFTP, u.String(), OptionSecret("secret"))
+	if otherFS, _ := unwrapFilesystem[*sftpFilesystem](other); otherFS.conn != conn {
+		t.Error("Expected the connection to be shared")
+	}
+
+	// Open files keep the connection from being idle.
+	fd, err := fs.Create("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conn.idleSince(time.Now().Add(time.Minute)) {
+		t.Error("Expected connection with open file not to be idle")
+	}
+	fd.Close()
+	fd.Close()
+	if !conn.idleSince(time.Now().Add(time.Minute)) {
+		t.Error("Expected connection without open files to be idle")
+	}
+}
@@ -37,3 +37,27 @@ func RegisterFilesystemType(fsType FilesystemType, fn FilesystemFactory) {
 	defer filesystemFactoriesMutex.Unlock()
 	filesystemFactories[fsType] = fn
 }
+
+// OptionSecret is the secret a remote filesystem authenticates with, such
+// as a password, kept out of the URI so that it's not shown or logged with
+// it. It's used by the filesystem when created and doesn't change what the
+// filesystem shows, so it's not part of the string either.
+type OptionSecret string
+
+func (o OptionSecret) apply(fs Filesystem) Filesystem {
+	return fs
+}
+
+func (OptionSecret) String() string {
+	return "secret"
+}
+
+// secretOption returns the secret among the options, if any.
+func secretOption(opts []Option) string {
+	for _, opt := range opts {
+		if secret, ok := opt.(OptionSecret); ok {
+			return string(secret)
+		}
+	}
+	return ""
+}
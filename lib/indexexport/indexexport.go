@@ -0,0 +1,394 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package indexexport exports the index of a folder to a portable file and
+// imports it again, typically on a new device with a copy of the same data.
+// Imported local files are verified against the folder contents using
+// metadata only, so that matching files need not be hashed again.
+package indexexport
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+
+	"github.com/syncthing/syncthing/internal/gen/dbproto"
+	"github.com/syncthing/syncthing/lib/build"
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/stats"
+)
+
+// FormatVersion is the version of the export format written by Export.
+const FormatVersion = 1
+
+// Number of files imported in one database update.
+const importBatchFiles = 1000
+
+var magic = []byte("STINDEX\n")
+
+var (
+	ErrFormat      = errors.New("not a Syncthing index export")
+	ErrNotEmpty    = errors.New("folder already has an index")
+	errLocalDevice = errors.New("header is missing the local device")
+)
+
+// Export writes the index of the folder, including the index IDs, mtime
+// mappings and folder statistics, to w.
+func Export(w io.Writer, folder string, fset *db.FileSet, folderStats *stats.FolderStatisticsReference) error {
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+
+	header := &dbproto.IndexExportHeader{
+		Version: FormatVersion,
+		Folder:  folder,
+		Created: time.Now().UnixNano(),
+	}
+	devices := append([]protocol.DeviceID{protocol.LocalDeviceID}, fset.ListDevices()...)
+	for _, dev := range devices {
+		header.Devices = append(header.Devices, &dbproto.IndexExportDevice{
+			DeviceId: dev[:],
+			IndexId:  uint64(fset.IndexID(dev)),
+			Sequence: snap.Sequence(dev),
+		})
+	}
+	if header.Statistics, err = exportStatistics(folderStats); err != nil {
+		return err
+	}
+
+	gw := gzip.NewWriter(w)
+	bw := bufio.NewWriter(gw)
+	if _, err := bw.Write(magic); err != nil {
+		return err
+	}
+	if _, err := protodelim.MarshalTo(bw, header); err != nil {
+		return err
+	}
+
+	err = fset.MtimeMappings(func(name string, mapping fs.MtimeMapping) bool {
+		_, err = protodelim.MarshalTo(bw, &dbproto.IndexExportRecord{
+			Record: &dbproto.IndexExportRecord_Mtime{Mtime: &dbproto.IndexExportMtime{
+				Name:    filepath.ToSlash(name),
+				Real:    unixNano(mapping.Real),
+				Virtual: unixNano(mapping.Virtual),
+			}},
+		})
+		return err == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, dev := range devices {
+		snap.WithHave(dev, func(f protocol.FileInfo) bool {
+			// Names in the database are already normalized, only the
+			// separators are native.
+			f.Name = filepath.ToSlash(f.Name)
+			_, err = protodelim.MarshalTo(bw, &dbproto.IndexExportRecord{
+				Record: &dbproto.IndexExportRecord_File{File: &dbproto.IndexExportFile{
+					Device: int32(i),
+					File:   f.ToWire(true),
+				}},
+			})
+			return err == nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func exportStatistics(folderStats *stats.FolderStatisticsReference) (*dbproto.IndexExportStatistics, error) {
+	lastScan, err := folderStats.GetLastScanTime()
+	if err != nil {
+		return nil, err
+	}
+	lastFile, err := folderStats.GetLastFile()
+	if err != nil {
+		return nil, err
+	}
+	return &dbproto.IndexExportStatistics{
+		LastScan:        unixNano(lastScan),
+		LastFile:        lastFile.Filename,
+		LastFileAt:      unixNano(lastFile.At),
+		LastFileDeleted: lastFile.Deleted,
+	}, nil
+}
+
+// A Reader reads an index written by Export.
+type Reader struct {
+	r      *bufio.Reader
+	header *dbproto.IndexExportHeader
+}
+
+// NewReader reads and checks the header of the export in r.
+func NewReader(r io.Reader) (*Reader, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+	br := bufio.NewReader(gr)
+	buf := make([]byte, len(magic))
+	if _, err := io.ReadFull(br, buf); err != nil || !bytes.Equal(buf, magic) {
+		return nil, ErrFormat
+	}
+	header := new(dbproto.IndexExportHeader)
+	if err := protodelim.UnmarshalFrom(br, header); err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	if header.Version != FormatVersion {
+		return nil, fmt.Errorf("unsupported export format version %d", header.Version)
+	}
+	if len(header.Devices) == 0 {
+		return nil, errLocalDevice
+	}
+	return &Reader{r: br, header: header}, nil
+}
+
+// Folder returns the ID of the exported folder.
+func (r *Reader) Folder() string {
+	return r.header.Folder
+}
+
+// Created returns the time the export was made.
+func (r *Reader) Created() time.Time {
+	return fromUnixNano(r.header.Created)
+}
+
+// ImportResult summarizes what was imported.
+type ImportResult struct {
+	LocalFiles   int // local files that match the folder contents
+	SkippedFiles int // local files left for the next scan
+	RemoteFiles  int
+	Devices      int // remote devices whose index was imported
+}
+
+// Import reads the exported index into the folder, which must not have an
+// index yet. Local files are only imported if they match what is on disk,
+// the same way the scanner decides a file is unchanged; everything else is
+// left to the initial scan. Remote files are imported for the devices the
+// folder is still shared with. The local index ID isn't imported, as the
+// local files get new sequence numbers: other devices will receive our
+// index once more. If the import fails, whatever was imported so far is
+// dropped again, so that it may be retried.
+func (r *Reader) Import(ldb *db.Lowlevel, fcfg config.FolderConfiguration) (ImportResult, error) {
+	fset, err := db.NewFileSet(fcfg.ID, ldb)
+	if err != nil {
+		return ImportResult{}, err
+	}
+	if fset.Sequence(protocol.LocalDeviceID) != 0 || len(fset.ListDevices()) != 0 {
+		return ImportResult{}, ErrNotEmpty
+	}
+	res, err := r.importInto(fset, fcfg, stats.NewFolderStatisticsReference(ldb, fcfg.ID))
+	if err != nil {
+		db.DropFolder(ldb, fcfg.ID)
+	}
+	return res, err
+}
+
+func (r *Reader) importInto(fset *db.FileSet, fcfg config.FolderConfiguration, folderStats *stats.FolderStatisticsReference) (ImportResult, error) {
+	var res ImportResult
+
+	devices := make([]protocol.DeviceID, len(r.header.Devices))
+	for i, d := range r.header.Devices {
+		dev, err := protocol.DeviceIDFromBytes(d.DeviceId)
+		if err != nil {
+			return res, fmt.Errorf("reading header: %w", err)
+		}
+		devices[i] = dev
+	}
+	if devices[0] != protocol.LocalDeviceID {
+		return res, errLocalDevice
+	}
+
+	v := newVerifier(fcfg, fset)
+	var batch []protocol.FileInfo
+	batchDevice := -1
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		fset.Update(devices[batchDevice], batch)
+		batch = batch[:0]
+	}
+
+	for {
+		var rec dbproto.IndexExportRecord
+		err := protodelim.UnmarshalFrom(r.r, &rec)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return res, fmt.Errorf("reading record: %w", err)
+		}
+
+		switch rec := rec.Record.(type) {
+		case *dbproto.IndexExportRecord_Mtime:
+			mapping := fs.MtimeMapping{
+				Real:    fromUnixNano(rec.Mtime.Real),
+				Virtual: fromUnixNano(rec.Mtime.Virtual),
+			}
+			if err := fset.SetMtimeMapping(filepath.FromSlash(rec.Mtime.Name), mapping); err != nil {
+				return res, err
+			}
+
+		case *dbproto.IndexExportRecord_File:
+			idx := int(rec.File.Device)
+			if idx < 0 || idx >= len(devices) {
+				return res, fmt.Errorf("reading record: invalid device index %d", idx)
+			}
+			if idx != 0 && !fcfg.SharedWith(devices[idx]) {
+				continue
+			}
+			f := protocol.FileInfoFromDB(rec.File.File)
+			f.Name = osutil.NormalizedFilename(f.Name)
+			if idx == 0 {
+				var ok bool
+				if f, ok = v.verify(f); !ok {
+					l.Debugln("Skipping changed or missing file", f.Name)
+					res.SkippedFiles++
+					continue
+				}
+				res.LocalFiles++
+			} else {
+				res.RemoteFiles++
+			}
+			if idx != batchDevice || len(batch) >= importBatchFiles {
+				flush()
+				batchDevice = idx
+			}
+			batch = append(batch, f)
+		}
+	}
+	flush()
+
+	for i, d := range r.header.Devices[1:] {
+		dev := devices[i+1]
+		if !fcfg.SharedWith(dev) {
+			continue
+		}
+		fset.SetIndexID(dev, protocol.IndexID(d.IndexId))
+		res.Devices++
+	}
+
+	if err := importStatistics(folderStats, r.header.Statistics); err != nil {
+		return res, err
+	}
+	return res, nil
+}
+
+func importStatistics(folderStats *stats.FolderStatisticsReference, st *dbproto.IndexExportStatistics) error {
+	if st == nil {
+		return nil
+	}
+	if st.LastScan != 0 {
+		if err := folderStats.SetLastScanTime(fromUnixNano(st.LastScan)); err != nil {
+			return err
+		}
+	}
+	if st.LastFile != "" {
+		return folderStats.SetLastFile(stats.LastFile{
+			At:       fromUnixNano(st.LastFileAt),
+			Filename: st.LastFile,
+			Deleted:  st.LastFileDeleted,
+		})
+	}
+	return nil
+}
+
+// verifier compares imported local files with the folder contents.
+type verifier struct {
+	fcfg       config.FolderConfiguration
+	filesystem fs.Filesystem
+	comp       protocol.FileInfoComparison
+	localFlags uint32
+}
+
+func newVerifier(fcfg config.FolderConfiguration, fset *db.FileSet) *verifier {
+	var localFlags uint32
+	switch fcfg.Type {
+	case config.FolderTypeReceiveOnly, config.FolderTypeReceiveEncrypted:
+		localFlags = protocol.FlagLocalReceiveOnly
+	}
+	return &verifier{
+		fcfg:       fcfg,
+		filesystem: fcfg.Filesystem(fset),
+		comp: protocol.FileInfoComparison{
+			ModTimeWindow:   fcfg.ModTimeWindow(),
+			IgnorePerms:     fcfg.IgnorePerms,
+			IgnoreBlocks:    true,
+			IgnoreFlags:     localFlags,
+			IgnoreOwnership: !(fcfg.SendOwnership || fcfg.SyncOwnership),
+			IgnoreXattrs:    !(fcfg.SendXattrs || fcfg.SyncXattrs),
+		},
+		localFlags: localFlags,
+	}
+}
+
+// verify returns the file as it should be imported and whether it matches
+// the folder contents. Deleted and invalid files are imported as is.
+func (v *verifier) verify(f protocol.FileInfo) (protocol.FileInfo, bool) {
+	if f.IsDeleted() || f.IsInvalid() {
+		return f, true
+	}
+	name := osutil.NativeFilename(f.Name)
+	info, err := v.filesystem.Lstat(name)
+	if err != nil {
+		return f, false
+	}
+	cur, err := scanner.CreateFileInfo(info, name, v.filesystem, !v.comp.IgnoreOwnership, !v.comp.IgnoreXattrs, v.fcfg.XattrFilter)
+	if err != nil {
+		return f, false
+	}
+	cur.Name = f.Name
+	if cur.Type == protocol.FileInfoTypeFile && build.IsWindows {
+		cur.Permissions |= f.Permissions & 0o111
+	}
+	cur.LocalFlags = v.localFlags
+	cur.NoPermissions = v.fcfg.IgnorePerms
+	cur.Platform.MergeWith(&f.Platform)
+
+	// The inode change time is specific to the device the export was made
+	// on.
+	f.InodeChangeNs = 0
+	if !f.IsEquivalentOptional(cur, v.comp) {
+		return f, false
+	}
+	f.InodeChangeNs = cur.InodeChangeNs
+	return f, true
+}
+
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func fromUnixNano(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
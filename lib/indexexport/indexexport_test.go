@@ -0,0 +1,262 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package indexexport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/db/backend"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/stats"
+)
+
+var (
+	myID, _     = protocol.DeviceIDFromString("ZNWFSWE-RWRV2BD-45BLMCV-LTDE2UR-4LJDW6J-R5BPWEB-TXD27XJ-IZF5RA4")
+	remoteID, _ = protocol.DeviceIDFromString("AIR6LPZ-7K4PTTV-UXQSMUU-CPQ5YWH-OEDFIIQ-JUG777G-2YQXXR5-YD6AWQR")
+	otherID, _  = protocol.DeviceIDFromString("GYRZZQB-IRNPV4Z-T7TC52W-EQYJ3TT-FDQW6MW-DFLMU42-SSSU6EM-FBK2VAY")
+)
+
+func TestExportImport(t *testing.T) {
+	dir := t.TempDir()
+	fcfg := config.FolderConfiguration{
+		ID:             "test",
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           dir,
+		Devices:        []config.FolderDeviceConfiguration{{DeviceID: myID}, {DeviceID: remoteID}},
+	}
+
+	src := newFolder(t, fcfg.ID)
+	filesystem := fcfg.Filesystem(src.fset)
+
+	writeFile(t, dir, "a", "hello", time.Unix(1600000000, 0))
+	writeFile(t, dir, filepath.Join("dir", "b"), "world", time.Unix(1600000001, 0))
+	writeFile(t, dir, "changed", "before", time.Unix(1600000002, 0))
+	// "mapped" has a virtual mtime, as on a filesystem that can't store
+	// the mtime we want.
+	writeFile(t, dir, "mapped", "mapped", time.Unix(1600000003, 0))
+	if err := src.fset.SetMtimeMapping("mapped", fs.MtimeMapping{Real: time.Unix(1600000003, 0), Virtual: time.Unix(1500000000, 0)}); err != nil {
+		t.Fatal(err)
+	}
+
+	var local []protocol.FileInfo
+	for _, name := range []string{"a", "dir", filepath.Join("dir", "b"), "changed", "mapped"} {
+		info, err := filesystem.Lstat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := scanner.CreateFileInfo(info, name, filesystem, false, false, fcfg.XattrFilter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Version = protocol.Vector{}.Update(myID.Short())
+		local = append(local, f)
+	}
+	if local[4].ModTime() != time.Unix(1500000000, 0) {
+		t.Fatal("mtime mapping not applied:", local[4].ModTime())
+	}
+	local = append(local, protocol.FileInfo{Name: "deleted", Deleted: true, Version: protocol.Vector{}.Update(myID.Short())})
+	src.fset.Update(protocol.LocalDeviceID, local)
+	remote := []protocol.FileInfo{local[0], local[3]}
+	remote[1].Version = remote[1].Version.Update(remoteID.Short())
+	for i := range remote {
+		remote[i].Sequence = int64(i + 1)
+	}
+	src.fset.Update(remoteID, remote)
+	src.fset.SetIndexID(remoteID, 42)
+	src.fset.Update(otherID, remote)
+	src.fset.SetIndexID(otherID, 43)
+
+	lastScan := time.Unix(1600000010, 0)
+	if err := src.stats.SetLastScanTime(lastScan); err != nil {
+		t.Fatal(err)
+	}
+	lastFile := stats.LastFile{At: time.Unix(1600000009, 0), Filename: "a"}
+	if err := src.stats.SetLastFile(lastFile); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, fcfg.ID, src.fset, src.stats); err != nil {
+		t.Fatal(err)
+	}
+
+	// Change a file after exporting, it must not be trusted on import.
+	writeFile(t, dir, "changed", "after the export", time.Unix(1600000020, 0))
+
+	r, err := NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Folder() != fcfg.ID {
+		t.Errorf("Expected folder %q, got %q", fcfg.ID, r.Folder())
+	}
+
+	dstDB := newLowlevel(t)
+	res, err := r.Import(dstDB, fcfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := openFolder(t, dstDB, fcfg.ID)
+	expected := ImportResult{LocalFiles: 5, SkippedFiles: 1, RemoteFiles: 2, Devices: 1}
+	if res != expected {
+		t.Errorf("Expected %+v, got %+v", expected, res)
+	}
+
+	snap, err := dst.fset.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer snap.Release()
+	for _, f := range local {
+		got, ok := snap.Get(protocol.LocalDeviceID, f.Name)
+		if f.Name == "changed" {
+			if ok {
+				t.Error("Changed file was imported")
+			}
+			continue
+		}
+		if !ok {
+			t.Errorf("File %q wasn't imported", f.Name)
+			continue
+		}
+		if !got.Version.Equal(f.Version) || !got.IsEquivalent(f, 0) {
+			t.Errorf("Imported file %v doesn't match exported %v", got, f)
+		}
+	}
+	if _, ok := snap.Get(otherID, "a"); ok {
+		t.Error("Imported files of a device the folder isn't shared with")
+	}
+	if id := dst.fset.IndexID(remoteID); id != 42 {
+		t.Errorf("Expected remote index ID 42, got %v", id)
+	}
+	if id := dst.fset.IndexID(protocol.LocalDeviceID); id == src.fset.IndexID(protocol.LocalDeviceID) {
+		t.Error("Local index ID was imported")
+	}
+
+	if got, err := dst.stats.GetLastScanTime(); err != nil {
+		t.Fatal(err)
+	} else if !got.Equal(lastScan) {
+		t.Errorf("Expected last scan %v, got %v", lastScan, got)
+	}
+	if got, err := dst.stats.GetLastFile(); err != nil {
+		t.Fatal(err)
+	} else if got.Filename != lastFile.Filename || !got.At.Equal(lastFile.At) {
+		t.Errorf("Expected last file %v, got %v", lastFile, got)
+	}
+
+	// Importing again is refused.
+	r, err = NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Import(dstDB, fcfg); !errors.Is(err, ErrNotEmpty) {
+		t.Errorf("Expected %v, got %v", ErrNotEmpty, err)
+	}
+}
+
+func TestImportFailure(t *testing.T) {
+	fcfg := config.FolderConfiguration{
+		ID:             "test",
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Devices:        []config.FolderDeviceConfiguration{{DeviceID: myID}, {DeviceID: remoteID}},
+	}
+
+	src := newFolder(t, fcfg.ID)
+	var remote []protocol.FileInfo
+	for i := 0; i < 2*importBatchFiles; i++ {
+		remote = append(remote, protocol.FileInfo{Name: fmt.Sprintf("file%d", i), Sequence: int64(i + 1), Version: protocol.Vector{}.Update(remoteID.Short())})
+	}
+	src.fset.Update(remoteID, remote)
+	var buf bytes.Buffer
+	if err := Export(&buf, fcfg.ID, src.fset, src.stats); err != nil {
+		t.Fatal(err)
+	}
+
+	// A truncated export fails after importing some of the files, which
+	// must not be left behind.
+	dstDB := newLowlevel(t)
+	r, err := NewReader(bytes.NewReader(buf.Bytes()[:buf.Len()-16]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Import(dstDB, fcfg); err == nil || errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("Expected the truncated import to fail, got %v", err)
+	}
+
+	r, err = NewReader(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := r.Import(dstDB, fcfg)
+	if err != nil {
+		t.Fatal("Retrying the import:", err)
+	}
+	if res.RemoteFiles != len(remote) {
+		t.Errorf("Expected %d remote files, got %d", len(remote), res.RemoteFiles)
+	}
+}
+
+func TestReaderFormat(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("not an export"))); !errors.Is(err, ErrFormat) {
+		t.Errorf("Expected %v, got %v", ErrFormat, err)
+	}
+}
+
+type folder struct {
+	fset  *db.FileSet
+	stats *stats.FolderStatisticsReference
+}
+
+func newFolder(t *testing.T, id string) folder {
+	t.Helper()
+	return openFolder(t, newLowlevel(t), id)
+}
+
+func newLowlevel(t *testing.T) *db.Lowlevel {
+	t.Helper()
+	ldb, err := db.NewLowlevel(backend.OpenMemory(), events.NoopLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ldb.Close() })
+	return ldb
+}
+
+func openFolder(t *testing.T, ldb *db.Lowlevel, id string) folder {
+	t.Helper()
+	fset, err := db.NewFileSet(id, ldb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return folder{fset: fset, stats: stats.NewFolderStatisticsReference(ldb, id)}
+}
+
+func writeFile(t *testing.T, dir, name, content string, mtime time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
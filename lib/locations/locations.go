@@ -28,6 +28,7 @@ const (
 	HTTPSCertFile LocationEnum = "httpsCertFile"
 	HTTPSKeyFile  LocationEnum = "httpsKeyFile"
 	Database      LocationEnum = "database"
+	SQLiteDB      LocationEnum = "sqliteDatabase"
 	LogFile       LocationEnum = "logFile"
 	PanicLog      LocationEnum = "panicLog"
 	AuditLog      LocationEnum = "auditLog"
@@ -48,6 +49,7 @@ const (
 	UserHomeBaseDir BaseDirEnum = "userHome"
 
 	LevelDBDir          = "index-v0.14.0.db"
+	SQLiteDBFile        = "index-v0.14.0.sqlite"
 	configFileName      = "config.xml"
 	defaultStateDir     = ".local/state/syncthing"
 	oldDefaultConfigDir = ".config/syncthing"
@@ -120,6 +122,7 @@ var locationTemplates = map[LocationEnum]string{
 	HTTPSCertFile: "${config}/https-cert.pem",
 	HTTPSKeyFile:  "${config}/https-key.pem",
 	Database:      "${data}/" + LevelDBDir,
+	SQLiteDB:      "${data}/" + SQLiteDBFile,
 	LogFile:       "${data}/syncthing.log", // --logfile on Windows
 	PanicLog:      "${data}/panic-%{timestamp}.log",
 	AuditLog:      "${data}/audit-%{timestamp}.log",
@@ -167,7 +170,7 @@ func PrettyPaths() string {
 	fmt.Fprintf(&b, "Configuration file:\n\t%s\n\n", Get(ConfigFile))
 	fmt.Fprintf(&b, "Device private key & certificate files:\n\t%s\n\t%s\n\n", Get(KeyFile), Get(CertFile))
 	fmt.Fprintf(&b, "GUI / API HTTPS private key & certificate files:\n\t%s\n\t%s\n\n", Get(HTTPSKeyFile), Get(HTTPSCertFile))
-	fmt.Fprintf(&b, "Database location:\n\t%s\n\n", Get(Database))
+	fmt.Fprintf(&b, "Database location:\n\t%s (LevelDB)\n\t%s (SQLite)\n\n", Get(Database), Get(SQLiteDB))
 	fmt.Fprintf(&b, "Log file:\n\t%s\n\n", Get(LogFile))
 	fmt.Fprintf(&b, "GUI override directory:\n\t%s\n\n", Get(GUIAssets))
 	fmt.Fprintf(&b, "Default sync folder directory:\n\t%s\n\n", Get(DefFolder))
@@ -241,10 +244,14 @@ func unixConfigDir(userHome, xdgConfigHome, xdgStateHome string, fileExists func
 // unixDataDir returns the default data directory, where we store the
 // database, log files, etc, on Unix-like systems.
 func unixDataDir(userHome, configDir, xdgDataHome, xdgStateHome string, fileExists func(string) bool) string {
+	dbExists := func(dir string) bool {
+		return fileExists(filepath.Join(dir, LevelDBDir)) || fileExists(filepath.Join(dir, SQLiteDBFile))
+	}
+
 	// If a database exists at the config location, use that. This is the
 	// most common case for both legacy (~/.config/syncthing) and current
 	// (~/.local/state/syncthing) setups.
-	if fileExists(filepath.Join(configDir, LevelDBDir)) {
+	if dbExists(configDir) {
 		return configDir
 	}
 
@@ -253,14 +260,14 @@ func unixDataDir(userHome, configDir, xdgDataHome, xdgStateHome string, fileExis
 	// but that's not what we did previously, so we retain the old behavior.
 	if xdgDataHome != "" {
 		candidate := filepath.Join(xdgDataHome, "syncthing")
-		if fileExists(filepath.Join(candidate, LevelDBDir)) {
+		if dbExists(candidate) {
 			return candidate
 		}
 	}
 
 	// Legacy: if a database exists under ~/.config/syncthing, use that
 	candidate := filepath.Join(userHome, oldDefaultConfigDir)
-	if fileExists(filepath.Join(candidate, LevelDBDir)) {
+	if dbExists(candidate) {
 		return candidate
 	}
 
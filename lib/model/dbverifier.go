@@ -0,0 +1,174 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/stats"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+// How often the database verifier looks for a folder that is due. At most
+// one folder is verified each time.
+const dbVerifierCheckInterval = time.Minute
+
+// DatabaseVerifyResult is the outcome of verifying the database of a
+// folder.
+type DatabaseVerifyResult struct {
+	Time     time.Time          `json:"time"`
+	Duration time.Duration      `json:"duration"`
+	Findings []db.VerifyFinding `json:"findings"`
+	Error    string             `json:"error,omitempty"`
+}
+
+// dbVerifier verifies and repairs the database of one folder after another
+// in the background, see db.FileSet.Verify. When each folder was last
+// verified is kept in its statistics, so that a device that's restarted
+// more often than the interval still gets verified.
+type dbVerifier struct {
+	model   *model
+	runMut  sync.Mutex // held while verifying
+	mut     sync.Mutex // protects results
+	results map[string]DatabaseVerifyResult
+}
+
+func newDBVerifier(m *model) *dbVerifier {
+	return &dbVerifier{
+		model:   m,
+		runMut:  sync.NewMutex(),
+		mut:     sync.NewMutex(),
+		results: make(map[string]DatabaseVerifyResult),
+	}
+}
+
+func (v *dbVerifier) Serve(ctx context.Context) error {
+	ticker := time.NewTicker(dbVerifierCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case now := <-ticker.C:
+			v.verifyDue(ctx, now)
+		}
+	}
+}
+
+func (*dbVerifier) String() string {
+	return "dbVerifier"
+}
+
+// verifyDue verifies the folder whose last verification is the longest
+// ago, if that is more than the configured interval.
+func (v *dbVerifier) verifyDue(ctx context.Context, now time.Time) {
+	interval := time.Duration(v.model.cfg.Options().DatabaseVerifyIntervalH) * time.Hour
+	if interval <= 0 {
+		return
+	}
+
+	v.model.mut.RLock()
+	fsets := make(map[string]*db.FileSet, len(v.model.folderFiles))
+	for folder, fset := range v.model.folderFiles {
+		if v.model.checkFolderRunningRLocked(folder) == nil {
+			fsets[folder] = fset
+		}
+	}
+	v.model.mut.RUnlock()
+
+	var due string
+	var dueLast time.Time
+	v.mut.Lock()
+	for folder := range v.results {
+		if _, ok := fsets[folder]; !ok {
+			delete(v.results, folder)
+		}
+	}
+	for folder := range fsets {
+		last := v.lastVerified(folder, now)
+		if now.Sub(last) < interval {
+			continue
+		}
+		if due == "" || last.Before(dueLast) || (last.Equal(dueLast) && folder < due) {
+			due, dueLast = folder, last
+		}
+	}
+	v.mut.Unlock()
+
+	if due != "" {
+		v.verify(ctx, due, fsets[due])
+	}
+}
+
+// verify verifies the database of the given folder, reporting any findings
+// as events.
+func (v *dbVerifier) verify(ctx context.Context, folder string, fset *db.FileSet) DatabaseVerifyResult {
+	v.runMut.Lock()
+	defer v.runMut.Unlock()
+
+	l.Debugln("Verifying database for folder", folder)
+	res := DatabaseVerifyResult{Time: time.Now()}
+	findings, err := fset.Verify(ctx)
+	res.Duration = time.Since(res.Time)
+	res.Findings = findings
+	if res.Findings == nil {
+		res.Findings = []db.VerifyFinding{}
+	}
+	if err != nil {
+		l.Warnf("Verifying database for folder %s: %v", folder, err)
+		res.Error = err.Error()
+	}
+	for _, finding := range findings {
+		v.model.evLogger.Log(events.DatabaseInconsistency, map[string]interface{}{
+			"folder": folder,
+			"check":  finding.Check,
+			"count":  finding.Count,
+		})
+	}
+
+	if ctx.Err() == nil {
+		v.mut.Lock()
+		v.results[folder] = res
+		v.mut.Unlock()
+		if err := stats.NewFolderStatisticsReference(v.model.db, folder).SetLastVerifyTime(res.Time); err != nil {
+			l.Debugln("Saving database verification time for folder", folder, err)
+		}
+	}
+	return res
+}
+
+// lastVerified returns when the folder was last verified. A folder that
+// never was counts as verified when first seen, so that it becomes due one
+// interval later and not right at startup.
+func (v *dbVerifier) lastVerified(folder string, now time.Time) time.Time {
+	folderStats := stats.NewFolderStatisticsReference(v.model.db, folder)
+	last, err := folderStats.GetLastVerifyTime()
+	if err != nil {
+		l.Debugln("Getting database verification time for folder", folder, err)
+		return now
+	}
+	if last.IsZero() {
+		if err := folderStats.SetLastVerifyTime(now); err != nil {
+			l.Debugln("Saving database verification time for folder", folder, err)
+		}
+		return now
+	}
+	return last
+}
+
+func (v *dbVerifier) Results() map[string]DatabaseVerifyResult {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+	res := make(map[string]DatabaseVerifyResult, len(v.results))
+	for folder, r := range v.results {
+		res[folder] = r
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDatabaseVerifier(t *testing.T) {
+	m, _, fcfg, wcfgCancel := setupModelWithConnection(t)
+	defer wcfgCancel()
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	if _, err := m.VerifyDatabase(context.Background(), "nonexistent"); !errors.Is(err, ErrFolderMissing) {
+		t.Errorf("Expected missing folder error, got %v", err)
+	}
+
+	res, err := m.VerifyDatabase(context.Background(), fcfg.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Error != "" || len(res.Findings) != 0 {
+		t.Errorf("Unexpected result for consistent database: %+v", res)
+	}
+	if got := m.DatabaseVerifyResults()[fcfg.ID]; !got.Time.Equal(res.Time) {
+		t.Errorf("Expected stored result %+v, got %+v", res, got)
+	}
+
+	// Not due yet.
+	m.dbVerifier.verifyDue(context.Background(), res.Time.Add(time.Hour))
+	if got := m.DatabaseVerifyResults()[fcfg.ID]; !got.Time.Equal(res.Time) {
+		t.Error("Folder verified before the interval passed")
+	}
+	m.dbVerifier.verifyDue(context.Background(), res.Time.Add(25*time.Hour))
+	if got := m.DatabaseVerifyResults()[fcfg.ID]; !got.Time.After(res.Time) {
+		t.Error("Folder not verified after the interval passed")
+	}
+
+	// When the folder was last verified survives a restart.
+	last := m.DatabaseVerifyResults()[fcfg.ID].Time
+	v := newDBVerifier(m.model)
+	v.verifyDue(context.Background(), last.Add(time.Hour))
+	if _, ok := v.Results()[fcfg.ID]; ok {
+		t.Error("Folder verified after a restart before the interval passed")
+	}
+	v.verifyDue(context.Background(), last.Add(25*time.Hour))
+	if _, ok := v.Results()[fcfg.ID]; !ok {
+		t.Error("Folder not verified after a restart after the interval passed")
+	}
+}
@@ -64,6 +64,7 @@ type folder struct {
 	pullScheduled chan struct{}
 	pullPause     time.Duration
 	pullFailTimer *time.Timer
+	scheduleTimer *time.Timer // next start or end of a pull-only sync window
 
 	scanErrors []FileError
 	pullErrors []FileError
@@ -83,6 +84,10 @@ type folder struct {
 
 	puller    puller
 	versioner versioner.Versioner
+	merger    *conflictMerger
+	conflicts *db.ConflictLog
+
+	localVersions *db.LocalVersions
 
 	warnedKqueue bool
 }
@@ -134,10 +139,16 @@ func newFolder(model *model, fset *db.FileSet, ignores *ignore.Matcher, cfg conf
 		watchMut:         sync.NewMutex(),
 
 		versioner: ver,
+		merger:    newConflictMerger(cfg, model.db),
+		conflicts: db.NewConflictLog(model.db, cfg.ID, maxConflictLogRecords),
+
+		localVersions: db.NewLocalVersions(model.db, cfg.ID, maxLocalVersionRecords),
 	}
 	f.pullPause = f.pullBasePause()
 	f.pullFailTimer = time.NewTimer(0)
 	<-f.pullFailTimer.C
+	f.scheduleTimer = time.NewTimer(0)
+	<-f.scheduleTimer.C
 
 	registerFolderMetrics(f.ID)
 
@@ -156,6 +167,7 @@ func (f *folder) Serve(ctx context.Context) error {
 	defer func() {
 		f.scanTimer.Stop()
 		f.versionCleanupTimer.Stop()
+		f.scheduleTimer.Stop()
 		f.setState(FolderIdle)
 	}()
 
@@ -192,6 +204,10 @@ func (f *folder) Serve(ctx context.Context) error {
 				f.pullPause *= 2
 			}
 
+		case <-f.scheduleTimer.C:
+			l.Debugln(f, "Pulling as a sync window started or ended")
+			_, err = f.pull()
+
 		case <-initialCompleted:
 			// Initial scan has completed, we should do a pull
 			initialCompleted = nil // never hit this case again
@@ -245,6 +261,12 @@ func (*folder) Override() {}
 
 func (*folder) Revert() {}
 
+func (*folder) ConfirmMassChange() {}
+
+func (*folder) Hydrate(string) error {
+	return errNotOnDemand
+}
+
 func (f *folder) DelayScan(next time.Duration) {
 	select {
 	case f.scanDelay <- next:
@@ -335,6 +357,10 @@ func (f *folder) getHealthErrorWithoutIgnores() error {
 
 	if minFree := f.model.cfg.Options().MinHomeDiskFree; minFree.Value > 0 {
 		dbPath := locations.Get(locations.Database)
+		if f.model.cfg.Options().DatabaseBackend == config.DatabaseBackendSQLite {
+			// The database is a file, the usage is that of its directory.
+			dbPath = filepath.Dir(locations.Get(locations.SQLiteDB))
+		}
 		if usage, err := fs.NewFilesystem(fs.FilesystemTypeBasic, dbPath).Usage("."); err == nil {
 			if err = config.CheckFreeSpace(minFree, usage); err != nil {
 				return fmt.Errorf("insufficient space on disk for database (%v): %w", dbPath, err)
@@ -385,6 +411,15 @@ func (f *folder) pull() (success bool, err error) {
 		return true, nil
 	}
 
+	// Outside of a sync window there's nothing to do until the next one
+	// starts, which is no failure.
+	now := time.Now()
+	f.resetScheduleTimer(now)
+	if !f.Schedule.AllowsPull(now) {
+		l.Debugln("Skipping pull of", f.Description(), "outside of scheduled sync window")
+		return true, nil
+	}
+
 	// Abort early (before acquiring a token) if there's a folder error
 	err = f.getHealthErrorWithoutIgnores()
 	if err != nil {
@@ -433,6 +468,31 @@ func (f *folder) pull() (success bool, err error) {
 	return false, err
 }
 
+// resetScheduleTimer sets the schedule timer to the next start or end of a
+// window in the pull-only schedule of the folder or of any device it's
+// shared with, so that what's held back gets pulled once allowed.
+func (f *folder) resetScheduleTimer(now time.Time) {
+	schedules := []config.Schedule{f.Schedule}
+	for _, device := range f.Devices {
+		if dcfg, ok := f.model.cfg.Device(device.DeviceID); ok {
+			schedules = append(schedules, dcfg.Schedule)
+		}
+	}
+	var next time.Time
+	for _, sched := range schedules {
+		if !sched.IsSet() || sched.Mode != config.ScheduleModePullOnly {
+			continue
+		}
+		if change := sched.NextChange(now); !change.IsZero() && (next.IsZero() || change.Before(next)) {
+			next = change
+		}
+	}
+	f.scheduleTimer.Stop()
+	if !next.IsZero() {
+		f.scheduleTimer.Reset(next.Sub(now))
+	}
+}
+
 func (f *folder) scanSubdirs(subDirs []string) error {
 	l.Debugf("%v scanning", f)
 
@@ -548,6 +608,8 @@ type scanBatch struct {
 	f           *folder
 	updateBatch *db.FileInfoBatch
 	toRemove    []string
+	// Set up on first use, for archiving local changes.
+	sources *blockSources
 }
 
 func (f *folder) newScanBatch() *scanBatch {
@@ -589,6 +651,15 @@ func (b *scanBatch) FlushIfFull() error {
 	return b.updateBatch.FlushIfFull()
 }
 
+// blockSources returns where to take blocks from to archive local changes,
+// the same for the whole scan.
+func (b *scanBatch) blockSources() *blockSources {
+	if b.sources == nil {
+		b.sources = b.f.newBlockSources()
+	}
+	return b.sources
+}
+
 // Update adds the fileinfo to the batch for updating, and does a few checks.
 // It returns false if the checks result in the file not going to be updated or removed.
 func (b *scanBatch) Update(fi protocol.FileInfo, snap *db.Snapshot) bool {
@@ -642,23 +713,26 @@ func (f *folder) scanSubdirsChangedAndNew(subDirs []string, batch *scanBatch) (i
 	defer scanCancel()
 
 	scanConfig := scanner.Config{
-		Folder:                f.ID,
-		Subs:                  subDirs,
-		Matcher:               f.ignores,
-		TempLifetime:          time.Duration(f.model.cfg.Options().KeepTemporariesH) * time.Hour,
-		CurrentFiler:          cFiler{snap},
-		Filesystem:            f.mtimefs,
-		IgnorePerms:           f.IgnorePerms,
-		AutoNormalize:         f.AutoNormalize,
-		Hashers:               f.model.numHashers(f.ID),
-		ShortID:               f.shortID,
-		ProgressTickIntervalS: f.ScanProgressIntervalS,
-		LocalFlags:            f.localFlags,
-		ModTimeWindow:         f.modTimeWindow,
-		EventLogger:           f.evLogger,
-		ScanOwnership:         f.SendOwnership || f.SyncOwnership,
-		ScanXattrs:            f.SendXattrs || f.SyncXattrs,
-		XattrFilter:           f.XattrFilter,
+		Folder:                 f.ID,
+		Subs:                   subDirs,
+		Matcher:                f.ignores,
+		TempLifetime:           time.Duration(f.model.cfg.Options().KeepTemporariesH) * time.Hour,
+		CurrentFiler:           cFiler{snap},
+		Filesystem:             f.mtimefs,
+		IgnorePerms:            f.IgnorePerms,
+		AutoNormalize:          f.AutoNormalize,
+		Hashers:                f.model.numHashers(f.ID),
+		ShortID:                f.shortID,
+		ProgressTickIntervalS:  f.ScanProgressIntervalS,
+		LocalFlags:             f.localFlags,
+		ModTimeWindow:          f.modTimeWindow,
+		EventLogger:            f.evLogger,
+		ScanOwnership:          f.SendOwnership || f.SyncOwnership,
+		ScanXattrs:             f.SendXattrs || f.SyncXattrs,
+		XattrFilter:            f.XattrFilter,
+		ContentDefinedChunking: f.model.contentDefinedChunking(f.FolderConfiguration),
+		HashAlgorithm:          f.model.blockHashAlgorithm(f.FolderConfiguration),
+		RehashIncompatible:     f.model.remoteLacksBlockFormat(f.FolderConfiguration),
 	}
 	var fchan chan scanner.ScanResult
 	if f.Type == config.FolderTypeReceiveEncrypted {
@@ -683,6 +757,12 @@ func (f *folder) scanSubdirsChangedAndNew(subDirs []string, batch *scanBatch) (i
 			return changes, err
 		}
 
+		if f.Versioning.VersionLocalChanges {
+			if cur, ok := snap.Get(protocol.LocalDeviceID, res.File.Name); ok && !cur.BlocksEqual(res.File) {
+				f.archiveLocalChange(cur, batch.blockSources())
+			}
+		}
+
 		if batch.Update(res.File, snap) {
 			changes++
 		}
@@ -770,7 +850,7 @@ func (f *folder) scanSubdirsDeletedAndIgnored(subDirs []string, batch *scanBatch
 				// Successfully scanned items are already un-ignored during
 				// the scan, so check whether it is deleted.
 				fallthrough
-			case !fi.IsIgnored() && !fi.IsDeleted() && !fi.IsUnsupported():
+			case !fi.IsIgnored() && !fi.IsDeleted() && !fi.IsUnsupported() && !fi.IsPlaceholder():
 				// The file is not ignored, deleted or unsupported. Lets check if
 				// it's still here. Simply stat:ing it won't do as there are
 				// tons of corner cases (e.g. parent dir->symlink, missing
@@ -792,6 +872,12 @@ func (f *folder) scanSubdirsDeletedAndIgnored(subDirs []string, batch *scanBatch
 					// sure the file gets in sync on the following pull.
 					nf.Version = protocol.Vector{}
 				}
+				if f.Versioning.VersionLocalChanges {
+					// fi is truncated, without the blocks.
+					if cur, ok := snap.Get(protocol.LocalDeviceID, fi.Name); ok {
+						f.archiveLocalChange(cur, batch.blockSources())
+					}
+				}
 				l.Debugln("marking file as deleted", nf)
 				if batch.Update(nf, snap) {
 					changes++
@@ -945,6 +1031,7 @@ func (f *folder) versionCleanupTimerFired() {
 	if err := f.versioner.Clean(f.ctx); err != nil {
 		l.Infoln("Failed to clean versions in %s: %v", f.Description(), err)
 	}
+	f.forgetRemovedLocalVersions()
 
 	f.versionCleanupTimer.Reset(f.versionCleanupInterval)
 }
@@ -1240,6 +1327,8 @@ func (f *folder) updateLocals(fs []protocol.FileInfo) {
 	}
 	f.forcedRescanPathsMut.Unlock()
 
+	f.rememberMergeAncestors(fs)
+
 	seq := f.fset.Sequence(protocol.LocalDeviceID)
 	f.evLogger.Log(events.LocalIndexUpdated, map[string]interface{}{
 		"folder":    f.ID,
@@ -0,0 +1,171 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"slices"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/ignore"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/semaphore"
+	"github.com/syncthing/syncthing/lib/sync"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+func init() {
+	folderFactories[config.FolderTypeOnDemand] = newOnDemandFolder
+}
+
+/*
+onDemandFolder is a folder that knows the full global state, but only pulls
+the files that have been explicitly requested. It works as follows:
+
+  - Directories, symlinks and deletions are handled as in a send-receive
+    folder, so the local directory structure mirrors the global one.
+
+  - Needed files that are not covered by a pinned path, and that we don't
+    already have locally, are recorded in the local index as placeholders
+    (FlagLocalPlaceholder). They have the global version, so they are not
+    needed anymore, but they are invalid and hence never offered to other
+    devices. As there is nothing on disk, the scanner ignores them instead of
+    detecting them as deleted.
+
+  - Hydrating a path pins it and resets the version of all placeholders
+    below it to the empty vector. That makes them needed again and they are
+    pulled through the regular job queue on the next pull. Files below a
+    pinned path, including ones that appear later, are kept in sync.
+
+  - Files that exist locally, including those changed locally, are synced as
+    in a send-receive folder.
+
+Pinned paths are persisted in the database.
+*/
+type onDemandFolder struct {
+	*sendReceiveFolder
+}
+
+func newOnDemandFolder(model *model, fset *db.FileSet, ignores *ignore.Matcher, cfg config.FolderConfiguration, ver versioner.Versioner, evLogger events.Logger, ioLimiter *semaphore.Semaphore) service {
+	sr := newSendReceiveFolder(model, fset, ignores, cfg, ver, evLogger, ioLimiter).(*sendReceiveFolder)
+	sr.pins = newPinnedPaths(db.NewFolderPinsNamespace(model.db, cfg.ID))
+	return &onDemandFolder{sr}
+}
+
+func (f *onDemandFolder) Hydrate(sub string) error {
+	sub = osutil.NativeFilename(filepath.Clean(sub))
+	if sub == "." || sub == string(fs.PathSeparator) {
+		sub = ""
+	}
+	if err := f.pins.add(sub); err != nil {
+		return err
+	}
+	return f.doInSync(func() error { return f.hydrate(sub) })
+}
+
+func (f *onDemandFolder) hydrate(sub string) error {
+	l.Infof("Hydrating %q in folder %v", sub, f.Description())
+
+	batch := db.NewFileInfoBatch(func(files []protocol.FileInfo) error {
+		f.updateLocalsFromScanning(files)
+		return nil
+	})
+	snap, err := f.dbSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+	snap.WithPrefixedHaveTruncated(protocol.LocalDeviceID, sub, func(fi protocol.FileInfo) bool {
+		if !fi.IsPlaceholder() {
+			return true
+		}
+		// The empty version is strictly older than the global one, so the
+		// file becomes needed, and it's not in conflict with anything.
+		fi.Version = protocol.Vector{}
+		batch.Append(fi)
+		_ = batch.FlushIfFull()
+		return true
+	})
+	if err := batch.Flush(); err != nil {
+		return err
+	}
+
+	// Files becoming needed in our own index doesn't trigger a pull by
+	// itself.
+	f.SchedulePull()
+
+	return nil
+}
+
+// keepPlaceholder returns true when the given needed file should be
+// recorded as a placeholder instead of being pulled.
+func (f *sendReceiveFolder) keepPlaceholder(file protocol.FileInfo, snap *db.Snapshot) bool {
+	if f.pins == nil || f.pins.covers(file.Name) {
+		return false
+	}
+	// Files we already have are kept up to date.
+	cur, ok := snap.Get(protocol.LocalDeviceID, file.Name)
+	return !ok || cur.IsDeleted() || cur.IsPlaceholder()
+}
+
+// pinnedPaths is the set of paths that should be present locally in an
+// on-demand folder. A path covers itself and everything below it, the empty
+// path covers the whole folder.
+type pinnedPaths struct {
+	kv    *db.NamespacedKV
+	paths []string
+	mut   sync.RWMutex
+}
+
+func newPinnedPaths(kv *db.NamespacedKV) *pinnedPaths {
+	p := &pinnedPaths{
+		kv:  kv,
+		mut: sync.NewRWMutex(),
+	}
+	if bs, ok, err := kv.Bytes(db.FolderPinsKey); err != nil {
+		l.Warnln("Failed to load pinned paths:", err)
+	} else if ok {
+		if err := json.Unmarshal(bs, &p.paths); err != nil {
+			l.Warnln("Failed to load pinned paths:", err)
+		}
+	}
+	return p
+}
+
+func (p *pinnedPaths) add(path string) error {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if slices.Contains(p.paths, path) {
+		return nil
+	}
+	paths := append(slices.Clone(p.paths), path)
+	bs, err := json.Marshal(paths)
+	if err != nil {
+		return err
+	}
+	if err := p.kv.PutBytes(db.FolderPinsKey, bs); err != nil {
+		return err
+	}
+	p.paths = paths
+	return nil
+}
+
+func (p *pinnedPaths) covers(name string) bool {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+	for _, path := range p.paths {
+		if path == "" || name == path || fs.IsParent(name, path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestOnDemandPlaceholdersAndHydrate(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	fcfg.Type = config.FolderTypeOnDemand
+	setFolder(t, w, fcfg)
+	m, fc := setupModelWithConnectionFromWrapper(t, w)
+	tfs := fcfg.Filesystem(nil)
+	defer cleanupModelAndRemoveDir(m, tfs.URI())
+
+	contents := []byte("on demand\n")
+	fc.addFile("dir", 0o755, protocol.FileInfoTypeDirectory, nil)
+	fc.addFile("dir/file", 0o644, protocol.FileInfoTypeFile, contents)
+	fc.addFile("other", 0o644, protocol.FileInfoTypeFile, contents)
+	fc.sendIndexUpdate()
+
+	// Everything is known, but nothing but the directory is pulled.
+
+	waitForLocal(t, m, fcfg.ID, "other", func(fi protocol.FileInfo) bool { return fi.IsPlaceholder() })
+	waitForLocal(t, m, fcfg.ID, "dir/file", func(fi protocol.FileInfo) bool { return fi.IsPlaceholder() })
+	if _, err := tfs.Lstat("dir"); err != nil {
+		t.Error("Directory should have been created:", err)
+	}
+	for _, name := range []string{"dir/file", "other"} {
+		if _, err := tfs.Lstat(name); !fs.IsNotExist(err) {
+			t.Errorf("%v should not exist, got %v", name, err)
+		}
+	}
+	if size := needSizeLocal(t, m, fcfg.ID); size.Files != 0 {
+		t.Error("Expected no needed files, got", size)
+	}
+
+	// Placeholders must not be detected as local deletions.
+
+	must(t, m.ScanFolder(fcfg.ID))
+	if fi, _, _ := m.CurrentFolderFile(fcfg.ID, "other"); !fi.IsPlaceholder() || fi.IsDeleted() {
+		t.Error("Expected placeholder to survive a scan, got", fi)
+	}
+
+	// Hydrating the directory pulls the file within, and nothing else.
+
+	must(t, m.Hydrate(fcfg.ID, "dir"))
+	waitForLocal(t, m, fcfg.ID, "dir/file", func(fi protocol.FileInfo) bool { return !fi.IsInvalid() })
+	if err := equalContents(tfs, "dir/file", contents); err != nil {
+		t.Error("File did not sync correctly:", err)
+	}
+	if fi, _, _ := m.CurrentFolderFile(fcfg.ID, "other"); !fi.IsPlaceholder() {
+		t.Error("Expected unrelated file to stay a placeholder, got", fi)
+	}
+
+	tree, err := m.GlobalDirectoryTree(fcfg.ID, "", -1, false)
+	must(t, err)
+	for _, entry := range tree {
+		switch entry.Name {
+		case "other":
+			if !entry.Placeholder {
+				t.Error("Expected other to be reported as placeholder")
+			}
+		case "dir":
+			if len(entry.Children) != 1 || entry.Children[0].Placeholder {
+				t.Error("Expected dir/file to be reported as present, got", entry.Children)
+			}
+		}
+	}
+}
+
+func TestOnDemandHydrateNotOnDemand(t *testing.T) {
+	m, _, fcfg, wCancel := setupModelWithConnection(t)
+	defer wCancel()
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	if err := m.Hydrate(fcfg.ID, ""); err != errNotOnDemand {
+		t.Error("Expected errNotOnDemand, got", err)
+	}
+}
+
+func TestPinnedPathsCovers(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	m := newModel(t, w, myID, nil)
+	defer cleanupModel(m)
+	kv := db.NewFolderPinsNamespace(m.db, fcfg.ID)
+
+	p := newPinnedPaths(kv)
+	if p.covers("a") {
+		t.Error("Empty set should not cover anything")
+	}
+
+	must(t, p.add(filepath.FromSlash("a/b")))
+	for name, exp := range map[string]bool{
+		"a":     false,
+		"a/b":   true,
+		"a/b/c": true,
+		"a/bc":  false,
+		"b":     false,
+	} {
+		if got := p.covers(filepath.FromSlash(name)); got != exp {
+			t.Errorf("covers(%q) = %v, expected %v", name, got, exp)
+		}
+	}
+
+	// Pins are persisted.
+	if !newPinnedPaths(kv).covers(filepath.FromSlash("a/b")) {
+		t.Error("Expected pin to be loaded from database")
+	}
+
+	must(t, p.add(""))
+	if !p.covers("b") {
+		t.Error("Expected the empty path to cover everything")
+	}
+}
+
+func waitForLocal(t *testing.T, m *testModel, folder, name string, cond func(protocol.FileInfo) bool) {
+	t.Helper()
+	timeout := time.After(10 * time.Second)
+	for {
+		if fi, ok, err := m.CurrentFolderFile(folder, name); err == nil && ok && cond(fi) {
+			return
+		}
+		select {
+		case <-timeout:
+			fi, ok, err := m.CurrentFolderFile(folder, name)
+			t.Fatalf("Timed out waiting for %v, got %v, %v, %v", name, fi, ok, err)
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
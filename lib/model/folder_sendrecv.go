@@ -9,7 +9,6 @@ package model
 import (
 	"bytes"
 	"context"
-	"crypto/sha256"
 	"errors"
 	"fmt"
 	"io"
@@ -61,6 +60,22 @@ type copyBlocksState struct {
 // Which filemode bits to preserve
 const retainBits = fs.ModeSetgid | fs.ModeSetuid | fs.ModeSticky
 
+const (
+	// Conflict copies are kept below this directory under the conflict
+	// directory policy, at the same path as the file.
+	conflictDirName = ".sync-conflicts"
+	// The number of resolved conflicts kept in the conflict log per folder.
+	maxConflictLogRecords = 1000
+)
+
+// How a conflict was resolved, as recorded in the conflict log.
+const (
+	conflictResolutionCopy      = "conflictCopy"
+	conflictResolutionVersioned = "versioned"
+	conflictResolutionRemoved   = "removed"
+	conflictResolutionMerged    = "merged"
+)
+
 var (
 	activity                  = newDeviceActivity()
 	errNoDevice               = errors.New("peers who had this file went away, or the file has changed while syncing. will retry later")
@@ -126,8 +141,14 @@ type sendReceiveFolder struct {
 	queue              *jobQueue
 	blockPullReorderer blockPullReorderer
 	writeLimiter       *semaphore.Semaphore
+	pins               *pinnedPaths // only set for on-demand folders
 
 	tempPullErrors map[string]string // pull errors that might be just transient
+	heldBack       bool              // items were left for a device's sync window
+
+	massChangeMut       sync.Mutex
+	massChangeDetected  bool // reported the current mass change already
+	massChangeConfirmed bool // pull despite a mass change until in sync
 }
 
 func newSendReceiveFolder(model *model, fset *db.FileSet, ignores *ignore.Matcher, cfg config.FolderConfiguration, ver versioner.Versioner, evLogger events.Logger, ioLimiter *semaphore.Semaphore) service {
@@ -136,6 +157,7 @@ func newSendReceiveFolder(model *model, fset *db.FileSet, ignores *ignore.Matche
 		queue:              newJobQueue(),
 		blockPullReorderer: newBlockPullReorderer(cfg.BlockPullOrder, model.id, cfg.DeviceIDs()),
 		writeLimiter:       semaphore.New(cfg.MaxConcurrentWrites),
+		massChangeMut:      sync.NewMutex(),
 	}
 	f.folder.puller = f
 
@@ -179,6 +201,10 @@ func (f *sendReceiveFolder) pull() (bool, error) {
 	f.pullErrors = nil
 	f.errorsMut.Unlock()
 
+	if err := f.checkMassChange(); err != nil {
+		return false, err
+	}
+
 	var err error
 	for tries := 0; tries < maxPullerIterations; tries++ {
 		select {
@@ -229,6 +255,9 @@ func (f *sendReceiveFolder) pull() (bool, error) {
 		})
 	}
 
+	if changed == 0 && !f.heldBack {
+		f.massChangeSynced()
+	}
 	return changed == 0, nil
 }
 
@@ -240,6 +269,7 @@ func (f *sendReceiveFolder) pullerIteration(scanChan chan<- string) (int, error)
 	f.errorsMut.Lock()
 	f.tempPullErrors = make(map[string]string)
 	f.errorsMut.Unlock()
+	f.heldBack = false
 
 	snap, err := f.dbSnapshot()
 	if err != nil {
@@ -384,6 +414,11 @@ func (f *sendReceiveFolder) processNeeded(snap *db.Snapshot, dbUpdateChan chan<-
 				}
 			}
 
+		case file.Type == protocol.FileInfoTypeFile && f.keepPlaceholder(file, snap):
+			file.SetPlaceholder()
+			l.Debugln(f, "Handling placeholder file", file)
+			dbUpdateChan <- dbUpdateJob{file, dbUpdateInvalidate}
+
 		case file.Type == protocol.FileInfoTypeFile:
 			curFile, hasCurFile := snap.Get(protocol.LocalDeviceID, file.Name)
 			if hasCurFile && file.BlocksEqual(curFile) {
@@ -508,6 +543,15 @@ nextFile:
 			f.handleFile(fi, snap, copyChan)
 			continue
 		}
+		if f.model.fileHeldBackBySchedule(f.FolderConfiguration, snap, fi) {
+			// Not a failure, it's pulled when the sync window of a device
+			// that has it starts.
+			l.Debugln(f, "not pulling", fileName, "outside of the sync windows of the devices that have it")
+			changed--
+			f.heldBack = true
+			f.queue.Done(fileName)
+			continue
+		}
 		f.newPullError(fileName, errNotAvailable)
 		f.queue.Done(fileName)
 	}
@@ -604,7 +648,7 @@ func (f *sendReceiveFolder) handleDir(file protocol.FileInfo, snap *db.Snapshot,
 			// Symlinks aren't checked for conflicts.
 
 			err = f.inWritableDir(func(name string) error {
-				return f.moveForConflict(name, file.ModifiedBy.String(), scanChan)
+				return f.moveForConflict(name, name, file.ModifiedBy, curFile.ModifiedBy, scanChan)
 			}, curFile.Name)
 		} else {
 			err = f.deleteItemOnDisk(curFile, snap, scanChan)
@@ -794,7 +838,7 @@ func (f *sendReceiveFolder) handleSymlinkCheckExisting(file protocol.FileInfo, s
 		// Directories and symlinks aren't checked for conflicts.
 
 		return f.inWritableDir(func(name string) error {
-			return f.moveForConflict(name, file.ModifiedBy.String(), scanChan)
+			return f.moveForConflict(name, name, file.ModifiedBy, curFile.ModifiedBy, scanChan)
 		}, curFile.Name)
 	} else {
 		return f.deleteItemOnDisk(curFile, snap, scanChan)
@@ -902,7 +946,7 @@ func (f *sendReceiveFolder) deleteFileWithCurrent(file, cur protocol.FileInfo, h
 	}
 
 	if f.versioner != nil && !cur.IsSymlink() {
-		err = f.inWritableDir(f.versioner.Archive, file.Name)
+		err = f.inWritableDir(f.archiveFunc(cur), file.Name)
 	} else {
 		err = f.inWritableDir(f.mtimefs.Remove, file.Name)
 	}
@@ -1021,7 +1065,7 @@ func (f *sendReceiveFolder) renameFile(cur, source, target protocol.FileInfo, sn
 		if err == nil {
 			err = osutil.Copy(f.CopyRangeMethod.ToFS(), f.mtimefs, f.mtimefs, source.Name, tempName)
 			if err == nil {
-				err = f.inWritableDir(f.versioner.Archive, source.Name)
+				err = f.inWritableDir(f.archiveFunc(cur), source.Name)
 			}
 		}
 	} else {
@@ -1133,15 +1177,24 @@ func (f *sendReceiveFolder) handleFile(file protocol.FileInfo, snap *db.Snapshot
 	copyChan <- cs
 }
 
+// hashTempFile hashes the temporary file the same way as the file it
+// becomes.
+func (f *sendReceiveFolder) hashTempFile(file protocol.FileInfo, tempName string) ([]protocol.BlockInfo, error) {
+	if file.HasVariableBlocks() {
+		return scanner.HashFileCDC(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, file.HashAlgorithm)
+	}
+	return scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false, file.HashAlgorithm)
+}
+
 func (f *sendReceiveFolder) reuseBlocks(blocks []protocol.BlockInfo, reused []int, file protocol.FileInfo, tempName string) ([]protocol.BlockInfo, []int) {
 	// Check for an old temporary file which might have some blocks we could
 	// reuse.
-	tempBlocks, err := scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false)
+	tempBlocks, err := f.hashTempFile(file, tempName)
 	if err != nil {
 		var caseErr *fs.ErrCaseConflict
 		if errors.As(err, &caseErr) {
 			if rerr := f.mtimefs.Rename(caseErr.Real, tempName); rerr == nil {
-				tempBlocks, err = scanner.HashFile(f.ctx, f.ID, f.mtimefs, tempName, file.BlockSize(), nil, false)
+				tempBlocks, err = f.hashTempFile(file, tempName)
 			}
 		}
 	}
@@ -1339,7 +1392,7 @@ func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan ch
 			var found bool
 			if f.Type != config.FolderTypeReceiveEncrypted {
 				found, err = weakHashFinder.Iterate(block.WeakHash, buf, func(offset int64) bool {
-					if f.verifyBuffer(buf, block) != nil {
+					if f.verifyBuffer(buf, block, state.file.HashAlgorithm) != nil {
 						return true
 					}
 
@@ -1361,7 +1414,7 @@ func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan ch
 			}
 
 			if !found {
-				found = f.model.finder.Iterate(folders, block.Hash, func(folder, path string, index int32) bool {
+				found = f.model.finder.Iterate(folders, state.file.HashAlgorithm, block.Hash, func(folder, path string, _ int32, srcOffset int64) bool {
 					ffs := folderFilesystems[folder]
 					fd, err := ffs.Open(path)
 					if err != nil {
@@ -1369,17 +1422,16 @@ func (f *sendReceiveFolder) copierRoutine(in <-chan copyBlocksState, pullChan ch
 					}
 					defer fd.Close()
 
-					srcOffset := int64(state.file.BlockSize()) * int64(index)
 					_, err = fd.ReadAt(buf, srcOffset)
 					if err != nil {
 						return false
 					}
 
-					// Hash is not SHA256 as it's an encrypted hash token. In that
+					// Hash is not a real hash as it's an encrypted token. In that
 					// case we can't verify the block integrity so we'll take it on
 					// trust. (The other side can and will verify.)
 					if f.Type != config.FolderTypeReceiveEncrypted {
-						if err := f.verifyBuffer(buf, block); err != nil {
+						if err := f.verifyBuffer(buf, block, state.file.HashAlgorithm); err != nil {
 							l.Debugln("Finder failed to verify buffer", err)
 							return false
 						}
@@ -1473,13 +1525,13 @@ func (f *sendReceiveFolder) initWeakHashFinder(state copyBlocksState) (*weakhash
 	return weakHashFinder, file
 }
 
-func (*sendReceiveFolder) verifyBuffer(buf []byte, block protocol.BlockInfo) error {
+func (*sendReceiveFolder) verifyBuffer(buf []byte, block protocol.BlockInfo, algo protocol.HashAlgorithm) error {
 	if len(buf) != int(block.Size) {
 		return fmt.Errorf("length mismatch %d != %d", len(buf), block.Size)
 	}
 
-	hash := sha256.Sum256(buf)
-	if !bytes.Equal(hash[:], block.Hash) {
+	hash := scanner.HashBlock(algo, buf)
+	if !bytes.Equal(hash, block.Hash) {
 		return fmt.Errorf("hash mismatch %x != %x", hash, block.Hash)
 	}
 
@@ -1573,8 +1625,8 @@ loop:
 		// leastBusy can select another device when someone else asks.
 		activity.using(selected)
 		var buf []byte
-		blockNo := int(state.block.Offset / int64(state.file.BlockSize()))
-		buf, lastError = f.model.RequestGlobal(f.ctx, selected.ID, f.folderID, state.file.Name, blockNo, state.block.Offset, int(state.block.Size), state.block.Hash, state.block.WeakHash, selected.FromTemporary)
+		blockNo := state.file.BlockIndex(state.block.Offset)
+		buf, lastError = f.model.RequestGlobal(f.ctx, selected.ID, f.folderID, state.file.Name, blockNo, state.block.Offset, int(state.block.Size), state.block.Hash, state.block.WeakHash, state.file.HashAlgorithm, selected.FromTemporary)
 		activity.done(selected)
 		if lastError != nil {
 			l.Debugln("request:", f.folderID, state.file.Name, state.block.Offset, state.block.Size, selected.ID.Short(), "returned error:", lastError)
@@ -1588,7 +1640,7 @@ loop:
 		// integrity so we'll take it on trust. (The other side can and
 		// will verify.)
 		if f.Type != config.FolderTypeReceiveEncrypted {
-			lastError = f.verifyBuffer(buf, state.block)
+			lastError = f.verifyBuffer(buf, state.block, state.file.HashAlgorithm)
 		}
 		if lastError != nil {
 			l.Debugln("request:", f.folderID, state.file.Name, state.block.Offset, state.block.Size, "hash mismatch")
@@ -1620,6 +1672,7 @@ func (f *sendReceiveFolder) performFinish(file, curFile protocol.FileInfo, hasCu
 		return fmt.Errorf("setting metadata: %w", err)
 	}
 
+	merged := false
 	if stat, err := f.mtimefs.Lstat(file.Name); err == nil {
 		// There is an old file or directory already in place. We need to
 		// handle that.
@@ -1628,25 +1681,74 @@ func (f *sendReceiveFolder) performFinish(file, curFile protocol.FileInfo, hasCu
 			return fmt.Errorf("checking existing file: %w", err)
 		}
 
+		keepExisting := false
 		if !curFile.IsDirectory() && !curFile.IsSymlink() && f.inConflict(curFile.Version, file.Version) {
 			// The new file has been changed in conflict with the existing one. We
 			// should file it away as a conflict instead of just removing or
-			// archiving.
+			// archiving, unless we can merge the changes.
 			// Directories and symlinks aren't checked for conflicts.
 
-			err = f.inWritableDir(func(name string) error {
-				return f.moveForConflict(name, file.ModifiedBy.String(), scanChan)
-			}, curFile.Name)
+			switch {
+			case f.merger.matches(file.Name) && f.mergeConflict(file, curFile, tempName):
+				// The temporary file now has both sides' changes, so the
+				// existing file is replaced like for any other update.
+				merged = true
+				f.recordConflict(file.Name, conflictResolutionMerged, file.ModifiedBy, curFile.ModifiedBy, "")
+				err = f.deleteItemOnDisk(curFile, snap, scanChan)
+			case f.existingWinsConflict(curFile, file):
+				// The conflict policy prefers the existing file, so it's
+				// the new one that is filed away, with the mtime it has on
+				// the other device.
+				keepExisting = true
+				f.mtimefs.Chtimes(tempName, file.ModTime(), file.ModTime()) // never fails
+				err = f.moveForConflict(tempName, file.Name, curFile.ModifiedBy, file.ModifiedBy, scanChan)
+			default:
+				err = f.inWritableDir(func(name string) error {
+					return f.moveForConflict(name, name, file.ModifiedBy, curFile.ModifiedBy, scanChan)
+				}, curFile.Name)
+			}
 		} else {
 			err = f.deleteItemOnDisk(curFile, snap, scanChan)
 		}
 		if err != nil {
 			return fmt.Errorf("moving for conflict: %w", err)
 		}
+
+		if keepExisting {
+			// Record the existing file as a change made here that
+			// supersedes the new one, so that it wins on the other devices
+			// as well.
+			curFile.Version = curFile.Version.Merge(file.Version).Update(f.shortID)
+			curFile.ModifiedBy = f.shortID
+			dbUpdateChan <- dbUpdateJob{curFile, dbUpdateHandleFile}
+			return nil
+		}
 	} else if !fs.IsNotExist(err) {
 		return fmt.Errorf("checking existing file: %w", err)
 	}
 
+	if merged {
+		// The merged file is based on both versions but is neither of
+		// them, so it's recorded as a change made here with its own
+		// contents.
+		blocks, err := f.hashTempFile(file, tempName)
+		if err != nil {
+			return fmt.Errorf("hashing merged file: %w", err)
+		}
+		var size int64
+		for _, b := range blocks {
+			size += int64(b.Size)
+		}
+		now := time.Now()
+		file.Blocks = blocks
+		file.BlocksHash = protocol.BlocksHash(blocks)
+		file.Size = size
+		file.ModifiedS = now.Unix()
+		file.ModifiedNs = int32(now.Nanosecond())
+		file.ModifiedBy = f.shortID
+		file.Version = file.Version.Merge(curFile.Version).Update(f.shortID)
+	}
+
 	// Replace the original content with the new one. If it didn't work,
 	// leave the temp file in place for reuse.
 	if err := osutil.RenameOrCopy(f.CopyRangeMethod.ToFS(), f.mtimefs, f.mtimefs, tempName, file.Name); err != nil {
@@ -1838,25 +1940,67 @@ func (f *sendReceiveFolder) inConflict(current, replacement protocol.Vector) boo
 	return false
 }
 
-func (f *sendReceiveFolder) moveForConflict(name, lastModBy string, scanChan chan<- string) error {
+// existingWinsConflict returns whether the conflict policy prefers the
+// existing file cur over the conflicting new version file. Otherwise the
+// new version wins, as it does under the default policy.
+func (f *sendReceiveFolder) existingWinsConflict(cur, file protocol.FileInfo) bool {
+	switch f.ConflictPolicy {
+	case config.ConflictPolicyPreferDevice:
+		preferred := f.ConflictPreferDevice.Short()
+		return cur.ModifiedBy == preferred && file.ModifiedBy != preferred
+	case config.ConflictPolicyLarger:
+		return cur.Size > file.Size
+	default:
+		return false
+	}
+}
+
+// moveForConflict files away the item at src, which is the version of name
+// last modified by loser, as it lost a conflict against the change by
+// winner. Depending on the conflict policy it's kept as a conflict copy
+// next to the file or in the conflicts directory, or archived by the
+// versioner. The resolution is recorded in the conflict log.
+func (f *sendReceiveFolder) moveForConflict(src, name string, winner, loser protocol.ShortID, scanChan chan<- string) error {
 	if isConflict(name) {
 		l.Infoln("Conflict for", name, "which is already a conflict copy; not copying again.")
-		if err := f.mtimefs.Remove(name); err != nil && !fs.IsNotExist(err) {
+		if err := f.mtimefs.Remove(src); err != nil && !fs.IsNotExist(err) {
+			return fmt.Errorf("%s: %w", contextRemovingOldItem, err)
+		}
+		f.recordConflict(name, conflictResolutionRemoved, winner, loser, "")
+		return nil
+	}
+
+	if f.ConflictPolicy == config.ConflictPolicyVersioner && f.versioner != nil && src == name {
+		if err := f.versioner.Archive(src); err != nil {
 			return fmt.Errorf("%s: %w", contextRemovingOldItem, err)
 		}
+		f.recordConflict(name, conflictResolutionVersioned, winner, loser, "")
 		return nil
 	}
 
 	if f.MaxConflicts == 0 {
-		if err := f.mtimefs.Remove(name); err != nil && !fs.IsNotExist(err) {
+		if err := f.mtimefs.Remove(src); err != nil && !fs.IsNotExist(err) {
 			return fmt.Errorf("%s: %w", contextRemovingOldItem, err)
 		}
+		f.recordConflict(name, conflictResolutionRemoved, winner, loser, "")
 		return nil
 	}
 
 	metricFolderConflictsTotal.WithLabelValues(f.ID).Inc()
-	newName := conflictName(name, lastModBy)
-	err := f.mtimefs.Rename(name, newName)
+	conflictPath := name
+	if f.ConflictPolicy == config.ConflictPolicyConflictDirectory {
+		conflictPath = filepath.Join(conflictDirName, name)
+		// The conflicts directory gets the same permissions as the folder.
+		mode := fs.FileMode(0o755)
+		if info, err := f.mtimefs.Lstat("."); err == nil {
+			mode = info.Mode() & 0o777
+		}
+		if err := f.mtimefs.MkdirAll(filepath.Dir(conflictPath), mode); err != nil {
+			return err
+		}
+	}
+	newName := conflictName(conflictPath, winner.String())
+	err := f.mtimefs.Rename(src, newName)
 	if fs.IsNotExist(err) {
 		// We were supposed to move a file away but it does not exist. Either
 		// the user has already moved it away, or the conflict was between a
@@ -1865,7 +2009,7 @@ func (f *sendReceiveFolder) moveForConflict(name, lastModBy string, scanChan cha
 		err = nil
 	}
 	if f.MaxConflicts > -1 {
-		matches := existingConflicts(name, f.mtimefs)
+		matches := existingConflicts(conflictPath, f.mtimefs)
 		if len(matches) > f.MaxConflicts {
 			sort.Sort(sort.Reverse(sort.StringSlice(matches)))
 			for _, match := range matches[f.MaxConflicts:] {
@@ -1876,11 +2020,29 @@ func (f *sendReceiveFolder) moveForConflict(name, lastModBy string, scanChan cha
 		}
 	}
 	if err == nil {
+		f.recordConflict(name, conflictResolutionCopy, winner, loser, newName)
 		scanChan <- newName
 	}
 	return err
 }
 
+// recordConflict adds the resolution of a conflict on name to the conflict
+// log.
+func (f *sendReceiveFolder) recordConflict(name, resolution string, winner, loser protocol.ShortID, conflictCopy string) {
+	err := f.conflicts.Add(db.ConflictRecord{
+		Time:         time.Now(),
+		Name:         name,
+		Policy:       f.ConflictPolicy.String(),
+		Resolution:   resolution,
+		Winner:       winner.String(),
+		Loser:        loser.String(),
+		ConflictCopy: conflictCopy,
+	})
+	if err != nil {
+		l.Debugf("%v recording conflict on %v: %v", f, name, err)
+	}
+}
+
 func (f *sendReceiveFolder) newPullError(path string, err error) {
 	if errors.Is(err, f.ctx.Err()) {
 		// Error because the folder stopped - no point logging/tracking
@@ -1926,12 +2088,24 @@ func (f *sendReceiveFolder) deleteItemOnDisk(item protocol.FileInfo, snap *db.Sn
 		// an error.
 		// Symlinks aren't archived.
 
-		return f.inWritableDir(f.versioner.Archive, item.Name)
+		return f.inWritableDir(f.archiveFunc(item), item.Name)
 	}
 
 	return f.inWritableDir(f.mtimefs.Remove, item.Name)
 }
 
+// archiveFunc returns a function archiving the file on disk, which is as
+// described by the given local file info, with the versioner.
+func (f *sendReceiveFolder) archiveFunc(file protocol.FileInfo) func(string) error {
+	fa, ok := f.versioner.(versioner.FileArchiver)
+	if !ok {
+		return f.versioner.Archive
+	}
+	return func(string) error {
+		return fa.ArchiveFile(file)
+	}
+}
+
 // deleteDirOnDisk attempts to delete a directory. It checks for files/dirs inside
 // the directory and removes them if possible or returns an error if it fails
 func (f *sendReceiveFolder) deleteDirOnDisk(dir string, snap *db.Snapshot, scanChan chan<- string) error {
@@ -28,6 +28,7 @@ import (
 	"github.com/syncthing/syncthing/lib/rand"
 	"github.com/syncthing/syncthing/lib/scanner"
 	"github.com/syncthing/syncthing/lib/sync"
+	"github.com/syncthing/syncthing/lib/versioner"
 )
 
 var blocks = []protocol.BlockInfo{
@@ -300,7 +301,7 @@ func TestCopierFinder(t *testing.T) {
 	}
 
 	// Verify that the fetched blocks have actually been written to the temp file
-	blks, err := scanner.HashFile(context.TODO(), f.ID, f.Filesystem(nil), tempFile, protocol.MinBlockSize, nil, false)
+	blks, err := scanner.HashFile(context.TODO(), f.ID, f.Filesystem(nil), tempFile, protocol.MinBlockSize, nil, false, protocol.HashAlgorithmSHA256)
 	if err != nil {
 		t.Log(err)
 	}
@@ -344,7 +345,7 @@ func TestWeakHash(t *testing.T) {
 	// File 1: abcdefgh
 	// File 2: xyabcdef
 	f.Seek(0, io.SeekStart)
-	existing, err := scanner.Blocks(context.TODO(), f, protocol.MinBlockSize, size, nil, true)
+	existing, err := scanner.Blocks(context.TODO(), f, protocol.MinBlockSize, size, nil, true, protocol.HashAlgorithmSHA256)
 	if err != nil {
 		t.Error(err)
 	}
@@ -353,7 +354,7 @@ func TestWeakHash(t *testing.T) {
 	remainder := io.LimitReader(f, size-shift)
 	prefix := io.LimitReader(rand.Reader, shift)
 	nf := io.MultiReader(prefix, remainder)
-	desired, err := scanner.Blocks(context.TODO(), nf, protocol.MinBlockSize, size, nil, true)
+	desired, err := scanner.Blocks(context.TODO(), nf, protocol.MinBlockSize, size, nil, true, protocol.HashAlgorithmSHA256)
 	if err != nil {
 		t.Error(err)
 	}
@@ -436,7 +437,7 @@ func TestWeakHash(t *testing.T) {
 
 // Test that updating a file removes its old blocks from the blockmap
 func TestCopierCleanup(t *testing.T) {
-	iterFn := func(folder, file string, index int32) bool {
+	iterFn := func(folder, file string, index int32, offset int64) bool {
 		return true
 	}
 
@@ -451,11 +452,11 @@ func TestCopierCleanup(t *testing.T) {
 	// Update index (removing old blocks)
 	f.updateLocalsFromScanning([]protocol.FileInfo{file})
 
-	if m.finder.Iterate(folders, blocks[0].Hash, iterFn) {
+	if m.finder.Iterate(folders, protocol.HashAlgorithmSHA256, blocks[0].Hash, iterFn) {
 		t.Error("Unexpected block found")
 	}
 
-	if !m.finder.Iterate(folders, blocks[1].Hash, iterFn) {
+	if !m.finder.Iterate(folders, protocol.HashAlgorithmSHA256, blocks[1].Hash, iterFn) {
 		t.Error("Expected block not found")
 	}
 
@@ -464,11 +465,11 @@ func TestCopierCleanup(t *testing.T) {
 	// Update index (removing old blocks)
 	f.updateLocalsFromScanning([]protocol.FileInfo{file})
 
-	if !m.finder.Iterate(folders, blocks[0].Hash, iterFn) {
+	if !m.finder.Iterate(folders, protocol.HashAlgorithmSHA256, blocks[0].Hash, iterFn) {
 		t.Error("Unexpected block found")
 	}
 
-	if m.finder.Iterate(folders, blocks[1].Hash, iterFn) {
+	if m.finder.Iterate(folders, protocol.HashAlgorithmSHA256, blocks[1].Hash, iterFn) {
 		t.Error("Expected block not found")
 	}
 }
@@ -709,8 +710,8 @@ func TestIssue3164(t *testing.T) {
 
 func TestDiff(t *testing.T) {
 	for i, test := range diffTestData {
-		a, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.a), test.s, -1, nil, false)
-		b, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.b), test.s, -1, nil, false)
+		a, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.a), test.s, -1, nil, false, protocol.HashAlgorithmSHA256)
+		b, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.b), test.s, -1, nil, false, protocol.HashAlgorithmSHA256)
 		_, d := blockDiff(a, b)
 		if len(d) != len(test.d) {
 			t.Fatalf("Incorrect length for diff %d; %d != %d", i, len(d), len(test.d))
@@ -730,8 +731,8 @@ func TestDiff(t *testing.T) {
 func BenchmarkDiff(b *testing.B) {
 	testCases := make([]struct{ a, b []protocol.BlockInfo }, 0, len(diffTestData))
 	for _, test := range diffTestData {
-		a, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.a), test.s, -1, nil, false)
-		b, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.b), test.s, -1, nil, false)
+		a, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.a), test.s, -1, nil, false, protocol.HashAlgorithmSHA256)
+		b, _ := scanner.Blocks(context.TODO(), bytes.NewBufferString(test.b), test.s, -1, nil, false, protocol.HashAlgorithmSHA256)
 		testCases = append(testCases, struct{ a, b []protocol.BlockInfo }{a, b})
 	}
 	b.ReportAllocs()
@@ -1399,3 +1400,359 @@ func startCopier(f *sendReceiveFolder, pullChan chan<- pullBlockState, finisherC
 	}()
 	return copyChan, wg
 }
+
+// setupSRConflict creates a local file with the contents ours and a
+// temporary file with the contents theirs for a conflicting remote version
+// of it, as if just pulled.
+func setupSRConflict(t *testing.T, f *sendReceiveFolder, name, ours, theirs string) (protocol.FileInfo, protocol.FileInfo, string) {
+	t.Helper()
+	ffs := f.Filesystem(nil)
+	writeFile(t, ffs, name, []byte(ours))
+	must(t, f.scanSubdirs(nil))
+	snap := fsetSnapshot(t, f.fset)
+	cur, _ := snap.Get(protocol.LocalDeviceID, name)
+	snap.Release()
+
+	remote := cur
+	remote.Version = protocol.Vector{}.Update(device1.Short())
+	remote.ModifiedBy = device1.Short()
+	remote.Size = int64(len(theirs))
+	if !f.inConflict(cur.Version, remote.Version) {
+		t.Fatal("expected versions to conflict")
+	}
+	temp := fs.TempName(name)
+	writeFile(t, ffs, temp, []byte(theirs))
+	return cur, remote, temp
+}
+
+func TestSRConflictPolicyLarger(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictPolicy = config.ConflictPolicyLarger
+
+	name := "file"
+	cur, remote, temp := setupSRConflict(t, f, name, "the larger local file", "remote")
+	remote.ModifiedS = 1600000000
+	remote.ModifiedNs = 0
+
+	snap := fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 1)
+	curCounter := cur.Version.Counter(f.shortID)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	// The local file is kept and recorded as superseding the remote one,
+	// while the remote one became the conflict copy.
+	if data := readMergedFile(t, ffs, name); data != "the larger local file" {
+		t.Errorf("unexpected contents %q", data)
+	}
+	confls := existingConflicts(name, ffs)
+	if len(confls) != 1 {
+		t.Fatal("expected one conflict copy, got", confls)
+	}
+	if data := readMergedFile(t, ffs, confls[0]); data != "remote" {
+		t.Errorf("unexpected conflict copy contents %q", data)
+	}
+	if info, err := ffs.Lstat(confls[0]); err != nil || !info.ModTime().Equal(remote.ModTime()) {
+		t.Errorf("expected conflict copy with the remote mtime %v, got %v", remote.ModTime(), info)
+	}
+	if _, err := ffs.Lstat(temp); !fs.IsNotExist(err) {
+		t.Error("expected temp file to be gone, got", err)
+	}
+	job := <-dbUpdateChan
+	if !job.file.Version.GreaterEqual(remote.Version) || job.file.Size != cur.Size {
+		t.Errorf("expected local file with version including %v, got %v", remote.Version, job.file)
+	}
+	if job.file.Version.Counter(f.shortID) <= curCounter || job.file.ModifiedBy != f.shortID {
+		t.Errorf("kept file %v not recorded as changed here", job.file)
+	}
+
+	recs, err := f.conflicts.Records()
+	must(t, err)
+	if len(recs) != 1 {
+		t.Fatal("expected one conflict log record, got", recs)
+	}
+	if rec := recs[0]; rec.Name != name || rec.Policy != "larger" || rec.Resolution != conflictResolutionCopy ||
+		rec.Winner != cur.ModifiedBy.String() || rec.Loser != remote.ModifiedBy.String() || rec.ConflictCopy != confls[0] {
+		t.Errorf("unexpected conflict log record %+v", rec)
+	}
+}
+
+func TestSRConflictPolicyPreferDevice(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictPolicy = config.ConflictPolicyPreferDevice
+	f.ConflictPreferDevice = device1
+
+	// The preferred device made the remote change, so it wins as it would
+	// anyway.
+	name := "file"
+	cur, remote, temp := setupSRConflict(t, f, name, "local", "remote")
+
+	snap := fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 10)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if data := readMergedFile(t, ffs, name); data != "remote" {
+		t.Errorf("unexpected contents %q", data)
+	}
+	if job := <-dbUpdateChan; !job.file.Version.Equal(remote.Version) {
+		t.Errorf("expected remote version %v, got %v", remote.Version, job.file.Version)
+	}
+
+	// With ourselves preferred, the local change wins.
+	f.ConflictPreferDevice = myID
+	name = "other"
+	cur, remote, temp = setupSRConflict(t, f, name, "local", "remote")
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if data := readMergedFile(t, ffs, name); data != "local" {
+		t.Errorf("unexpected contents %q", data)
+	}
+	if job := <-dbUpdateChan; !job.file.Version.GreaterEqual(remote.Version) || !job.file.Version.GreaterEqual(cur.Version) {
+		t.Errorf("expected version including %v and %v, got %v", cur.Version, remote.Version, job.file.Version)
+	}
+}
+
+func TestSRConflictPolicyDirectory(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictPolicy = config.ConflictPolicyConflictDirectory
+
+	name := filepath.Join("dir", "file")
+	must(t, ffs.MkdirAll("dir", 0o755))
+	must(t, ffs.Chmod(".", 0o750))
+	cur, remote, temp := setupSRConflict(t, f, name, "local", "remote")
+
+	snap := fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 1)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if confls := existingConflicts(name, ffs); len(confls) != 0 {
+		t.Error("expected no conflict copies next to the file, got", confls)
+	}
+	confls := existingConflicts(filepath.Join(conflictDirName, name), ffs)
+	if len(confls) != 1 {
+		t.Fatal("expected one conflict copy in the conflicts directory, got", confls)
+	}
+	if data := readMergedFile(t, ffs, confls[0]); data != "local" {
+		t.Errorf("unexpected conflict copy contents %q", data)
+	}
+	if !build.IsWindows {
+		for _, dir := range []string{conflictDirName, filepath.Join(conflictDirName, "dir")} {
+			if info, err := ffs.Lstat(dir); err != nil || info.Mode()&0o777 != 0o750 {
+				t.Errorf("expected %v with the folder's permissions, got %v", dir, info)
+			}
+		}
+	}
+	if scan := <-scanChan; scan != confls[0] {
+		t.Errorf("expected request to scan %v, got %v", confls[0], scan)
+	}
+}
+
+func TestSRConflictPolicyVersioner(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictPolicy = config.ConflictPolicyVersioner
+	ver := &archivingVersioner{fs: ffs}
+	f.versioner = ver
+
+	name := "file"
+	cur, remote, temp := setupSRConflict(t, f, name, "local", "remote")
+
+	snap := fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 1)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if confls := existingConflicts(name, ffs); len(confls) != 0 {
+		t.Error("expected no conflict copies, got", confls)
+	}
+	if len(ver.archived) != 1 || ver.archived[0] != "local" {
+		t.Error("expected the local file to be versioned, got", ver.archived)
+	}
+	recs, err := f.conflicts.Records()
+	must(t, err)
+	if len(recs) != 1 || recs[0].Resolution != conflictResolutionVersioned {
+		t.Error("unexpected conflict log records", recs)
+	}
+}
+
+func TestScanVersionsLocalChanges(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.Versioning.VersionLocalChanges = true
+	ver := &archivingVersioner{fs: ffs}
+	f.versioner = ver
+
+	writeFile(t, ffs, "a", []byte("contents one"))
+	writeFile(t, ffs, "b", []byte("contents one"))
+	must(t, f.scanSubdirs(nil))
+	if len(ver.archived) != 0 {
+		t.Fatal("expected nothing versioned for new files, got", ver.archived)
+	}
+
+	// The earlier contents of a are gone from it, but still in b.
+	writeFile(t, ffs, "a", []byte("contents two, longer"))
+	must(t, f.scanSubdirs(nil))
+	if len(ver.archived) != 1 || ver.archived[0] != "contents one" {
+		t.Fatal("expected the earlier contents of a to be versioned, got", ver.archived)
+	}
+
+	// The contents of b aren't anywhere anymore.
+	must(t, ffs.Remove("b"))
+	must(t, f.scanSubdirs(nil))
+	if len(ver.archived) != 1 {
+		t.Fatal("expected nothing versioned for lost contents, got", ver.archived)
+	}
+
+	// Pulled versions are remote, the one above local.
+	must(t, ffs.Rename("a", "c"))
+	must(t, ver.Archive("c"))
+	versions, err := ver.GetVersions()
+	must(t, err)
+	must(t, setVersionOrigins(f.localVersions, versions))
+	if len(versions["a"]) != 1 || versions["a"][0].Origin != versioner.OriginLocal {
+		t.Errorf("expected a local version of a, got %v", versions["a"])
+	}
+	if len(versions["c"]) != 1 || versions["c"][0].Origin != versioner.OriginRemote {
+		t.Errorf("expected a remote version of c, got %v", versions["c"])
+	}
+}
+
+func TestPullMassChange(t *testing.T) {
+	_, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.MassChangeMaxFiles = 2
+
+	names := []string{"a", "b", "c"}
+	for _, name := range names {
+		writeFile(t, ffs, name, []byte(name))
+	}
+	must(t, f.scanSubdirs(nil))
+
+	// The remote deletes all three files.
+	var deleted []protocol.FileInfo
+	snap := fsetSnapshot(t, f.fset)
+	snap.WithHave(protocol.LocalDeviceID, func(fi protocol.FileInfo) bool {
+		fi.SetDeleted(device1.Short())
+		deleted = append(deleted, fi)
+		return true
+	})
+	snap.Release()
+	f.fset.Update(device1, deleted)
+
+	if _, err := f.pull(); !errors.Is(err, errMassChange) {
+		t.Fatalf("expected mass change error, got %v", err)
+	}
+	if errs := f.Errors(); len(errs) != 1 {
+		t.Errorf("expected a folder error, got %v", errs)
+	}
+	for _, name := range names {
+		if _, err := ffs.Lstat(name); err != nil {
+			t.Errorf("expected %v to be left alone, got %v", name, err)
+		}
+	}
+
+	f.ConfirmMassChange()
+	if _, err := f.pull(); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range names {
+		if _, err := ffs.Lstat(name); !fs.IsNotExist(err) {
+			t.Errorf("expected %v to be deleted, got %v", name, err)
+		}
+	}
+	if f.massChangeConfirmed {
+		t.Error("expected confirmation to be reset once in sync")
+	}
+}
+
+// archivingVersioner keeps the contents of archived files in memory.
+type archivingVersioner struct {
+	fs       fs.Filesystem
+	archived []string
+	versions map[string][]versioner.FileVersion
+}
+
+func (v *archivingVersioner) Archive(name string) error {
+	return v.archive(name, name, time.Now())
+}
+
+func (v *archivingVersioner) ArchiveCopy(name, copyName string, versionTime time.Time) error {
+	return v.archive(name, copyName, versionTime)
+}
+
+func (v *archivingVersioner) archive(name, src string, versionTime time.Time) error {
+	fd, err := v.fs.Open(src)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(fd)
+	fd.Close()
+	if err != nil {
+		return err
+	}
+	v.archived = append(v.archived, string(data))
+	if v.versions == nil {
+		v.versions = make(map[string][]versioner.FileVersion)
+	}
+	v.versions[name] = append(v.versions[name], versioner.FileVersion{VersionTime: versionTime, Size: int64(len(data))})
+	return v.fs.Remove(src)
+}
+
+func (v *archivingVersioner) GetVersions() (map[string][]versioner.FileVersion, error) {
+	return v.versions, nil
+}
+
+func (*archivingVersioner) Restore(string, time.Time) error {
+	return nil
+}
+
+func (*archivingVersioner) Clean(context.Context) error {
+	return nil
+}
+
+func TestPullHeldBackBySchedule(t *testing.T) {
+	m, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	conn := addFakeConn(m, device1, f.ID)
+
+	// The only device that has the file is outside of its sync window.
+	now := time.Now()
+	window := now.Add(2*time.Hour).Format("15:04") + "-" + now.Add(3*time.Hour).Format("15:04")
+	waiter, err := m.cfg.Modify(func(cfg *config.Configuration) {
+		_, i, _ := cfg.Device(device1)
+		cfg.Devices[i].Schedule = config.Schedule{Windows: []string{window}}
+	})
+	must(t, err)
+	waiter.Wait()
+
+	file := setupFile("foo", []int{1})
+	file.Version = protocol.Vector{}.Update(device1.Short())
+	must(t, m.Index(conn, &protocol.Index{Folder: f.ID, Files: []protocol.FileInfo{file}}))
+
+	changed, err := f.pullerIteration(make(chan string))
+	must(t, err)
+	if changed != 0 {
+		t.Error("Expected no changes held back by the schedule, got", changed)
+	}
+	if !f.heldBack {
+		t.Error("Expected the file to be held back")
+	}
+	if len(f.tempPullErrors) != 0 {
+		t.Error("Expected no pull errors, got", f.tempPullErrors)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/thejerf/suture/v4"
 
 	"github.com/syncthing/syncthing/lib/config"
@@ -44,6 +45,15 @@ type folderSummaryService struct {
 	// For keeping track of folders to recalculate for
 	foldersMut sync.Mutex
 	folders    map[string]struct{}
+
+	// When folders, or remote devices for a folder, were first seen out of
+	// sync. Only touched when sending summaries.
+	outOfSyncSince map[outOfSyncKey]time.Time
+}
+
+type outOfSyncKey struct {
+	folder string
+	device string // empty for the folder itself
 }
 
 func NewFolderSummaryService(cfg config.Wrapper, m Model, id protocol.DeviceID, evLogger events.Logger) FolderSummaryService {
@@ -56,6 +66,8 @@ func NewFolderSummaryService(cfg config.Wrapper, m Model, id protocol.DeviceID,
 		immediate:  make(chan string),
 		folders:    make(map[string]struct{}),
 		foldersMut: sync.NewMutex(),
+
+		outOfSyncSince: make(map[outOfSyncKey]time.Time),
 	}
 
 	service.Add(svcutil.AsService(service.listenForUpdates, fmt.Sprintf("%s/listenForUpdates", service)))
@@ -210,7 +222,7 @@ func (c *folderSummaryService) Summary(folder string) (*FolderSummary, error) {
 // listenForUpdates subscribes to the event bus and makes note of folders that
 // need their data recalculated.
 func (c *folderSummaryService) listenForUpdates(ctx context.Context) error {
-	sub := c.evLogger.Subscribe(events.LocalIndexUpdated | events.RemoteIndexUpdated | events.StateChanged | events.RemoteDownloadProgress | events.DeviceConnected | events.ClusterConfigReceived | events.FolderWatchStateChanged | events.DownloadProgress)
+	sub := c.evLogger.Subscribe(events.LocalIndexUpdated | events.RemoteIndexUpdated | events.StateChanged | events.RemoteDownloadProgress | events.DeviceConnected | events.DeviceDisconnected | events.ClusterConfigReceived | events.FolderWatchStateChanged | events.DownloadProgress)
 	defer sub.Unsubscribe()
 
 	for {
@@ -233,12 +245,12 @@ func (c *folderSummaryService) processUpdate(ev events.Event) {
 	var folder string
 
 	switch ev.Type {
-	case events.DeviceConnected, events.ClusterConfigReceived:
-		// When a device connects we schedule a refresh of all
-		// folders shared with that device.
+	case events.DeviceConnected, events.DeviceDisconnected, events.ClusterConfigReceived:
+		// When a device connects or disconnects we schedule a refresh of
+		// all folders shared with that device.
 
 		var deviceID protocol.DeviceID
-		if ev.Type == events.DeviceConnected {
+		if ev.Type != events.ClusterConfigReceived {
 			data := ev.Data.(map[string]string)
 			deviceID, _ = protocol.DeviceIDFromString(data["id"])
 		} else {
@@ -331,6 +343,8 @@ func (c *folderSummaryService) calculateSummaries(ctx context.Context) error {
 			// We don't want to spend all our time calculating summaries. Lets
 			// set an arbitrary limit at not spending more than about 30% of
 			// our time here...
+			c.pruneOutOfSyncSince()
+
 			wait := 2*time.Since(t0) + pumpInterval
 			pump.Reset(wait)
 
@@ -392,6 +406,10 @@ func (c *folderSummaryService) sendSummary(ctx context.Context, folder string) {
 	metricFolderSummary.WithLabelValues(folder, metricScopeNeed, metricTypeDeleted).Set(float64(data.NeedDeletes))
 	metricFolderSummary.WithLabelValues(folder, metricScopeNeed, metricTypeBytes).Set(float64(data.NeedBytes))
 
+	metricFolderErrors.WithLabelValues(folder).Set(float64(data.Errors))
+	inSync := data.State == FolderIdle.String() && data.NeedTotalItems == 0 && data.Errors == 0
+	c.setOutOfSyncSince(metricFolderOutOfSyncSinceSeconds.WithLabelValues(folder), outOfSyncKey{folder: folder}, inSync)
+
 	for _, devCfg := range c.cfg.Folders()[folder].Devices {
 		select {
 		case <-ctx.Done():
@@ -415,5 +433,49 @@ func (c *folderSummaryService) sendSummary(ctx context.Context, folder string) {
 		ev["folder"] = folder
 		ev["device"] = devCfg.DeviceID.String()
 		c.evLogger.Log(events.FolderCompletion, ev)
+
+		device := devCfg.DeviceID.String()
+		metricFolderDeviceCompletion.WithLabelValues(folder, device).Set(comp.CompletionPct)
+		metricFolderDeviceNeed.WithLabelValues(folder, device, metricTypeItems).Set(float64(comp.NeedItems))
+		metricFolderDeviceNeed.WithLabelValues(folder, device, metricTypeDeleted).Set(float64(comp.NeedDeletes))
+		metricFolderDeviceNeed.WithLabelValues(folder, device, metricTypeBytes).Set(float64(comp.NeedBytes))
+		inSync := comp.RemoteState == remoteFolderValid && comp.NeedItems == 0 && comp.NeedDeletes == 0 && c.model.ConnectedTo(devCfg.DeviceID)
+		c.setOutOfSyncSince(metricFolderDeviceOutOfSyncSinceSeconds.WithLabelValues(folder, device), outOfSyncKey{folder, device}, inSync)
+	}
+}
+
+// pruneOutOfSyncSince forgets about folders that are gone and devices they
+// are no longer shared with.
+func (c *folderSummaryService) pruneOutOfSyncSince() {
+	folders := c.cfg.Folders()
+	for key := range c.outOfSyncSince {
+		fcfg, ok := folders[key.folder]
+		if !ok {
+			delete(c.outOfSyncSince, key)
+			continue
+		}
+		if key.device == "" {
+			continue
+		}
+		if dev, err := protocol.DeviceIDFromString(key.device); err != nil || !fcfg.SharedWith(dev) {
+			delete(c.outOfSyncSince, key)
+		}
+	}
+}
+
+// setOutOfSyncSince sets the gauge to when the folder or device was first
+// seen out of sync, which stays put until it is back in sync, or to zero
+// while in sync.
+func (c *folderSummaryService) setOutOfSyncSince(gauge prometheus.Gauge, key outOfSyncKey, inSync bool) {
+	if inSync {
+		delete(c.outOfSyncSince, key)
+		gauge.Set(0)
+		return
+	}
+	since, ok := c.outOfSyncSince[key]
+	if !ok {
+		since = time.Now()
+		c.outOfSyncSince[key] = since
 	}
+	gauge.Set(float64(since.UnixNano()) / 1e9)
 }
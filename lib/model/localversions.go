@@ -0,0 +1,172 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+// The number of local versions we remember the origin of, per folder.
+const maxLocalVersionRecords = 10000
+
+var errBlockUnavailable = errors.New("block not available locally")
+
+// archiveLocalChange archives the earlier contents of a file that the
+// scanner found changed or deleted, if the folder is versioned. As they
+// are gone from the file already, they are put together from the blocks
+// that are still available in this or other folders, and the file isn't
+// versioned if any block is missing.
+func (f *folder) archiveLocalChange(cur protocol.FileInfo, sources *blockSources) {
+	if f.versioner == nil || f.Type == config.FolderTypeReceiveEncrypted {
+		return
+	}
+	if cur.Type != protocol.FileInfoTypeFile || cur.IsDeleted() || cur.IsInvalid() || cur.IsUnsupported() || cur.IsPlaceholder() || len(cur.Blocks) == 0 && cur.Size > 0 {
+		return
+	}
+	archiver, ok := f.versioner.(versioner.CopyArchiver)
+	if !ok {
+		return
+	}
+
+	tempName := fs.TempName(cur.Name)
+	if err := f.reconstructFile(cur, tempName, sources); err != nil {
+		l.Debugf("%v not versioning local change to %v: %v", f, cur.Name, err)
+		f.mtimefs.Remove(tempName)
+		return
+	}
+
+	versionTime := time.Now().Truncate(time.Second)
+	if err := archiver.ArchiveCopy(cur.Name, tempName, versionTime); err != nil {
+		if errors.Is(err, versioner.ErrCopyArchiveNotSupported) {
+			l.Debugf("%v not versioning local change to %v: %v", f, cur.Name, err)
+		} else {
+			l.Infof("Failed to version local change to %s in folder %s: %v", cur.Name, f.Description(), err)
+		}
+		f.mtimefs.Remove(tempName)
+		return
+	}
+	if err := f.localVersions.Add(cur.Name, versionTime); err != nil {
+		l.Debugf("%v recording local version of %v: %v", f, cur.Name, err)
+	}
+}
+
+// blockSources are the folders that blocks are taken from to reconstruct
+// files, and their filesystems.
+type blockSources struct {
+	folders     []string
+	filesystems map[string]fs.Filesystem
+}
+
+func (f *folder) newBlockSources() *blockSources {
+	s := &blockSources{
+		// Hope that it's usually in the same folder, so start with that one.
+		folders:     []string{f.folderID},
+		filesystems: make(map[string]fs.Filesystem),
+	}
+	for folder, cfg := range f.model.cfg.Folders() {
+		s.filesystems[folder] = cfg.Filesystem(nil)
+		if folder != f.folderID {
+			s.folders = append(s.folders, folder)
+		}
+	}
+	return s
+}
+
+// reconstructFile writes the contents of the given file to name, taking
+// each block from wherever the block map says it can be found.
+func (f *folder) reconstructFile(file protocol.FileInfo, name string, sources *blockSources) error {
+	fd, err := f.mtimefs.Create(name)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	buf := protocol.BufferPool.Get(protocol.MinBlockSize)
+	defer func() {
+		protocol.BufferPool.Put(buf)
+	}()
+
+	for _, block := range file.Blocks {
+		buf = protocol.BufferPool.Upgrade(buf, int(block.Size))
+		if block.IsEmpty() {
+			clear(buf)
+		} else {
+			found := f.model.finder.Iterate(sources.folders, file.HashAlgorithm, block.Hash, func(folder, path string, _ int32, offset int64) bool {
+				src, err := sources.filesystems[folder].Open(path)
+				if err != nil {
+					return false
+				}
+				defer src.Close()
+				if _, err := src.ReadAt(buf, offset); err != nil {
+					return false
+				}
+				return bytes.Equal(scanner.HashBlock(file.HashAlgorithm, buf), block.Hash)
+			})
+			if !found {
+				return fmt.Errorf("block at offset %d: %w", block.Offset, errBlockUnavailable)
+			}
+		}
+		if _, err := fd.WriteAt(buf, block.Offset); err != nil {
+			return err
+		}
+	}
+
+	if err := fd.Close(); err != nil {
+		return err
+	}
+	return f.mtimefs.Chtimes(name, file.ModTime(), file.ModTime())
+}
+
+// forgetRemovedLocalVersions drops the records of local versions that are
+// no longer in the version archive.
+func (f *folder) forgetRemovedLocalVersions() {
+	recorded, err := f.localVersions.Get()
+	if err != nil || len(recorded) == 0 {
+		return
+	}
+	versions, err := f.versioner.GetVersions()
+	if err != nil {
+		return
+	}
+	err = f.localVersions.Retain(func(name string, versionTime time.Time) bool {
+		return slices.ContainsFunc(versions[name], func(ver versioner.FileVersion) bool {
+			return ver.VersionTime.Equal(versionTime)
+		})
+	})
+	if err != nil {
+		l.Debugf("%v forgetting removed local versions: %v", f, err)
+	}
+}
+
+// setVersionOrigins marks the given versions of a folder as local or
+// remote, according to the record of local versions.
+func setVersionOrigins(localVersions *db.LocalVersions, versions map[string][]versioner.FileVersion) error {
+	local, err := localVersions.Get()
+	if err != nil {
+		return err
+	}
+	for name, fileVersions := range versions {
+		for i := range fileVersions {
+			fileVersions[i].Origin = versioner.OriginRemote
+			if slices.ContainsFunc(local[name], fileVersions[i].VersionTime.Equal) {
+				fileVersions[i].Origin = versioner.OriginLocal
+			}
+		}
+	}
+	return nil
+}
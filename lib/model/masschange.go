@@ -0,0 +1,105 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The percentage threshold only applies to folders with at least this many
+// files, so that changing one of a handful of files isn't a mass change.
+const massChangeMinFiles = 20
+
+var errMassChange = errors.New("pulling paused due to a mass change, confirm to continue")
+
+// checkMassChange returns an error if pulling would change or delete more
+// of the existing files than the folder allows at once, as happens when a
+// remote device runs amok or is hit by ransomware. Pulling is then held off
+// until the change is confirmed or the need goes away.
+func (f *sendReceiveFolder) checkMassChange() error {
+	if f.MassChangeMaxFiles == 0 && f.MassChangeMaxPct == 0 {
+		return nil
+	}
+
+	f.massChangeMut.Lock()
+	defer f.massChangeMut.Unlock()
+	if f.massChangeConfirmed {
+		return nil
+	}
+
+	snap, err := f.dbSnapshot()
+	if err != nil {
+		return err
+	}
+	defer snap.Release()
+
+	files := snap.LocalSize().Files
+	changes := 0
+	snap.WithNeed(protocol.LocalDeviceID, func(file protocol.FileInfo) bool {
+		if file.IsDirectory() || f.IgnoreDelete && file.IsDeleted() {
+			return true
+		}
+		cur, ok := snap.Get(protocol.LocalDeviceID, file.Name)
+		if !ok || cur.IsDeleted() || cur.IsInvalid() || cur.IsDirectory() {
+			// Nothing to lose here.
+			return true
+		}
+		if file.IsDeleted() || !file.BlocksEqual(cur) {
+			changes++
+		}
+		return true
+	})
+
+	exceeded := f.MassChangeMaxFiles > 0 && changes > f.MassChangeMaxFiles ||
+		f.MassChangeMaxPct > 0 && files >= massChangeMinFiles && changes*100 > f.MassChangeMaxPct*files
+	if !exceeded {
+		f.massChangeDetected = false
+		return nil
+	}
+
+	err = fmt.Errorf("%w (%d of %d files would be changed or deleted)", errMassChange, changes, files)
+	f.errorsMut.Lock()
+	f.pullErrors = []FileError{{Path: "", Err: err.Error()}}
+	f.errorsMut.Unlock()
+
+	if !f.massChangeDetected {
+		f.massChangeDetected = true
+		l.Warnf("Folder %s: pulling would change or delete %d of %d files; holding off until confirmed", f.Description(), changes, files)
+		f.evLogger.Log(events.MassChangeDetected, map[string]interface{}{
+			"folder":  f.folderID,
+			"changes": changes,
+			"files":   files,
+		})
+		f.evLogger.Log(events.FolderErrors, map[string]interface{}{
+			"folder": f.folderID,
+			"errors": f.Errors(),
+		})
+	}
+	return err
+}
+
+// ConfirmMassChange lets pulling go ahead despite a mass change, until the
+// folder is in sync again.
+func (f *sendReceiveFolder) ConfirmMassChange() {
+	f.massChangeMut.Lock()
+	f.massChangeConfirmed = true
+	f.massChangeDetected = false
+	f.massChangeMut.Unlock()
+	f.SchedulePull()
+}
+
+// massChangeSynced re-arms the mass change check after a confirmed change
+// has been pulled completely.
+func (f *sendReceiveFolder) massChangeSynced() {
+	f.massChangeMut.Lock()
+	f.massChangeConfirmed = false
+	f.massChangeMut.Unlock()
+}
@@ -0,0 +1,369 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/db"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+)
+
+const (
+	// Files larger than this are never merged.
+	maxMergeFileSize = 1 << 20
+	// The number of earlier versions we keep per file, as candidates for
+	// the common ancestor.
+	maxMergeAncestors = 3
+	// We give up finding the differences between two versions of a file
+	// when there are more changed lines than this.
+	maxMergeEdits = 4000
+)
+
+var (
+	errMergeNoAncestor = errors.New("no common ancestor available")
+	errMergeNotText    = errors.New("not a text file")
+	errMergeTooLarge   = errors.New("file too large to merge")
+	errMergeTooChanged = errors.New("too many changes to merge")
+	errMergeOverlap    = errors.New("both sides changed the same lines")
+)
+
+// conflictMerger resolves conflicting changes to text files matching the
+// configured patterns by a line based three way merge, instead of keeping
+// a conflict copy. The common ancestor is taken from the ancestor cache,
+// which records the contents of each version of a matching file that we
+// have scanned or pulled.
+type conflictMerger struct {
+	patterns  []string
+	ancestors *db.MergeAncestors
+}
+
+func newConflictMerger(cfg config.FolderConfiguration, ldb *db.Lowlevel) *conflictMerger {
+	if len(cfg.ConflictMergePatterns) == 0 || cfg.Type == config.FolderTypeReceiveEncrypted {
+		return nil
+	}
+	return &conflictMerger{
+		patterns:  cfg.ConflictMergePatterns,
+		ancestors: db.NewMergeAncestors(ldb, cfg.ID, maxMergeAncestors),
+	}
+}
+
+// matches returns whether conflicts on the given file should be merged.
+// Patterns containing a slash are matched against the full path, others
+// against the file name only. A nil merger matches nothing.
+func (m *conflictMerger) matches(name string) bool {
+	if m == nil {
+		return false
+	}
+	slashed := filepath.ToSlash(name)
+	base := path.Base(slashed)
+	for _, pattern := range m.patterns {
+		target := base
+		if strings.Contains(pattern, "/") {
+			target = slashed
+		}
+		if ok, _ := path.Match(pattern, target); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// remember records data as the contents of file, if it is text. The data is
+// verified against the block hashes, as the file may have changed since it
+// was hashed.
+func (m *conflictMerger) remember(file protocol.FileInfo, data []byte) error {
+	if int64(len(data)) != file.Size || !isMergeableText(data) {
+		return nil
+	}
+	for _, block := range file.Blocks {
+		if !bytes.Equal(scanner.HashBlock(file.HashAlgorithm, data[block.Offset:block.Offset+int64(block.Size)]), block.Hash) {
+			return nil
+		}
+	}
+	return m.ancestors.Put(file.Name, file.Version, data)
+}
+
+// merge merges the changes of ours and theirs, which have the given
+// versions, relative to their latest recorded common ancestor.
+func (m *conflictMerger) merge(name string, ours, theirs []byte, ourVersion, theirVersion protocol.Vector) ([]byte, error) {
+	if len(ours) > maxMergeFileSize || len(theirs) > maxMergeFileSize {
+		return nil, errMergeTooLarge
+	}
+	if !isMergeableText(ours) || !isMergeableText(theirs) {
+		return nil, errMergeNotText
+	}
+	base, ok, err := m.ancestors.Get(name, ourVersion, theirVersion)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errMergeNoAncestor
+	}
+	return merge3(base, ours, theirs)
+}
+
+func isMergeableText(data []byte) bool {
+	return len(data) <= maxMergeFileSize && utf8.Valid(data) && bytes.IndexByte(data, 0) == -1
+}
+
+// merge3 performs a line based three way merge of the changes from base to
+// ours and from base to theirs. It fails if both change the same region of
+// base in different ways.
+func merge3(base, ours, theirs []byte) ([]byte, error) {
+	baseLines := splitLines(base)
+	ourLines := splitLines(ours)
+	theirLines := splitLines(theirs)
+
+	ourMatch, ok := matchLines(baseLines, ourLines)
+	if !ok {
+		return nil, errMergeTooChanged
+	}
+	theirMatch, ok := matchLines(baseLines, theirLines)
+	if !ok {
+		return nil, errMergeTooChanged
+	}
+
+	var res []byte
+	var b, o, t int // positions in base, ours and theirs
+	for b < len(baseLines) || o < len(ourLines) || t < len(theirLines) {
+		// Find the next base line that is unchanged on both sides.
+		next := b
+		for next < len(baseLines) && (ourMatch[next] < 0 || theirMatch[next] < 0) {
+			next++
+		}
+		if next < len(baseLines) && next == b && ourMatch[b] == o && theirMatch[b] == t {
+			res = append(res, baseLines[b]...)
+			b, o, t = b+1, o+1, t+1
+			continue
+		}
+
+		// Everything up to that line has been changed on at least one
+		// side.
+		ourEnd, theirEnd := len(ourLines), len(theirLines)
+		if next < len(baseLines) {
+			ourEnd, theirEnd = ourMatch[next], theirMatch[next]
+		}
+		baseChunk := baseLines[b:next]
+		ourChunk := ourLines[o:ourEnd]
+		theirChunk := theirLines[t:theirEnd]
+		switch {
+		case slices.Equal(ourChunk, baseChunk):
+			res = appendLines(res, theirChunk)
+		case slices.Equal(theirChunk, baseChunk), slices.Equal(ourChunk, theirChunk):
+			res = appendLines(res, ourChunk)
+		default:
+			return nil, errMergeOverlap
+		}
+		b, o, t = next, ourEnd, theirEnd
+	}
+	return res, nil
+}
+
+// splitLines splits data into lines, keeping the line endings.
+func splitLines(data []byte) []string {
+	var lines []string
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n') + 1
+		if i == 0 {
+			i = len(data)
+		}
+		lines = append(lines, string(data[:i]))
+		data = data[i:]
+	}
+	return lines
+}
+
+func appendLines(res []byte, lines []string) []byte {
+	for _, line := range lines {
+		res = append(res, line...)
+	}
+	return res
+}
+
+// matchLines returns for each line in a the index of the corresponding line
+// in b, or -1 if it was removed or changed, based on a shortest edit script
+// found with Myers' algorithm. It returns false if there are more than
+// maxMergeEdits changes.
+func matchLines(a, b []string) ([]int, bool) {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+
+	// Unchanged lines at the start and end need no searching.
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		match[pre] = pre
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		match[len(a)-1-suf] = len(b) - 1 - suf
+		suf++
+	}
+	am, bm := a[pre:len(a)-suf], b[pre:len(b)-suf]
+	n, m := len(am), len(bm)
+
+	maxD := min(n+m, maxMergeEdits)
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3) // furthest x on each diagonal k, at v[offset+k]
+	var trace [][]int          // v for k in [-d, d] after each round d
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && am[x] == bm[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				backtrackMatches(match, pre, trace, d, n, m)
+				return match, true
+			}
+		}
+		trace = append(trace, slices.Clone(v[offset-d:offset+d+1]))
+	}
+	return nil, false
+}
+
+// backtrackMatches walks the edit path found by matchLines back from the
+// end, recording the lines on the diagonal stretches as matching.
+func backtrackMatches(match []int, pre int, trace [][]int, d, x, y int) {
+	for ; d > 0; d-- {
+		prev := trace[d-1]
+		at := func(k int) int { return prev[k+d-1] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		startX := prevX
+		if prevK == k-1 {
+			startX++ // a line was removed from a, the snake starts after it
+		}
+		for x > startX {
+			x--
+			y--
+			match[pre+x] = pre + y
+		}
+		x, y = prevX, prevX-prevK
+	}
+	for x > 0 {
+		x--
+		y--
+		match[pre+x] = pre + y
+	}
+}
+
+// rememberMergeAncestors records the current contents of the given files
+// in the ancestor cache, if they are subject to merging.
+func (f *folder) rememberMergeAncestors(files []protocol.FileInfo) {
+	for _, file := range files {
+		if !f.merger.matches(file.Name) || file.Type != protocol.FileInfoTypeFile || file.IsInvalid() || file.Size > maxMergeFileSize {
+			continue
+		}
+		var err error
+		if file.IsDeleted() {
+			err = f.merger.ancestors.Delete(file.Name)
+		} else {
+			var data []byte
+			data, err = readMergeFile(f.mtimefs, file.Name)
+			if err == nil {
+				err = f.merger.remember(file, data)
+			}
+		}
+		if err != nil {
+			l.Debugf("%v remembering merge ancestor for %v: %v", f, file.Name, err)
+		}
+	}
+}
+
+// mergeConflict attempts to resolve the conflict between the existing file
+// curFile and the new version file, the contents of which are in tempName,
+// by merging the local changes into the temporary file. An event describes
+// the outcome; on failure the caller should fall back to a conflict copy.
+func (f *sendReceiveFolder) mergeConflict(file, curFile protocol.FileInfo, tempName string) bool {
+	err := f.mergeConflictInto(file, curFile, tempName)
+	if err != nil {
+		l.Debugf("%v merging conflict on %v: %v", f, file.Name, err)
+	} else {
+		l.Infof("Merged conflicting changes to %s in folder %s", file.Name, f.Description())
+	}
+	f.evLogger.Log(events.ConflictMergeFinished, map[string]interface{}{
+		"folder":     f.folderID,
+		"item":       file.Name,
+		"modifiedBy": file.ModifiedBy.String(),
+		"merged":     err == nil,
+		"error":      events.Error(err),
+	})
+	return err == nil
+}
+
+func (f *sendReceiveFolder) mergeConflictInto(file, curFile protocol.FileInfo, tempName string) error {
+	if file.Size > maxMergeFileSize || curFile.Size > maxMergeFileSize {
+		return errMergeTooLarge
+	}
+	ours, err := readMergeFile(f.mtimefs, curFile.Name)
+	if err != nil {
+		return err
+	}
+	theirs, err := readMergeFile(f.mtimefs, tempName)
+	if err != nil {
+		return err
+	}
+	merged, err := f.merger.merge(file.Name, ours, theirs, curFile.Version, file.Version)
+	if err != nil {
+		return err
+	}
+
+	fd, err := f.mtimefs.OpenFile(tempName, fs.OptWriteOnly|fs.OptTruncate, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(merged); err != nil {
+		fd.Close()
+		return err
+	}
+	return fd.Close()
+}
+
+func readMergeFile(ffs fs.Filesystem, name string) ([]byte, error) {
+	fd, err := ffs.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	data, err := io.ReadAll(io.LimitReader(fd, maxMergeFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxMergeFileSize {
+		return nil, errMergeTooLarge
+	}
+	return data, nil
+}
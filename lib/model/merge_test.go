@@ -0,0 +1,211 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+)
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	cases := []struct {
+		name         string
+		ours, theirs string
+		res          string // empty if the merge should fail
+	}{
+		{"unchanged", base, base, base},
+		{"only ours", "a\nB\nc\nd\ne\n", base, "a\nB\nc\nd\ne\n"},
+		{"only theirs", base, "a\nb\nc\nd\nE\n", "a\nb\nc\nd\nE\n"},
+		{"separate lines", "a\nB\nc\nd\ne\n", "a\nb\nc\nD\ne\n", "a\nB\nc\nD\ne\n"},
+		{"insert and delete", "0\na\nb\nc\nd\ne\n", "a\nb\nd\ne\n", "0\na\nb\nd\ne\n"},
+		{"append both", base + "f\n", "0\n" + base, "0\n" + base + "f\n"},
+		{"same change", "a\nB\nc\nd\ne\n", "a\nB\nc\nd\ne\n", "a\nB\nc\nd\ne\n"},
+		{"no trailing newline", "a\nB\nc\nd\ne", "a\nb\nc\nd\ne\nf", ""},
+		{"same line", "a\nB\nc\nd\ne\n", "a\nX\nc\nd\ne\n", ""},
+		{"adjacent insert", "a\nb\nx\nc\nd\ne\n", "a\nb\ny\nc\nd\ne\n", ""},
+		{"edit deleted", "a\nc\nd\ne\n", "a\nB\nc\nd\ne\n", ""},
+	}
+	for _, tc := range cases {
+		res, err := merge3([]byte(base), []byte(tc.ours), []byte(tc.theirs))
+		if tc.res == "" {
+			if err == nil {
+				t.Errorf("%s: expected merge to fail, got %q", tc.name, res)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tc.name, err)
+		} else if string(res) != tc.res {
+			t.Errorf("%s: merged to %q, expected %q", tc.name, res, tc.res)
+		}
+	}
+}
+
+func TestMatchLines(t *testing.T) {
+	a := splitLines([]byte("a\nb\nc\nd\ne\nf\n"))
+	b := splitLines([]byte("x\nb\nc\ny\ne\nf\nz\n"))
+	match, ok := matchLines(a, b)
+	if !ok {
+		t.Fatal("no match")
+	}
+	expected := []int{-1, 1, 2, -1, 4, 5}
+	for i := range expected {
+		if match[i] != expected[i] {
+			t.Fatalf("got matches %v, expected %v", match, expected)
+		}
+	}
+}
+
+func TestConflictMergePatterns(t *testing.T) {
+	m := &conflictMerger{patterns: []string{"*.md", "conf/*.ini"}}
+	for name, matches := range map[string]bool{
+		"README.md":             true,
+		"docs/README.md":        true,
+		"conf/app.ini":          true,
+		"other/conf/app.ini":    false,
+		"app.ini":               false,
+		"README.md.sync-backup": false,
+	} {
+		if m.matches(name) != matches {
+			t.Errorf("%s: expected match to be %v", name, matches)
+		}
+	}
+	if (*conflictMerger)(nil).matches("README.md") {
+		t.Error("nil merger shouldn't match anything")
+	}
+}
+
+func TestSRConflictMerge(t *testing.T) {
+	m, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictMergePatterns = []string{"*.txt"}
+	f.merger = newConflictMerger(f.FolderConfiguration, m.db)
+
+	sub := m.evLogger.Subscribe(events.ConflictMergeFinished)
+	defer sub.Unsubscribe()
+
+	// The base version is recorded as ancestor when scanned, then changed
+	// locally.
+	name := "file.txt"
+	writeFile(t, ffs, name, []byte("one\ntwo\nthree\nfour\n"))
+	must(t, f.scanSubdirs(nil))
+	snap := fsetSnapshot(t, f.fset)
+	base, _ := snap.Get(protocol.LocalDeviceID, name)
+	snap.Release()
+
+	writeFile(t, ffs, name, []byte("one\nTWO (local)\nthree\nfour\n"))
+	must(t, f.scanSubdirs(nil))
+	snap = fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	cur, _ := snap.Get(protocol.LocalDeviceID, name)
+
+	// Concurrently, the remote changed another line.
+	remote := base
+	remote.Version = base.Version.Update(device1.Short())
+	remote.ModifiedBy = device1.Short()
+	if !f.inConflict(cur.Version, remote.Version) {
+		t.Fatal("expected versions to conflict")
+	}
+	temp := fs.TempName(name)
+	writeFile(t, ffs, temp, []byte("one\ntwo\nthree\nFOUR (remote)\n"))
+
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 10)
+	curCounter := cur.Version.Counter(f.shortID)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if data := readMergedFile(t, ffs, name); data != "one\nTWO (local)\nthree\nFOUR (remote)\n" {
+		t.Errorf("unexpected merged contents %q", data)
+	}
+	if confls := existingConflicts(name, ffs); len(confls) != 0 {
+		t.Error("expected no conflict copies, got", confls)
+	}
+	job := <-dbUpdateChan
+	if !job.file.Version.GreaterEqual(cur.Version) || !job.file.Version.GreaterEqual(remote.Version) {
+		t.Errorf("recorded version %v doesn't include %v and %v", job.file.Version, cur.Version, remote.Version)
+	}
+	if job.file.Version.Counter(f.shortID) <= curCounter || job.file.ModifiedBy != f.shortID {
+		t.Errorf("merged file %v not recorded as changed here", job.file)
+	}
+	blocks, err := scanner.HashFile(context.Background(), f.ID, ffs, name, remote.BlockSize(), nil, false, remote.HashAlgorithm)
+	must(t, err)
+	if job.file.Size != int64(len("one\nTWO (local)\nthree\nFOUR (remote)\n")) || !bytes.Equal(job.file.BlocksHash, protocol.BlocksHash(blocks)) {
+		t.Errorf("merged file recorded with the wrong contents: %v", job.file)
+	}
+	if ev, err := sub.Poll(time.Minute); err != nil {
+		t.Fatal(err)
+	} else if data := ev.Data.(map[string]interface{}); data["merged"] != true {
+		t.Error("expected event for successful merge, got", data)
+	}
+}
+
+func TestSRConflictMergeFailure(t *testing.T) {
+	m, f, wcfgCancel := setupSendReceiveFolder(t)
+	defer wcfgCancel()
+	ffs := f.Filesystem(nil)
+	f.ConflictMergePatterns = []string{"*.txt"}
+	f.merger = newConflictMerger(f.FolderConfiguration, m.db)
+
+	sub := m.evLogger.Subscribe(events.ConflictMergeFinished)
+	defer sub.Unsubscribe()
+
+	name := "file.txt"
+	writeFile(t, ffs, name, []byte("one\ntwo\n"))
+	must(t, f.scanSubdirs(nil))
+	snap := fsetSnapshot(t, f.fset)
+	base, _ := snap.Get(protocol.LocalDeviceID, name)
+	snap.Release()
+
+	writeFile(t, ffs, name, []byte("one\nlocal\n"))
+	must(t, f.scanSubdirs(nil))
+	snap = fsetSnapshot(t, f.fset)
+	defer snap.Release()
+	cur, _ := snap.Get(protocol.LocalDeviceID, name)
+
+	// Both changed the same line, so we fall back to a conflict copy.
+	remote := base
+	remote.Version = base.Version.Update(device1.Short())
+	remote.ModifiedBy = device1.Short()
+	temp := fs.TempName(name)
+	writeFile(t, ffs, temp, []byte("one\nremote\n"))
+
+	dbUpdateChan := make(chan dbUpdateJob, 1)
+	scanChan := make(chan string, 10)
+	must(t, f.performFinish(remote, cur, true, temp, snap, dbUpdateChan, scanChan))
+
+	if data := readMergedFile(t, ffs, name); data != "one\nremote\n" {
+		t.Errorf("unexpected contents %q", data)
+	}
+	if confls := existingConflicts(name, ffs); len(confls) != 1 {
+		t.Error("expected one conflict copy, got", confls)
+	}
+	if ev, err := sub.Poll(time.Minute); err != nil {
+		t.Fatal(err)
+	} else if data := ev.Data.(map[string]interface{}); data["merged"] != false || data["error"] == nil {
+		t.Error("expected event for failed merge, got", data)
+	}
+}
+
+func readMergedFile(t *testing.T, ffs fs.Filesystem, name string) string {
+	t.Helper()
+	fd, err := ffs.Open(name)
+	must(t, err)
+	defer fd.Close()
+	data, err := io.ReadAll(fd)
+	must(t, err)
+	return string(data)
+}
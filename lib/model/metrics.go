@@ -64,6 +64,44 @@ var (
 		Name:      "folder_conflicts_total",
 		Help:      "Total number of conflicts",
 	}, []string{"folder"})
+
+	metricFolderErrors = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_errors",
+		Help:      "Current number of items that failed to sync, per folder ID",
+	}, []string{"folder"})
+	metricFolderOutOfSyncSinceSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_out_of_sync_since_timestamp_seconds",
+		Help:      "Time since when the folder has not been idle without needed items and errors, as a Unix timestamp or zero while in sync, per folder ID",
+	}, []string{"folder"})
+
+	metricFolderDeviceNeed = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_device_need",
+		Help:      "Current amount of data needed by the remote device, per folder ID, device ID and type (items/deletes/bytes)",
+	}, []string{"folder", "device", "type"})
+	metricFolderDeviceCompletion = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_device_completion_percent",
+		Help:      "Current completion percentage of the remote device, per folder ID and device ID",
+	}, []string{"folder", "device"})
+	metricFolderDeviceOutOfSyncSinceSeconds = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_device_out_of_sync_since_timestamp_seconds",
+		Help:      "Time since when the remote device has not been connected without needed items, as a Unix timestamp or zero while in sync, per folder ID and device ID",
+	}, []string{"folder", "device"})
+	metricFolderDeviceTransferredBytesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "syncthing",
+		Subsystem: "model",
+		Name:      "folder_device_transferred_bytes_total",
+		Help:      "Total amount of block data transferred, per folder ID, device ID and direction (sent/received)",
+	}, []string{"folder", "device", "direction"})
 )
 
 const (
@@ -82,6 +120,10 @@ const (
 	metricTypeSymlinks    = "symlinks"
 	metricTypeDeleted     = "deleted"
 	metricTypeBytes       = "bytes"
+	metricTypeItems       = "items"
+
+	metricDirectionSent     = "sent"
+	metricDirectionReceived = "received"
 )
 
 func registerFolderMetrics(folderID string) {
@@ -98,4 +140,93 @@ func registerFolderMetrics(folderID string) {
 	metricFolderProcessedBytesTotal.WithLabelValues(folderID, metricSourceLocalShifted)
 	metricFolderProcessedBytesTotal.WithLabelValues(folderID, metricSourceSkipped)
 	metricFolderConflictsTotal.WithLabelValues(folderID)
+	metricFolderErrors.WithLabelValues(folderID)
+}
+
+// deleteFolderMetrics removes all series of a folder that's gone, including
+// those per device.
+func deleteFolderMetrics(folderID string) {
+	labels := prometheus.Labels{"folder": folderID}
+	metricFolderState.DeletePartialMatch(labels)
+	metricFolderSummary.DeletePartialMatch(labels)
+	metricFolderPulls.DeletePartialMatch(labels)
+	metricFolderPullSeconds.DeletePartialMatch(labels)
+	metricFolderScans.DeletePartialMatch(labels)
+	metricFolderScanSeconds.DeletePartialMatch(labels)
+	metricFolderProcessedBytesTotal.DeletePartialMatch(labels)
+	metricFolderConflictsTotal.DeletePartialMatch(labels)
+	metricFolderErrors.DeletePartialMatch(labels)
+	metricFolderOutOfSyncSinceSeconds.DeletePartialMatch(labels)
+	deleteFolderDeviceMetrics(labels)
+}
+
+// deleteFolderDeviceMetrics removes the series per device matching the
+// labels, i.e. of a folder or of a device the folder is no longer shared
+// with.
+func deleteFolderDeviceMetrics(labels prometheus.Labels) {
+	metricFolderDeviceNeed.DeletePartialMatch(labels)
+	metricFolderDeviceCompletion.DeletePartialMatch(labels)
+	metricFolderDeviceOutOfSyncSinceSeconds.DeletePartialMatch(labels)
+	metricFolderDeviceTransferredBytesTotal.DeletePartialMatch(labels)
+}
+
+// The deviceMetricsCollector reports the device statistics and connections
+// of the model at the time of collection, as they aren't kept up to date
+// anywhere else.
+type deviceMetricsCollector struct {
+	model *model
+}
+
+var (
+	descDeviceLastSeenSeconds = prometheus.NewDesc(
+		prometheus.BuildFQName("syncthing", "model", "device_last_seen_timestamp_seconds"),
+		"Last time the device was seen connected, as a Unix timestamp, per device ID",
+		[]string{"device"}, nil)
+	descDeviceConnections = prometheus.NewDesc(
+		prometheus.BuildFQName("syncthing", "model", "device_connections"),
+		"Current number of connections, per device ID and connection type",
+		[]string{"device", "type"}, nil)
+	descDeviceConnectionPriority = prometheus.NewDesc(
+		prometheus.BuildFQName("syncthing", "model", "device_connection_priority"),
+		"Priority of the primary connection, lower being better, per device ID and connection type",
+		[]string{"device", "type"}, nil)
+)
+
+func (c *deviceMetricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descDeviceLastSeenSeconds
+	ch <- descDeviceConnections
+	ch <- descDeviceConnectionPriority
+}
+
+func (c *deviceMetricsCollector) Collect(ch chan<- prometheus.Metric) {
+	if stats, err := c.model.DeviceStatistics(); err == nil {
+		for id, stat := range stats {
+			if id == c.model.id || stat.LastSeen.Unix() <= 0 {
+				// Never seen, the zero time being 1970 in the database
+				continue
+			}
+			ch <- prometheus.MustNewConstMetric(descDeviceLastSeenSeconds, prometheus.GaugeValue, float64(stat.LastSeen.Unix()), id.String())
+		}
+	}
+
+	c.model.mut.RLock()
+	defer c.model.mut.RUnlock()
+	for id, connIDs := range c.model.deviceConnIDs {
+		if len(connIDs) == 0 {
+			continue
+		}
+		device := id.String()
+		if primary, ok := c.model.connections[connIDs[0]]; ok {
+			ch <- prometheus.MustNewConstMetric(descDeviceConnectionPriority, prometheus.GaugeValue, float64(primary.Priority()), device, primary.Type())
+		}
+		types := make(map[string]int)
+		for _, connID := range connIDs {
+			if conn, ok := c.model.connections[connID]; ok {
+				types[conn.Type()]++
+			}
+		}
+		for typ, count := range types {
+			ch <- prometheus.MustNewConstMetric(descDeviceConnections, prometheus.GaugeValue, float64(count), device, typ)
+		}
+	}
 }
@@ -0,0 +1,163 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestDeviceMetricsCollector(t *testing.T) {
+	m, fc, fcfg, wcfgCancel := setupModelWithConnection(t)
+	defer wcfgCancel()
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	fc.TypeReturns("tcp-client")
+	fc.PriorityReturns(10)
+
+	collector := &deviceMetricsCollector{model: m.model}
+	reg := prometheus.NewPedanticRegistry()
+	if err := reg.Register(collector); err != nil {
+		t.Fatal(err)
+	}
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := make(map[string]float64)
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["device"] != device1.String() {
+				continue
+			}
+			if typ, ok := labels["type"]; ok && typ != "tcp-client" {
+				t.Errorf("Unexpected connection type %q for %s", typ, family.GetName())
+			}
+			values[family.GetName()] = metric.GetGauge().GetValue()
+		}
+	}
+
+	if v := values["syncthing_model_device_connections"]; v != 1 {
+		t.Errorf("Expected one connection, got %v", v)
+	}
+	if v := values["syncthing_model_device_connection_priority"]; v != 10 {
+		t.Errorf("Expected priority 10, got %v", v)
+	}
+	if v := values["syncthing_model_device_last_seen_timestamp_seconds"]; v <= 0 {
+		t.Errorf("Expected last seen time for connected device, got %v", v)
+	}
+}
+
+func TestTransferredBytesMetric(t *testing.T) {
+	m, fc, fcfg, wcfgCancel := setupModelWithConnection(t)
+	defer wcfgCancel()
+	tfs := fcfg.Filesystem(nil)
+	defer cleanupModelAndRemoveDir(m, tfs.URI())
+
+	sent := metricFolderDeviceTransferredBytesTotal.WithLabelValues("default", device1.String(), metricDirectionSent)
+	received := metricFolderDeviceTransferredBytesTotal.WithLabelValues("default", device1.String(), metricDirectionReceived)
+	sentBefore, receivedBefore := testutil.ToFloat64(sent), testutil.ToFloat64(received)
+
+	contents := []byte("test file contents\n")
+	writeFile(t, tfs, "local", contents)
+	res, err := m.Request(fc, &protocol.Request{Folder: "default", Name: "local", Size: len(contents)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Close()
+	if d := testutil.ToFloat64(sent) - sentBefore; d != float64(len(contents)) {
+		t.Errorf("Expected %d bytes sent, got %v", len(contents), d)
+	}
+
+	fc.addFile("remote", 0o644, protocol.FileInfoTypeFile, contents[:4])
+	if _, err := m.RequestGlobal(context.Background(), device1, "default", "remote", 0, 0, 4, nil, 0, protocol.HashAlgorithmSHA256, false); err != nil {
+		t.Fatal(err)
+	}
+	if d := testutil.ToFloat64(received) - receivedBefore; d != 4 {
+		t.Errorf("Expected 4 bytes received, got %v", d)
+	}
+}
+
+func TestOutOfSyncSinceMetric(t *testing.T) {
+	c := &folderSummaryService{outOfSyncSince: make(map[outOfSyncKey]time.Time)}
+	gauge := metricFolderOutOfSyncSinceSeconds.WithLabelValues("outofsync")
+	key := outOfSyncKey{folder: "outofsync"}
+
+	c.setOutOfSyncSince(gauge, key, true)
+	if v := testutil.ToFloat64(gauge); v != 0 {
+		t.Errorf("Expected zero while in sync, got %v", v)
+	}
+
+	// The time it got out of sync is kept, however often we look.
+	c.setOutOfSyncSince(gauge, key, false)
+	since := testutil.ToFloat64(gauge)
+	if since <= 0 {
+		t.Fatalf("Expected a timestamp while out of sync, got %v", since)
+	}
+	c.outOfSyncSince[key] = c.outOfSyncSince[key].Add(-time.Hour)
+	c.setOutOfSyncSince(gauge, key, false)
+	if v := testutil.ToFloat64(gauge); math.Abs(v-(since-3600)) > 0.001 {
+		t.Errorf("Expected the first out of sync time %v, got %v", since-3600, v)
+	}
+
+	c.setOutOfSyncSince(gauge, key, true)
+	if v := testutil.ToFloat64(gauge); v != 0 {
+		t.Errorf("Expected zero when back in sync, got %v", v)
+	}
+}
+
+func TestFolderMetricsDeleted(t *testing.T) {
+	m, _, fcfg, wcfgCancel := setupModelWithConnection(t)
+	defer wcfgCancel()
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	c := &folderSummaryService{cfg: m.cfg, outOfSyncSince: make(map[outOfSyncKey]time.Time)}
+	device := device1.String()
+	folderKey := outOfSyncKey{folder: fcfg.ID}
+	deviceKey := outOfSyncKey{folder: fcfg.ID, device: device}
+	c.setOutOfSyncSince(metricFolderOutOfSyncSinceSeconds.WithLabelValues(fcfg.ID), folderKey, false)
+	c.setOutOfSyncSince(metricFolderDeviceOutOfSyncSinceSeconds.WithLabelValues(fcfg.ID, device), deviceKey, false)
+
+	// No longer sharing the folder with the device removes its series.
+	fcfg.Devices = []config.FolderDeviceConfiguration{{DeviceID: myID}}
+	setFolder(t, m.cfg, fcfg)
+	c.pruneOutOfSyncSince()
+	if metricFolderDeviceOutOfSyncSinceSeconds.DeleteLabelValues(fcfg.ID, device) {
+		t.Error("Expected the series of the device to be deleted")
+	}
+	if _, ok := c.outOfSyncSince[deviceKey]; ok {
+		t.Error("Expected the device to be forgotten")
+	}
+	if _, ok := c.outOfSyncSince[folderKey]; !ok {
+		t.Error("Expected the folder to still be out of sync")
+	}
+
+	// Removing the folder removes all of its series.
+	waiter, err := m.cfg.RemoveFolder(fcfg.ID)
+	must(t, err)
+	waiter.Wait()
+	c.pruneOutOfSyncSince()
+	if metricFolderOutOfSyncSinceSeconds.DeleteLabelValues(fcfg.ID) || metricFolderState.DeleteLabelValues(fcfg.ID) {
+		t.Error("Expected the series of the folder to be deleted")
+	}
+	if len(c.outOfSyncSince) != 0 {
+		t.Error("Expected the folder to be forgotten, got", c.outOfSyncSince)
+	}
+}
@@ -76,6 +76,17 @@ type Model struct {
 		result1 model.FolderCompletion
 		result2 error
 	}
+	ConfirmMassChangeStub        func(string) error
+	confirmMassChangeMutex       sync.RWMutex
+	confirmMassChangeArgsForCall []struct {
+		arg1 string
+	}
+	confirmMassChangeReturns struct {
+		result1 error
+	}
+	confirmMassChangeReturnsOnCall map[int]struct {
+		result1 error
+	}
 	ConnectedToStub        func(protocol.DeviceID) bool
 	connectedToMutex       sync.RWMutex
 	connectedToArgsForCall []struct {
@@ -157,6 +168,16 @@ type Model struct {
 		result1 *db.Snapshot
 		result2 error
 	}
+	DatabaseVerifyResultsStub        func() map[string]model.DatabaseVerifyResult
+	databaseVerifyResultsMutex       sync.RWMutex
+	databaseVerifyResultsArgsForCall []struct {
+	}
+	databaseVerifyResultsReturns struct {
+		result1 map[string]model.DatabaseVerifyResult
+	}
+	databaseVerifyResultsReturnsOnCall map[int]struct {
+		result1 map[string]model.DatabaseVerifyResult
+	}
 	DelayScanStub        func(string, time.Duration)
 	delayScanMutex       sync.RWMutex
 	delayScanArgsForCall []struct {
@@ -210,6 +231,19 @@ type Model struct {
 	downloadProgressReturnsOnCall map[int]struct {
 		result1 error
 	}
+	FolderConflictsStub        func(string) ([]db.ConflictRecord, error)
+	folderConflictsMutex       sync.RWMutex
+	folderConflictsArgsForCall []struct {
+		arg1 string
+	}
+	folderConflictsReturns struct {
+		result1 []db.ConflictRecord
+		result2 error
+	}
+	folderConflictsReturnsOnCall map[int]struct {
+		result1 []db.ConflictRecord
+		result2 error
+	}
 	FolderErrorsStub        func(string) ([]model.FileError, error)
 	folderErrorsMutex       sync.RWMutex
 	folderErrorsArgsForCall []struct {
@@ -246,6 +280,19 @@ type Model struct {
 		result1 map[string]stats.FolderStatistics
 		result2 error
 	}
+	GetExpiredFolderVersionsStub        func(string) (map[string][]versioner.FileVersion, error)
+	getExpiredFolderVersionsMutex       sync.RWMutex
+	getExpiredFolderVersionsArgsForCall []struct {
+		arg1 string
+	}
+	getExpiredFolderVersionsReturns struct {
+		result1 map[string][]versioner.FileVersion
+		result2 error
+	}
+	getExpiredFolderVersionsReturnsOnCall map[int]struct {
+		result1 map[string][]versioner.FileVersion
+		result2 error
+	}
 	GetFolderVersionsStub        func(string) (map[string][]versioner.FileVersion, error)
 	getFolderVersionsMutex       sync.RWMutex
 	getFolderVersionsArgsForCall []struct {
@@ -289,6 +336,18 @@ type Model struct {
 		result1 []*model.TreeEntry
 		result2 error
 	}
+	HydrateStub        func(string, string) error
+	hydrateMutex       sync.RWMutex
+	hydrateArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	hydrateReturns struct {
+		result1 error
+	}
+	hydrateReturnsOnCall map[int]struct {
+		result1 error
+	}
 	IndexStub        func(protocol.Connection, *protocol.Index) error
 	indexMutex       sync.RWMutex
 	indexArgsForCall []struct {
@@ -435,7 +494,7 @@ type Model struct {
 		result1 protocol.RequestResponse
 		result2 error
 	}
-	RequestGlobalStub        func(context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, bool) ([]byte, error)
+	RequestGlobalStub        func(context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, protocol.HashAlgorithm, bool) ([]byte, error)
 	requestGlobalMutex       sync.RWMutex
 	requestGlobalArgsForCall []struct {
 		arg1  context.Context
@@ -447,7 +506,8 @@ type Model struct {
 		arg7  int
 		arg8  []byte
 		arg9  uint32
-		arg10 bool
+		arg10 protocol.HashAlgorithm
+		arg11 bool
 	}
 	requestGlobalReturns struct {
 		result1 []byte
@@ -487,6 +547,22 @@ type Model struct {
 	revertArgsForCall []struct {
 		arg1 string
 	}
+	RollbackFolderStub        func(string, string, time.Time, bool) (map[string]model.RollbackChange, error)
+	rollbackFolderMutex       sync.RWMutex
+	rollbackFolderArgsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 time.Time
+		arg4 bool
+	}
+	rollbackFolderReturns struct {
+		result1 map[string]model.RollbackChange
+		result2 error
+	}
+	rollbackFolderReturnsOnCall map[int]struct {
+		result1 map[string]model.RollbackChange
+		result2 error
+	}
 	ScanFolderStub        func(string) error
 	scanFolderMutex       sync.RWMutex
 	scanFolderArgsForCall []struct {
@@ -565,6 +641,20 @@ type Model struct {
 		arg2 int
 		arg3 bool
 	}
+	VerifyDatabaseStub        func(context.Context, string) (model.DatabaseVerifyResult, error)
+	verifyDatabaseMutex       sync.RWMutex
+	verifyDatabaseArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	verifyDatabaseReturns struct {
+		result1 model.DatabaseVerifyResult
+		result2 error
+	}
+	verifyDatabaseReturnsOnCall map[int]struct {
+		result1 model.DatabaseVerifyResult
+		result2 error
+	}
 	WatchErrorStub        func(string) error
 	watchErrorMutex       sync.RWMutex
 	watchErrorArgsForCall []struct {
@@ -872,6 +962,67 @@ func (fake *Model) CompletionReturnsOnCall(i int, result1 model.FolderCompletion
 	}{result1, result2}
 }
 
+func (fake *Model) ConfirmMassChange(arg1 string) error {
+	fake.confirmMassChangeMutex.Lock()
+	ret, specificReturn := fake.confirmMassChangeReturnsOnCall[len(fake.confirmMassChangeArgsForCall)]
+	fake.confirmMassChangeArgsForCall = append(fake.confirmMassChangeArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.ConfirmMassChangeStub
+	fakeReturns := fake.confirmMassChangeReturns
+	fake.recordInvocation("ConfirmMassChange", []interface{}{arg1})
+	fake.confirmMassChangeMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) ConfirmMassChangeCallCount() int {
+	fake.confirmMassChangeMutex.RLock()
+	defer fake.confirmMassChangeMutex.RUnlock()
+	return len(fake.confirmMassChangeArgsForCall)
+}
+
+func (fake *Model) ConfirmMassChangeCalls(stub func(string) error) {
+	fake.confirmMassChangeMutex.Lock()
+	defer fake.confirmMassChangeMutex.Unlock()
+	fake.ConfirmMassChangeStub = stub
+}
+
+func (fake *Model) ConfirmMassChangeArgsForCall(i int) string {
+	fake.confirmMassChangeMutex.RLock()
+	defer fake.confirmMassChangeMutex.RUnlock()
+	argsForCall := fake.confirmMassChangeArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) ConfirmMassChangeReturns(result1 error) {
+	fake.confirmMassChangeMutex.Lock()
+	defer fake.confirmMassChangeMutex.Unlock()
+	fake.ConfirmMassChangeStub = nil
+	fake.confirmMassChangeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) ConfirmMassChangeReturnsOnCall(i int, result1 error) {
+	fake.confirmMassChangeMutex.Lock()
+	defer fake.confirmMassChangeMutex.Unlock()
+	fake.ConfirmMassChangeStub = nil
+	if fake.confirmMassChangeReturnsOnCall == nil {
+		fake.confirmMassChangeReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.confirmMassChangeReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Model) ConnectedTo(arg1 protocol.DeviceID) bool {
 	fake.connectedToMutex.Lock()
 	ret, specificReturn := fake.connectedToReturnsOnCall[len(fake.connectedToArgsForCall)]
@@ -1253,6 +1404,59 @@ func (fake *Model) DBSnapshotReturnsOnCall(i int, result1 *db.Snapshot, result2
 	}{result1, result2}
 }
 
+func (fake *Model) DatabaseVerifyResults() map[string]model.DatabaseVerifyResult {
+	fake.databaseVerifyResultsMutex.Lock()
+	ret, specificReturn := fake.databaseVerifyResultsReturnsOnCall[len(fake.databaseVerifyResultsArgsForCall)]
+	fake.databaseVerifyResultsArgsForCall = append(fake.databaseVerifyResultsArgsForCall, struct {
+	}{})
+	stub := fake.DatabaseVerifyResultsStub
+	fakeReturns := fake.databaseVerifyResultsReturns
+	fake.recordInvocation("DatabaseVerifyResults", []interface{}{})
+	fake.databaseVerifyResultsMutex.Unlock()
+	if stub != nil {
+		return stub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) DatabaseVerifyResultsCallCount() int {
+	fake.databaseVerifyResultsMutex.RLock()
+	defer fake.databaseVerifyResultsMutex.RUnlock()
+	return len(fake.databaseVerifyResultsArgsForCall)
+}
+
+func (fake *Model) DatabaseVerifyResultsCalls(stub func() map[string]model.DatabaseVerifyResult) {
+	fake.databaseVerifyResultsMutex.Lock()
+	defer fake.databaseVerifyResultsMutex.Unlock()
+	fake.DatabaseVerifyResultsStub = stub
+}
+
+func (fake *Model) DatabaseVerifyResultsReturns(result1 map[string]model.DatabaseVerifyResult) {
+	fake.databaseVerifyResultsMutex.Lock()
+	defer fake.databaseVerifyResultsMutex.Unlock()
+	fake.DatabaseVerifyResultsStub = nil
+	fake.databaseVerifyResultsReturns = struct {
+		result1 map[string]model.DatabaseVerifyResult
+	}{result1}
+}
+
+func (fake *Model) DatabaseVerifyResultsReturnsOnCall(i int, result1 map[string]model.DatabaseVerifyResult) {
+	fake.databaseVerifyResultsMutex.Lock()
+	defer fake.databaseVerifyResultsMutex.Unlock()
+	fake.DatabaseVerifyResultsStub = nil
+	if fake.databaseVerifyResultsReturnsOnCall == nil {
+		fake.databaseVerifyResultsReturnsOnCall = make(map[int]struct {
+			result1 map[string]model.DatabaseVerifyResult
+		})
+	}
+	fake.databaseVerifyResultsReturnsOnCall[i] = struct {
+		result1 map[string]model.DatabaseVerifyResult
+	}{result1}
+}
+
 func (fake *Model) DelayScan(arg1 string, arg2 time.Duration) {
 	fake.delayScanMutex.Lock()
 	fake.delayScanArgsForCall = append(fake.delayScanArgsForCall, struct {
@@ -1527,6 +1731,70 @@ func (fake *Model) DownloadProgressReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *Model) FolderConflicts(arg1 string) ([]db.ConflictRecord, error) {
+	fake.folderConflictsMutex.Lock()
+	ret, specificReturn := fake.folderConflictsReturnsOnCall[len(fake.folderConflictsArgsForCall)]
+	fake.folderConflictsArgsForCall = append(fake.folderConflictsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.FolderConflictsStub
+	fakeReturns := fake.folderConflictsReturns
+	fake.recordInvocation("FolderConflicts", []interface{}{arg1})
+	fake.folderConflictsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) FolderConflictsCallCount() int {
+	fake.folderConflictsMutex.RLock()
+	defer fake.folderConflictsMutex.RUnlock()
+	return len(fake.folderConflictsArgsForCall)
+}
+
+func (fake *Model) FolderConflictsCalls(stub func(string) ([]db.ConflictRecord, error)) {
+	fake.folderConflictsMutex.Lock()
+	defer fake.folderConflictsMutex.Unlock()
+	fake.FolderConflictsStub = stub
+}
+
+func (fake *Model) FolderConflictsArgsForCall(i int) string {
+	fake.folderConflictsMutex.RLock()
+	defer fake.folderConflictsMutex.RUnlock()
+	argsForCall := fake.folderConflictsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) FolderConflictsReturns(result1 []db.ConflictRecord, result2 error) {
+	fake.folderConflictsMutex.Lock()
+	defer fake.folderConflictsMutex.Unlock()
+	fake.FolderConflictsStub = nil
+	fake.folderConflictsReturns = struct {
+		result1 []db.ConflictRecord
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) FolderConflictsReturnsOnCall(i int, result1 []db.ConflictRecord, result2 error) {
+	fake.folderConflictsMutex.Lock()
+	defer fake.folderConflictsMutex.Unlock()
+	fake.FolderConflictsStub = nil
+	if fake.folderConflictsReturnsOnCall == nil {
+		fake.folderConflictsReturnsOnCall = make(map[int]struct {
+			result1 []db.ConflictRecord
+			result2 error
+		})
+	}
+	fake.folderConflictsReturnsOnCall[i] = struct {
+		result1 []db.ConflictRecord
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) FolderErrors(arg1 string) ([]model.FileError, error) {
 	fake.folderErrorsMutex.Lock()
 	ret, specificReturn := fake.folderErrorsReturnsOnCall[len(fake.folderErrorsArgsForCall)]
@@ -1708,6 +1976,70 @@ func (fake *Model) FolderStatisticsReturnsOnCall(i int, result1 map[string]stats
 	}{result1, result2}
 }
 
+func (fake *Model) GetExpiredFolderVersions(arg1 string) (map[string][]versioner.FileVersion, error) {
+	fake.getExpiredFolderVersionsMutex.Lock()
+	ret, specificReturn := fake.getExpiredFolderVersionsReturnsOnCall[len(fake.getExpiredFolderVersionsArgsForCall)]
+	fake.getExpiredFolderVersionsArgsForCall = append(fake.getExpiredFolderVersionsArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.GetExpiredFolderVersionsStub
+	fakeReturns := fake.getExpiredFolderVersionsReturns
+	fake.recordInvocation("GetExpiredFolderVersions", []interface{}{arg1})
+	fake.getExpiredFolderVersionsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) GetExpiredFolderVersionsCallCount() int {
+	fake.getExpiredFolderVersionsMutex.RLock()
+	defer fake.getExpiredFolderVersionsMutex.RUnlock()
+	return len(fake.getExpiredFolderVersionsArgsForCall)
+}
+
+func (fake *Model) GetExpiredFolderVersionsCalls(stub func(string) (map[string][]versioner.FileVersion, error)) {
+	fake.getExpiredFolderVersionsMutex.Lock()
+	defer fake.getExpiredFolderVersionsMutex.Unlock()
+	fake.GetExpiredFolderVersionsStub = stub
+}
+
+func (fake *Model) GetExpiredFolderVersionsArgsForCall(i int) string {
+	fake.getExpiredFolderVersionsMutex.RLock()
+	defer fake.getExpiredFolderVersionsMutex.RUnlock()
+	argsForCall := fake.getExpiredFolderVersionsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *Model) GetExpiredFolderVersionsReturns(result1 map[string][]versioner.FileVersion, result2 error) {
+	fake.getExpiredFolderVersionsMutex.Lock()
+	defer fake.getExpiredFolderVersionsMutex.Unlock()
+	fake.GetExpiredFolderVersionsStub = nil
+	fake.getExpiredFolderVersionsReturns = struct {
+		result1 map[string][]versioner.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) GetExpiredFolderVersionsReturnsOnCall(i int, result1 map[string][]versioner.FileVersion, result2 error) {
+	fake.getExpiredFolderVersionsMutex.Lock()
+	defer fake.getExpiredFolderVersionsMutex.Unlock()
+	fake.GetExpiredFolderVersionsStub = nil
+	if fake.getExpiredFolderVersionsReturnsOnCall == nil {
+		fake.getExpiredFolderVersionsReturnsOnCall = make(map[int]struct {
+			result1 map[string][]versioner.FileVersion
+			result2 error
+		})
+	}
+	fake.getExpiredFolderVersionsReturnsOnCall[i] = struct {
+		result1 map[string][]versioner.FileVersion
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) GetFolderVersions(arg1 string) (map[string][]versioner.FileVersion, error) {
 	fake.getFolderVersionsMutex.Lock()
 	ret, specificReturn := fake.getFolderVersionsReturnsOnCall[len(fake.getFolderVersionsArgsForCall)]
@@ -1904,6 +2236,68 @@ func (fake *Model) GlobalDirectoryTreeReturnsOnCall(i int, result1 []*model.Tree
 	}{result1, result2}
 }
 
+func (fake *Model) Hydrate(arg1 string, arg2 string) error {
+	fake.hydrateMutex.Lock()
+	ret, specificReturn := fake.hydrateReturnsOnCall[len(fake.hydrateArgsForCall)]
+	fake.hydrateArgsForCall = append(fake.hydrateArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.HydrateStub
+	fakeReturns := fake.hydrateReturns
+	fake.recordInvocation("Hydrate", []interface{}{arg1, arg2})
+	fake.hydrateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *Model) HydrateCallCount() int {
+	fake.hydrateMutex.RLock()
+	defer fake.hydrateMutex.RUnlock()
+	return len(fake.hydrateArgsForCall)
+}
+
+func (fake *Model) HydrateCalls(stub func(string, string) error) {
+	fake.hydrateMutex.Lock()
+	defer fake.hydrateMutex.Unlock()
+	fake.HydrateStub = stub
+}
+
+func (fake *Model) HydrateArgsForCall(i int) (string, string) {
+	fake.hydrateMutex.RLock()
+	defer fake.hydrateMutex.RUnlock()
+	argsForCall := fake.hydrateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) HydrateReturns(result1 error) {
+	fake.hydrateMutex.Lock()
+	defer fake.hydrateMutex.Unlock()
+	fake.HydrateStub = nil
+	fake.hydrateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *Model) HydrateReturnsOnCall(i int, result1 error) {
+	fake.hydrateMutex.Lock()
+	defer fake.hydrateMutex.Unlock()
+	fake.HydrateStub = nil
+	if fake.hydrateReturnsOnCall == nil {
+		fake.hydrateReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.hydrateReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *Model) Index(arg1 protocol.Connection, arg2 *protocol.Index) error {
 	fake.indexMutex.Lock()
 	ret, specificReturn := fake.indexReturnsOnCall[len(fake.indexArgsForCall)]
@@ -2580,7 +2974,7 @@ func (fake *Model) RequestReturnsOnCall(i int, result1 protocol.RequestResponse,
 	}{result1, result2}
 }
 
-func (fake *Model) RequestGlobal(arg1 context.Context, arg2 protocol.DeviceID, arg3 string, arg4 string, arg5 int, arg6 int64, arg7 int, arg8 []byte, arg9 uint32, arg10 bool) ([]byte, error) {
+func (fake *Model) RequestGlobal(arg1 context.Context, arg2 protocol.DeviceID, arg3 string, arg4 string, arg5 int, arg6 int64, arg7 int, arg8 []byte, arg9 uint32, arg10 protocol.HashAlgorithm, arg11 bool) ([]byte, error) {
 	var arg8Copy []byte
 	if arg8 != nil {
 		arg8Copy = make([]byte, len(arg8))
@@ -2598,14 +2992,15 @@ func (fake *Model) RequestGlobal(arg1 context.Context, arg2 protocol.DeviceID, a
 		arg7  int
 		arg8  []byte
 		arg9  uint32
-		arg10 bool
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8Copy, arg9, arg10})
+		arg10 protocol.HashAlgorithm
+		arg11 bool
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8Copy, arg9, arg10, arg11})
 	stub := fake.RequestGlobalStub
 	fakeReturns := fake.requestGlobalReturns
-	fake.recordInvocation("RequestGlobal", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8Copy, arg9, arg10})
+	fake.recordInvocation("RequestGlobal", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8Copy, arg9, arg10, arg11})
 	fake.requestGlobalMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8, arg9, arg10, arg11)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -2619,17 +3014,17 @@ func (fake *Model) RequestGlobalCallCount() int {
 	return len(fake.requestGlobalArgsForCall)
 }
 
-func (fake *Model) RequestGlobalCalls(stub func(context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, bool) ([]byte, error)) {
+func (fake *Model) RequestGlobalCalls(stub func(context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, protocol.HashAlgorithm, bool) ([]byte, error)) {
 	fake.requestGlobalMutex.Lock()
 	defer fake.requestGlobalMutex.Unlock()
 	fake.RequestGlobalStub = stub
 }
 
-func (fake *Model) RequestGlobalArgsForCall(i int) (context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, bool) {
+func (fake *Model) RequestGlobalArgsForCall(i int) (context.Context, protocol.DeviceID, string, string, int, int64, int, []byte, uint32, protocol.HashAlgorithm, bool) {
 	fake.requestGlobalMutex.RLock()
 	defer fake.requestGlobalMutex.RUnlock()
 	argsForCall := fake.requestGlobalArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8, argsForCall.arg9, argsForCall.arg10
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8, argsForCall.arg9, argsForCall.arg10, argsForCall.arg11
 }
 
 func (fake *Model) RequestGlobalReturns(result1 []byte, result2 error) {
@@ -2816,6 +3211,73 @@ func (fake *Model) RevertArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
+func (fake *Model) RollbackFolder(arg1 string, arg2 string, arg3 time.Time, arg4 bool) (map[string]model.RollbackChange, error) {
+	fake.rollbackFolderMutex.Lock()
+	ret, specificReturn := fake.rollbackFolderReturnsOnCall[len(fake.rollbackFolderArgsForCall)]
+	fake.rollbackFolderArgsForCall = append(fake.rollbackFolderArgsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 time.Time
+		arg4 bool
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.RollbackFolderStub
+	fakeReturns := fake.rollbackFolderReturns
+	fake.recordInvocation("RollbackFolder", []interface{}{arg1, arg2, arg3, arg4})
+	fake.rollbackFolderMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) RollbackFolderCallCount() int {
+	fake.rollbackFolderMutex.RLock()
+	defer fake.rollbackFolderMutex.RUnlock()
+	return len(fake.rollbackFolderArgsForCall)
+}
+
+func (fake *Model) RollbackFolderCalls(stub func(string, string, time.Time, bool) (map[string]model.RollbackChange, error)) {
+	fake.rollbackFolderMutex.Lock()
+	defer fake.rollbackFolderMutex.Unlock()
+	fake.RollbackFolderStub = stub
+}
+
+func (fake *Model) RollbackFolderArgsForCall(i int) (string, string, time.Time, bool) {
+	fake.rollbackFolderMutex.RLock()
+	defer fake.rollbackFolderMutex.RUnlock()
+	argsForCall := fake.rollbackFolderArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+}
+
+func (fake *Model) RollbackFolderReturns(result1 map[string]model.RollbackChange, result2 error) {
+	fake.rollbackFolderMutex.Lock()
+	defer fake.rollbackFolderMutex.Unlock()
+	fake.RollbackFolderStub = nil
+	fake.rollbackFolderReturns = struct {
+		result1 map[string]model.RollbackChange
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) RollbackFolderReturnsOnCall(i int, result1 map[string]model.RollbackChange, result2 error) {
+	fake.rollbackFolderMutex.Lock()
+	defer fake.rollbackFolderMutex.Unlock()
+	fake.RollbackFolderStub = nil
+	if fake.rollbackFolderReturnsOnCall == nil {
+		fake.rollbackFolderReturnsOnCall = make(map[int]struct {
+			result1 map[string]model.RollbackChange
+			result2 error
+		})
+	}
+	fake.rollbackFolderReturnsOnCall[i] = struct {
+		result1 map[string]model.RollbackChange
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) ScanFolder(arg1 string) error {
 	fake.scanFolderMutex.Lock()
 	ret, specificReturn := fake.scanFolderReturnsOnCall[len(fake.scanFolderArgsForCall)]
@@ -3226,6 +3688,71 @@ func (fake *Model) UsageReportingStatsArgsForCall(i int) (*contract.Report, int,
 	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
+func (fake *Model) VerifyDatabase(arg1 context.Context, arg2 string) (model.DatabaseVerifyResult, error) {
+	fake.verifyDatabaseMutex.Lock()
+	ret, specificReturn := fake.verifyDatabaseReturnsOnCall[len(fake.verifyDatabaseArgsForCall)]
+	fake.verifyDatabaseArgsForCall = append(fake.verifyDatabaseArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.VerifyDatabaseStub
+	fakeReturns := fake.verifyDatabaseReturns
+	fake.recordInvocation("VerifyDatabase", []interface{}{arg1, arg2})
+	fake.verifyDatabaseMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *Model) VerifyDatabaseCallCount() int {
+	fake.verifyDatabaseMutex.RLock()
+	defer fake.verifyDatabaseMutex.RUnlock()
+	return len(fake.verifyDatabaseArgsForCall)
+}
+
+func (fake *Model) VerifyDatabaseCalls(stub func(context.Context, string) (model.DatabaseVerifyResult, error)) {
+	fake.verifyDatabaseMutex.Lock()
+	defer fake.verifyDatabaseMutex.Unlock()
+	fake.VerifyDatabaseStub = stub
+}
+
+func (fake *Model) VerifyDatabaseArgsForCall(i int) (context.Context, string) {
+	fake.verifyDatabaseMutex.RLock()
+	defer fake.verifyDatabaseMutex.RUnlock()
+	argsForCall := fake.verifyDatabaseArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *Model) VerifyDatabaseReturns(result1 model.DatabaseVerifyResult, result2 error) {
+	fake.verifyDatabaseMutex.Lock()
+	defer fake.verifyDatabaseMutex.Unlock()
+	fake.VerifyDatabaseStub = nil
+	fake.verifyDatabaseReturns = struct {
+		result1 model.DatabaseVerifyResult
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Model) VerifyDatabaseReturnsOnCall(i int, result1 model.DatabaseVerifyResult, result2 error) {
+	fake.verifyDatabaseMutex.Lock()
+	defer fake.verifyDatabaseMutex.Unlock()
+	fake.VerifyDatabaseStub = nil
+	if fake.verifyDatabaseReturnsOnCall == nil {
+		fake.verifyDatabaseReturnsOnCall = make(map[int]struct {
+			result1 model.DatabaseVerifyResult
+			result2 error
+		})
+	}
+	fake.verifyDatabaseReturnsOnCall[i] = struct {
+		result1 model.DatabaseVerifyResult
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Model) WatchError(arg1 string) error {
 	fake.watchErrorMutex.Lock()
 	ret, specificReturn := fake.watchErrorReturnsOnCall[len(fake.watchErrorArgsForCall)]
@@ -3302,6 +3829,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.clusterConfigMutex.RUnlock()
 	fake.completionMutex.RLock()
 	defer fake.completionMutex.RUnlock()
+	fake.confirmMassChangeMutex.RLock()
+	defer fake.confirmMassChangeMutex.RUnlock()
 	fake.connectedToMutex.RLock()
 	defer fake.connectedToMutex.RUnlock()
 	fake.connectionStatsMutex.RLock()
@@ -3314,6 +3843,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.currentIgnoresMutex.RUnlock()
 	fake.dBSnapshotMutex.RLock()
 	defer fake.dBSnapshotMutex.RUnlock()
+	fake.databaseVerifyResultsMutex.RLock()
+	defer fake.databaseVerifyResultsMutex.RUnlock()
 	fake.delayScanMutex.RLock()
 	defer fake.delayScanMutex.RUnlock()
 	fake.deviceStatisticsMutex.RLock()
@@ -3324,18 +3855,24 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.dismissPendingFolderMutex.RUnlock()
 	fake.downloadProgressMutex.RLock()
 	defer fake.downloadProgressMutex.RUnlock()
+	fake.folderConflictsMutex.RLock()
+	defer fake.folderConflictsMutex.RUnlock()
 	fake.folderErrorsMutex.RLock()
 	defer fake.folderErrorsMutex.RUnlock()
 	fake.folderProgressBytesCompletedMutex.RLock()
 	defer fake.folderProgressBytesCompletedMutex.RUnlock()
 	fake.folderStatisticsMutex.RLock()
 	defer fake.folderStatisticsMutex.RUnlock()
+	fake.getExpiredFolderVersionsMutex.RLock()
+	defer fake.getExpiredFolderVersionsMutex.RUnlock()
 	fake.getFolderVersionsMutex.RLock()
 	defer fake.getFolderVersionsMutex.RUnlock()
 	fake.getMtimeMappingMutex.RLock()
 	defer fake.getMtimeMappingMutex.RUnlock()
 	fake.globalDirectoryTreeMutex.RLock()
 	defer fake.globalDirectoryTreeMutex.RUnlock()
+	fake.hydrateMutex.RLock()
+	defer fake.hydrateMutex.RUnlock()
 	fake.indexMutex.RLock()
 	defer fake.indexMutex.RUnlock()
 	fake.indexUpdateMutex.RLock()
@@ -3366,6 +3903,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.restoreFolderVersionsMutex.RUnlock()
 	fake.revertMutex.RLock()
 	defer fake.revertMutex.RUnlock()
+	fake.rollbackFolderMutex.RLock()
+	defer fake.rollbackFolderMutex.RUnlock()
 	fake.scanFolderMutex.RLock()
 	defer fake.scanFolderMutex.RUnlock()
 	fake.scanFolderSubdirsMutex.RLock()
@@ -3380,6 +3919,8 @@ func (fake *Model) Invocations() map[string][][]interface{} {
 	defer fake.stateMutex.RUnlock()
 	fake.usageReportingStatsMutex.RLock()
 	defer fake.usageReportingStatsMutex.RUnlock()
+	fake.verifyDatabaseMutex.RLock()
+	defer fake.verifyDatabaseMutex.RUnlock()
 	fake.watchErrorMutex.RLock()
 	defer fake.watchErrorMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
@@ -26,6 +26,7 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/thejerf/suture/v4"
 
 	"github.com/syncthing/syncthing/lib/build"
@@ -52,6 +53,8 @@ type service interface {
 	BringToFront(string)
 	Override()
 	Revert()
+	Hydrate(sub string) error
+	ConfirmMassChange()
 	DelayScan(d time.Duration)
 	ScheduleScan()
 	SchedulePull()                                    // something relevant changed, we should try a pull
@@ -82,18 +85,25 @@ type Model interface {
 	ScanFolderSubdirs(folder string, subs []string) error
 	State(folder string) (string, time.Time, error)
 	FolderErrors(folder string) ([]FileError, error)
+	FolderConflicts(folder string) ([]db.ConflictRecord, error)
 	WatchError(folder string) error
 	Override(folder string)
 	Revert(folder string)
+	Hydrate(folder, sub string) error
+	ConfirmMassChange(folder string) error
 	BringToFront(folder, file string)
 	LoadIgnores(folder string) ([]string, []string, error)
 	CurrentIgnores(folder string) ([]string, []string, error)
 	SetIgnores(folder string, content []string) error
 
 	GetFolderVersions(folder string) (map[string][]versioner.FileVersion, error)
+	GetExpiredFolderVersions(folder string) (map[string][]versioner.FileVersion, error)
 	RestoreFolderVersions(folder string, versions map[string]time.Time) (map[string]error, error)
+	RollbackFolder(folder, sub string, at time.Time, dryRun bool) (map[string]RollbackChange, error)
 
 	DBSnapshot(folder string) (*db.Snapshot, error)
+	VerifyDatabase(ctx context.Context, folder string) (DatabaseVerifyResult, error)
+	DatabaseVerifyResults() map[string]DatabaseVerifyResult
 	NeedFolderFiles(folder string, page, perpage int) ([]protocol.FileInfo, []protocol.FileInfo, []protocol.FileInfo, error)
 	RemoteNeedFolderFiles(folder string, device protocol.DeviceID, page, perpage int) ([]protocol.FileInfo, error)
 	LocalChangedFolderFiles(folder string, page, perpage int) ([]protocol.FileInfo, error)
@@ -118,7 +128,7 @@ type Model interface {
 
 	GlobalDirectoryTree(folder, prefix string, levels int, dirsOnly bool) ([]*TreeEntry, error)
 
-	RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, fromTemporary bool) ([]byte, error)
+	RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, hashAlgorithm protocol.HashAlgorithm, fromTemporary bool) ([]byte, error)
 }
 
 type model struct {
@@ -134,6 +144,7 @@ type model struct {
 	// constant or concurrency safe fields
 	finder          *db.BlockFinder
 	progressEmitter *ProgressEmitter
+	dbVerifier      *dbVerifier
 	shortID         protocol.ShortID
 	// globalRequestLimiter limits the amount of data in concurrent incoming
 	// requests
@@ -165,6 +176,7 @@ type model struct {
 	helloMessages                  map[protocol.DeviceID]protocol.Hello
 	deviceDownloads                map[protocol.DeviceID]*deviceDownloadState
 	remoteFolderStates             map[protocol.DeviceID]map[string]remoteFolderState // deviceID -> folders
+	remoteFolderOptions            map[protocol.DeviceID]map[string]protocol.Folder   // deviceID -> folders as announced, for negotiating options
 	indexHandlers                  *serviceMap[protocol.DeviceID, *indexHandlerRegistry]
 
 	// for testing only
@@ -184,6 +196,7 @@ var (
 	ErrFolderNotRunning = errors.New("folder is not running")
 	ErrFolderMissing    = errors.New("no such folder")
 	errNoVersioner      = errors.New("folder has no versioner")
+	errNotOnDemand      = errors.New("folder is not an on-demand folder")
 	// errors about why a connection is closed
 	errStopped                            = errors.New("Syncthing is being stopped")
 	errEncryptionInvConfigLocal           = errors.New("can't encrypt outgoing data because local data is encrypted (folder-type receive-encrypted)")
@@ -243,6 +256,7 @@ func NewModel(cfg config.Wrapper, id protocol.DeviceID, ldb *db.Lowlevel, protec
 		helloMessages:                  make(map[protocol.DeviceID]protocol.Hello),
 		deviceDownloads:                make(map[protocol.DeviceID]*deviceDownloadState),
 		remoteFolderStates:             make(map[protocol.DeviceID]map[string]remoteFolderState),
+		remoteFolderOptions:            make(map[protocol.DeviceID]map[string]protocol.Folder),
 		indexHandlers:                  newServiceMap[protocol.DeviceID, *indexHandlerRegistry](evLogger),
 	}
 	for devID, cfg := range cfg.Devices() {
@@ -250,7 +264,9 @@ func NewModel(cfg config.Wrapper, id protocol.DeviceID, ldb *db.Lowlevel, protec
 		m.setConnRequestLimitersLocked(cfg)
 	}
 	m.Add(m.folderRunners)
+	m.dbVerifier = newDBVerifier(m)
 	m.Add(m.progressEmitter)
+	m.Add(m.dbVerifier)
 	m.Add(m.indexHandlers)
 	m.Add(svcutil.AsService(m.serve, m.String()))
 
@@ -270,6 +286,15 @@ func (m *model) serve(ctx context.Context) error {
 
 	close(m.started)
 
+	// There is usually one model per process; should there be more, only
+	// the first one gets its device metrics collected.
+	collector := &deviceMetricsCollector{model: m}
+	if err := prometheus.Register(collector); err != nil {
+		l.Debugln(m, "not registering device metrics:", err)
+	} else {
+		defer prometheus.Unregister(collector)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -481,6 +506,8 @@ func (m *model) removeFolder(cfg config.FolderConfiguration) {
 
 	m.mut.Unlock()
 
+	deleteFolderMetrics(cfg.ID)
+
 	// Remove it from the database
 	db.DropFolder(m.db, cfg.ID)
 }
@@ -530,6 +557,11 @@ func (m *model) restartFolder(from, to config.FolderConfiguration, cacheIgnoredF
 	fsetNil := fset == nil
 
 	m.cleanupFolderLocked(from)
+	for _, dev := range from.Devices {
+		if !to.SharedWith(dev.DeviceID) {
+			deleteFolderDeviceMetrics(prometheus.Labels{"folder": folder, "device": dev.DeviceID.String()})
+		}
+	}
 	if !to.Paused {
 		if fsetNil {
 			// Create a new fset. Might take a while and we do it under
@@ -763,6 +795,8 @@ func (m *model) ConnectionStats() map[string]interface{} {
 				}
 				cs.InBytesTotal += sec.InBytesTotal
 				cs.OutBytesTotal += sec.OutBytesTotal
+				cs.InBytesDecompressed += sec.InBytesDecompressed
+				cs.OutBytesUncompressed += sec.OutBytesUncompressed
 				cs.Secondary = append(cs.Secondary, sec)
 			}
 		}
@@ -967,6 +1001,25 @@ func (m *model) DBSnapshot(folder string) (*db.Snapshot, error) {
 	return rf.Snapshot()
 }
 
+// VerifyDatabase verifies and repairs the database of the given folder
+// right away.
+func (m *model) VerifyDatabase(ctx context.Context, folder string) (DatabaseVerifyResult, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	rf := m.folderFiles[folder]
+	m.mut.RUnlock()
+	if err != nil {
+		return DatabaseVerifyResult{}, err
+	}
+	return m.dbVerifier.verify(ctx, folder, rf), nil
+}
+
+// DatabaseVerifyResults returns the result of the last database
+// verification per folder.
+func (m *model) DatabaseVerifyResults() map[string]DatabaseVerifyResult {
+	return m.dbVerifier.Results()
+}
+
 func (m *model) FolderProgressBytesCompleted(folder string) int64 {
 	return m.progressEmitter.BytesCompleted(folder)
 }
@@ -1277,10 +1330,36 @@ func (m *model) ClusterConfig(conn protocol.Connection, cm *protocol.ClusterConf
 		return err
 	}
 
+	folderOptions := make(map[string]protocol.Folder, len(cm.Folders))
+	for _, folder := range cm.Folders {
+		folder.Devices = nil
+		folderOptions[folder.ID] = folder
+	}
+
 	m.mut.Lock()
+	prevFolderOptions := m.remoteFolderOptions[deviceID]
 	m.remoteFolderStates[deviceID] = states
+	m.remoteFolderOptions[deviceID] = folderOptions
 	m.mut.Unlock()
 
+	// Files hashed in a way the device can't use are hashed again by the
+	// next scan, which we don't wait for. Sharing a folder with a device
+	// restarts the folder, which scans anyway.
+	for id, folder := range folderOptions {
+		if prev, ok := prevFolderOptions[id]; ok && prev.ContentDefinedChunking == folder.ContentDefinedChunking && prev.HashAlgorithm == folder.HashAlgorithm {
+			continue
+		}
+		if fcfg, ok := m.cfg.Folder(id); !ok || !fcfg.SharedWith(deviceID) || !lacksBlockFormat(fcfg, folder) {
+			continue
+		}
+		m.mut.RLock()
+		runner, ok := m.folderRunners.Get(id)
+		m.mut.RUnlock()
+		if ok {
+			runner.ScheduleScan()
+		}
+	}
+
 	m.evLogger.Log(events.ClusterConfigReceived, ClusterConfigReceivedEventData{
 		Device: deviceID,
 	})
@@ -2036,7 +2115,8 @@ func (m *model) Request(conn protocol.Connection, req *protocol.Request) (out pr
 			return nil, protocol.ErrNoSuchFile
 		}
 		_, err := readOffsetIntoBuf(folderFs, tempFn, req.Offset, res.data)
-		if err == nil && scanner.Validate(res.data, req.Hash, req.WeakHash) {
+		if err == nil && scanner.Validate(res.data, req.Hash, req.WeakHash, req.HashAlgorithm) {
+			metricFolderDeviceTransferredBytesTotal.WithLabelValues(req.Folder, deviceID.String(), metricDirectionSent).Add(float64(len(res.data)))
 			return res, nil
 		}
 		// Fall through to reading from a non-temp file, just in case the temp
@@ -2065,12 +2145,13 @@ func (m *model) Request(conn protocol.Connection, req *protocol.Request) (out pr
 		return nil, protocol.ErrGeneric
 	}
 
-	if folderCfg.Type != config.FolderTypeReceiveEncrypted && len(req.Hash) > 0 && !scanner.Validate(res.data[:n], req.Hash, req.WeakHash) {
+	if folderCfg.Type != config.FolderTypeReceiveEncrypted && len(req.Hash) > 0 && !scanner.Validate(res.data[:n], req.Hash, req.WeakHash, req.HashAlgorithm) {
 		m.recheckFile(deviceID, req.Folder, req.Name, req.Offset, req.Hash, req.WeakHash)
 		l.Debugf("%v REQ(in) failed validating data: %s: %q / %q o=%d s=%d", m, deviceID.Short(), req.Folder, req.Name, req.Offset, req.Size)
 		return nil, protocol.ErrNoSuchFile
 	}
 
+	metricFolderDeviceTransferredBytesTotal.WithLabelValues(req.Folder, deviceID.String(), metricDirectionSent).Add(float64(len(res.data)))
 	return res, nil
 }
 
@@ -2108,9 +2189,9 @@ func (m *model) recheckFile(deviceID protocol.DeviceID, folder, name string, off
 		return
 	}
 
-	blockIndex := int(offset / int64(cf.BlockSize()))
-	if blockIndex >= len(cf.Blocks) {
-		l.Debugf("%v recheckFile: %s: %q / %q i=%d: block index too far", m, deviceID, folder, name, blockIndex)
+	blockIndex := cf.BlockIndex(offset)
+	if blockIndex < 0 {
+		l.Debugf("%v recheckFile: %s: %q / %q o=%d: no block at offset", m, deviceID, folder, name, offset)
 		return
 	}
 
@@ -2462,14 +2543,19 @@ func (m *model) deviceDidCloseRLocked(deviceID protocol.DeviceID, duration time.
 	}
 }
 
-func (m *model) RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, fromTemporary bool) ([]byte, error) {
+func (m *model) RequestGlobal(ctx context.Context, deviceID protocol.DeviceID, folder, name string, blockNo int, offset int64, size int, hash []byte, weakHash uint32, hashAlgorithm protocol.HashAlgorithm, fromTemporary bool) ([]byte, error) {
 	conn, connOK := m.requestConnectionForDevice(deviceID)
 	if !connOK {
 		return nil, fmt.Errorf("requestGlobal: no connection to device: %s", deviceID.Short())
 	}
 
 	l.Debugf("%v REQ(out): %s (%s): %q / %q b=%d o=%d s=%d h=%x wh=%x ft=%t", m, deviceID.Short(), conn, folder, name, blockNo, offset, size, hash, weakHash, fromTemporary)
-	return conn.Request(ctx, &protocol.Request{Folder: folder, Name: name, BlockNo: blockNo, Offset: offset, Size: size, Hash: hash, WeakHash: weakHash, FromTemporary: fromTemporary})
+	data, err := conn.Request(ctx, &protocol.Request{Folder: folder, Name: name, BlockNo: blockNo, Offset: offset, Size: size, Hash: hash, WeakHash: weakHash, HashAlgorithm: hashAlgorithm, FromTemporary: fromTemporary})
+	if err != nil {
+		return nil, err
+	}
+	metricFolderDeviceTransferredBytesTotal.WithLabelValues(folder, deviceID.String(), metricDirectionReceived).Add(float64(len(data)))
+	return data, nil
 }
 
 // requestConnectionForDevice returns a connection to the given device, to
@@ -2609,12 +2695,14 @@ func (m *model) generateClusterConfigRLocked(device protocol.DeviceID) (*protoco
 		}
 
 		protocolFolder := protocol.Folder{
-			ID:                 folderCfg.ID,
-			Label:              folderCfg.Label,
-			ReadOnly:           folderCfg.Type == config.FolderTypeSendOnly,
-			IgnorePermissions:  folderCfg.IgnorePerms,
-			IgnoreDelete:       folderCfg.IgnoreDelete,
-			DisableTempIndexes: folderCfg.DisableTempIndexes,
+			ID:                     folderCfg.ID,
+			Label:                  folderCfg.Label,
+			ReadOnly:               folderCfg.Type == config.FolderTypeSendOnly,
+			IgnorePermissions:      folderCfg.IgnorePerms,
+			IgnoreDelete:           folderCfg.IgnoreDelete,
+			DisableTempIndexes:     folderCfg.DisableTempIndexes,
+			ContentDefinedChunking: folderCfg.ContentDefinedChunking,
+			HashAlgorithm:          folderCfg.HashAlgorithm.ToProtocol(),
 		}
 
 		fs := m.folderFiles[folderCfg.ID]
@@ -2665,6 +2753,83 @@ func (m *model) generateClusterConfigRLocked(device protocol.DeviceID) (*protoco
 	return message, passwords
 }
 
+// contentDefinedChunking returns whether files in the folder should be
+// hashed into content defined blocks. That requires all devices sharing
+// the folder to have announced it, as older versions expect blocks of the
+// same size.
+func (m *model) contentDefinedChunking(cfg config.FolderConfiguration) bool {
+	if !cfg.ContentDefinedChunking {
+		return false
+	}
+	return m.allRemotesAnnounced(cfg, func(f protocol.Folder) bool {
+		return f.ContentDefinedChunking
+	})
+}
+
+// blockHashAlgorithm returns the algorithm to hash blocks in the folder
+// with. Anything but SHA-256 requires all devices sharing the folder to
+// have announced the same algorithm, as older versions can't verify the
+// blocks otherwise.
+func (m *model) blockHashAlgorithm(cfg config.FolderConfiguration) protocol.HashAlgorithm {
+	algo := cfg.HashAlgorithm.ToProtocol()
+	if algo == protocol.HashAlgorithmSHA256 {
+		return algo
+	}
+	if !m.allRemotesAnnounced(cfg, func(f protocol.Folder) bool {
+		return f.HashAlgorithm == algo
+	}) {
+		return protocol.HashAlgorithmSHA256
+	}
+	return algo
+}
+
+// remoteLacksBlockFormat returns true if another device sharing the folder
+// has announced it without the content defined chunking or hash algorithm
+// it's configured with. Files already hashed that way must then be hashed
+// again, as the device can't use their blocks.
+func (m *model) remoteLacksBlockFormat(cfg config.FolderConfiguration) bool {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	for _, device := range cfg.Devices {
+		if device.DeviceID == m.id {
+			continue
+		}
+		if folder, ok := m.remoteFolderOptions[device.DeviceID][cfg.ID]; ok && lacksBlockFormat(cfg, folder) {
+			return true
+		}
+	}
+	return false
+}
+
+// lacksBlockFormat returns true if the folder as announced by another device
+// doesn't support all of the content defined chunking and hash algorithm
+// it's configured with.
+func lacksBlockFormat(cfg config.FolderConfiguration, folder protocol.Folder) bool {
+	if cfg.ContentDefinedChunking && !folder.ContentDefinedChunking {
+		return true
+	}
+	algo := cfg.HashAlgorithm.ToProtocol()
+	return algo != protocol.HashAlgorithmSHA256 && folder.HashAlgorithm != algo
+}
+
+// allRemotesAnnounced returns true if the folder as announced by all other
+// devices sharing it satisfies the predicate. Devices we haven't talked to
+// since startup count as not satisfying it.
+func (m *model) allRemotesAnnounced(cfg config.FolderConfiguration, pred func(protocol.Folder) bool) bool {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	for _, device := range cfg.Devices {
+		if device.DeviceID == m.id {
+			continue
+		}
+		folder, ok := m.remoteFolderOptions[device.DeviceID][cfg.ID]
+		if !ok || !pred(folder) {
+			return false
+		}
+	}
+	return true
+}
+
 func (m *model) State(folder string) (string, time.Time, error) {
 	m.mut.RLock()
 	runner, ok := m.folderRunners.Get(folder)
@@ -2690,6 +2855,15 @@ func (m *model) FolderErrors(folder string) ([]FileError, error) {
 	return runner.Errors(), nil
 }
 
+// FolderConflicts returns the log of resolved conflicts in the folder,
+// newest first.
+func (m *model) FolderConflicts(folder string) ([]db.ConflictRecord, error) {
+	if _, ok := m.cfg.Folder(folder); !ok {
+		return nil, ErrFolderMissing
+	}
+	return db.NewConflictLog(m.db, folder, maxConflictLogRecords).Records()
+}
+
 func (m *model) WatchError(folder string) error {
 	m.mut.RLock()
 	err := m.checkFolderRunningRLocked(folder)
@@ -2731,12 +2905,42 @@ func (m *model) Revert(folder string) {
 	runner.Revert()
 }
 
+// Hydrate requests the given path of an on-demand folder, including
+// everything below it, to be pulled and kept in sync.
+func (m *model) Hydrate(folder, sub string) error {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	runner, _ := m.folderRunners.Get(folder)
+	m.mut.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return runner.Hydrate(sub)
+}
+
+// ConfirmMassChange lets a folder that holds off pulling because of a mass
+// change go ahead.
+func (m *model) ConfirmMassChange(folder string) error {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	runner, _ := m.folderRunners.Get(folder)
+	m.mut.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	runner.ConfirmMassChange()
+	return nil
+}
+
 type TreeEntry struct {
-	Name     string       `json:"name"`
-	ModTime  time.Time    `json:"modTime"`
-	Size     int64        `json:"size"`
-	Type     string       `json:"type"`
-	Children []*TreeEntry `json:"children,omitempty"`
+	Name        string       `json:"name"`
+	ModTime     time.Time    `json:"modTime"`
+	Size        int64        `json:"size"`
+	Type        string       `json:"type"`
+	Placeholder bool         `json:"placeholder,omitempty"`
+	Children    []*TreeEntry `json:"children,omitempty"`
 }
 
 func findByName(slice []*TreeEntry, name string) *TreeEntry {
@@ -2751,6 +2955,7 @@ func findByName(slice []*TreeEntry, name string) *TreeEntry {
 func (m *model) GlobalDirectoryTree(folder, prefix string, levels int, dirsOnly bool) ([]*TreeEntry, error) {
 	m.mut.RLock()
 	files, ok := m.folderFiles[folder]
+	onDemand := m.folderCfgs[folder].Type == config.FolderTypeOnDemand
 	m.mut.RUnlock()
 	if !ok {
 		return nil, ErrFolderMissing
@@ -2777,6 +2982,7 @@ func (m *model) GlobalDirectoryTree(folder, prefix string, levels int, dirsOnly
 			return true
 		}
 
+		name := f.Name
 		f.Name = strings.Replace(f.Name, prefix, "", 1)
 
 		dir := filepath.Dir(f.Name)
@@ -2802,12 +3008,17 @@ func (m *model) GlobalDirectoryTree(folder, prefix string, levels int, dirsOnly
 			return true
 		}
 
-		parent.Children = append(parent.Children, &TreeEntry{
+		entry := &TreeEntry{
 			Name:    base,
 			Type:    f.Type.String(),
 			ModTime: f.ModTime(),
 			Size:    f.FileSize(),
-		})
+		}
+		if onDemand && f.Type == protocol.FileInfoTypeFile {
+			lf, ok := snap.Get(protocol.LocalDeviceID, name)
+			entry.Placeholder = !ok || lf.IsPlaceholder()
+		}
+		parent.Children = append(parent.Children, entry)
 
 		return true
 	})
@@ -2830,7 +3041,35 @@ func (m *model) GetFolderVersions(folder string) (map[string][]versioner.FileVer
 		return nil, errNoVersioner
 	}
 
-	return ver.GetVersions()
+	versions, err := ver.GetVersions()
+	if err != nil {
+		return nil, err
+	}
+	if err := setVersionOrigins(db.NewLocalVersions(m.db, folder, maxLocalVersionRecords), versions); err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// GetExpiredFolderVersions returns the versions that cleaning the folder's
+// version archive would remove now.
+func (m *model) GetExpiredFolderVersions(folder string) (map[string][]versioner.FileVersion, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	ver := m.folderVersioners[folder]
+	m.mut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if ver == nil {
+		return nil, errNoVersioner
+	}
+	dr, ok := ver.(versioner.DryRunCleaner)
+	if !ok {
+		return nil, versioner.ErrDryRunNotSupported
+	}
+
+	return dr.CleanDryRun(context.Background())
 }
 
 func (m *model) RestoreFolderVersions(folder string, versions map[string]time.Time) (map[string]error, error) {
@@ -2913,7 +3152,7 @@ func (m *model) fileAvailabilityRLocked(cfg config.FolderConfiguration, snap *db
 			continue
 		}
 		_, ok := m.deviceConnIDs[device]
-		if ok {
+		if ok && m.schedulePermitsPull(device) {
 			availabilities = append(availabilities, Availability{ID: device, FromTemporary: false})
 		}
 	}
@@ -2923,13 +3162,36 @@ func (m *model) fileAvailabilityRLocked(cfg config.FolderConfiguration, snap *db
 func (m *model) blockAvailabilityFromTemporaryRLocked(cfg config.FolderConfiguration, file protocol.FileInfo, block protocol.BlockInfo) []Availability {
 	var availabilities []Availability
 	for _, device := range cfg.Devices {
-		if m.deviceDownloads[device.DeviceID].Has(cfg.ID, file.Name, file.Version, int(block.Offset/int64(file.BlockSize()))) {
+		if m.deviceDownloads[device.DeviceID].Has(cfg.ID, file.Name, file.Version, file.BlockIndex(block.Offset)) && m.schedulePermitsPull(device.DeviceID) {
 			availabilities = append(availabilities, Availability{ID: device.DeviceID, FromTemporary: true})
 		}
 	}
 	return availabilities
 }
 
+// fileHeldBackBySchedule returns whether the file could be pulled from a
+// connected device, if not for the sync schedule of the device.
+func (m *model) fileHeldBackBySchedule(cfg config.FolderConfiguration, snap *db.Snapshot, file protocol.FileInfo) bool {
+	m.mut.RLock()
+	defer m.mut.RUnlock()
+	for _, device := range snap.Availability(file.Name) {
+		if state, ok := m.remoteFolderStates[device][cfg.ID]; !ok || state != remoteFolderValid {
+			continue
+		}
+		if _, ok := m.deviceConnIDs[device]; ok && !m.schedulePermitsPull(device) {
+			return true
+		}
+	}
+	return false
+}
+
+// schedulePermitsPull returns whether the sync schedule of the device lets
+// us pull from it at this time.
+func (m *model) schedulePermitsPull(device protocol.DeviceID) bool {
+	cfg, ok := m.cfg.Device(device)
+	return !ok || cfg.Schedule.AllowsPull(time.Now())
+}
+
 // BringToFront bumps the given files priority in the job queue.
 func (m *model) BringToFront(folder, file string) {
 	m.mut.RLock()
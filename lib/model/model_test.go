@@ -222,7 +222,7 @@ func BenchmarkRequestOut(b *testing.B) {
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		data, err := m.RequestGlobal(context.Background(), device1, "default", files[i%n].Name, 0, 0, 32, nil, 0, false)
+		data, err := m.RequestGlobal(context.Background(), device1, "default", files[i%n].Name, 0, 0, 32, nil, 0, protocol.HashAlgorithmSHA256, false)
 		if err != nil {
 			b.Error(err)
 		}
@@ -3560,6 +3560,108 @@ func TestClusterConfigOnFolderUnpause(t *testing.T) {
 	})
 }
 
+func TestContentDefinedChunkingNegotiation(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	fcfg.ContentDefinedChunking = true
+	setFolder(t, w, fcfg)
+	m := setupModel(t, w)
+	defer cleanupModel(m)
+
+	// The option is announced to other devices.
+	cm, _ := m.generateClusterConfig(device1)
+	if len(cm.Folders) != 1 || !cm.Folders[0].ContentDefinedChunking {
+		t.Error("Expected content defined chunking to be announced")
+	}
+
+	// It's not used until the other device announces it too.
+	if m.contentDefinedChunking(fcfg) {
+		t.Error("Expected content defined chunking to be off before the other device announced it")
+	}
+
+	fc := newFakeConnection(device1, m)
+	m.AddConnection(fc, protocol.Hello{})
+	cc := basicClusterConfig(myID, device1, fcfg.ID)
+	m.ClusterConfig(fc, cc)
+	if m.contentDefinedChunking(fcfg) {
+		t.Error("Expected content defined chunking to be off when the other device doesn't use it")
+	}
+
+	cc.Folders[0].ContentDefinedChunking = true
+	m.ClusterConfig(fc, cc)
+	if !m.contentDefinedChunking(fcfg) {
+		t.Error("Expected content defined chunking to be on when all devices use it")
+	}
+
+	fcfg.ContentDefinedChunking = false
+	if m.contentDefinedChunking(fcfg) {
+		t.Error("Expected content defined chunking to be off when disabled locally")
+	}
+}
+
+func TestBlockHashAlgorithmNegotiation(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	fcfg.HashAlgorithm = config.HashAlgorithmBLAKE3
+	setFolder(t, w, fcfg)
+	m := setupModel(t, w)
+	defer cleanupModel(m)
+
+	cm, _ := m.generateClusterConfig(device1)
+	if len(cm.Folders) != 1 || cm.Folders[0].HashAlgorithm != protocol.HashAlgorithmBLAKE3 {
+		t.Error("Expected the hash algorithm to be announced")
+	}
+
+	// SHA-256 is used until the other device announces the same algorithm.
+	if algo := m.blockHashAlgorithm(fcfg); algo != protocol.HashAlgorithmSHA256 {
+		t.Error("Expected SHA-256 before the other device announced anything, got", algo)
+	}
+
+	fc := newFakeConnection(device1, m)
+	m.AddConnection(fc, protocol.Hello{})
+	cc := basicClusterConfig(myID, device1, fcfg.ID)
+	m.ClusterConfig(fc, cc)
+	if algo := m.blockHashAlgorithm(fcfg); algo != protocol.HashAlgorithmSHA256 {
+		t.Error("Expected SHA-256 when the other device uses it, got", algo)
+	}
+
+	cc.Folders[0].HashAlgorithm = protocol.HashAlgorithmBLAKE3
+	m.ClusterConfig(fc, cc)
+	if algo := m.blockHashAlgorithm(fcfg); algo != protocol.HashAlgorithmBLAKE3 {
+		t.Error("Expected BLAKE3 when all devices use it, got", algo)
+	}
+}
+
+func TestRehashForIncompatibleDevice(t *testing.T) {
+	w, fcfg, wCancel := newDefaultCfgWrapper()
+	defer wCancel()
+	fcfg.HashAlgorithm = config.HashAlgorithmBLAKE3
+	setFolder(t, w, fcfg)
+	m := setupModel(t, w)
+	defer cleanupModel(m)
+
+	fc := newFakeConnection(device1, m)
+	m.AddConnection(fc, protocol.Hello{})
+	cc := basicClusterConfig(myID, device1, fcfg.ID)
+	cc.Folders[0].HashAlgorithm = protocol.HashAlgorithmBLAKE3
+	m.ClusterConfig(fc, cc)
+
+	writeFile(t, fcfg.Filesystem(nil), "file", []byte("data"))
+	must(t, m.ScanFolder(fcfg.ID))
+	if f, ok := m.testCurrentFolderFile(fcfg.ID, "file"); !ok || f.HashAlgorithm != protocol.HashAlgorithmBLAKE3 {
+		t.Fatal("Expected the file to be hashed with BLAKE3, got", f)
+	}
+
+	// The other device now uses SHA-256 and can't verify the blocks, so
+	// the unchanged file is hashed again.
+	cc.Folders[0].HashAlgorithm = protocol.HashAlgorithmSHA256
+	m.ClusterConfig(fc, cc)
+	must(t, m.ScanFolder(fcfg.ID))
+	if f, ok := m.testCurrentFolderFile(fcfg.ID, "file"); !ok || f.HashAlgorithm != protocol.HashAlgorithmSHA256 {
+		t.Error("Expected the file to be hashed again with SHA-256, got", f)
+	}
+}
+
 func TestAddFolderCompletion(t *testing.T) {
 	// Empty folders are always 100% complete.
 	comp := newFolderCompletion(db.Counts{}, db.Counts{}, 0, remoteFolderValid)
@@ -4051,6 +4153,42 @@ func TestPendingFolder(t *testing.T) {
 	}
 }
 
+func TestPullOnlyScheduleAvailability(t *testing.T) {
+	m, fc, fcfg, wcfgCancel := setupModelWithConnection(t)
+	defer wcfgCancel()
+	defer cleanupModelAndRemoveDir(m, fcfg.Filesystem(nil).URI())
+
+	contents := []byte("test file contents\n")
+	fc.addFile("foo", 0o644, protocol.FileInfoTypeFile, contents)
+	fc.sendIndexUpdate()
+	file := fc.files[0]
+
+	setSchedule := func(from, to time.Duration) {
+		t.Helper()
+		now := time.Now()
+		window := now.Add(from).Format("15:04") + "-" + now.Add(to).Format("15:04")
+		waiter, err := m.cfg.Modify(func(cfg *config.Configuration) {
+			_, i, _ := cfg.Device(device1)
+			cfg.Devices[i].Schedule = config.Schedule{Windows: []string{window}}
+		})
+		must(t, err)
+		waiter.Wait()
+	}
+
+	setSchedule(-time.Hour, time.Hour)
+	if av := m.testAvailability("default", file, file.Blocks[0]); len(av) != 1 {
+		t.Errorf("Expected the device to be available inside the window, got %v", av)
+	}
+
+	setSchedule(2*time.Hour, 3*time.Hour)
+	if av := m.testAvailability("default", file, file.Blocks[0]); len(av) != 0 {
+		t.Errorf("Expected no pulling from the device outside the window, got %v", av)
+	}
+	if dcfg, _ := m.cfg.Device(device1); dcfg.Paused {
+		t.Error("Expected pull-only schedule not to pause the device")
+	}
+}
+
 func TestDeletedNotLocallyChangedReceiveOnly(t *testing.T) {
 	deletedNotLocallyChanged(t, config.FolderTypeReceiveOnly)
 }
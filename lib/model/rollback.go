@@ -0,0 +1,140 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"time"
+
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+const (
+	// The file is restored from the version that was current at the time.
+	rollbackRestore = "restore"
+	// There is no version of the file from the time, it was created or
+	// changed without versioning since. It is moved to the versions.
+	rollbackArchive = "archive"
+)
+
+// A RollbackChange is what rolling back a folder does to a file.
+type RollbackChange struct {
+	Action string `json:"action"`
+	// The version restored, for the restore action.
+	VersionTime time.Time `json:"versionTime"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// RollbackFolder brings the files in the folder, or in the given
+// subdirectory of it, back to their state at the given time, as far as the
+// versioner has kept it. Files changed or deleted since are restored from
+// their versions, other files modified since are archived. It returns
+// what is done, or what would be done for a dry run.
+func (m *model) RollbackFolder(folder, sub string, at time.Time, dryRun bool) (map[string]RollbackChange, error) {
+	m.mut.RLock()
+	err := m.checkFolderRunningRLocked(folder)
+	fcfg := m.folderCfgs[folder]
+	ver := m.folderVersioners[folder]
+	fset := m.folderFiles[folder]
+	m.mut.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+	if ver == nil {
+		return nil, errNoVersioner
+	}
+
+	versions, err := ver.GetVersions()
+	if err != nil {
+		return nil, err
+	}
+	sub = osutil.NormalizedFilename(sub)
+	for name := range versions {
+		if sub != "" && name != sub && !fs.IsParent(name, sub) {
+			delete(versions, name)
+		}
+	}
+
+	snap, err := fset.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]time.Time)
+	snap.WithPrefixedHaveTruncated(protocol.LocalDeviceID, sub, func(fi protocol.FileInfo) bool {
+		if fi.Type == protocol.FileInfoTypeFile && !fi.IsDeleted() && !fi.IsInvalid() {
+			current[fi.Name] = fi.ModTime()
+		}
+		return true
+	})
+	snap.Release()
+
+	changes := rollbackChanges(versions, current, at)
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	for name, change := range changes {
+		var err error
+		switch change.Action {
+		case rollbackRestore:
+			err = ver.Restore(name, change.VersionTime)
+		case rollbackArchive:
+			err = ver.Archive(name)
+		}
+		if err != nil {
+			change.Error = err.Error()
+			changes[name] = change
+		}
+	}
+	l.Infof("Rolled back %d files in folder %s to %v", len(changes), fcfg.Description(), at)
+
+	// Trigger scan
+	if !fcfg.FSWatcherEnabled {
+		go func() { _ = m.ScanFolder(folder) }()
+	}
+
+	return changes, nil
+}
+
+// rollbackChanges works out how to bring files back to their state at the
+// given time, given their versions and the modification times of the files
+// existing now. The version current at the time is the first one archived
+// after it, provided it had been modified before. Files modified after the
+// time without such a version are archived, so that the folder has nothing
+// from after the time.
+func rollbackChanges(versions map[string][]versioner.FileVersion, current map[string]time.Time, at time.Time) map[string]RollbackChange {
+	changes := make(map[string]RollbackChange)
+	check := func(name string) {
+		if modTime, ok := current[name]; ok && !modTime.After(at) {
+			// Unchanged since.
+			return
+		}
+		var next *versioner.FileVersion
+		for i, version := range versions[name] {
+			if version.VersionTime.After(at) && (next == nil || version.VersionTime.Before(next.VersionTime)) {
+				next = &versions[name][i]
+			}
+		}
+		switch _, exists := current[name]; {
+		case next != nil && !next.ModTime.After(at):
+			changes[name] = RollbackChange{Action: rollbackRestore, VersionTime: next.VersionTime}
+		case exists:
+			changes[name] = RollbackChange{Action: rollbackArchive}
+		}
+	}
+	for name := range current {
+		check(name)
+	}
+	for name := range versions {
+		if _, ok := current[name]; !ok {
+			check(name)
+		}
+	}
+	return changes
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/versioner"
+)
+
+func TestRollbackChanges(t *testing.T) {
+	at := time.Date(2026, 6, 16, 14, 0, 0, 0, time.Local)
+	before := func(h int) time.Time { return at.Add(-time.Duration(h) * time.Hour) }
+	after := func(h int) time.Time { return at.Add(time.Duration(h) * time.Hour) }
+
+	versions := map[string][]versioner.FileVersion{
+		// Changed twice since, the first version archived after the time
+		// is the one to restore.
+		"changed": {
+			{VersionTime: before(1), ModTime: before(5)},
+			{VersionTime: after(2), ModTime: after(1)},
+			{VersionTime: after(1), ModTime: before(1)},
+		},
+		// Deleted since.
+		"deleted": {
+			{VersionTime: after(1), ModTime: before(3)},
+		},
+		// Deleted before the time already.
+		"gone": {
+			{VersionTime: before(1), ModTime: before(3)},
+		},
+		// Changed since, but the version from the time wasn't kept.
+		"unversioned": {
+			{VersionTime: after(2), ModTime: after(1)},
+		},
+		// Unchanged, even though changed and restored before.
+		"unchanged": {
+			{VersionTime: before(2), ModTime: before(3)},
+		},
+	}
+	current := map[string]time.Time{
+		"changed":     after(2),
+		"unversioned": after(3),
+		"unchanged":   before(1),
+		"created":     after(1),
+	}
+
+	changes := rollbackChanges(versions, current, at)
+	expected := map[string]RollbackChange{
+		"changed":     {Action: rollbackRestore, VersionTime: after(1)},
+		"deleted":     {Action: rollbackRestore, VersionTime: after(1)},
+		"unversioned": {Action: rollbackArchive},
+		"created":     {Action: rollbackArchive},
+	}
+	if len(changes) != len(expected) {
+		t.Errorf("got %d changes, expected %d: %v", len(changes), len(expected), changes)
+	}
+	for name, exp := range expected {
+		if got, ok := changes[name]; !ok || got.Action != exp.Action || !got.VersionTime.Equal(exp.VersionTime) {
+			t.Errorf("%s: got %+v, expected %+v", name, got, exp)
+		}
+	}
+}
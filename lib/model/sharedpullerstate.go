@@ -258,7 +258,7 @@ func (s *sharedPullerState) copyDone(block protocol.BlockInfo) {
 	s.mut.Lock()
 	s.copyNeeded--
 	s.updated = time.Now()
-	s.available = append(s.available, int(block.Offset/int64(s.file.BlockSize())))
+	s.available = append(s.available, s.file.BlockIndex(block.Offset))
 	s.availableUpdated = time.Now()
 	l.Debugln("sharedPullerState", s.folder, s.file.Name, "copyNeeded ->", s.copyNeeded)
 	s.mut.Unlock()
@@ -309,7 +309,7 @@ func (s *sharedPullerState) pullDone(block protocol.BlockInfo) {
 	s.mut.Lock()
 	s.pullNeeded--
 	s.updated = time.Now()
-	s.available = append(s.available, int(block.Offset/int64(s.file.BlockSize())))
+	s.available = append(s.available, s.file.BlockIndex(block.Offset))
 	s.availableUpdated = time.Now()
 	l.Debugln("sharedPullerState", s.folder, s.file.Name, "pullNeeded done ->", s.pullNeeded)
 	s.mut.Unlock()
@@ -414,6 +414,13 @@ func (s *sharedPullerState) Progress() *PullerProgress {
 	total := s.reused + s.copyTotal + s.pullTotal
 	done := total - s.copyNeeded - s.pullNeeded
 	file := len(s.file.Blocks)
+	bytesTotal := blocksToSize(total, file, s.file.BlockSize(), s.file.Size)
+	bytesDone := blocksToSize(done, file, s.file.BlockSize(), s.file.Size)
+	if file > 0 && s.file.HasVariableBlocks() {
+		// Assume the blocks are of average size.
+		bytesTotal = int64(total) * s.file.Size / int64(file)
+		bytesDone = int64(done) * s.file.Size / int64(file)
+	}
 	return &PullerProgress{
 		Total:               total,
 		Reused:              s.reused,
@@ -421,8 +428,8 @@ func (s *sharedPullerState) Progress() *PullerProgress {
 		CopiedFromElsewhere: s.copyTotal - s.copyNeeded - s.copyOrigin,
 		Pulled:              s.pullTotal - s.pullNeeded,
 		Pulling:             s.pullNeeded,
-		BytesTotal:          blocksToSize(total, file, s.file.BlockSize(), s.file.Size),
-		BytesDone:           blocksToSize(done, file, s.file.BlockSize(), s.file.Size),
+		BytesTotal:          bytesTotal,
+		BytesDone:           bytesDone,
 	}
 }
 
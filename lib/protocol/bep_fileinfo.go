@@ -11,6 +11,7 @@ import (
 	"crypto/sha256"
 	"encoding/binary"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/syncthing/syncthing/internal/gen/bep"
@@ -23,16 +24,17 @@ const (
 	FlagLocalIgnored     = 1 << 1 // Matches local ignore patterns
 	FlagLocalMustRescan  = 1 << 2 // Doesn't match content on disk, must be rechecked fully
 	FlagLocalReceiveOnly = 1 << 3 // Change detected on receive only folder
+	FlagLocalPlaceholder = 1 << 4 // Known globally but not pulled, in an on-demand folder
 
 	// Flags that should result in the Invalid bit on outgoing updates
-	LocalInvalidFlags = FlagLocalUnsupported | FlagLocalIgnored | FlagLocalMustRescan | FlagLocalReceiveOnly
+	LocalInvalidFlags = FlagLocalUnsupported | FlagLocalIgnored | FlagLocalMustRescan | FlagLocalReceiveOnly | FlagLocalPlaceholder
 
 	// Flags that should result in a file being in conflict with its
 	// successor, due to us not having an up to date picture of its state on
 	// disk.
 	LocalConflictFlags = FlagLocalUnsupported | FlagLocalIgnored | FlagLocalReceiveOnly
 
-	LocalAllFlags = FlagLocalUnsupported | FlagLocalIgnored | FlagLocalMustRescan | FlagLocalReceiveOnly
+	LocalAllFlags = FlagLocalUnsupported | FlagLocalIgnored | FlagLocalMustRescan | FlagLocalReceiveOnly | FlagLocalPlaceholder
 )
 
 // BlockSizes is the list of valid block sizes, from min to max
@@ -58,6 +60,13 @@ const (
 	FileInfoTypeSymlink          = bep.FileInfoType_FILE_INFO_TYPE_SYMLINK
 )
 
+type HashAlgorithm = bep.HashAlgorithm
+
+const (
+	HashAlgorithmSHA256 = bep.HashAlgorithm_HASH_ALGORITHM_SHA256
+	HashAlgorithmBLAKE3 = bep.HashAlgorithm_HASH_ALGORITHM_BLAKE3
+)
+
 type FileInfo struct {
 	Name          string
 	Size          int64
@@ -71,10 +80,11 @@ type FileInfo struct {
 	Encrypted     []byte
 	Platform      PlatformData
 
-	Type         FileInfoType
-	Permissions  uint32
-	ModifiedNs   int32
-	RawBlockSize int32
+	Type          FileInfoType
+	Permissions   uint32
+	ModifiedNs    int32
+	RawBlockSize  int32
+	HashAlgorithm HashAlgorithm
 
 	// The local_flags fields stores flags that are relevant to the local
 	// host only. It is not part of the protocol, doesn't get sent or
@@ -125,6 +135,7 @@ func (f *FileInfo) ToWire(withInternalFields bool) *bep.FileInfo {
 		ModifiedNs:    f.ModifiedNs,
 		BlockSize:     f.RawBlockSize,
 		Platform:      f.Platform.toWire(),
+		HashAlgorithm: f.HashAlgorithm,
 		Deleted:       f.Deleted,
 		Invalid:       f.RawInvalid,
 		NoPermissions: f.NoPermissions,
@@ -195,6 +206,7 @@ type FileInfoWithoutBlocks interface {
 	GetModifiedNs() int32
 	GetBlockSize() int32
 	GetPlatform() *bep.PlatformData
+	GetHashAlgorithm() HashAlgorithm
 	GetLocalFlags() uint32
 	GetVersionHash() []byte
 	GetInodeChangeNs() int64
@@ -221,6 +233,7 @@ func fileInfoFromWireWithBlocks(w FileInfoWithoutBlocks, blocks []BlockInfo) Fil
 		ModifiedNs:    w.GetModifiedNs(),
 		RawBlockSize:  w.GetBlockSize(),
 		Platform:      platformDataFromWire(w.GetPlatform()),
+		HashAlgorithm: w.GetHashAlgorithm(),
 		Deleted:       w.GetDeleted(),
 		RawInvalid:    w.GetInvalid(),
 		NoPermissions: w.GetNoPermissions(),
@@ -286,6 +299,10 @@ func (f FileInfo) IsReceiveOnlyChanged() bool {
 	return f.LocalFlags&FlagLocalReceiveOnly != 0
 }
 
+func (f FileInfo) IsPlaceholder() bool {
+	return f.LocalFlags&FlagLocalPlaceholder != 0
+}
+
 func (f FileInfo) IsDirectory() bool {
 	return f.Type == FileInfoTypeDirectory
 }
@@ -324,6 +341,35 @@ func (f FileInfo) BlockSize() int {
 	return int(f.RawBlockSize)
 }
 
+// HasVariableBlocks returns true if the blocks are not all of the block
+// size (apart from the last one), as is the case with content defined
+// chunking. The block size is then the average block size.
+func (f FileInfo) HasVariableBlocks() bool {
+	blockSize := f.BlockSize()
+	for i, b := range f.Blocks {
+		if b.Size > blockSize || b.Size < blockSize && i < len(f.Blocks)-1 {
+			return true
+		}
+	}
+	return false
+}
+
+// BlockIndex returns the index of the block starting at the given offset,
+// or -1 if there is no such block.
+func (f FileInfo) BlockIndex(offset int64) int {
+	// Blocks of the same size are the common case.
+	if i := int(offset / int64(f.BlockSize())); i < len(f.Blocks) && f.Blocks[i].Offset == offset {
+		return i
+	}
+	i := sort.Search(len(f.Blocks), func(i int) bool {
+		return f.Blocks[i].Offset >= offset
+	})
+	if i < len(f.Blocks) && f.Blocks[i].Offset == offset {
+		return i
+	}
+	return -1
+}
+
 // BlockSize returns the block size to use for the given file size
 func BlockSize(fileSize int64) int {
 	var blockSize int
@@ -523,6 +569,10 @@ func (f *FileInfo) SetUnsupported() {
 	f.setLocalFlags(FlagLocalUnsupported)
 }
 
+func (f *FileInfo) SetPlaceholder() {
+	f.setLocalFlags(FlagLocalPlaceholder)
+}
+
 func (f *FileInfo) SetDeleted(by ShortID) {
 	f.ModifiedBy = by
 	f.Deleted = true
@@ -11,6 +11,7 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"io"
+	"slices"
 	"testing"
 
 	"google.golang.org/protobuf/proto"
@@ -23,6 +24,7 @@ func TestVersion14Hello(t *testing.T) {
 		DeviceName:    "test device",
 		ClientName:    "syncthing",
 		ClientVersion: "v0.14.5",
+		Compressions:  []MessageCompression{MessageCompressionZstd},
 	}
 	msgBuf, err := proto.Marshal(expected.toWire())
 	if err != nil {
@@ -62,6 +64,9 @@ func TestVersion14Hello(t *testing.T) {
 	if res.DeviceName != expected.DeviceName {
 		t.Errorf("incorrect DeviceName %q != expected %q", res.DeviceName, expected.DeviceName)
 	}
+	if !slices.Equal(res.Compressions, expected.Compressions) {
+		t.Errorf("incorrect Compressions %v != expected %v", res.Compressions, expected.Compressions)
+	}
 }
 
 func TestOldHelloMsgs(t *testing.T) {
@@ -27,6 +27,7 @@ type Request struct {
 	FromTemporary bool
 	WeakHash      uint32
 	BlockNo       int
+	HashAlgorithm HashAlgorithm
 }
 
 func (r *Request) toWire() *bep.Request {
@@ -40,6 +41,7 @@ func (r *Request) toWire() *bep.Request {
 		FromTemporary: r.FromTemporary,
 		WeakHash:      r.WeakHash,
 		BlockNo:       int32(r.BlockNo),
+		HashAlgorithm: r.HashAlgorithm,
 	}
 }
 
@@ -54,6 +56,7 @@ func requestFromWire(w *bep.Request) *Request {
 		FromTemporary: w.FromTemporary,
 		WeakHash:      w.WeakHash,
 		BlockNo:       int(w.BlockNo),
+		HashAlgorithm: w.HashAlgorithm,
 	}
 }
 
@@ -0,0 +1,132 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package protocol
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/syncthing/syncthing/internal/gen/bep"
+)
+
+const (
+	CompressionZstdMetadata = bep.Compression_COMPRESSION_ZSTD_METADATA
+	CompressionZstdAlways   = bep.Compression_COMPRESSION_ZSTD_ALWAYS
+)
+
+type MessageCompression = bep.MessageCompression
+
+const (
+	MessageCompressionNone = bep.MessageCompression_MESSAGE_COMPRESSION_NONE
+	MessageCompressionLZ4  = bep.MessageCompression_MESSAGE_COMPRESSION_LZ4
+	MessageCompressionZstd = bep.MessageCompression_MESSAGE_COMPRESSION_ZSTD
+)
+
+// SupportedCompressions are the message compressions we announce in the
+// Hello message. LZ4 is supported by all versions and not announced.
+var SupportedCompressions = []MessageCompression{MessageCompressionZstd}
+
+// NegotiateCompression returns the compression setting to use towards the
+// device that sent the given Hello. Zstandard is only used when the other
+// side has announced support for it; otherwise we fall back to LZ4 for the
+// same set of messages.
+func NegotiateCompression(compression Compression, remote Hello) Compression {
+	if slices.Contains(remote.Compressions, MessageCompressionZstd) {
+		return compression
+	}
+	switch compression {
+	case CompressionZstdMetadata:
+		return CompressionMetadata
+	case CompressionZstdAlways:
+		return CompressionAlways
+	default:
+		return compression
+	}
+}
+
+// messageCompression returns the message compression to use for the given
+// compression setting.
+func messageCompression(compression Compression) MessageCompression {
+	switch compression {
+	case CompressionNever:
+		return MessageCompressionNone
+	case CompressionZstdMetadata, CompressionZstdAlways:
+		return MessageCompressionZstd
+	default:
+		return MessageCompressionLZ4
+	}
+}
+
+var (
+	zstdEncoderOnce sync.Once
+	zstdEncoder     *zstd.Encoder
+	zstdDecoderOnce sync.Once
+	zstdDecoder     *zstd.Decoder
+)
+
+// The encoder and decoder are safe for concurrent use with EncodeAll and
+// DecodeAll, so a single instance of each is shared by all connections.
+
+func getZstdEncoder() *zstd.Encoder {
+	zstdEncoderOnce.Do(func() {
+		// Can only fail on invalid options
+		zstdEncoder, _ = zstd.NewWriter(nil, zstd.WithEncoderLevel(zstd.SpeedDefault))
+	})
+	return zstdEncoder
+}
+
+func getZstdDecoder() *zstd.Decoder {
+	zstdDecoderOnce.Do(func() {
+		// Can only fail on invalid options
+		zstdDecoder, _ = zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(MaxMessageLen))
+	})
+	return zstdDecoder
+}
+
+// zstdCompress compresses src into buf, which must be large enough to hold
+// the result. Like for LZ4, the compressed data is prefixed by the size of
+// the uncompressed data.
+func zstdCompress(src, buf []byte) (int, error) {
+	dst := buf[4:4:len(buf)]
+	out := getZstdEncoder().EncodeAll(src, dst)
+	if len(out) > cap(dst) {
+		// The encoder had to allocate, so the result doesn't fit.
+		return -1, errNotCompressible
+	}
+
+	binary.BigEndian.PutUint32(buf, uint32(len(src)))
+
+	return len(out) + 4, nil
+}
+
+func zstdDecompress(src []byte) ([]byte, error) {
+	if len(src) < 4 {
+		return nil, errors.New("compressed message too short")
+	}
+	size := binary.BigEndian.Uint32(src)
+	if size > MaxMessageLen {
+		return nil, fmt.Errorf("decompressed size %d exceeds maximum %d", size, MaxMessageLen)
+	}
+	buf := BufferPool.Get(int(size))
+
+	out, err := getZstdDecoder().DecodeAll(src[4:], buf[:0])
+	if err != nil {
+		BufferPool.Put(buf)
+		return nil, err
+	}
+	if len(out) != int(size) {
+		BufferPool.Put(buf)
+		return nil, fmt.Errorf("decompressed size %d does not match announced size %d", len(out), size)
+	}
+
+	return out, nil
+}
@@ -232,13 +232,14 @@ func (e encryptedConnection) Request(ctx context.Context, req *Request) ([]byte,
 	// Perform that request, getting back an encrypted block.
 
 	encReq := &Request{
-		ID:      req.ID,
-		Folder:  req.Folder,
-		Name:    encName,
-		Offset:  encOffset,
-		Size:    encSize,
-		Hash:    encHash,
-		BlockNo: req.BlockNo,
+		ID:            req.ID,
+		Folder:        req.Folder,
+		Name:          encName,
+		Offset:        encOffset,
+		Size:          encSize,
+		Hash:          encHash,
+		BlockNo:       req.BlockNo,
+		HashAlgorithm: req.HashAlgorithm,
 	}
 	bs, err := e.conn.Request(ctx, encReq)
 	if err != nil {
@@ -369,6 +370,9 @@ func encryptFileInfo(keyGen *KeyGenerator, fi FileInfo, folderKey *[keySize]byte
 		enc.Size = offset // new total file size
 		enc.Blocks = blocks
 		enc.RawBlockSize = int32(fi.BlockSize() + blockOverhead)
+		// The untrusted device passes this on in its requests, so that
+		// we know how to verify the decrypted hash.
+		enc.HashAlgorithm = fi.HashAlgorithm
 	}
 
 	return enc
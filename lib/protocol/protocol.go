@@ -25,6 +25,7 @@ import (
 	"path"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	lz4 "github.com/pierrec/lz4/v4"
@@ -186,7 +187,9 @@ type rawConnection struct {
 	closeOnce             sync.Once
 	sendCloseOnce         sync.Once
 	compression           Compression
-	startStopMut          sync.Mutex // start and stop must be serialized
+	sentUncompressed      atomic.Int64 // bytes, before compression
+	recvDecompressed      atomic.Int64 // bytes, after decompression
+	startStopMut          sync.Mutex   // start and stop must be serialized
 
 	loopWG sync.WaitGroup // Need to ensure no leftover routines in testing
 }
@@ -531,13 +534,21 @@ func (c *rawConnection) readMessageAfterHeader(hdr *bep.Header, fourByteBuf []by
 		}
 		buf = decomp
 
+	case bep.MessageCompression_MESSAGE_COMPRESSION_ZSTD:
+		decomp, err := zstdDecompress(buf)
+		BufferPool.Put(buf)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing message: %w", err)
+		}
+		buf = decomp
+
 	default:
 		return nil, fmt.Errorf("unknown message compression %d", hdr.Compression)
 	}
 
 	// ... and is then unmarshalled
 
-	metricDeviceRecvDecompressedBytes.WithLabelValues(c.idString).Add(float64(4 + len(buf)))
+	c.countRecvDecompressed(4 + len(buf))
 
 	msg, err := newMessage(hdr.Type)
 	if err != nil {
@@ -579,7 +590,7 @@ func (c *rawConnection) readHeader(fourByteBuf []byte) (*bep.Header, error) {
 		return nil, fmt.Errorf("unmarshalling header: %w %x", err, buf)
 	}
 
-	metricDeviceRecvDecompressedBytes.WithLabelValues(c.idString).Add(float64(2 + len(buf)))
+	c.countRecvDecompressed(2 + len(buf))
 
 	return &hdr, nil
 }
@@ -789,7 +800,7 @@ func (c *rawConnection) writeMessage(msg proto.Message) error {
 		}
 	}
 
-	metricDeviceSentUncompressedBytes.WithLabelValues(c.idString).Add(float64(totSize))
+	c.countSentUncompressed(totSize)
 
 	// Header length
 	binary.BigEndian.PutUint16(buf, uint16(hdrSize))
@@ -816,7 +827,7 @@ func (c *rawConnection) writeMessage(msg proto.Message) error {
 func (c *rawConnection) writeCompressedMessage(msg proto.Message, marshaled []byte) (ok bool, err error) {
 	hdr := &bep.Header{
 		Type:        typeOf(msg),
-		Compression: bep.MessageCompression_MESSAGE_COMPRESSION_LZ4,
+		Compression: messageCompression(c.compression),
 	}
 	hdrSize := proto.Size(hdr)
 	if hdrSize > 1<<16-1 {
@@ -825,8 +836,6 @@ func (c *rawConnection) writeCompressedMessage(msg proto.Message, marshaled []by
 
 	cOverhead := 2 + hdrSize + 4
 
-	metricDeviceSentUncompressedBytes.WithLabelValues(c.idString).Add(float64(cOverhead + len(marshaled)))
-
 	// The compressed size may be at most n-n/32 = .96875*n bytes,
 	// I.e., if we can't save at least 3.125% bandwidth, we forgo compression.
 	// This number is arbitrary but cheap to compute.
@@ -834,12 +843,20 @@ func (c *rawConnection) writeCompressedMessage(msg proto.Message, marshaled []by
 	buf := BufferPool.Get(maxCompressed)
 	defer BufferPool.Put(buf)
 
-	compressedSize, err := lz4Compress(marshaled, buf[cOverhead:])
+	var compressedSize int
+	switch hdr.Compression {
+	case bep.MessageCompression_MESSAGE_COMPRESSION_ZSTD:
+		compressedSize, err = zstdCompress(marshaled, buf[cOverhead:])
+	default:
+		compressedSize, err = lz4Compress(marshaled, buf[cOverhead:])
+	}
 	totSize := compressedSize + cOverhead
 	if err != nil {
 		return false, nil
 	}
 
+	c.countSentUncompressed(cOverhead + len(marshaled))
+
 	// Header length
 	binary.BigEndian.PutUint16(buf, uint16(hdrSize))
 	// Header
@@ -908,11 +925,11 @@ func (c *rawConnection) shouldCompressMessage(msg proto.Message) bool {
 	case CompressionNever:
 		return false
 
-	case CompressionAlways:
+	case CompressionAlways, CompressionZstdAlways:
 		// Use compression for large enough messages
 		return proto.Size(msg) >= compressionThreshold
 
-	case CompressionMetadata:
+	case CompressionMetadata, CompressionZstdMetadata:
 		_, isResponse := msg.(*bep.Response)
 		// Compress if it's large enough and not a response message
 		return !isResponse && proto.Size(msg) >= compressionThreshold
@@ -1030,21 +1047,54 @@ func (c *rawConnection) pingReceiver() {
 }
 
 type Statistics struct {
-	At            time.Time `json:"at"`
-	InBytesTotal  int64     `json:"inBytesTotal"`
-	OutBytesTotal int64     `json:"outBytesTotal"`
-	StartedAt     time.Time `json:"startedAt"`
+	At                   time.Time `json:"at"`
+	InBytesTotal         int64     `json:"inBytesTotal"`
+	OutBytesTotal        int64     `json:"outBytesTotal"`
+	InBytesDecompressed  int64     `json:"inBytesDecompressed"`
+	OutBytesUncompressed int64     `json:"outBytesUncompressed"`
+	StartedAt            time.Time `json:"startedAt"`
+}
+
+// InCompressionRatio returns how many bytes of messages we received per
+// byte on the wire, i.e. 2 means incoming messages were compressed to half
+// their size. It is 1 when nothing has been received yet.
+func (s Statistics) InCompressionRatio() float64 {
+	return compressionRatio(s.InBytesDecompressed, s.InBytesTotal)
+}
+
+// OutCompressionRatio is like InCompressionRatio, for sent messages.
+func (s Statistics) OutCompressionRatio() float64 {
+	return compressionRatio(s.OutBytesUncompressed, s.OutBytesTotal)
+}
+
+func compressionRatio(uncompressed, wire int64) float64 {
+	if uncompressed == 0 || wire == 0 {
+		return 1
+	}
+	return float64(uncompressed) / float64(wire)
 }
 
 func (c *rawConnection) Statistics() Statistics {
 	return Statistics{
-		At:            time.Now().Truncate(time.Second),
-		InBytesTotal:  c.cr.Tot(),
-		OutBytesTotal: c.cw.Tot(),
-		StartedAt:     c.startTime,
+		At:                   time.Now().Truncate(time.Second),
+		InBytesTotal:         c.cr.Tot(),
+		OutBytesTotal:        c.cw.Tot(),
+		InBytesDecompressed:  c.recvDecompressed.Load(),
+		OutBytesUncompressed: c.sentUncompressed.Load(),
+		StartedAt:            c.startTime,
 	}
 }
 
+func (c *rawConnection) countSentUncompressed(n int) {
+	c.sentUncompressed.Add(int64(n))
+	metricDeviceSentUncompressedBytes.WithLabelValues(c.idString).Add(float64(n))
+}
+
+func (c *rawConnection) countRecvDecompressed(n int) {
+	c.recvDecompressed.Add(int64(n))
+	metricDeviceRecvDecompressedBytes.WithLabelValues(c.idString).Add(float64(n))
+}
+
 func lz4Compress(src, buf []byte) (int, error) {
 	n, err := lz4.CompressBlock(src, buf[4:], nil)
 	if err != nil {
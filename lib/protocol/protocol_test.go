@@ -9,6 +9,7 @@ package protocol
 import (
 	"bytes"
 	"context"
+	"encoding/binary"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
@@ -303,36 +304,56 @@ func testMarshal(t *testing.T, prefix string, m1, m2 proto.Message) bool {
 }
 
 func TestWriteCompressed(t *testing.T) {
-	for _, random := range []bool{false, true} {
-		buf := new(bytes.Buffer)
-		c := &rawConnection{
-			cr:          &countingReader{Reader: buf},
-			cw:          &countingWriter{Writer: buf},
-			compression: CompressionAlways,
-		}
-
-		msg := (&Response{Data: make([]byte, 10240)}).toWire()
-		if random {
-			// This should make the message uncompressible.
-			rand.Read(msg.Data)
-		}
-
-		if err := c.writeMessage(msg); err != nil {
-			t.Fatal(err)
-		}
-		got, err := c.readMessage(make([]byte, 4))
-		if err != nil {
-			t.Fatal(err)
-		}
-		if !bytes.Equal(got.(*bep.Response).Data, msg.Data) {
-			t.Error("received the wrong message")
-		}
-
-		hdr := &bep.Header{Type: typeOf(msg)}
-		size := int64(2 + proto.Size(hdr) + 4 + proto.Size(msg))
-		if c.cr.Tot() > size {
-			t.Errorf("compression enlarged message from %d to %d",
-				size, c.cr.Tot())
+	for _, compression := range []Compression{CompressionAlways, CompressionZstdAlways} {
+		for _, random := range []bool{false, true} {
+			buf := new(bytes.Buffer)
+			c := &rawConnection{
+				cr:          &countingReader{Reader: buf},
+				cw:          &countingWriter{Writer: buf},
+				compression: compression,
+			}
+
+			msg := (&Response{Data: make([]byte, 10240)}).toWire()
+			if random {
+				// This should make the message uncompressible.
+				rand.Read(msg.Data)
+			}
+
+			if err := c.writeMessage(msg); err != nil {
+				t.Fatal(err)
+			}
+			if !random {
+				var hdr bep.Header
+				hdrLen := binary.BigEndian.Uint16(buf.Bytes())
+				if err := proto.Unmarshal(buf.Bytes()[2:2+hdrLen], &hdr); err != nil {
+					t.Fatal(err)
+				}
+				if hdr.Compression != messageCompression(compression) {
+					t.Errorf("%v: message compressed with %v", compression, hdr.Compression)
+				}
+			}
+			got, err := c.readMessage(make([]byte, 4))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got.(*bep.Response).Data, msg.Data) {
+				t.Error("received the wrong message")
+			}
+
+			hdr := &bep.Header{Type: typeOf(msg)}
+			size := int64(2 + proto.Size(hdr) + 4 + proto.Size(msg))
+			if c.cr.Tot() > size {
+				t.Errorf("compression enlarged message from %d to %d",
+					size, c.cr.Tot())
+			}
+
+			stats := c.Statistics()
+			if stats.OutBytesUncompressed != stats.InBytesDecompressed || stats.OutBytesUncompressed < int64(proto.Size(msg)) {
+				t.Errorf("unexpected uncompressed byte counts, %d out and %d in", stats.OutBytesUncompressed, stats.InBytesDecompressed)
+			}
+			if ratio := stats.OutCompressionRatio(); random && ratio > 1 || !random && ratio < 10 {
+				t.Errorf("unexpected compression ratio %f (random data %v)", ratio, random)
+			}
 		}
 	}
 }
@@ -398,6 +419,75 @@ func TestLZ4CompressionUpdate(t *testing.T) {
 	}
 }
 
+func TestZstdCompression(t *testing.T) {
+	for i := 0; i < 10; i++ {
+		dataLen := 150 + rand.Intn(150)
+		data := make([]byte, dataLen)
+		_, err := io.ReadFull(rand.Reader, data[100:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		comp := make([]byte, 4+dataLen)
+		compLen, err := zstdCompress(data, comp)
+		if err != nil {
+			t.Errorf("compressing %d bytes: %v", dataLen, err)
+			continue
+		}
+
+		res, err := zstdDecompress(comp[:compLen])
+		if err != nil {
+			t.Errorf("decompressing %d bytes to %d: %v", compLen, dataLen, err)
+			continue
+		}
+		if !bytes.Equal(data, res) {
+			t.Error("Incorrect decompressed data")
+		}
+	}
+
+	// Data that doesn't fit in the buffer when compressed is rejected.
+	data := make([]byte, 1000)
+	rand.Read(data)
+	if _, err := zstdCompress(data, make([]byte, 4+len(data)/2)); err != errNotCompressible {
+		t.Error("expected incompressible data to be rejected, got", err)
+	}
+
+	// As is a size prefix that doesn't match the data.
+	comp := make([]byte, 4+len(data)+64)
+	n, err := zstdCompress(data, comp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	binary.BigEndian.PutUint32(comp, uint32(len(data)-1))
+	if _, err := zstdDecompress(comp[:n]); err == nil {
+		t.Error("expected mismatching size to be rejected")
+	}
+}
+
+func TestNegotiateCompression(t *testing.T) {
+	zstd := Hello{Compressions: SupportedCompressions}
+	old := Hello{}
+
+	cases := []struct {
+		local  Compression
+		remote Hello
+		res    Compression
+	}{
+		{CompressionZstdAlways, zstd, CompressionZstdAlways},
+		{CompressionZstdMetadata, zstd, CompressionZstdMetadata},
+		{CompressionZstdAlways, old, CompressionAlways},
+		{CompressionZstdMetadata, old, CompressionMetadata},
+		{CompressionAlways, zstd, CompressionAlways},
+		{CompressionNever, zstd, CompressionNever},
+		{CompressionNever, old, CompressionNever},
+	}
+	for _, tc := range cases {
+		if res := NegotiateCompression(tc.local, tc.remote); res != tc.res {
+			t.Errorf("NegotiateCompression(%v, %v) = %v, expected %v", tc.local, tc.remote.Compressions, res, tc.res)
+		}
+	}
+}
+
 func TestCheckFilename(t *testing.T) {
 	cases := []struct {
 		name string
@@ -9,6 +9,7 @@ package scanner
 import (
 	"context"
 	"errors"
+	"io"
 
 	"github.com/syncthing/syncthing/lib/fs"
 	"github.com/syncthing/syncthing/lib/protocol"
@@ -16,7 +17,21 @@ import (
 )
 
 // HashFile hashes the files and returns a list of blocks representing the file.
-func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path string, blockSize int, counter Counter, useWeakHashes bool) ([]protocol.BlockInfo, error) {
+func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path string, blockSize int, counter Counter, useWeakHashes bool, algo protocol.HashAlgorithm) ([]protocol.BlockInfo, error) {
+	return hashFile(ctx, folderID, fs, path, func(r io.Reader, size int64) ([]protocol.BlockInfo, error) {
+		return Blocks(ctx, r, blockSize, size, counter, useWeakHashes, algo)
+	})
+}
+
+// HashFileCDC is like HashFile, but with content defined block boundaries
+// around the given average block size.
+func HashFileCDC(ctx context.Context, folderID string, fs fs.Filesystem, path string, avgBlockSize int, counter Counter, algo protocol.HashAlgorithm) ([]protocol.BlockInfo, error) {
+	return hashFile(ctx, folderID, fs, path, func(r io.Reader, size int64) ([]protocol.BlockInfo, error) {
+		return CDCBlocks(ctx, r, avgBlockSize, size, counter, algo)
+	})
+}
+
+func hashFile(ctx context.Context, folderID string, fs fs.Filesystem, path string, blocksFn func(io.Reader, int64) ([]protocol.BlockInfo, error)) ([]protocol.BlockInfo, error) {
 	fd, err := fs.Open(path)
 	if err != nil {
 		l.Debugln("open:", err)
@@ -36,7 +51,7 @@ func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path strin
 
 	// Hash the file. This may take a while for large files.
 
-	blocks, err := Blocks(ctx, fd, blockSize, size, counter, useWeakHashes)
+	blocks, err := blocksFn(fd, size)
 	if err != nil {
 		l.Debugln("blocks:", err)
 		return nil, err
@@ -64,24 +79,28 @@ func HashFile(ctx context.Context, folderID string, fs fs.Filesystem, path strin
 // workers are used in parallel. The outbox will become closed when the inbox
 // is closed and all items handled.
 type parallelHasher struct {
-	folderID string
-	fs       fs.Filesystem
-	outbox   chan<- ScanResult
-	inbox    <-chan protocol.FileInfo
-	counter  Counter
-	done     chan<- struct{}
-	wg       sync.WaitGroup
+	folderID       string
+	fs             fs.Filesystem
+	outbox         chan<- ScanResult
+	inbox          <-chan protocol.FileInfo
+	counter        Counter
+	done           chan<- struct{}
+	contentDefined bool
+	hashAlgorithm  protocol.HashAlgorithm
+	wg             sync.WaitGroup
 }
 
-func newParallelHasher(ctx context.Context, folderID string, fs fs.Filesystem, workers int, outbox chan<- ScanResult, inbox <-chan protocol.FileInfo, counter Counter, done chan<- struct{}) {
+func newParallelHasher(ctx context.Context, folderID string, fs fs.Filesystem, workers int, outbox chan<- ScanResult, inbox <-chan protocol.FileInfo, counter Counter, done chan<- struct{}, contentDefined bool, hashAlgorithm protocol.HashAlgorithm) {
 	ph := &parallelHasher{
-		folderID: folderID,
-		fs:       fs,
-		outbox:   outbox,
-		inbox:    inbox,
-		counter:  counter,
-		done:     done,
-		wg:       sync.NewWaitGroup(),
+		folderID:       folderID,
+		fs:             fs,
+		outbox:         outbox,
+		inbox:          inbox,
+		counter:        counter,
+		done:           done,
+		contentDefined: contentDefined,
+		hashAlgorithm:  hashAlgorithm,
+		wg:             sync.NewWaitGroup(),
 	}
 
 	ph.wg.Add(workers)
@@ -108,7 +127,13 @@ func (ph *parallelHasher) hashFiles(ctx context.Context) {
 				panic("Bug. Asked to hash a directory or a deleted file.")
 			}
 
-			blocks, err := HashFile(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter, true)
+			var blocks []protocol.BlockInfo
+			var err error
+			if ph.contentDefined {
+				blocks, err = HashFileCDC(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter, ph.hashAlgorithm)
+			} else {
+				blocks, err = HashFile(ctx, ph.folderID, ph.fs, f.Name, f.BlockSize(), ph.counter, true, ph.hashAlgorithm)
+			}
 			if err != nil {
 				handleError(ctx, "hashing", f.Name, err, ph.outbox)
 				continue
@@ -116,6 +141,7 @@ func (ph *parallelHasher) hashFiles(ctx context.Context) {
 
 			f.Blocks = blocks
 			f.BlocksHash = protocol.BlocksHash(blocks)
+			f.HashAlgorithm = ph.hashAlgorithm
 
 			// The size we saw when initially deciding to hash the file
 			// might not have been the size it actually had when we hashed
@@ -14,6 +14,8 @@ import (
 	"hash/adler32"
 	"io"
 
+	"github.com/zeebo/blake3"
+
 	"github.com/syncthing/syncthing/lib/protocol"
 )
 
@@ -23,14 +25,44 @@ type Counter interface {
 	Update(bytes int64)
 }
 
+// NewBlockHash returns a new hash for block data with the given algorithm.
+// Unknown algorithms are treated as SHA-256.
+func NewBlockHash(algo protocol.HashAlgorithm) hash.Hash {
+	switch algo {
+	case protocol.HashAlgorithmBLAKE3:
+		return blake3.New()
+	default:
+		return sha256.New()
+	}
+}
+
+// HashBlock returns the hash of the block data with the given algorithm.
+func HashBlock(algo protocol.HashAlgorithm, data []byte) []byte {
+	switch algo {
+	case protocol.HashAlgorithmBLAKE3:
+		hash := blake3.Sum256(data)
+		return hash[:]
+	default:
+		hash := sha256.Sum256(data)
+		return hash[:]
+	}
+}
+
+func hashOfNothing(algo protocol.HashAlgorithm) []byte {
+	if algo == protocol.HashAlgorithmSHA256 {
+		return SHA256OfNothing
+	}
+	return HashBlock(algo, nil)
+}
+
 // Blocks returns the blockwise hash of the reader.
-func Blocks(ctx context.Context, r io.Reader, blocksize int, sizehint int64, counter Counter, useWeakHashes bool) ([]protocol.BlockInfo, error) {
+func Blocks(ctx context.Context, r io.Reader, blocksize int, sizehint int64, counter Counter, useWeakHashes bool, algo protocol.HashAlgorithm) ([]protocol.BlockInfo, error) {
 	if counter == nil {
 		counter = &noopCounter{}
 	}
 
-	hf := sha256.New()
-	const hashLength = sha256.Size
+	hf := NewBlockHash(algo)
+	hashLength := hf.Size()
 
 	var weakHf hash.Hash32 = noopHash{}
 	var multiHf io.Writer = hf
@@ -54,7 +86,7 @@ func Blocks(ctx context.Context, r io.Reader, blocksize int, sizehint int64, cou
 			numBlocks++
 		}
 		blocks = make([]protocol.BlockInfo, 0, numBlocks)
-		hashes = make([]byte, 0, hashLength*numBlocks)
+		hashes = make([]byte, 0, int64(hashLength)*numBlocks)
 	}
 
 	// A 32k buffer is used for copying into the hash function.
@@ -105,7 +137,7 @@ func Blocks(ctx context.Context, r io.Reader, blocksize int, sizehint int64, cou
 		blocks = append(blocks, protocol.BlockInfo{
 			Offset: 0,
 			Size:   0,
-			Hash:   SHA256OfNothing,
+			Hash:   hashOfNothing(algo),
 		})
 	}
 
@@ -113,16 +145,15 @@ func Blocks(ctx context.Context, r io.Reader, blocksize int, sizehint int64, cou
 }
 
 // Validate quickly validates buf against the 32-bit weakHash, if not zero,
-// else against the cryptohash hash, if len(hash)>0. It is satisfied if
-// either hash matches or neither hash is given.
-func Validate(buf, hash []byte, weakHash uint32) bool {
+// else against the cryptohash hash of the given algorithm, if len(hash)>0.
+// It is satisfied if either hash matches or neither hash is given.
+func Validate(buf, hash []byte, weakHash uint32, algo protocol.HashAlgorithm) bool {
 	if weakHash != 0 && adler32.Checksum(buf) == weakHash {
 		return true
 	}
 
 	if len(hash) > 0 {
-		hbuf := sha256.Sum256(buf)
-		return bytes.Equal(hbuf[:], hash)
+		return bytes.Equal(HashBlock(algo, buf), hash)
 	}
 
 	return true
@@ -18,6 +18,8 @@ import (
 	"testing/quick"
 
 	rollingAdler32 "github.com/chmduquesne/rollinghash/adler32"
+	"github.com/zeebo/blake3"
+
 	"github.com/syncthing/syncthing/lib/protocol"
 )
 
@@ -95,7 +97,7 @@ var blocksTestData = []struct {
 func TestBlocks(t *testing.T) {
 	for testNo, test := range blocksTestData {
 		buf := bytes.NewBuffer(test.data)
-		blocks, err := Blocks(context.TODO(), buf, test.blocksize, -1, nil, true)
+		blocks, err := Blocks(context.TODO(), buf, test.blocksize, -1, nil, true, protocol.HashAlgorithmSHA256)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -129,6 +131,38 @@ func TestBlocks(t *testing.T) {
 	}
 }
 
+func TestBlocksBLAKE3(t *testing.T) {
+	data := []byte("contenten")
+	blocks, err := Blocks(context.TODO(), bytes.NewReader(data), 3, -1, nil, false, protocol.HashAlgorithmBLAKE3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 3 {
+		t.Fatalf("Incorrect number of blocks %d != 3", len(blocks))
+	}
+	for i, b := range blocks {
+		expected := blake3.Sum256(data[b.Offset : b.Offset+int64(b.Size)])
+		if !bytes.Equal(b.Hash, expected[:]) {
+			t.Errorf("%d: Incorrect block hash %x != %x", i, b.Hash, expected)
+		}
+		block := data[b.Offset : b.Offset+int64(b.Size)]
+		if !Validate(block, b.Hash, 0, protocol.HashAlgorithmBLAKE3) {
+			t.Errorf("%d: Block failed validation", i)
+		}
+		if Validate(block, b.Hash, 0, protocol.HashAlgorithmSHA256) {
+			t.Errorf("%d: Block passed validation with the wrong algorithm", i)
+		}
+	}
+
+	empty, err := Blocks(context.TODO(), bytes.NewReader(nil), 3, -1, nil, false, protocol.HashAlgorithmBLAKE3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := blake3.Sum256(nil); len(empty) != 1 || !bytes.Equal(empty[0].Hash, expected[:]) {
+		t.Error("Unexpected blocks for empty data", empty)
+	}
+}
+
 func TestAdler32Variants(t *testing.T) {
 	// Verify that the two adler32 functions give matching results for a few
 	// different blocks of data.
@@ -148,7 +182,7 @@ func TestAdler32Variants(t *testing.T) {
 
 		// Make sure whatever we use in Validate matches too resp. this
 		// tests gets adjusted if we ever switch the weak hash algo.
-		return sum1 == sum2 && Validate(data, nil, sum1)
+		return sum1 == sum2 && Validate(data, nil, sum1, protocol.HashAlgorithmSHA256)
 	}
 
 	// protocol block sized data
@@ -195,7 +229,7 @@ func TestAdler32Variants(t *testing.T) {
 				t.Errorf("Mismatch after roll; i=%d, sum1=%08x, sum3=%08x", i, sum1, sum3)
 				break
 			}
-			if !Validate(window, nil, sum1) {
+			if !Validate(window, nil, sum1, protocol.HashAlgorithmSHA256) {
 				t.Errorf("Validation failure after roll; i=%d", i)
 			}
 		}
@@ -238,7 +272,7 @@ func BenchmarkValidate(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		for _, b := range blocks {
-			Validate(b.data, b.hash[:], b.weakhash)
+			Validate(b.data, b.hash[:], b.weakhash, protocol.HashAlgorithmSHA256)
 		}
 	}
 }
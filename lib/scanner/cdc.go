@@ -0,0 +1,161 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/bits"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+// The block boundaries are chosen using FastCDC ("FastCDC: a Fast and
+// Efficient Content-Defined Chunking Approach for Data Deduplication", Xia
+// et al, 2016): a gear hash is rolled over the data and a block ends where
+// the hash has enough zero bits. Up to the average size more zero bits are
+// required than after it, which keeps the block sizes close to the
+// average.
+
+// cdcGear is the gear hash table. It must be the same on all devices for
+// them to find the same block boundaries, so it is generated from a fixed
+// seed rather than randomly.
+var cdcGear = func() [256]uint64 {
+	var gear [256]uint64
+	state := uint64(0x5379_6e63_7468_696e) // "Syncthin"
+	for i := range gear {
+		// splitmix64
+		state += 0x9e3779b97f4a7c15
+		z := state
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		gear[i] = z ^ (z >> 31)
+	}
+	return gear
+}()
+
+type cdcParams struct {
+	min, avg, max int
+	maskS, maskL  uint64
+}
+
+func newCDCParams(avgSize int) cdcParams {
+	avgBits := bits.Len(uint(avgSize)) - 1
+	p := cdcParams{
+		min: avgSize / 4,
+		avg: avgSize,
+		max: avgSize * 4,
+		// The gear hash is shifted left for every byte, so the high bits
+		// depend on the most data.
+		maskS: ^uint64(0) << (64 - (avgBits + 2)),
+		maskL: ^uint64(0) << (64 - (avgBits - 2)),
+	}
+	if p.max > protocol.MaxBlockSize {
+		p.max = protocol.MaxBlockSize
+	}
+	return p
+}
+
+// cut returns the length of the block at the start of data, which is
+// either at least max long or the end of the input.
+func (p cdcParams) cut(data []byte) int {
+	n := len(data)
+	if n <= p.min {
+		return n
+	}
+	if n > p.max {
+		n = p.max
+	}
+	normal := p.avg
+	if normal > n {
+		normal = n
+	}
+
+	var h uint64
+	i := p.min
+	for ; i < normal; i++ {
+		h = h<<1 + cdcGear[data[i]]
+		if h&p.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		h = h<<1 + cdcGear[data[i]]
+		if h&p.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// CDCBlocks returns the blockwise hash of the reader, like Blocks, but with
+// content defined block boundaries. Inserting or removing data then only
+// changes the blocks around the change instead of all blocks after it.
+// Blocks are between a quarter and four times the average size, which must
+// be a power of two. Weak hashes are not calculated, as finding shifted
+// data is what content defined chunking is for.
+func CDCBlocks(ctx context.Context, r io.Reader, avgSize int, sizehint int64, counter Counter, algo protocol.HashAlgorithm) ([]protocol.BlockInfo, error) {
+	if counter == nil {
+		counter = &noopCounter{}
+	}
+
+	params := newCDCParams(avgSize)
+
+	var blocks []protocol.BlockInfo
+	if sizehint >= 0 {
+		r = io.LimitReader(r, sizehint)
+		blocks = make([]protocol.BlockInfo, 0, sizehint/int64(avgSize)+1)
+	}
+
+	buf := make([]byte, params.max)
+	var buffered int
+	var offset int64
+	eof := false
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		if !eof {
+			n, err := io.ReadFull(r, buf[buffered:])
+			buffered += n
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				eof = true
+			} else if err != nil {
+				return nil, err
+			}
+		}
+		if buffered == 0 {
+			break
+		}
+
+		n := params.cut(buf[:buffered])
+		counter.Update(int64(n))
+		blocks = append(blocks, protocol.BlockInfo{
+			Size:   n,
+			Offset: offset,
+			Hash:   HashBlock(algo, buf[:n]),
+		})
+		offset += int64(n)
+
+		buffered = copy(buf, buf[n:buffered])
+	}
+
+	if len(blocks) == 0 {
+		// Empty file
+		blocks = append(blocks, protocol.BlockInfo{
+			Offset: 0,
+			Size:   0,
+			Hash:   hashOfNothing(algo),
+		})
+	}
+
+	return blocks, nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	mrand "math/rand"
+	"testing"
+
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestCDCBlocks(t *testing.T) {
+	const avg = protocol.MinBlockSize
+	data := make([]byte, 8<<20)
+	mrand.New(mrand.NewSource(42)).Read(data)
+
+	blocks, err := CDCBlocks(context.TODO(), bytes.NewReader(data), avg, int64(len(data)), nil, protocol.HashAlgorithmSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var offset int64
+	for i, b := range blocks {
+		if b.Offset != offset {
+			t.Fatalf("block %d at offset %d, expected %d", i, b.Offset, offset)
+		}
+		if b.Size > 4*avg || b.Size < avg/4 && i < len(blocks)-1 {
+			t.Errorf("block %d has size %d outside of bounds", i, b.Size)
+		}
+		hash := sha256.Sum256(data[b.Offset : b.Offset+int64(b.Size)])
+		if !bytes.Equal(hash[:], b.Hash) {
+			t.Errorf("block %d has wrong hash", i)
+		}
+		offset += int64(b.Size)
+	}
+	if offset != int64(len(data)) {
+		t.Fatalf("blocks cover %d bytes, expected %d", offset, len(data))
+	}
+
+	// The block count should be in the ballpark of what the average size
+	// gives.
+	if expected := len(data) / avg; len(blocks) < expected/2 || len(blocks) > expected*2 {
+		t.Errorf("got %d blocks, expected about %d", len(blocks), expected)
+	}
+
+	// Without a size hint the result is the same.
+	again, err := CDCBlocks(context.TODO(), bytes.NewReader(data), avg, -1, nil, protocol.HashAlgorithmSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(protocol.BlocksHash(blocks), protocol.BlocksHash(again)) {
+		t.Error("hashing the same data again gave different blocks")
+	}
+}
+
+func TestCDCBlocksShifted(t *testing.T) {
+	const avg = protocol.MinBlockSize
+	data := make([]byte, 8<<20)
+	mrand.New(mrand.NewSource(42)).Read(data)
+
+	// Insert some bytes close to the start.
+	shifted := make([]byte, 0, len(data)+len("inserted"))
+	shifted = append(shifted, data[:1000]...)
+	shifted = append(shifted, "inserted"...)
+	shifted = append(shifted, data[1000:]...)
+
+	orig, err := CDCBlocks(context.TODO(), bytes.NewReader(data), avg, -1, nil, protocol.HashAlgorithmSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed, err := CDCBlocks(context.TODO(), bytes.NewReader(shifted), avg, -1, nil, protocol.HashAlgorithmSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	have := make(map[string]struct{}, len(orig))
+	for _, b := range orig {
+		have[string(b.Hash)] = struct{}{}
+	}
+	var differ int
+	for _, b := range changed {
+		if _, ok := have[string(b.Hash)]; !ok {
+			differ++
+		}
+	}
+	if differ > 2 {
+		t.Errorf("%d of %d blocks changed after inserting data, expected at most 2", differ, len(changed))
+	}
+}
+
+func TestCDCBlocksEmpty(t *testing.T) {
+	blocks, err := CDCBlocks(context.TODO(), bytes.NewReader(nil), protocol.MinBlockSize, 0, nil, protocol.HashAlgorithmSHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Size != 0 || !bytes.Equal(blocks[0].Hash, SHA256OfNothing) {
+		t.Error("unexpected blocks for empty data", blocks)
+	}
+}
@@ -65,6 +65,16 @@ type Config struct {
 	ScanXattrs bool
 	// Filter for extended attributes
 	XattrFilter XattrFilter
+	// If ContentDefinedChunking is true, files are hashed into variable
+	// length blocks with content defined boundaries.
+	ContentDefinedChunking bool
+	// The algorithm used for block hashes.
+	HashAlgorithm protocol.HashAlgorithm
+	// If RehashIncompatible is true, unchanged files are hashed again if
+	// their blocks can't be used with the above, i.e. they were hashed with
+	// another algorithm than SHA-256 and HashAlgorithm, or have content
+	// defined blocks while ContentDefinedChunking is false.
+	RehashIncompatible bool
 }
 
 type CurrentFiler interface {
@@ -134,7 +144,7 @@ func (w *walker) walk(ctx context.Context) chan ScanResult {
 	// We're not required to emit scan progress events, just kick off hashers,
 	// and feed inputs directly from the walker.
 	if w.ProgressTickIntervalS < 0 {
-		newParallelHasher(ctx, w.Folder, w.Filesystem, w.Hashers, finishedChan, toHashChan, nil, nil)
+		newParallelHasher(ctx, w.Folder, w.Filesystem, w.Hashers, finishedChan, toHashChan, nil, nil, w.ContentDefinedChunking, w.HashAlgorithm)
 		return finishedChan
 	}
 
@@ -168,7 +178,7 @@ func (w *walker) walk(ctx context.Context) chan ScanResult {
 		done := make(chan struct{})
 		progress := newByteCounter()
 
-		newParallelHasher(ctx, w.Folder, w.Filesystem, w.Hashers, finishedChan, realToHashChan, progress, done)
+		newParallelHasher(ctx, w.Folder, w.Filesystem, w.Hashers, finishedChan, realToHashChan, progress, done, w.ContentDefinedChunking, w.HashAlgorithm)
 
 		// A routine which actually emits the FolderScanProgress events
 		// every w.ProgressTicker ticks, until the hasher routines terminate.
@@ -464,7 +474,7 @@ func (w *walker) walkRegular(ctx context.Context, relPath string, info fs.FileIn
 			IgnoreFlags:     w.LocalFlags,
 			IgnoreOwnership: !w.ScanOwnership,
 			IgnoreXattrs:    !w.ScanXattrs,
-		}) {
+		}) && !w.incompatibleBlocks(curFile) {
 			l.Debugln(w, "unchanged:", curFile)
 			return nil
 		}
@@ -492,6 +502,18 @@ func (w *walker) walkRegular(ctx context.Context, relPath string, info fs.FileIn
 	return nil
 }
 
+// incompatibleBlocks returns true if the file must be hashed again, as its
+// blocks can't be used with the current hashing options.
+func (w *walker) incompatibleBlocks(f protocol.FileInfo) bool {
+	if !w.RehashIncompatible {
+		return false
+	}
+	if f.HashAlgorithm != protocol.HashAlgorithmSHA256 && f.HashAlgorithm != w.HashAlgorithm {
+		return true
+	}
+	return !w.ContentDefinedChunking && f.HasVariableBlocks()
+}
+
 func (w *walker) walkDir(ctx context.Context, relPath string, info fs.FileInfo, finishedChan chan<- ScanResult) error {
 	curFile, hasCurFile := w.CurrentFiler.CurrentFile(relPath)
 
@@ -511,7 +533,7 @@ func (w *walker) walkDir(ctx context.Context, relPath string, info fs.FileInfo,
 			IgnoreFlags:     w.LocalFlags,
 			IgnoreOwnership: !w.ScanOwnership,
 			IgnoreXattrs:    !w.ScanXattrs,
-		}) {
+		}) && !w.incompatibleBlocks(curFile) {
 			l.Debugln(w, "unchanged:", curFile)
 			return nil
 		}
@@ -0,0 +1,224 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+// Package schedule pauses and resumes folders and devices according to their
+// configured sync schedules, for schedules in pause mode. Pulling within
+// the windows of pull-only schedules is left to the model; the service
+// only announces their start and end with the usual paused and resumed
+// events.
+package schedule
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/thejerf/suture/v4"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+type Service interface {
+	suture.Service
+	config.Committer
+}
+
+// The schedule only acts when the state a schedule asks for changes, i.e.
+// at the start and end of a window, or when a schedule is added or
+// changed. In between the user is free to pause or resume manually. The
+// change is done by setting the Paused field of the folder or device, so
+// everything else (closing connections, events, the GUI) happens exactly as
+// for a manual pause.
+type service struct {
+	cfg      config.Wrapper
+	evLogger events.Logger
+	changed  chan struct{}
+	last     map[string]state
+	pulling  map[string]bool // whether pull-only schedules allowed pulling
+	now      func() time.Time
+}
+
+type state struct {
+	windows string
+	active  bool
+}
+
+func New(cfg config.Wrapper, evLogger events.Logger) Service {
+	return &service{
+		cfg:      cfg,
+		evLogger: evLogger,
+		changed:  make(chan struct{}, 1),
+		last:     make(map[string]state),
+		pulling:  make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+func (s *service) Serve(ctx context.Context) error {
+	s.cfg.Subscribe(s)
+	defer s.cfg.Unsubscribe(s)
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-timer.C:
+		case <-s.changed:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		now := s.now()
+		s.apply(s.cfg.RawCopy(), now)
+
+		// Windows have a resolution of one minute.
+		timer.Reset(now.Truncate(time.Minute).Add(time.Minute).Sub(now))
+	}
+}
+
+// apply pauses and resumes folders and devices whose scheduled state
+// changed since the last call.
+func (s *service) apply(cfg config.Configuration, now time.Time) {
+	pauseFolders := make(map[string]bool)
+	pauseDevices := make(map[protocol.DeviceID]bool)
+	seen := make(map[string]struct{})
+
+	for _, folder := range cfg.Folders {
+		key := "folder/" + folder.ID
+		seen[key] = struct{}{}
+		if pause, ok := s.transition(key, folder.Schedule, now); ok && pause != folder.Paused {
+			pauseFolders[folder.ID] = pause
+		}
+		if pull, ok := s.pullTransition(key, folder.Schedule, now); ok {
+			logPullTransition(pull, "into folder", folder.Description())
+			eventType := events.FolderPaused
+			if pull {
+				eventType = events.FolderResumed
+			}
+			s.evLogger.Log(eventType, map[string]string{"id": folder.ID, "label": folder.Label, "reason": "schedule"})
+		}
+	}
+	for _, device := range cfg.Devices {
+		key := "device/" + device.DeviceID.String()
+		seen[key] = struct{}{}
+		if pause, ok := s.transition(key, device.Schedule, now); ok && pause != device.Paused {
+			pauseDevices[device.DeviceID] = pause
+		}
+		if pull, ok := s.pullTransition(key, device.Schedule, now); ok {
+			logPullTransition(pull, "from device", device.DeviceID.Short().String())
+			eventType := events.DevicePaused
+			if pull {
+				eventType = events.DeviceResumed
+			}
+			s.evLogger.Log(eventType, map[string]string{"device": device.DeviceID.String(), "reason": "schedule"})
+		}
+	}
+
+	for key := range s.last {
+		if _, ok := seen[key]; !ok {
+			delete(s.last, key)
+		}
+	}
+	for key := range s.pulling {
+		if _, ok := seen[key]; !ok {
+			delete(s.pulling, key)
+		}
+	}
+
+	if len(pauseFolders) == 0 && len(pauseDevices) == 0 {
+		return
+	}
+
+	_, err := s.cfg.Modify(func(cfg *config.Configuration) {
+		for i, folder := range cfg.Folders {
+			if pause, ok := pauseFolders[folder.ID]; ok {
+				logTransition(pause, "folder", folder.Description())
+				cfg.Folders[i].Paused = pause
+			}
+		}
+		for i, device := range cfg.Devices {
+			if pause, ok := pauseDevices[device.DeviceID]; ok {
+				logTransition(pause, "device", device.DeviceID.Short().String())
+				cfg.Devices[i].Paused = pause
+			}
+		}
+	})
+	if err != nil {
+		l.Warnln("Applying schedule:", err)
+	}
+}
+
+// transition returns whether the item should be paused, and true if that
+// differs from what the schedule asked for the last time.
+func (s *service) transition(key string, sched config.Schedule, now time.Time) (bool, bool) {
+	if !sched.IsSet() || sched.Mode != config.ScheduleModePause {
+		delete(s.last, key)
+		return false, false
+	}
+	cur := state{
+		windows: strings.Join(sched.Windows, "\n"),
+		active:  sched.Active(now),
+	}
+	prev, ok := s.last[key]
+	s.last[key] = cur
+	if ok && prev == cur {
+		return false, false
+	}
+	return !cur.active, true
+}
+
+// pullTransition returns whether a pull-only schedule allows pulling, and
+// true if that changed since the last call. A schedule that starts out
+// allowing it is no change, as that's how it is without one.
+func (s *service) pullTransition(key string, sched config.Schedule, now time.Time) (bool, bool) {
+	if !sched.IsSet() || sched.Mode != config.ScheduleModePullOnly {
+		delete(s.pulling, key)
+		return true, false
+	}
+	pull := sched.Active(now)
+	prev, ok := s.pulling[key]
+	s.pulling[key] = pull
+	if !ok {
+		return pull, !pull
+	}
+	return pull, pull != prev
+}
+
+func logTransition(pause bool, what, descr string) {
+	if pause {
+		l.Infof("Pausing %s %s outside of scheduled sync window", what, descr)
+	} else {
+		l.Infof("Resuming %s %s in scheduled sync window", what, descr)
+	}
+}
+
+func logPullTransition(pull bool, what, descr string) {
+	if pull {
+		l.Infof("Pulling %s %s in scheduled sync window", what, descr)
+	} else {
+		l.Infof("Not pulling %s %s outside of scheduled sync window", what, descr)
+	}
+}
+
+func (s *service) CommitConfiguration(_, _ config.Configuration) bool {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (*service) String() string {
+	return "schedule.Service"
+}
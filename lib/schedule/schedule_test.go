@@ -0,0 +1,152 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package schedule
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/events"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+var device1, _ = protocol.DeviceIDFromString("AIR6LPZ-7K4PTTV-UXQSMUU-CPQ5YWH-OEDFIIQ-JUG777G-2YQXXR5-YD6AWQR")
+
+func TestApply(t *testing.T) {
+	cfg := config.New(protocol.LocalDeviceID)
+	cfg.Folders = []config.FolderConfiguration{
+		{ID: "scheduled", Path: t.TempDir(), Schedule: config.Schedule{Windows: []string{"20:00-06:00"}, Mode: config.ScheduleModePause}},
+		{ID: "unscheduled", Path: t.TempDir()},
+		{ID: "pullonly", Path: t.TempDir(), Schedule: config.Schedule{Windows: []string{"20:00-06:00"}}},
+	}
+	cfg.Devices = append(cfg.Devices, config.DeviceConfiguration{
+		DeviceID: device1,
+		Schedule: config.Schedule{Windows: []string{"Mon-Fri 20:00-06:00"}, Mode: config.ScheduleModePause},
+	})
+	w := config.Wrap("", cfg, protocol.LocalDeviceID, events.NoopLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Serve(ctx)
+
+	s := New(w, events.NoopLogger).(*service)
+
+	// 2026-10-12 is a Monday.
+	at := func(d, h int) time.Time {
+		return time.Date(2026, 10, 12+d, h, 0, 0, 0, time.Local)
+	}
+	check := func(folder, unscheduled, device bool) {
+		t.Helper()
+		if fcfg, _ := w.Folder("scheduled"); fcfg.Paused != folder {
+			t.Errorf("Expected scheduled folder paused %v", folder)
+		}
+		if fcfg, _ := w.Folder("unscheduled"); fcfg.Paused != unscheduled {
+			t.Errorf("Expected unscheduled folder paused %v", unscheduled)
+		}
+		// Pull-only schedules are left to the model.
+		if fcfg, _ := w.Folder("pullonly"); fcfg.Paused {
+			t.Error("Expected pull-only folder not to be paused")
+		}
+		if dcfg, _ := w.Device(device1); dcfg.Paused != device {
+			t.Errorf("Expected device paused %v", device)
+		}
+	}
+
+	s.apply(w.RawCopy(), at(0, 12))
+	check(true, false, true)
+
+	s.apply(w.RawCopy(), at(0, 21))
+	check(false, false, false)
+
+	// Manual changes within a window are left alone.
+	setFolderPaused(t, w, "scheduled", true)
+	s.apply(w.RawCopy(), at(0, 22))
+	check(true, false, false)
+
+	s.apply(w.RawCopy(), at(1, 12))
+	check(true, false, true)
+
+	// The device has no window on the weekend.
+	s.apply(w.RawCopy(), at(5, 21))
+	check(false, false, true)
+}
+
+func TestApplyPullOnlyEvents(t *testing.T) {
+	cfg := config.New(protocol.LocalDeviceID)
+	cfg.Folders = []config.FolderConfiguration{
+		{ID: "pullonly", Path: t.TempDir(), Schedule: config.Schedule{Windows: []string{"20:00-06:00"}}},
+	}
+	cfg.Devices = append(cfg.Devices, config.DeviceConfiguration{
+		DeviceID: device1,
+		Schedule: config.Schedule{Windows: []string{"Mon-Fri 20:00-06:00"}},
+	})
+	w := config.Wrap("", cfg, protocol.LocalDeviceID, events.NoopLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go w.Serve(ctx)
+	evLogger := events.NewLogger()
+	go evLogger.Serve(ctx)
+	sub := evLogger.Subscribe(events.FolderPaused | events.FolderResumed | events.DevicePaused | events.DeviceResumed)
+	defer sub.Unsubscribe()
+
+	s := New(w, evLogger).(*service)
+
+	// 2026-10-12 is a Monday.
+	at := func(d, h int) time.Time {
+		return time.Date(2026, 10, 12+d, h, 0, 0, 0, time.Local)
+	}
+	expect := func(types ...events.EventType) {
+		t.Helper()
+		for _, typ := range types {
+			ev, err := sub.Poll(time.Second)
+			if err != nil {
+				t.Fatalf("Expected %v: %v", typ, err)
+			}
+			if ev.Type != typ {
+				t.Errorf("Got %v, expected %v", ev.Type, typ)
+			}
+		}
+		if ev, err := sub.Poll(100 * time.Millisecond); err == nil {
+			t.Errorf("Unexpected event %v", ev.Type)
+		}
+	}
+
+	// Starting outside of the windows is announced, inside isn't.
+	s.apply(w.RawCopy(), at(0, 12))
+	expect(events.FolderPaused, events.DevicePaused)
+	s.apply(w.RawCopy(), at(0, 13))
+	expect()
+	s.apply(w.RawCopy(), at(0, 21))
+	expect(events.FolderResumed, events.DeviceResumed)
+
+	// The device has no window on the weekend.
+	s.apply(w.RawCopy(), at(5, 21))
+	expect(events.DevicePaused)
+
+	if fcfg, _ := w.Folder("pullonly"); fcfg.Paused {
+		t.Error("Expected pull-only folder not to be paused")
+	}
+	if dcfg, _ := w.Device(device1); dcfg.Paused {
+		t.Error("Expected pull-only device not to be paused")
+	}
+}
+
+func setFolderPaused(t *testing.T, w config.Wrapper, id string, paused bool) {
+	t.Helper()
+	waiter, err := w.Modify(func(cfg *config.Configuration) {
+		for i := range cfg.Folders {
+			if cfg.Folders[i].ID == id {
+				cfg.Folders[i].Paused = paused
+			}
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	waiter.Wait()
+}
@@ -61,20 +61,32 @@ func (s *FolderStatisticsReference) GetLastFile() (LastFile, error) {
 
 func (s *FolderStatisticsReference) ReceivedFile(file string, deleted bool) error {
 	l.Debugln("stats.FolderStatisticsReference.ReceivedFile:", s.folder, file)
-	if err := s.ns.PutTime("lastFileAt", time.Now().Truncate(time.Second)); err != nil {
+	return s.SetLastFile(LastFile{
+		At:       time.Now().Truncate(time.Second),
+		Filename: file,
+		Deleted:  deleted,
+	})
+}
+
+func (s *FolderStatisticsReference) SetLastFile(file LastFile) error {
+	if err := s.ns.PutTime("lastFileAt", file.At); err != nil {
 		return err
 	}
-	if err := s.ns.PutString("lastFileName", file); err != nil {
+	if err := s.ns.PutString("lastFileName", file.Filename); err != nil {
 		return err
 	}
-	if err := s.ns.PutBool("lastFileDeleted", deleted); err != nil {
+	if err := s.ns.PutBool("lastFileDeleted", file.Deleted); err != nil {
 		return err
 	}
 	return nil
 }
 
 func (s *FolderStatisticsReference) ScanCompleted() error {
-	return s.ns.PutTime("lastScan", time.Now().Truncate(time.Second))
+	return s.SetLastScanTime(time.Now().Truncate(time.Second))
+}
+
+func (s *FolderStatisticsReference) SetLastScanTime(t time.Time) error {
+	return s.ns.PutTime("lastScan", t)
 }
 
 func (s *FolderStatisticsReference) GetLastScanTime() (time.Time, error) {
@@ -87,6 +99,24 @@ func (s *FolderStatisticsReference) GetLastScanTime() (time.Time, error) {
 	return lastScan, nil
 }
 
+// SetLastVerifyTime records when the database of the folder was last
+// verified.
+func (s *FolderStatisticsReference) SetLastVerifyTime(t time.Time) error {
+	return s.ns.PutTime("lastVerify", t)
+}
+
+// GetLastVerifyTime returns when the database of the folder was last
+// verified, or the zero time if it never was.
+func (s *FolderStatisticsReference) GetLastVerifyTime() (time.Time, error) {
+	lastVerify, ok, err := s.ns.Time("lastVerify")
+	if err != nil {
+		return time.Time{}, err
+	} else if !ok {
+		return time.Time{}, nil
+	}
+	return lastVerify, nil
+}
+
 func (s *FolderStatisticsReference) GetStatistics() (FolderStatistics, error) {
 	lastFile, err := s.GetLastFile()
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/thejerf/suture/v4"
 
 	"github.com/syncthing/syncthing/lib/api"
+	"github.com/syncthing/syncthing/lib/audit"
 	"github.com/syncthing/syncthing/lib/build"
 	"github.com/syncthing/syncthing/lib/config"
 	"github.com/syncthing/syncthing/lib/connections"
@@ -36,6 +37,7 @@ import (
 	"github.com/syncthing/syncthing/lib/model"
 	"github.com/syncthing/syncthing/lib/osutil"
 	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/schedule"
 	"github.com/syncthing/syncthing/lib/svcutil"
 	"github.com/syncthing/syncthing/lib/tlsutil"
 	"github.com/syncthing/syncthing/lib/upgrade"
@@ -127,7 +129,11 @@ func (a *App) startup() error {
 	a.mainService.Add(a.ll)
 
 	if a.opts.AuditWriter != nil {
-		a.mainService.Add(newAuditService(a.opts.AuditWriter, a.evLogger))
+		if a.cfg.Options().AuditFormat == config.AuditFormatEvents {
+			a.mainService.Add(newAuditService(a.opts.AuditWriter, a.evLogger))
+		} else {
+			a.mainService.Add(audit.NewService(a.opts.AuditWriter, a.evLogger, a.cfg))
+		}
 	}
 
 	if a.opts.Verbose {
@@ -192,6 +198,7 @@ func (a *App) startup() error {
 
 	protectedFiles := []string{
 		locations.Get(locations.Database),
+		locations.Get(locations.SQLiteDB),
 		locations.Get(locations.ConfigFile),
 		locations.Get(locations.CertFile),
 		locations.Get(locations.KeyFile),
@@ -249,6 +256,9 @@ func (a *App) startup() error {
 
 	a.mainService.Add(m)
 
+	// Pauses and resumes folders and devices that have a sync schedule.
+	a.mainService.Add(schedule.New(a.cfg, a.evLogger))
+
 	// The TLS configuration is used for both the listening socket and outgoing
 	// connections.
 
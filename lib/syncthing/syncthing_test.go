@@ -8,6 +8,7 @@ package syncthing
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -109,3 +110,44 @@ func TestStartupFail(t *testing.T) {
 		t.Error("Expected error due to db being closed, got", err)
 	}
 }
+
+func TestMigrateDB(t *testing.T) {
+	dir := t.TempDir()
+	levelDBPath := filepath.Join(dir, "index.db")
+	sqlitePath := filepath.Join(dir, "index.sqlite")
+	openLevelDB := func(path string) (backend.Backend, error) { return backend.OpenLevelDB(path, backend.TuningAuto) }
+	openSQLite := func(path string) (backend.Backend, error) { return backend.OpenSQLite(path, backend.TuningAuto) }
+
+	ldb, err := openLevelDB(levelDBPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ldb.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	ldb.Close()
+
+	if err := migrateDB(sqlitePath, openSQLite, levelDBPath, openLevelDB); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(levelDBPath); !os.IsNotExist(err) {
+		t.Error("Migrated database not moved away")
+	}
+	if _, err := os.Stat(levelDBPath + ".migrated"); err != nil {
+		t.Error(err)
+	}
+
+	sdb, err := openSQLite(sqlitePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sdb.Close()
+	if val, err := sdb.Get([]byte("key")); err != nil || string(val) != "value" {
+		t.Errorf("Got %q, %v after migration", val, err)
+	}
+
+	// Nothing more to migrate
+	if err := migrateDB(sqlitePath, openSQLite, levelDBPath, openLevelDB); err != nil {
+		t.Fatal(err)
+	}
+}
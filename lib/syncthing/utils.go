@@ -150,6 +150,84 @@ func copyFile(src, dst string) error {
 	return nil
 }
 
-func OpenDBBackend(path string, tuning config.Tuning) (backend.Backend, error) {
-	return backend.Open(path, backend.Tuning(tuning))
+// OpenDBBackend opens the database with the backend selected in the
+// options. If there is no such database yet but one of the other backend,
+// it's migrated first.
+func OpenDBBackend(opts config.OptionsConfiguration) (backend.Backend, error) {
+	levelDBPath := locations.Get(locations.Database)
+	sqlitePath := locations.Get(locations.SQLiteDB)
+	tuning := backend.Tuning(opts.DatabaseTuning)
+	openLevelDB := func(path string) (backend.Backend, error) { return backend.OpenLevelDB(path, tuning) }
+	openSQLite := func(path string) (backend.Backend, error) { return backend.OpenSQLite(path, tuning) }
+
+	if opts.DatabaseBackend == config.DatabaseBackendSQLite {
+		if err := migrateDB(sqlitePath, openSQLite, levelDBPath, openLevelDB); err != nil {
+			return nil, fmt.Errorf("migrating database to SQLite: %w", err)
+		}
+		return openSQLite(sqlitePath)
+	}
+	if err := migrateDB(levelDBPath, openLevelDB, sqlitePath, openSQLite); err != nil {
+		return nil, fmt.Errorf("migrating database to LevelDB: %w", err)
+	}
+	return openLevelDB(levelDBPath)
+}
+
+// migrateDB copies the database at fromPath to a new one at toPath, unless
+// there already is a database at toPath or there is none at fromPath. The
+// old database is then renamed with a ".migrated" suffix, so that switching
+// back migrates the current data rather than using stale data.
+func migrateDB(toPath string, openTo func(string) (backend.Backend, error), fromPath string, openFrom func(string) (backend.Backend, error)) error {
+	if _, err := os.Stat(toPath); err == nil {
+		return nil
+	}
+	if _, err := os.Stat(fromPath); err != nil {
+		return nil
+	}
+
+	l.Infof("Migrating database from %s to %s", fromPath, toPath)
+	from, err := openFrom(fromPath)
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+
+	// Migrate into a temporary location first, to not leave a partial
+	// database behind when interrupted.
+	tmpPath := toPath + ".tmp"
+	if err := backend.RemoveAll(tmpPath); err != nil {
+		return err
+	}
+	to, err := openTo(tmpPath)
+	if err != nil {
+		return err
+	}
+	n, err := backend.Copy(to, from)
+	if err == nil {
+		if checker, ok := to.(backend.IntegrityChecker); ok {
+			err = checker.CheckIntegrity()
+		}
+	}
+	if cerr := to.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = backend.RemoveAll(tmpPath)
+		return err
+	}
+	if err := from.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, toPath); err != nil {
+		return err
+	}
+	migratedPath := fromPath + ".migrated"
+	if err := backend.RemoveAll(migratedPath); err != nil {
+		return err
+	}
+	if err := os.Rename(fromPath, migratedPath); err != nil {
+		return err
+	}
+	l.Infof("Migrated %d database entries; the old database was kept as %s", n, migratedPath)
+	return nil
 }
@@ -473,7 +473,7 @@ func cpuBenchOnce(ctx context.Context, duration time.Duration, useWeakHash bool,
 	var err error
 	for time.Since(t0) < duration {
 		r := bytes.NewReader(bs)
-		blocksResult, err = scanner.Blocks(ctx, r, protocol.MinBlockSize, int64(len(bs)), nil, useWeakHash)
+		blocksResult, err = scanner.Blocks(ctx, r, protocol.MinBlockSize, int64(len(bs)), nil, useWeakHash, protocol.HashAlgorithmSHA256)
 		if err != nil {
 			return 0 // Context done
 		}
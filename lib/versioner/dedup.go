@@ -0,0 +1,506 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/osutil"
+	"github.com/syncthing/syncthing/lib/protocol"
+	"github.com/syncthing/syncthing/lib/scanner"
+	"github.com/syncthing/syncthing/lib/sync"
+)
+
+func init() {
+	// Register the constructor for this type of versioner with the name "dedup"
+	factories["dedup"] = newDedup
+}
+
+// The directory in the versions directory holding the blocks of all
+// versions, named by their hash.
+const dedupBlocksDir = ".stblocks"
+
+var errBlockMismatch = errors.New("stored block does not match its hash")
+
+// dedup is a versioner that keeps the contents of archived versions as
+// content addressed blocks, so that blocks shared between versions and
+// files are stored only once. Files are split into blocks the same way the
+// scanner does, so the block hashes match those in the index. Each version
+// is described by a manifest, stored where the simple versioner would keep
+// the full copy. Versions expire as for the simple versioner; blocks no
+// longer referenced by any manifest are removed by Clean.
+type dedup struct {
+	keep           int
+	cleanoutDays   int
+	contentDefined bool
+	hashAlgorithm  config.HashAlgorithm
+	folderFs       fs.Filesystem
+	versionsFs     fs.Filesystem
+	mut            sync.Mutex
+}
+
+type dedupManifest struct {
+	ModTime       time.Time            `json:"modTime"`
+	Size          int64                `json:"size"`
+	Permissions   fs.FileMode          `json:"permissions"`
+	HashAlgorithm config.HashAlgorithm `json:"hashAlgorithm"`
+	Blocks        []dedupBlock         `json:"blocks"`
+}
+
+type dedupBlock struct {
+	Size int    `json:"size"`
+	Hash []byte `json:"hash"`
+}
+
+func newDedup(cfg config.FolderConfiguration) Versioner {
+	keep, err := strconv.Atoi(cfg.Versioning.Params["keep"])
+	cleanoutDays, _ := strconv.Atoi(cfg.Versioning.Params["cleanoutDays"])
+	// On error we default to 0, "do not clean out the versioned items"
+
+	if err != nil {
+		keep = 5 // A reasonable default
+	}
+
+	v := &dedup{
+		keep:           keep,
+		cleanoutDays:   cleanoutDays,
+		contentDefined: cfg.ContentDefinedChunking,
+		hashAlgorithm:  cfg.HashAlgorithm,
+		folderFs:       cfg.Filesystem(nil),
+		versionsFs:     versionerFsFromFolderCfg(cfg),
+		mut:            sync.NewMutex(),
+	}
+
+	l.Debugf("instantiated %#v", v)
+	return v
+}
+
+// Archive stores the blocks of the named file that aren't stored yet and
+// records a manifest for it, then removes the file. If this function
+// returns nil, the named file does not exist any more (has been archived).
+func (v *dedup) Archive(filePath string) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if err := v.archive(filePath, filePath, time.Now(), nil); err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filePath), v.toRemove)
+
+	return nil
+}
+
+// ArchiveFile archives the file like Archive, reusing its block hashes
+// instead of hashing it again when they match the file on disk and the
+// folder's hash algorithm.
+func (v *dedup) ArchiveFile(file protocol.FileInfo) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if err := v.archive(file.Name, file.Name, time.Now(), &file); err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, file.Name), v.toRemove)
+
+	return nil
+}
+
+// ArchiveCopy archives the file at copyPath like Archive does, as a version
+// of the named file.
+func (v *dedup) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if err := v.archive(copyPath, filePath, versionTime, nil); err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filePath), v.toRemove)
+
+	return nil
+}
+
+// archive stores the file at srcPath as the version of filePath at the
+// given time. The file is hashed unless known describes it.
+func (v *dedup) archive(srcPath, filePath string, now time.Time, known *protocol.FileInfo) error {
+	srcPath = osutil.NativeFilename(srcPath)
+	filePath = osutil.NativeFilename(filePath)
+	info, err := v.folderFs.Lstat(srcPath)
+	if fs.IsNotExist(err) {
+		l.Debugln("not archiving nonexistent file", srcPath)
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if info.IsSymlink() {
+		panic("bug: attempting to version a symlink")
+	}
+
+	algo := v.hashAlgorithm.ToProtocol()
+	var blocks []protocol.BlockInfo
+	switch {
+	case known != nil && known.HashAlgorithm == algo && known.Size == info.Size() && known.ModTime().Equal(info.ModTime()) && (len(known.Blocks) > 0 || known.Size == 0):
+		// The hashes are checked against the data as the blocks are
+		// stored, in case the file changed regardless.
+		blocks = known.Blocks
+	case v.contentDefined:
+		blocks, err = scanner.HashFileCDC(context.Background(), "", v.folderFs, srcPath, protocol.BlockSize(info.Size()), nil, algo)
+	default:
+		blocks, err = scanner.HashFile(context.Background(), "", v.folderFs, srcPath, protocol.BlockSize(info.Size()), nil, false, algo)
+	}
+	if err != nil {
+		return err
+	}
+
+	manifest := dedupManifest{
+		ModTime:       info.ModTime(),
+		Size:          info.Size(),
+		Permissions:   info.Mode() & fs.ModePerm,
+		HashAlgorithm: v.hashAlgorithm,
+		Blocks:        make([]dedupBlock, 0, len(blocks)),
+	}
+	fd, err := v.folderFs.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	for _, block := range blocks {
+		if err := v.storeBlock(fd, block); err != nil {
+			return err
+		}
+		manifest.Blocks = append(manifest.Blocks, dedupBlock{Size: int(block.Size), Hash: block.Hash})
+	}
+	fd.Close()
+
+	dst := TagFilename(filePath, now.Format(TimeFormat))
+	l.Debugln("archiving", srcPath, "with manifest", dst)
+	if err := v.writeManifest(dst, manifest); err != nil {
+		return err
+	}
+
+	return v.folderFs.Remove(srcPath)
+}
+
+// storeBlock copies the given block from the file to the block store,
+// unless it's already there.
+func (v *dedup) storeBlock(fd fs.File, block protocol.BlockInfo) error {
+	name := blockPath(block.Hash)
+	if _, err := v.versionsFs.Lstat(name); err == nil {
+		return nil
+	} else if !fs.IsNotExist(err) {
+		return err
+	}
+
+	buf := make([]byte, block.Size)
+	if _, err := fd.ReadAt(buf, block.Offset); err != nil {
+		return err
+	}
+	if !bytes.Equal(scanner.HashBlock(v.hashAlgorithm.ToProtocol(), buf), block.Hash) {
+		// The file changed since it was hashed.
+		return errBlockMismatch
+	}
+	return v.writeVersionsFile(name, buf)
+}
+
+func (v *dedup) writeManifest(name string, manifest dedupManifest) error {
+	bs, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	return v.writeVersionsFile(name, bs)
+}
+
+// writeVersionsFile atomically writes data to the named file in the
+// versions directory.
+func (v *dedup) writeVersionsFile(name string, data []byte) error {
+	if err := v.versionsFs.MkdirAll(filepath.Dir(name), 0o755); err != nil && !fs.IsExist(err) {
+		return err
+	}
+	_ = v.versionsFs.Hide(".")
+
+	tempName := fs.TempName(name)
+	fd, err := v.versionsFs.Create(tempName)
+	if err != nil {
+		return err
+	}
+	if _, err := fd.Write(data); err != nil {
+		fd.Close()
+		v.versionsFs.Remove(tempName)
+		return err
+	}
+	if err := fd.Close(); err != nil {
+		v.versionsFs.Remove(tempName)
+		return err
+	}
+	return v.versionsFs.Rename(tempName, name)
+}
+
+func (v *dedup) readManifest(name string) (dedupManifest, error) {
+	var manifest dedupManifest
+	fd, err := v.versionsFs.Open(name)
+	if err != nil {
+		return manifest, err
+	}
+	defer fd.Close()
+	bs, err := io.ReadAll(fd)
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(bs, &manifest); err != nil {
+		return manifest, fmt.Errorf("reading manifest %s: %w", name, err)
+	}
+	return manifest, nil
+}
+
+func (v *dedup) GetVersions() (map[string][]FileVersion, error) {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	files := make(map[string][]FileVersion)
+	err := v.walkManifests(func(path, name string, versionTime time.Time) error {
+		manifest, err := v.readManifest(path)
+		if err != nil {
+			l.Debugln("versioner: skipping version", path, err)
+			return nil
+		}
+		name = osutil.NormalizedFilename(name)
+		files[name] = append(files[name], FileVersion{
+			VersionTime: versionTime,
+			ModTime:     manifest.ModTime.Truncate(time.Second),
+			Size:        manifest.Size,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// walkManifests calls fn for each manifest in the versions directory, with
+// the name of the file and the time it was archived.
+func (v *dedup) walkManifests(fn func(path, name string, versionTime time.Time) error) error {
+	if _, err := v.versionsFs.Stat("."); fs.IsNotExist(err) {
+		return nil
+	}
+	return v.versionsFs.Walk(".", func(path string, f fs.FileInfo, err error) error {
+		if path == "." {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if path == dedupBlocksDir || f.IsSymlink() {
+			return fs.SkipDir
+		}
+		if f.IsDir() || fs.IsTemporary(path) {
+			return nil
+		}
+		name, tag := UntagFilename(path)
+		if name == "" {
+			return nil
+		}
+		versionTime, err := time.ParseInLocation(TimeFormat, tag, time.Local)
+		if err != nil {
+			return nil
+		}
+		return fn(path, name, versionTime)
+	})
+}
+
+// Restore recreates the file from the blocks of the given version, which is
+// then removed from the archive like for the other versioners.
+func (v *dedup) Restore(filePath string, versionTime time.Time) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	filePath = osutil.NativeFilename(filePath)
+	tag := versionTime.In(time.Local).Truncate(time.Second).Format(TimeFormat)
+	manifestPath := TagFilename(filePath, tag)
+	manifest, err := v.readManifest(manifestPath)
+	if fs.IsNotExist(err) {
+		return errNotFound
+	} else if err != nil {
+		return err
+	}
+
+	// If the something already exists where we are restoring to, archive existing file for versioning
+	// remove if it's a symlink, or fail if it's a directory
+	if info, err := v.folderFs.Lstat(filePath); err == nil {
+		switch {
+		case info.IsDir():
+			return ErrDirectory
+		case info.IsSymlink():
+			// Remove existing symlinks (as we don't want to archive them)
+			if err := v.folderFs.Remove(filePath); err != nil {
+				return fmt.Errorf("removing existing symlink: %w", err)
+			}
+		case info.IsRegular():
+			if err := v.archive(filePath, filePath, time.Now(), nil); err != nil {
+				return fmt.Errorf("archiving existing file: %w", err)
+			}
+		default:
+			panic("bug: unknown item type")
+		}
+	} else if !fs.IsNotExist(err) {
+		return err
+	}
+
+	_ = v.folderFs.MkdirAll(filepath.Dir(filePath), 0o755)
+	tempName := fs.TempName(filePath)
+	if err := v.assemble(tempName, manifest); err != nil {
+		v.folderFs.Remove(tempName)
+		return err
+	}
+	if err := v.folderFs.Rename(tempName, filePath); err != nil {
+		v.folderFs.Remove(tempName)
+		return err
+	}
+	_ = v.folderFs.Chtimes(filePath, manifest.ModTime, manifest.ModTime)
+
+	return v.versionsFs.Remove(manifestPath)
+}
+
+// assemble writes the contents described by the manifest to the named file
+// in the folder, verifying each block.
+func (v *dedup) assemble(name string, manifest dedupManifest) error {
+	perms := manifest.Permissions
+	if perms == 0 {
+		perms = 0o644
+	}
+	fd, err := v.folderFs.OpenFile(name, fs.OptReadWrite|fs.OptCreate|fs.OptTruncate, perms)
+	if err != nil {
+		return err
+	}
+	for _, block := range manifest.Blocks {
+		data, err := v.readBlock(block, manifest.HashAlgorithm.ToProtocol())
+		if err != nil {
+			fd.Close()
+			return err
+		}
+		if _, err := fd.Write(data); err != nil {
+			fd.Close()
+			return err
+		}
+	}
+	return fd.Close()
+}
+
+func (v *dedup) readBlock(block dedupBlock, algo protocol.HashAlgorithm) ([]byte, error) {
+	fd, err := v.versionsFs.Open(blockPath(block.Hash))
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != block.Size || !bytes.Equal(scanner.HashBlock(algo, data), block.Hash) {
+		return nil, errBlockMismatch
+	}
+	return data, nil
+}
+
+// Clean expires old versions, then removes the blocks that are no longer
+// referenced by any version.
+func (v *dedup) Clean(ctx context.Context) error {
+	v.mut.Lock()
+	defer v.mut.Unlock()
+
+	if err := clean(ctx, v.versionsFs, v.toRemove); err != nil {
+		return err
+	}
+	return v.collectGarbage(ctx)
+}
+
+func (v *dedup) collectGarbage(ctx context.Context) error {
+	referenced := make(map[string]struct{})
+	err := v.walkManifests(func(path, _ string, _ time.Time) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		manifest, err := v.readManifest(path)
+		if err != nil {
+			// Without knowing which blocks this version needs we can't
+			// safely remove any.
+			return err
+		}
+		for _, block := range manifest.Blocks {
+			referenced[blockPath(block.Hash)] = struct{}{}
+		}
+		return nil
+	})
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			l.Warnln("Versioner: not removing unused blocks:", err)
+		}
+		return err
+	}
+
+	if _, err := v.versionsFs.Lstat(dedupBlocksDir); fs.IsNotExist(err) {
+		return nil
+	}
+	dirTracker := make(emptyDirTracker)
+	err = v.versionsFs.Walk(dedupBlocksDir, func(path string, f fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if f.IsDir() {
+			if path != dedupBlocksDir {
+				dirTracker.addDir(path)
+			}
+			return nil
+		}
+		if _, ok := referenced[path]; ok {
+			dirTracker.addFile(path)
+			return nil
+		}
+		l.Debugln("Versioner: removing unused block", path)
+		if err := v.versionsFs.Remove(path); err != nil {
+			l.Warnf("Versioner: can't remove %q: %v", path, err)
+			dirTracker.addFile(path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	dirTracker.deleteEmptyDirs(v.versionsFs)
+	return nil
+}
+
+func (v *dedup) toRemove(versions []string, now time.Time) []string {
+	return simple{keep: v.keep, cleanoutDays: v.cleanoutDays}.toRemove(versions, now)
+}
+
+// blockPath returns the name of the block with the given hash in the
+// versions directory.
+func blockPath(hash []byte) string {
+	name := hex.EncodeToString(hash)
+	return filepath.Join(dedupBlocksDir, name[:2], name)
+}
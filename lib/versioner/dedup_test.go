@@ -0,0 +1,224 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/protocol"
+)
+
+func TestDedupVersioning(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Test takes some time, skipping.")
+	}
+
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Versioning: config.VersioningConfiguration{
+			Type: "dedup",
+			Params: map[string]string{
+				"keep": "2",
+			},
+		},
+	}
+	folderFs := cfg.Filesystem(nil)
+	v := newDedup(cfg).(*dedup)
+
+	// Three versions of a file of four blocks, differing only in the first
+	// block.
+	blockSize := protocol.MinBlockSize
+	data := make([]byte, 4*blockSize)
+	var contents [][]byte
+	for i := 0; i < 3; i++ {
+		data[0] = byte(i + 1)
+		contents = append(contents, bytes.Clone(data))
+		writeVersionFile(t, folderFs, "file", data)
+		if err := v.Archive("file"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := folderFs.Lstat("file"); !fs.IsNotExist(err) {
+			t.Fatal("expected file to be archived, got", err)
+		}
+		time.Sleep(time.Second)
+	}
+
+	// The first version has expired, but its blocks are only removed by
+	// Clean. Identical blocks are only stored once.
+	versions, err := v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 2 {
+		t.Fatalf("expected two versions, got %v", versions)
+	}
+	if n := countBlocks(t, v.versionsFs); n != 4 {
+		t.Errorf("expected 4 distinct blocks for all versions, got %d", n)
+	}
+	if err := v.Clean(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := countBlocks(t, v.versionsFs); n != 3 {
+		t.Errorf("expected 3 distinct blocks for the remaining versions, got %d", n)
+	}
+
+	// Restoring the older remaining version archives the current file.
+	writeVersionFile(t, folderFs, "file", []byte("current"))
+	oldest := versions["file"][0]
+	for _, ver := range versions["file"] {
+		if ver.VersionTime.Before(oldest.VersionTime) {
+			oldest = ver
+		}
+	}
+	if err := v.Restore("file", oldest.VersionTime); err != nil {
+		t.Fatal(err)
+	}
+	if got := readVersionFile(t, folderFs, "file"); !bytes.Equal(got, contents[1]) {
+		t.Error("restored file has unexpected contents")
+	}
+	versions, err = v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 2 {
+		t.Fatalf("expected two versions after restore, got %v", versions)
+	}
+	for _, ver := range versions["file"] {
+		if ver.VersionTime.Equal(oldest.VersionTime) {
+			t.Error("restored version is still in the archive")
+		}
+	}
+}
+
+func TestDedupRestoreCorruptBlock(t *testing.T) {
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Versioning:     config.VersioningConfiguration{Type: "dedup"},
+	}
+	folderFs := cfg.Filesystem(nil)
+	v := newDedup(cfg).(*dedup)
+
+	writeVersionFile(t, folderFs, "file", []byte("contents"))
+	if err := v.Archive("file"); err != nil {
+		t.Fatal(err)
+	}
+	versions, err := v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 1 {
+		t.Fatalf("expected one version, got %v", versions)
+	}
+
+	// Damage the only block.
+	err = v.versionsFs.Walk(dedupBlocksDir, func(path string, info fs.FileInfo, err error) error {
+		if err == nil && info.IsRegular() {
+			writeVersionFile(t, v.versionsFs, path, []byte("garbage!"))
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v.Restore("file", versions["file"][0].VersionTime); err == nil {
+		t.Fatal("expected restore from a corrupt block to fail")
+	}
+	if _, err := folderFs.Lstat("file"); !fs.IsNotExist(err) {
+		t.Error("expected no file after failed restore, got", err)
+	}
+}
+
+func TestDedupArchiveFileReusesBlocks(t *testing.T) {
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Versioning:     config.VersioningConfiguration{Type: "dedup"},
+	}
+	folderFs := cfg.Filesystem(nil)
+	v := newDedup(cfg).(*dedup)
+
+	writeVersionFile(t, folderFs, "file", []byte("contents"))
+	info, err := folderFs.Lstat("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Hashes that don't match the data show whether the file was hashed
+	// again.
+	file := protocol.FileInfo{
+		Name:          "file",
+		Size:          info.Size(),
+		ModifiedS:     info.ModTime().Unix(),
+		ModifiedNs:    int32(info.ModTime().Nanosecond()),
+		HashAlgorithm: cfg.HashAlgorithm.ToProtocol(),
+		Blocks:        []protocol.BlockInfo{{Size: int(info.Size()), Hash: make([]byte, 32)}},
+	}
+
+	if err := v.ArchiveFile(file); err != errBlockMismatch {
+		t.Fatal("expected the given blocks to be used, got", err)
+	}
+
+	// The file is hashed when the blocks are from another algorithm.
+	file.HashAlgorithm = protocol.HashAlgorithmBLAKE3
+	if err := v.ArchiveFile(file); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := folderFs.Lstat("file"); !fs.IsNotExist(err) {
+		t.Fatal("expected file to be archived, got", err)
+	}
+}
+
+func writeVersionFile(t *testing.T, filesystem fs.Filesystem, name string, data []byte) {
+	t.Helper()
+	fd, err := filesystem.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fd.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := fd.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readVersionFile(t *testing.T, filesystem fs.Filesystem, name string) []byte {
+	t.Helper()
+	fd, err := filesystem.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	data, err := io.ReadAll(fd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func countBlocks(t *testing.T, versionsFs fs.Filesystem) int {
+	t.Helper()
+	n := 0
+	err := versionsFs.Walk(dedupBlocksDir, func(_ string, info fs.FileInfo, err error) error {
+		if err == nil && info.IsRegular() {
+			n++
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
@@ -0,0 +1,313 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"context"
+	"errors"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/fs"
+	"github.com/syncthing/syncthing/lib/osutil"
+)
+
+func init() {
+	// Register the constructor for this type of versioner with the name "retention"
+	factories["retention"] = newRetention
+}
+
+// Parameters with this prefix set the retention policy for the files
+// matching the pattern following it, instead of the "policy" parameter.
+const retentionOverridePrefix = "policy:"
+
+// retention is a versioner that keeps versions like the simple versioner,
+// expiring them according to a retention policy (see retentionPolicy).
+type retention struct {
+	folderFs        fs.Filesystem
+	versionsFs      fs.Filesystem
+	copyRangeMethod fs.CopyRangeMethod
+	policy          retentionPolicy
+	overrides       []retentionOverride // in order of precedence
+}
+
+type retentionOverride struct {
+	pattern string
+	policy  retentionPolicy
+}
+
+// A retentionVersion is a version in the versions directory.
+type retentionVersion struct {
+	path    string
+	name    string
+	time    time.Time
+	modTime time.Time
+	size    int64
+}
+
+func newRetention(cfg config.FolderConfiguration) Versioner {
+	params := cfg.Versioning.Params
+
+	policy, err := parseRetentionPolicy(params["policy"])
+	if err != nil {
+		// Keeping everything is the safe choice.
+		l.Warnf("Versioner: invalid retention policy for folder %s, keeping all versions: %v", cfg.Description(), err)
+		policy = retentionPolicy{}
+	}
+
+	var overrides []retentionOverride
+	for key, val := range params {
+		pattern, ok := strings.CutPrefix(key, retentionOverridePrefix)
+		if !ok {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			l.Warnf("Versioner: invalid retention policy pattern %q for folder %s: %v", pattern, cfg.Description(), err)
+			continue
+		}
+		override, err := parseRetentionPolicy(val)
+		if err != nil {
+			l.Warnf("Versioner: invalid retention policy for %q in folder %s, keeping all versions: %v", pattern, cfg.Description(), err)
+			override = retentionPolicy{}
+		}
+		overrides = append(overrides, retentionOverride{pattern: pattern, policy: override})
+	}
+	// The most specific, i.e. longest, pattern takes precedence.
+	sort.Slice(overrides, func(a, b int) bool {
+		if len(overrides[a].pattern) != len(overrides[b].pattern) {
+			return len(overrides[a].pattern) > len(overrides[b].pattern)
+		}
+		return overrides[a].pattern < overrides[b].pattern
+	})
+
+	v := &retention{
+		folderFs:        cfg.Filesystem(nil),
+		versionsFs:      versionerFsFromFolderCfg(cfg),
+		copyRangeMethod: cfg.CopyRangeMethod.ToFS(),
+		policy:          policy,
+		overrides:       overrides,
+	}
+
+	l.Debugf("instantiated %#v", v)
+	return v
+}
+
+// policyFor returns the index of the override applying to the named file,
+// or -1 if none does, and the policy to use. Patterns containing a slash
+// are matched against the full path, others against the file name only.
+func (v *retention) policyFor(name string) (int, retentionPolicy) {
+	slashed := filepath.ToSlash(name)
+	base := path.Base(slashed)
+	for i, override := range v.overrides {
+		target := base
+		if strings.Contains(override.pattern, "/") {
+			target = slashed
+		}
+		if ok, _ := path.Match(override.pattern, target); ok {
+			return i, override.policy
+		}
+	}
+	return -1, v.policy
+}
+
+// Archive moves the named file away to a version archive. If this function
+// returns nil, the named file does not exist any more (has been archived).
+func (v *retention) Archive(filePath string) error {
+	if err := archiveFile(v.copyRangeMethod, v.folderFs, v.versionsFs, filePath, TagFilename); err != nil {
+		return err
+	}
+	v.thin(filePath)
+	return nil
+}
+
+// ArchiveCopy moves the file at copyPath away to the version archive, as a
+// version of the named file.
+func (v *retention) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	if err := archiveFileAs(v.copyRangeMethod, v.folderFs, v.versionsFs, copyPath, filePath, versionTime, TagFilename); err != nil {
+		return err
+	}
+	v.thin(filePath)
+	return nil
+}
+
+// thin removes the versions of the named file that its policy doesn't keep.
+func (v *retention) thin(filePath string) {
+	// The size limit applies to all files under a policy, so it's only
+	// enforced by Clean.
+	_, policy := v.policyFor(filePath)
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filePath), func(versions []string, now time.Time) []string {
+		var valid []string
+		var times []time.Time
+		for _, version := range versions {
+			versionTime, err := time.ParseInLocation(TimeFormat, extractTag(version), time.Local)
+			if err != nil {
+				l.Debugf("Versioner: file name %q is invalid: %v", version, err)
+				continue
+			}
+			valid = append(valid, version)
+			times = append(times, versionTime)
+		}
+		var remove []string
+		for i, keep := range policy.keeps(times, now) {
+			if !keep {
+				remove = append(remove, valid[i])
+			}
+		}
+		return remove
+	})
+}
+
+func (v *retention) GetVersions() (map[string][]FileVersion, error) {
+	return retrieveVersions(v.versionsFs)
+}
+
+func (v *retention) Restore(filepath string, versionTime time.Time) error {
+	return restoreFile(v.copyRangeMethod, v.versionsFs, v.folderFs, filepath, versionTime, TagFilename)
+}
+
+func (v *retention) Clean(ctx context.Context) error {
+	l.Debugln("Versioner clean: Cleaning", v.versionsFs)
+
+	remove, dirTracker, err := v.expired(ctx, time.Now())
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			l.Warnln("Versioner: scanning versions dir:", err)
+		}
+		return err
+	}
+	for _, version := range remove {
+		if err := v.versionsFs.Remove(version.path); err != nil {
+			l.Warnf("Versioner: can't remove %q: %v", version.path, err)
+		}
+	}
+	dirTracker.deleteEmptyDirs(v.versionsFs)
+
+	l.Debugln("Cleaner: Finished cleaning", v.versionsFs)
+	return nil
+}
+
+// CleanDryRun returns the versions that Clean would remove now.
+func (v *retention) CleanDryRun(ctx context.Context) (map[string][]FileVersion, error) {
+	remove, _, err := v.expired(ctx, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string][]FileVersion)
+	for _, version := range remove {
+		name := osutil.NormalizedFilename(version.name)
+		files[name] = append(files[name], FileVersion{
+			VersionTime: version.time,
+			ModTime:     version.modTime.Truncate(time.Second),
+			Size:        version.size,
+		})
+	}
+	return files, nil
+}
+
+// expired returns the versions that the policies don't keep at the given
+// time, and the directories that will be empty once they are removed.
+func (v *retention) expired(ctx context.Context, now time.Time) ([]retentionVersion, emptyDirTracker, error) {
+	dirTracker := make(emptyDirTracker)
+	if _, err := v.versionsFs.Stat("."); fs.IsNotExist(err) {
+		// There is nothing to clean in a nonexistent dir.
+		return nil, dirTracker, nil
+	}
+
+	// Versions per file, per policy (override index + 1).
+	versions := make([]map[string][]retentionVersion, len(v.overrides)+1)
+	walkFn := func(path string, f fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if f.IsDir() && !f.IsSymlink() {
+			dirTracker.addDir(path)
+			return nil
+		}
+
+		name, tag := UntagFilename(path)
+		versionTime, err := time.ParseInLocation(TimeFormat, tag, time.Local)
+		if name == "" || err != nil {
+			// Not a version, leave it be.
+			dirTracker.addFile(path)
+			return nil
+		}
+
+		idx, _ := v.policyFor(name)
+		if versions[idx+1] == nil {
+			versions[idx+1] = make(map[string][]retentionVersion)
+		}
+		versions[idx+1][name] = append(versions[idx+1][name], retentionVersion{
+			path:    path,
+			name:    name,
+			time:    versionTime,
+			modTime: f.ModTime(),
+			size:    f.Size(),
+		})
+		return nil
+	}
+	if err := v.versionsFs.Walk(".", walkFn); err != nil {
+		return nil, nil, err
+	}
+
+	var remove []retentionVersion
+	for i, perFile := range versions {
+		policy := v.policy
+		if i > 0 {
+			policy = v.overrides[i-1].policy
+		}
+
+		var kept []retentionVersion
+		for _, fileVersions := range perFile {
+			times := make([]time.Time, len(fileVersions))
+			for j, version := range fileVersions {
+				times[j] = version.time
+			}
+			for j, keep := range policy.keeps(times, now) {
+				if keep {
+					kept = append(kept, fileVersions[j])
+				} else {
+					remove = append(remove, fileVersions[j])
+				}
+			}
+		}
+
+		if policy.maxTotal > 0 {
+			// Latest first, removing the oldest beyond the limit.
+			sort.Slice(kept, func(a, b int) bool {
+				return kept[a].time.After(kept[b].time)
+			})
+			var total int64
+			for j, version := range kept {
+				total += version.size
+				if total > policy.maxTotal {
+					remove = append(remove, kept[j:]...)
+					kept = kept[:j]
+					break
+				}
+			}
+		}
+
+		for _, version := range kept {
+			dirTracker.addFile(version.path)
+		}
+	}
+
+	sort.Slice(remove, func(a, b int) bool {
+		return remove[a].path < remove[b].path
+	})
+	return remove, dirTracker, nil
+}
@@ -0,0 +1,144 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/syncthing/syncthing/lib/config"
+)
+
+func TestParseRetentionPolicy(t *testing.T) {
+	p, err := parseRetentionPolicy("keep last 10, hourly for 2 days, daily for 30 days, monthly for 2 years, max 50 GiB total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.keepLast != 10 || len(p.periods) != 3 || p.maxTotal != 50<<30 {
+		t.Errorf("unexpected policy %+v", p)
+	}
+	if p.periods[1].maxAge != (ageSpec{days: 30}) || p.periods[2].maxAge != (ageSpec{years: 2}) {
+		t.Errorf("unexpected ages %+v", p.periods)
+	}
+
+	if p, err := parseRetentionPolicy("max 1.5GB total"); err != nil || p.maxTotal != 1500*1000*1000 {
+		t.Errorf("got %+v, %v", p, err)
+	}
+
+	for _, invalid := range []string{
+		"keep 10",
+		"keep last ten",
+		"hourly for 2",
+		"hourly for 2 fortnights",
+		"max 10 XB total",
+		"max 10 GB",
+		"sometimes",
+	} {
+		if _, err := parseRetentionPolicy(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
+
+func TestRetentionPolicyKeeps(t *testing.T) {
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+	times := []time.Time{
+		now.Add(-10 * time.Minute),            // 0: latest
+		now.Add(-20 * time.Minute),            // 1: same hour as 0
+		now.Add(-90 * time.Minute),            // 2: previous hour
+		now.Add(-30 * time.Hour),              // 3: previous day, beyond hourly
+		now.Add(-31 * time.Hour),              // 4: same day as 3
+		now.AddDate(0, -2, 0),                 // 5: beyond daily, monthly
+		now.AddDate(0, -2, 0).Add(-time.Hour), // 6: same month as 5
+		now.AddDate(-3, 0, 0),                 // 7: too old
+	}
+
+	p, err := parseRetentionPolicy("keep last 1, hourly for 1 day, daily for 7 days, monthly for 1 year")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []bool{true, false, true, true, false, true, false, false}
+	keeps := p.keeps(times, now)
+	for i := range expected {
+		if keeps[i] != expected[i] {
+			t.Errorf("version %d: keep is %v, expected %v", i, keeps[i], expected[i])
+		}
+	}
+
+	// A policy without rules keeps everything.
+	for i, keep := range (retentionPolicy{}).keeps(times, now) {
+		if !keep {
+			t.Errorf("version %d not kept by empty policy", i)
+		}
+	}
+}
+
+func TestRetentionCleanDryRun(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           dir,
+		Versioning: config.VersioningConfiguration{
+			Type: "retention",
+			Params: map[string]string{
+				"policy":         "keep last 2",
+				"policy:*.psd":   "keep last 1",
+				"policy:big/*":   "max 15 B total",
+				"policy:invalid": "whenever",
+			},
+		},
+	}
+	v := newRetention(cfg).(*retention)
+
+	now := time.Now()
+	var all []string
+	create := func(name string, age time.Duration, size int) string {
+		t.Helper()
+		version := TagFilename(name, now.Add(-age).Format(TimeFormat))
+		if err := v.versionsFs.MkdirAll(filepath.Dir(version), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		writeVersionFile(t, v.versionsFs, version, make([]byte, size))
+		all = append(all, version)
+		return version
+	}
+	create("doc.txt", time.Hour, 1)
+	create("doc.txt", 2*time.Hour, 1)
+	oldDoc := create("doc.txt", 3*time.Hour, 1)
+	create("image.psd", time.Hour, 1)
+	oldImage := create("image.psd", 2*time.Hour, 1)
+	create(filepath.Join("big", "a"), time.Hour, 10)
+	oldBig := create(filepath.Join("big", "b"), 2*time.Hour, 10)
+	create("invalid", 100*time.Hour, 1)
+
+	expired, err := v.CleanDryRun(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(expired) != 3 || len(expired["doc.txt"]) != 1 || len(expired["image.psd"]) != 1 || len(expired[filepath.Join("big", "b")]) != 1 {
+		t.Fatalf("unexpected expired versions %v", expired)
+	}
+
+	// The dry run didn't remove anything, Clean removes exactly those.
+	for _, version := range all {
+		if _, err := v.versionsFs.Lstat(version); err != nil {
+			t.Fatal("version removed by dry run:", err)
+		}
+	}
+	if err := v.Clean(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range all {
+		_, err := v.versionsFs.Lstat(version)
+		removed := version == oldDoc || version == oldImage || version == oldBig
+		if removed != (err != nil) {
+			t.Errorf("%s: expected removed to be %v, got error %v", version, removed, err)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright (C) 2026 The Syncthing Authors.
+//
+// This Source Code Form is subject to the terms of the Mozilla Public
+// License, v. 2.0. If a copy of the MPL was not distributed with this file,
+// You can obtain one at https://mozilla.org/MPL/2.0/.
+
+package versioner
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A retentionPolicy decides which versions of a file to keep. It's written
+// as a comma separated list of clauses, for example
+//
+//	keep last 10, hourly for 2 days, daily for 30 days, monthly for 2 years, max 50 GiB total
+//
+// "keep last N" keeps the N latest versions. "<period> for N <unit>" keeps
+// the latest version in each hour, day, week, month or year, for versions
+// up to the given age. A version is kept if any of these clauses keeps it;
+// a policy without any keeps all versions. "max <size> total" then removes
+// the oldest of the kept versions of all files under the policy until their
+// total size is within the limit.
+type retentionPolicy struct {
+	keepLast int
+	periods  []retentionPeriod
+	maxTotal int64 // bytes, zero for no limit
+}
+
+type retentionPeriod struct {
+	bucket func(time.Time) string // identifies the period a time falls in
+	maxAge ageSpec
+}
+
+// ageSpec is an age in calendar units, as the length of months and years
+// varies.
+type ageSpec struct {
+	years, months, days int
+	hours               time.Duration
+}
+
+func (a ageSpec) cutoff(now time.Time) time.Time {
+	return now.AddDate(-a.years, -a.months, -a.days).Add(-a.hours)
+}
+
+var retentionBuckets = map[string]func(time.Time) string{
+	"hourly": func(t time.Time) string { return t.Format("2006-01-02T15") },
+	"daily":  func(t time.Time) string { return t.Format("2006-01-02") },
+	"weekly": func(t time.Time) string {
+		year, week := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	},
+	"monthly": func(t time.Time) string { return t.Format("2006-01") },
+	"yearly":  func(t time.Time) string { return t.Format("2006") },
+}
+
+func parseRetentionPolicy(s string) (retentionPolicy, error) {
+	var p retentionPolicy
+	for _, clause := range strings.Split(s, ",") {
+		fields := strings.Fields(strings.ToLower(clause))
+		switch {
+		case len(fields) == 0:
+			continue
+
+		case fields[0] == "keep":
+			if len(fields) != 3 || fields[1] != "last" {
+				return p, fmt.Errorf("invalid clause %q, expected \"keep last N\"", clause)
+			}
+			n, err := strconv.Atoi(fields[2])
+			if err != nil || n < 0 {
+				return p, fmt.Errorf("invalid number of versions in %q", clause)
+			}
+			p.keepLast = n
+
+		case fields[0] == "max":
+			if len(fields) < 3 || fields[len(fields)-1] != "total" {
+				return p, fmt.Errorf("invalid clause %q, expected \"max <size> total\"", clause)
+			}
+			size, err := parseRetentionSize(strings.Join(fields[1:len(fields)-1], ""))
+			if err != nil {
+				return p, fmt.Errorf("invalid size in %q: %w", clause, err)
+			}
+			p.maxTotal = size
+
+		case retentionBuckets[fields[0]] != nil:
+			if len(fields) != 4 || fields[1] != "for" {
+				return p, fmt.Errorf("invalid clause %q, expected \"%s for N <unit>\"", clause, fields[0])
+			}
+			age, err := parseAgeSpec(fields[2], fields[3])
+			if err != nil {
+				return p, fmt.Errorf("invalid age in %q: %w", clause, err)
+			}
+			p.periods = append(p.periods, retentionPeriod{bucket: retentionBuckets[fields[0]], maxAge: age})
+
+		default:
+			return p, fmt.Errorf("unknown clause %q", clause)
+		}
+	}
+	return p, nil
+}
+
+func parseAgeSpec(num, unit string) (ageSpec, error) {
+	n, err := strconv.Atoi(num)
+	if err != nil || n < 0 {
+		return ageSpec{}, fmt.Errorf("invalid number %q", num)
+	}
+	switch strings.TrimSuffix(unit, "s") {
+	case "hour":
+		return ageSpec{hours: time.Duration(n) * time.Hour}, nil
+	case "day":
+		return ageSpec{days: n}, nil
+	case "week":
+		return ageSpec{days: 7 * n}, nil
+	case "month":
+		return ageSpec{months: n}, nil
+	case "year":
+		return ageSpec{years: n}, nil
+	default:
+		return ageSpec{}, fmt.Errorf("unknown unit %q", unit)
+	}
+}
+
+var retentionSizeUnits = map[string]int64{
+	"":    1,
+	"b":   1,
+	"kb":  1000,
+	"mb":  1000 * 1000,
+	"gb":  1000 * 1000 * 1000,
+	"tb":  1000 * 1000 * 1000 * 1000,
+	"kib": 1 << 10,
+	"mib": 1 << 20,
+	"gib": 1 << 30,
+	"tib": 1 << 40,
+}
+
+// parseRetentionSize parses a size like "50gib", as lower cased and without
+// spaces.
+func parseRetentionSize(s string) (int64, error) {
+	i := 0
+	for i < len(s) && (s[i] >= '0' && s[i] <= '9' || s[i] == '.') {
+		i++
+	}
+	val, err := strconv.ParseFloat(s[:i], 64)
+	if err != nil || val < 0 {
+		return 0, fmt.Errorf("invalid number %q", s[:i])
+	}
+	mult, ok := retentionSizeUnits[s[i:]]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", s[i:])
+	}
+	return int64(val * float64(mult)), nil
+}
+
+// keeps returns, for each of the given versions of a file, whether the
+// policy keeps it, ignoring the size limit.
+func (p retentionPolicy) keeps(versionTimes []time.Time, now time.Time) []bool {
+	order := make([]int, len(versionTimes))
+	for i := range order {
+		order[i] = i
+	}
+	// Latest first
+	sort.SliceStable(order, func(a, b int) bool {
+		return versionTimes[order[a]].After(versionTimes[order[b]])
+	})
+
+	keep := make([]bool, len(versionTimes))
+	if p.keepLast == 0 && len(p.periods) == 0 {
+		for i := range keep {
+			keep[i] = true
+		}
+		return keep
+	}
+
+	for rank, i := range order {
+		if rank < p.keepLast {
+			keep[i] = true
+		}
+	}
+	for _, period := range p.periods {
+		cutoff := period.maxAge.cutoff(now)
+		seen := make(map[string]struct{})
+		for _, i := range order {
+			t := versionTimes[i]
+			if t.Before(cutoff) {
+				break
+			}
+			bucket := period.bucket(t)
+			if _, ok := seen[bucket]; ok {
+				continue
+			}
+			seen[bucket] = struct{}{}
+			keep[i] = true
+		}
+	}
+	return keep
+}
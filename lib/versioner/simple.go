@@ -63,6 +63,19 @@ func (v simple) Archive(filePath string) error {
 	return nil
 }
 
+// ArchiveCopy moves the file at copyPath away to the version archive, as a
+// version of the named file.
+func (v simple) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	err := archiveFileAs(v.copyRangeMethod, v.folderFs, v.versionsFs, copyPath, filePath, versionTime, TagFilename)
+	if err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filePath), v.toRemove)
+
+	return nil
+}
+
 func (v simple) GetVersions() (map[string][]FileVersion, error) {
 	return retrieveVersions(v.versionsFs)
 }
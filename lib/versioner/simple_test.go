@@ -93,6 +93,49 @@ func TestSimpleVersioningVersionCount(t *testing.T) {
 	}
 }
 
+func TestSimpleVersioningArchiveCopy(t *testing.T) {
+	cfg := config.FolderConfiguration{
+		FilesystemType: config.FilesystemTypeBasic,
+		Path:           t.TempDir(),
+		Versioning: config.VersioningConfiguration{
+			Type: "simple",
+		},
+	}
+	folderFs := cfg.Filesystem(nil)
+	v, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file has changed already, its earlier contents are in a copy.
+	writeVersionFile(t, folderFs, "file", []byte("current"))
+	writeVersionFile(t, folderFs, ".syncthing.file.tmp", []byte("earlier"))
+	versionTime := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+	if err := v.(CopyArchiver).ArchiveCopy("file", ".syncthing.file.tmp", versionTime); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readVersionFile(t, folderFs, "file"); string(got) != "current" {
+		t.Errorf("file changed to %q", got)
+	}
+	if _, err := folderFs.Lstat(".syncthing.file.tmp"); err == nil {
+		t.Error("copy was not moved to the archive")
+	}
+	versions, err := v.GetVersions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions["file"]) != 1 || !versions["file"][0].VersionTime.Equal(versionTime) {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+	if err := v.Restore("file", versionTime); err != nil {
+		t.Fatal(err)
+	}
+	if got := readVersionFile(t, folderFs, "file"); string(got) != "earlier" {
+		t.Errorf("restored %q, expected the earlier contents", got)
+	}
+}
+
 func TestPathTildes(t *testing.T) {
 	// Test that folder and version paths with leading tildes are expanded
 	// to the user's home directory. (issue #9241)
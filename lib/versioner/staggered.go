@@ -125,6 +125,18 @@ func (v *staggered) Archive(filePath string) error {
 	return nil
 }
 
+// ArchiveCopy moves the file at copyPath away to the version archive, as a
+// version of the named file.
+func (v *staggered) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	if err := archiveFileAs(v.copyRangeMethod, v.folderFs, v.versionsFs, copyPath, filePath, versionTime, TagFilename); err != nil {
+		return err
+	}
+
+	cleanVersions(v.versionsFs, findAllVersions(v.versionsFs, filePath), v.toRemove)
+
+	return nil
+}
+
 func (v *staggered) GetVersions() (map[string][]FileVersion, error) {
 	return retrieveVersions(v.versionsFs)
 }
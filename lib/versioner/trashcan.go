@@ -51,6 +51,14 @@ func (t *trashcan) Archive(filePath string) error {
 	})
 }
 
+// ArchiveCopy moves the file at copyPath away to the trash can, in place of
+// the named file.
+func (t *trashcan) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	return archiveFileAs(t.copyRangeMethod, t.folderFs, t.versionsFs, copyPath, filePath, versionTime, func(name, tag string) string {
+		return name
+	})
+}
+
 func (t *trashcan) String() string {
 	return fmt.Sprintf("trashcan@%p", t)
 }
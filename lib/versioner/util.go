@@ -136,10 +136,17 @@ func retrieveVersions(fileSystem fs.Filesystem) (map[string][]FileVersion, error
 type fileTagger func(string, string) string
 
 func archiveFile(method fs.CopyRangeMethod, srcFs, dstFs fs.Filesystem, filePath string, tagger fileTagger) error {
+	return archiveFileAs(method, srcFs, dstFs, filePath, filePath, time.Now(), tagger)
+}
+
+// archiveFileAs moves the file at srcPath to the archive, as the version of
+// filePath at the given time.
+func archiveFileAs(method fs.CopyRangeMethod, srcFs, dstFs fs.Filesystem, srcPath, filePath string, now time.Time, tagger fileTagger) error {
+	srcPath = osutil.NativeFilename(srcPath)
 	filePath = osutil.NativeFilename(filePath)
-	info, err := srcFs.Lstat(filePath)
+	info, err := srcFs.Lstat(srcPath)
 	if fs.IsNotExist(err) {
-		l.Debugln("not archiving nonexistent file", filePath)
+		l.Debugln("not archiving nonexistent file", srcPath)
 		return nil
 	} else if err != nil {
 		return err
@@ -171,12 +178,10 @@ func archiveFile(method fs.CopyRangeMethod, srcFs, dstFs fs.Filesystem, filePath
 		return err
 	}
 
-	now := time.Now()
-
 	ver := tagger(file, now.Format(TimeFormat))
 	dst := filepath.Join(inFolderPath, ver)
-	l.Debugln("archiving", filePath, "moving to", dst)
-	err = osutil.RenameOrCopy(method, srcFs, dstFs, filePath, dst)
+	l.Debugln("archiving", srcPath, "moving to", dst)
+	err = osutil.RenameOrCopy(method, srcFs, dstFs, srcPath, dst)
 
 	mtime := info.ModTime()
 	// If it's a trashcan versioner type thing, then it does not have version time in the name
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/syncthing/syncthing/lib/config"
+	"github.com/syncthing/syncthing/lib/protocol"
 )
 
 type Versioner interface {
@@ -24,17 +25,53 @@ type Versioner interface {
 	Clean(context.Context) error
 }
 
+// A DryRunCleaner can tell which versions Clean would remove, without
+// removing them.
+type DryRunCleaner interface {
+	CleanDryRun(context.Context) (map[string][]FileVersion, error)
+}
+
+// A CopyArchiver can archive a copy of earlier contents of a file, for when
+// they are no longer at the file's path because it was changed locally.
+type CopyArchiver interface {
+	// ArchiveCopy moves the file at copyPath in the folder to the archive,
+	// as the version of filePath at the given time.
+	ArchiveCopy(filePath, copyPath string, versionTime time.Time) error
+}
+
+// A FileArchiver can archive a file using what the index knows about it,
+// such as its block hashes, instead of reading it all again.
+type FileArchiver interface {
+	// ArchiveFile archives the named file like Archive. The file on disk is
+	// expected to be as described.
+	ArchiveFile(file protocol.FileInfo) error
+}
+
 type FileVersion struct {
-	VersionTime time.Time `json:"versionTime"`
-	ModTime     time.Time `json:"modTime"`
-	Size        int64     `json:"size"`
+	VersionTime time.Time     `json:"versionTime"`
+	ModTime     time.Time     `json:"modTime"`
+	Size        int64         `json:"size"`
+	Origin      VersionOrigin `json:"origin,omitempty"`
 }
 
+// VersionOrigin tells whether a version was archived because of a change
+// made by another device or one made locally.
+type VersionOrigin string
+
+const (
+	OriginRemote VersionOrigin = "remote"
+	OriginLocal  VersionOrigin = "local"
+)
+
 type factory func(cfg config.FolderConfiguration) Versioner
 
 var factories = make(map[string]factory)
 
-var ErrRestorationNotSupported = errors.New("version restoration not supported with the current versioner")
+var (
+	ErrRestorationNotSupported = errors.New("version restoration not supported with the current versioner")
+	ErrDryRunNotSupported      = errors.New("clean dry run not supported with the current versioner")
+	ErrCopyArchiveNotSupported = errors.New("archiving copies not supported with the current versioner")
+)
 
 const (
 	TimeFormat = "20060102-150405"
@@ -69,6 +106,22 @@ func (v *versionerWithErrorContext) Archive(filePath string) error {
 	return v.wrapError(v.Versioner.Archive(filePath), "archive")
 }
 
+func (v *versionerWithErrorContext) ArchiveCopy(filePath, copyPath string, versionTime time.Time) error {
+	ca, ok := v.Versioner.(CopyArchiver)
+	if !ok {
+		return ErrCopyArchiveNotSupported
+	}
+	return v.wrapError(ca.ArchiveCopy(filePath, copyPath, versionTime), "archive copy")
+}
+
+func (v *versionerWithErrorContext) ArchiveFile(file protocol.FileInfo) error {
+	fa, ok := v.Versioner.(FileArchiver)
+	if !ok {
+		return v.Archive(file.Name)
+	}
+	return v.wrapError(fa.ArchiveFile(file), "archive")
+}
+
 func (v *versionerWithErrorContext) GetVersions() (map[string][]FileVersion, error) {
 	versions, err := v.Versioner.GetVersions()
 	return versions, v.wrapError(err, "get versions")
@@ -81,3 +134,12 @@ func (v *versionerWithErrorContext) Restore(filePath string, versionTime time.Ti
 func (v *versionerWithErrorContext) Clean(ctx context.Context) error {
 	return v.wrapError(v.Versioner.Clean(ctx), "clean")
 }
+
+func (v *versionerWithErrorContext) CleanDryRun(ctx context.Context) (map[string][]FileVersion, error) {
+	dr, ok := v.Versioner.(DryRunCleaner)
+	if !ok {
+		return nil, ErrDryRunNotSupported
+	}
+	versions, err := dr.CleanDryRun(ctx)
+	return versions, v.wrapError(err, "clean dry run")
+}